@@ -0,0 +1,259 @@
+// Package agc 移植WebRTC的数字自动增益控制（AGC）
+//
+// 以1ms子帧的峰值能量驱动快、慢两个包络跟随器，取二者较大值作为当前电平，
+// 经压缩曲线映射为增益后在子帧内线性插值施加。慢包络只在检测到语音时衰减，
+// 因此增益随语音电平缓慢上升，而在静音段保持不变、不会放大背景噪声。
+// 压缩曲线在低电平处提供CompressionGainDb的固定增益，高于拐点后以3:1压缩，
+// 满幅输入恰好压缩到TargetLevelDbfs；启用限幅时截断超出目标电平的输出。相比WebRTC的实现，增益直接以
+// 浮点计算而非查表，并省略了门限（gate）处理，其余常量与更新规则保持一致。
+//
+// 使用示例:
+//
+//	c, err := agc.New(16000, agc.DefaultConfig())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	// frame: 10ms整数倍的16位样本，就地调整增益
+//	if err := c.Process(frame); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// 增益控制不引入延迟。
+package agc
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrInvalidSampleRate 不支持的采样率
+	ErrInvalidSampleRate = errors.New("agc: sample rate must be 8000, 16000, 24000, 32000, or 48000 Hz")
+
+	// ErrInvalidConfig 无效的配置
+	ErrInvalidConfig = errors.New("agc: target level must be in [0, 31] dBFS and compression gain in [0, 90] dB")
+
+	// ErrInvalidFrameLength 帧长度不是10ms的整数倍
+	ErrInvalidFrameLength = errors.New("agc: frame length must be a multiple of 10 ms")
+)
+
+// 与WebRTC digital_agc一致的常量
+const (
+	subframes     = 10     // 每个10ms块的子帧数
+	compRatio     = 3.0    // 压缩比
+	fastDecay     = 1000.0 // 快包络每子帧的衰减（Q16）
+	slowAttack    = 500.0  // 慢包络每子帧的上升系数（Q16）
+	maxSlowDecay  = 65.0   // 语音时慢包络每子帧的衰减（Q16），约1s
+	avgDecayTime  = 250    // 长期电平统计的平均块数
+	stdLowerBound = 6.0    // 长期电平标准差低于此值（dB）时不衰减，防止长时间稳态噪声被当作语音
+	stdUpperBound = 12.0   // 长期电平标准差高于此值（dB）时完全衰减
+	fullScale     = 32768.0
+)
+
+// Config 增益控制配置
+type Config struct {
+	// TargetLevelDbfs 目标电平，以低于满幅的dB数表示（0-31，如3表示-3dBFS）
+	TargetLevelDbfs int
+
+	// CompressionGainDb 低电平输入的最大增益（0-90dB）
+	CompressionGainDb int
+
+	// LimiterEnable 将输出包络限制在目标电平以内
+	LimiterEnable bool
+}
+
+// DefaultConfig 返回WebRTC的默认配置：目标-3dBFS，压缩增益9dB，启用限幅
+func DefaultConfig() Config {
+	return Config{
+		TargetLevelDbfs:   3,
+		CompressionGainDb: 9,
+		LimiterEnable:     true,
+	}
+}
+
+// Controller 数字增益控制器
+//
+// Controller有状态且不是并发安全的，每路音频流使用一个实例
+type Controller struct {
+	cfg       Config
+	blockLen  int // 块长度（10ms样本数）
+	subLen    int // 子帧长度（1ms样本数）
+	kneeLevel float64
+
+	env   [subframes]float64
+	gains [subframes + 1]float64
+
+	capacitorFast float64
+	capacitorSlow float64
+	gain          float64 // 上一块结束时的线性增益
+
+	// 近端语音检测（WebRTC的ProcessVad）
+	logRatio       float64
+	meanLongTerm   float64
+	meanSqLongTerm float64
+	stdLongTerm    float64
+	counter        int
+}
+
+// New 创建增益控制器
+//
+// 参数:
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
+//   - cfg: 增益控制配置
+//
+// 返回:
+//   - *Controller: 增益控制器实例
+//   - error: 错误信息
+func New(sampleRate int, cfg Config) (*Controller, error) {
+	switch sampleRate {
+	case 8000, 16000, 24000, 32000, 48000:
+	default:
+		return nil, ErrInvalidSampleRate
+	}
+	if cfg.TargetLevelDbfs < 0 || cfg.TargetLevelDbfs > 31 ||
+		cfg.CompressionGainDb < 0 || cfg.CompressionGainDb > 90 {
+		return nil, ErrInvalidConfig
+	}
+
+	c := &Controller{
+		cfg:      cfg,
+		blockLen: sampleRate / 100,
+		subLen:   sampleRate / 1000,
+		// 拐点以上按3:1压缩，满幅输入恰好输出目标电平：
+		// knee + gain + (0-knee)/3 = -target
+		kneeLevel: -float64(cfg.TargetLevelDbfs+cfg.CompressionGainDb) * compRatio / (compRatio - 1),
+	}
+	c.Reset()
+	return c, nil
+}
+
+// Reset 重置为初始状态
+func (c *Controller) Reset() {
+	c.capacitorFast = 0
+	c.capacitorSlow = 0
+	c.gain = 1
+	c.logRatio = 0
+	c.meanLongTerm = 30
+	c.meanSqLongTerm = 30*30 + 25*25
+	c.stdLongTerm = 25
+	c.counter = 3
+}
+
+// Gain 返回当前增益（dB）
+func (c *Controller) Gain() float64 {
+	return 20 * math.Log10(c.gain)
+}
+
+// Process 就地调整一帧的增益
+//
+// 参数:
+//   - frame: 16位样本，长度须为10ms的整数倍
+//
+// 返回:
+//   - error: 帧长度无效时返回ErrInvalidFrameLength
+func (c *Controller) Process(frame []int16) error {
+	if len(frame) == 0 || len(frame)%c.blockLen != 0 {
+		return ErrInvalidFrameLength
+	}
+	for off := 0; off < len(frame); off += c.blockLen {
+		c.processBlock(frame[off : off+c.blockLen])
+	}
+	return nil
+}
+
+// processBlock 处理一个10ms块（WebRTC的WebRtcAgc_ProcessDigital）
+func (c *Controller) processBlock(block []int16) {
+	decay := c.decay(block)
+
+	// 各子帧的峰值能量
+	for k := range c.env {
+		var peak float64
+		for _, v := range block[k*c.subLen : (k+1)*c.subLen] {
+			peak = max(peak, float64(v)*float64(v))
+		}
+		c.env[k] = peak
+	}
+
+	// 各子帧结束时的增益
+	c.gains[0] = c.gain
+	for k, env := range c.env {
+		c.capacitorFast -= c.capacitorFast * fastDecay / 65536
+		c.capacitorFast = max(c.capacitorFast, env)
+		if env > c.capacitorSlow {
+			c.capacitorSlow += (env - c.capacitorSlow) * slowAttack / 65536
+		} else {
+			c.capacitorSlow -= c.capacitorSlow * decay / 65536
+		}
+		level := max(c.capacitorFast, c.capacitorSlow)
+
+		g := math.Pow(10, c.gainDb(level)/20)
+		// 避免削波
+		if env*g*g > math.MaxInt16*math.MaxInt16 {
+			g = math.MaxInt16 / math.Sqrt(env)
+		}
+		c.gains[k+1] = g
+	}
+	// 增益下降比上升提前1ms生效
+	for k := 1; k < subframes; k++ {
+		c.gains[k] = min(c.gains[k], c.gains[k+1])
+	}
+	c.gain = c.gains[subframes]
+
+	// 子帧内线性插值施加增益
+	for k := 0; k < subframes; k++ {
+		g, delta := c.gains[k], (c.gains[k+1]-c.gains[k])/float64(c.subLen)
+		for i := k * c.subLen; i < (k+1)*c.subLen; i++ {
+			v := math.Round(float64(block[i]) * g)
+			block[i] = int16(max(math.MinInt16, min(math.MaxInt16, v)))
+			g += delta
+		}
+	}
+}
+
+// gainDb 将包络能量映射为增益（dB）
+func (c *Controller) gainDb(env float64) float64 {
+	if env <= 0 {
+		return float64(c.cfg.CompressionGainDb)
+	}
+	level := 10 * math.Log10(env/(fullScale*fullScale))
+	if level <= c.kneeLevel {
+		return float64(c.cfg.CompressionGainDb)
+	}
+	// 拐点以上按压缩比压缩，限幅只截断超出目标电平的输出
+	out := c.kneeLevel + float64(c.cfg.CompressionGainDb) + (level-c.kneeLevel)/compRatio
+	if c.cfg.LimiterEnable {
+		out = min(out, -float64(c.cfg.TargetLevelDbfs))
+	}
+	return out - level
+}
+
+// decay 更新近端语音检测，返回慢包络本块的衰减系数（Q16）
+func (c *Controller) decay(block []int16) float64 {
+	var nrg float64
+	for _, v := range block {
+		nrg += float64(v) * float64(v)
+	}
+	db := 10 * math.Log10(nrg/float64(len(block))+1)
+
+	// 长期电平的均值与标准差
+	if c.counter < avgDecayTime {
+		c.counter++
+	}
+	n := float64(c.counter)
+	c.meanLongTerm = (c.meanLongTerm*n + db) / (n + 1)
+	c.meanSqLongTerm = (c.meanSqLongTerm*n + db*db) / (n + 1)
+	c.stdLongTerm = math.Sqrt(max(c.meanSqLongTerm-c.meanLongTerm*c.meanLongTerm, 1e-6))
+
+	// 平滑的对数似然比
+	c.logRatio = (13*c.logRatio + 3*(db-c.meanLongTerm)/c.stdLongTerm) / 16
+	c.logRatio = max(-2, min(2, c.logRatio))
+
+	decay := maxSlowDecay * max(0, min(1, c.logRatio))
+	switch {
+	case c.stdLongTerm < stdLowerBound:
+		decay = 0
+	case c.stdLongTerm < stdUpperBound:
+		decay *= (c.stdLongTerm - stdLowerBound) / (stdUpperBound - stdLowerBound)
+	}
+	return decay
+}
@@ -0,0 +1,110 @@
+package agc
+
+import (
+	"math"
+	"testing"
+)
+
+// tone 生成n个样本的440Hz正弦波
+func tone(rate, n int, amp float64) []int16 {
+	x := make([]int16, n)
+	for i := range x {
+		x[i] = int16(amp * math.Sin(2*math.Pi*440*float64(i)/float64(rate)))
+	}
+	return x
+}
+
+// peak 返回最大绝对值
+func peak(x []int16) int {
+	p := 0
+	for _, v := range x {
+		p = max(p, int(v), -int(v))
+	}
+	return p
+}
+
+// TestController 测试低电平放大与高电平限幅
+func TestController(t *testing.T) {
+	for _, rate := range []int{8000, 16000, 48000} {
+		// 低电平输入获得压缩增益
+		c, err := New(rate, DefaultConfig())
+		if err != nil {
+			t.Fatalf("创建控制器失败: %v", err)
+		}
+		quiet := tone(rate, 2*rate, 300)
+		if err := c.Process(quiet); err != nil {
+			t.Fatalf("处理失败: %v", err)
+		}
+		if got, want := peak(quiet[rate:]), int(300*math.Pow(10, 9.0/20)); got < want*95/100 || got > want*105/100 {
+			t.Errorf("%dHz: 低电平输入应放大9dB至约%d, 得到%d", rate, want, got)
+		}
+		if g := c.Gain(); math.Abs(g-9) > 0.1 {
+			t.Errorf("%dHz: 当前增益应为9dB, 得到%.2f", rate, g)
+		}
+
+		// 拐点（-18dBFS）以上按3:1压缩，-12dBFS输入输出-7dBFS，低于目标电平不受限幅影响
+		c.Reset()
+		mid := tone(rate, 2*rate, fullScale*math.Pow(10, -12.0/20))
+		c.Process(mid)
+		if got, want := peak(mid[rate:]), int(fullScale*math.Pow(10, -7.0/20)); got < want*95/100 || got > want*105/100 {
+			t.Errorf("%dHz: 拐点以上应按3:1压缩到约%d, 得到%d", rate, want, got)
+		}
+
+		// 接近满幅的输入压缩到约-3dBFS
+		c.Reset()
+		loud := tone(rate, 2*rate, 30000)
+		c.Process(loud)
+		if got, want := peak(loud[rate:]), int(fullScale*math.Pow(10, -3.0/20)); got < want*95/100 || got > want*105/100 {
+			t.Errorf("%dHz: 高电平输入应压缩到约%d, 得到%d", rate, want, got)
+		}
+	}
+}
+
+// TestControllerNoLimiter 测试关闭限幅时按压缩比输出且不削波
+func TestControllerNoLimiter(t *testing.T) {
+	c, _ := New(16000, Config{TargetLevelDbfs: 9, CompressionGainDb: 9})
+	// 拐点为-27dBFS，输入-12dBFS时按3:1压缩输出-13dBFS
+	in := tone(16000, 32000, fullScale*math.Pow(10, -12.0/20))
+	c.Process(in)
+	if got, want := peak(in[16000:]), int(fullScale*math.Pow(10, -13.0/20)); got < want*95/100 || got > want*105/100 {
+		t.Errorf("压缩后峰值应约为%d, 得到%d", want, got)
+	}
+
+	// 拐点为-30dBFS，-6.2dBFS输入压缩到约-2.1dBFS
+	c, _ = New(16000, Config{CompressionGainDb: 20})
+	in = tone(16000, 32000, 16000)
+	c.Process(in)
+	if got, want := peak(in[16000:]), int(fullScale*math.Pow(10, -2.1/20)); got < want*95/100 || got > want*105/100 {
+		t.Errorf("压缩后峰值应约为%d, 得到%d", want, got)
+	}
+}
+
+// TestControllerSilence 测试语音后的静音段保持语音时的增益，不会放大到最大增益
+func TestControllerSilence(t *testing.T) {
+	c, _ := New(16000, DefaultConfig())
+	speech := tone(16000, 16000, 20000)
+	c.Process(speech)
+	g := c.Gain()
+	noise := tone(16000, 16000, 100)
+	c.Process(noise)
+	if math.Abs(c.Gain()-g) > 1 {
+		t.Errorf("静音段增益应保持在%.2fdB附近, 得到%.2fdB", g, c.Gain())
+	}
+}
+
+// TestControllerErrors 测试参数校验
+func TestControllerErrors(t *testing.T) {
+	if _, err := New(44100, DefaultConfig()); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := New(16000, Config{TargetLevelDbfs: 32}); err != ErrInvalidConfig {
+		t.Errorf("应返回ErrInvalidConfig, 得到%v", err)
+	}
+	if _, err := New(16000, Config{CompressionGainDb: -1}); err != ErrInvalidConfig {
+		t.Errorf("应返回ErrInvalidConfig, 得到%v", err)
+	}
+	c, _ := New(16000, DefaultConfig())
+	if err := c.Process(make([]int16, 100)); err != ErrInvalidFrameLength {
+		t.Errorf("应返回ErrInvalidFrameLength, 得到%v", err)
+	}
+}
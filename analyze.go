@@ -0,0 +1,57 @@
+package webrtcvad
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// analyze.go 提供加窗、补零、实数FFT求幅度谱的一站式分析
+
+// realFFT64Pools 按阶数共享的RealFFT64（含工作缓冲区与旋转因子表）
+var realFFT64Pools [kMaxFFT64Order + 1]sync.Pool
+
+func init() {
+	for order := range realFFT64Pools {
+		realFFT64Pools[order].New = func() any { return CreateRealFFT64(order) }
+	}
+}
+
+// AnalyzeFrame 计算一帧的幅度谱
+//
+// 依次加窗、补零到不小于帧长度的2的幂（至少4点）、做浮点实数FFT并取模，
+// 即大多数频谱分析的固定组合。可并发调用。
+//
+// 参数:
+//   - frame: 16位样本
+//   - win: 窗函数，nil表示矩形窗（不加窗）
+//
+// 返回:
+//   - magnitudes: N/2+1个频点的幅度（未归一化），第k个频点的频率为k·采样率/N；
+//     frame为空或超过2^24个样本时返回nil
+func AnalyzeFrame(frame []int16, win WindowFunc) (magnitudes []float64) {
+	n := len(frame)
+	order := max(bits.Len(uint(n-1)), 2)
+	if n == 0 || order > kMaxFFT64Order {
+		return nil
+	}
+	size := 1 << order
+
+	in := make([]float64, size)
+	for i, v := range frame {
+		in[i] = float64(v)
+		if win != nil {
+			in[i] *= win(i, n)
+		}
+	}
+	spec := make([]float64, size+2)
+	fft := realFFT64Pools[order].Get().(*RealFFT64)
+	fft.RealForwardFFT(in, spec)
+	realFFT64Pools[order].Put(fft)
+
+	magnitudes = make([]float64, size/2+1)
+	for k := range magnitudes {
+		magnitudes[k] = math.Hypot(spec[2*k], spec[2*k+1])
+	}
+	return magnitudes
+}
@@ -0,0 +1,43 @@
+package webrtcvad
+
+import (
+	"math"
+	"testing"
+)
+
+// TestAnalyzeFrame 测试补零长度、峰值频点与窗函数的作用
+func TestAnalyzeFrame(t *testing.T) {
+	// 480个样本（16kHz下30ms）补零到512点，1000Hz正好位于第32个频点
+	frame := make([]int16, 480)
+	for i := range frame {
+		frame[i] = int16(10000 * math.Sin(2*math.Pi*1000*float64(i)/16000))
+	}
+	mags := AnalyzeFrame(frame, HannWindow)
+	if len(mags) != 257 {
+		t.Fatalf("应输出257个频点, 得到%d", len(mags))
+	}
+	peak := 0
+	for k, m := range mags {
+		if m > mags[peak] {
+			peak = k
+		}
+	}
+	if peak != 32 {
+		t.Errorf("峰值应位于第32个频点, 得到%d", peak)
+	}
+
+	// 矩形窗的泄漏明显大于Hann窗
+	rect := AnalyzeFrame(frame, nil)
+	if rect[100]/rect[32] <= mags[100]/mags[32] {
+		t.Errorf("矩形窗在远处频点的泄漏应大于Hann窗: %.2e <= %.2e", rect[100]/rect[32], mags[100]/mags[32])
+	}
+
+	// 直流信号：矩形窗下直流频点为样本之和
+	dc := AnalyzeFrame([]int16{100, 100, 100}, nil)
+	if len(dc) != 3 || dc[0] != 300 {
+		t.Errorf("3个样本应补零到4点且直流为300, 得到%v", dc)
+	}
+	if AnalyzeFrame(nil, HannWindow) != nil {
+		t.Error("空帧应返回nil")
+	}
+}
@@ -0,0 +1,260 @@
+package webrtcvad
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// batch.go 提供目录级的批量处理：遍历目录中的音频文件，由多个goroutine并行检测，
+// 逐个文件交付片段与统计
+
+// DefaultBatchExtensions BatchConfig.Extensions为空时选取的文件扩展名
+var DefaultBatchExtensions = []string{".wav", ".aif", ".aiff", ".pcm", ".raw"}
+
+// BatchConfig 批量处理配置
+type BatchConfig struct {
+	// Workers 并行处理的文件数，0表示runtime.NumCPU()
+	Workers int
+
+	// Extensions 选取的文件扩展名（含点，不区分大小写），空表示DefaultBatchExtensions
+	Extensions []string
+
+	// Options 各文件StreamVAD的选项。采样率取自文件头（原始PCM为DefaultRawSampleRate），
+	// 覆盖WithSampleRate；需要片段音频时加入WithCaptureAudio
+	Options []StreamVADOption
+
+	// OnProgress 每交付一个文件的结果后调用，done为已交付的文件数，total为文件总数。
+	// 与结果回调在同一goroutine中调用，可为nil
+	OnProgress func(done, total int)
+}
+
+// BatchResult 单个文件的处理结果
+type BatchResult struct {
+	// Path 文件路径
+	Path string
+	// SampleRate 文件的采样率
+	SampleRate int
+	// Segments 文件的全部片段（最后一个片段已结束）
+	Segments []VoiceSegment
+	// Stats 文件的逐帧判决统计
+	Stats Stats
+	// Err 处理该文件的错误（格式不支持、读取失败等），非nil时其余字段可能不完整
+	Err error
+}
+
+// BatchProcessor 目录批量处理器，可并发使用
+type BatchProcessor struct {
+	cfg BatchConfig
+}
+
+// NewBatchProcessor 创建批量处理器
+//
+// 参数:
+//   - cfg: 配置
+//
+// 返回:
+//   - *BatchProcessor: 批量处理器
+//   - error: Workers为负时返回ErrInvalidBatchConfig，选项无效时返回选项的错误
+func NewBatchProcessor(cfg BatchConfig) (*BatchProcessor, error) {
+	if cfg.Workers < 0 {
+		return nil, ErrInvalidBatchConfig
+	}
+	if cfg.Workers == 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if len(cfg.Extensions) == 0 {
+		cfg.Extensions = DefaultBatchExtensions
+	}
+	exts := make([]string, len(cfg.Extensions))
+	for i, ext := range cfg.Extensions {
+		exts[i] = strings.ToLower(ext)
+	}
+	cfg.Extensions = exts
+	cfg.Options = slices.Clip(cfg.Options)
+
+	if _, err := NewStreamVADWithOptions(cfg.Options...); err != nil {
+		return nil, err
+	}
+	return &BatchProcessor{cfg: cfg}, nil
+}
+
+// Run 处理root下（递归）的所有音频文件，每个文件处理完后以结果调用fn
+//
+// 先遍历目录确定文件列表，再由Workers个goroutine并行处理。fn与OnProgress在调用Run的
+// goroutine中依次调用，顺序为文件处理完成的顺序。单个文件的错误记录在BatchResult.Err中，
+// 不中断处理；fn返回错误或ctx取消时停止，进行中的文件被放弃。
+//
+// 参数:
+//   - ctx: 取消处理
+//   - root: 目录（也可以是单个文件）
+//   - fn: 结果回调
+//
+// 返回:
+//   - error: 遍历目录的错误、fn返回的错误或ctx.Err()，全部处理完成时为nil
+func (b *BatchProcessor) Run(ctx context.Context, root string, fn func(BatchResult) error) error {
+	paths, err := b.collect(root)
+	if err != nil {
+		return err
+	}
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan string)
+	results := make(chan BatchResult)
+	go func() {
+		defer close(jobs)
+		for _, path := range paths {
+			select {
+			case jobs <- path:
+			case <-workCtx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(b.cfg.Workers, len(paths)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				results <- b.processFile(workCtx, path)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 停止后继续读取results直到所有worker退出
+	var done int
+	var fnErr error
+	for r := range results {
+		if fnErr != nil || workCtx.Err() != nil {
+			continue
+		}
+		if fnErr = fn(r); fnErr != nil {
+			cancel()
+			continue
+		}
+		done++
+		if b.cfg.OnProgress != nil {
+			b.cfg.OnProgress(done, len(paths))
+		}
+	}
+	if fnErr != nil {
+		return fnErr
+	}
+	return ctx.Err()
+}
+
+// Stream 在后台goroutine中运行Run，返回结果通道和错误通道
+//
+// 结果通道在处理结束后关闭；之后错误通道（容量为1）给出Run的返回值并关闭。
+// 消费者应读取结果通道直到其关闭，或取消ctx
+func (b *BatchProcessor) Stream(ctx context.Context, root string) (<-chan BatchResult, <-chan error) {
+	out := make(chan BatchResult)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		err := b.Run(ctx, root, func(r BatchResult) error {
+			select {
+			case out <- r:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(out)
+		errc <- err
+	}()
+	return out, errc
+}
+
+// collect 递归列出root下扩展名匹配的文件，root为文件时直接返回
+func (b *BatchProcessor) collect(root string) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{root}, nil
+	}
+
+	var paths []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && slices.Contains(b.cfg.Extensions, strings.ToLower(filepath.Ext(path))) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// processFile 检测单个文件，ctx取消时放弃
+func (b *BatchProcessor) processFile(ctx context.Context, path string) BatchResult {
+	r := BatchResult{Path: path}
+
+	f, err := os.Open(path)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	defer f.Close()
+
+	src, sampleRate, err := openAudio(f)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.SampleRate = sampleRate
+
+	svad, err := NewStreamVADWithOptions(append(b.cfg.Options, WithSampleRate(sampleRate))...)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	buf := make([]byte, svad.frameSize*32)
+	for {
+		if err := ctx.Err(); err != nil {
+			r.Err = err
+			return r
+		}
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			if _, err := svad.Process(buf[:n]); err != nil {
+				r.Err = err
+				return r
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			r.Err = readErr
+			return r
+		}
+	}
+	if err := svad.Close(); err != nil {
+		r.Err = err
+		return r
+	}
+
+	r.Segments = svad.GetSegments()
+	r.Stats = svad.Stats()
+	return r
+}
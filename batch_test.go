@@ -0,0 +1,135 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// writeBatchDir 在临时目录中写入测试用的音频文件，返回目录
+func writeBatchDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	pcm := harmonicNoise(16000, 2*time.Second, 500*time.Millisecond, 1500*time.Millisecond)
+
+	var wav bytes.Buffer
+	if err := wave.Write(&wav, pcm, 16000); err != nil {
+		t.Fatal(err)
+	}
+	// 8位WAV不受支持
+	eightBit := bytes.Clone(wav.Bytes())
+	eightBit[34] = 8
+
+	files := map[string][]byte{
+		"a.wav":          wav.Bytes(),
+		"sub/b.WAV":      wav.Bytes(),
+		"sub/c.pcm":      pcm,
+		"notes.txt":      []byte("not audio"),
+		"sub/broken.wav": eightBit,
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// TestBatchProcessor 测试目录批量处理
+func TestBatchProcessor(t *testing.T) {
+	dir := writeBatchDir(t)
+
+	var progress []int
+	bp, err := NewBatchProcessor(BatchConfig{
+		Workers:    2,
+		Options:    []StreamVADOption{WithStreamMode(2)},
+		OnProgress: func(done, total int) { progress = append(progress, done*10+total) },
+	})
+	if err != nil {
+		t.Fatalf("创建批量处理器失败: %v", err)
+	}
+
+	results := make(map[string]BatchResult)
+	err = bp.Run(context.Background(), dir, func(r BatchResult) error {
+		rel, _ := filepath.Rel(dir, r.Path)
+		results[filepath.ToSlash(rel)] = r
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("批量处理失败: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("应处理4个音频文件（扩展名不区分大小写，忽略.txt）, 得到%d", len(results))
+	}
+	if want := []int{14, 24, 34, 44}; !slices.Equal(progress, want) {
+		t.Errorf("进度应为%v, 得到%v", want, progress)
+	}
+
+	for _, name := range []string{"a.wav", "sub/b.WAV", "sub/c.pcm"} {
+		r := results[name]
+		if r.Err != nil {
+			t.Fatalf("%s: 处理失败: %v", name, r.Err)
+		}
+		if r.SampleRate != 16000 || r.Stats.Frames != 100 || Report(r.Segments).Speech == 0 {
+			t.Errorf("%s: 结果错误: 采样率%d, %d帧, 片段%+v", name, r.SampleRate, r.Stats.Frames, r.Segments)
+		}
+	}
+	if results["sub/broken.wav"].Err == nil {
+		t.Error("损坏的文件应记录错误")
+	}
+
+	if _, err := NewBatchProcessor(BatchConfig{Workers: -1}); err != ErrInvalidBatchConfig {
+		t.Errorf("负的并行数应返回ErrInvalidBatchConfig, 得到%v", err)
+	}
+	if err := bp.Run(context.Background(), filepath.Join(dir, "missing"), func(BatchResult) error { return nil }); !os.IsNotExist(err) {
+		t.Errorf("不存在的目录应返回不存在错误, 得到%v", err)
+	}
+}
+
+// TestBatchProcessorCancel 测试回调错误、取消与通道接口
+func TestBatchProcessorCancel(t *testing.T) {
+	dir := writeBatchDir(t)
+	bp, _ := NewBatchProcessor(BatchConfig{Workers: 1})
+
+	stop := errors.New("stop")
+	var calls int
+	err := bp.Run(context.Background(), dir, func(BatchResult) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("回调出错后应停止并返回该错误, 得到%v（调用%d次）", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, errc := bp.Stream(ctx, dir)
+	var once sync.Once
+	for range results {
+		once.Do(cancel)
+	}
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("取消后应返回context.Canceled, 得到%v", err)
+	}
+
+	results, errc = bp.Stream(context.Background(), dir)
+	var n int
+	for range results {
+		n++
+	}
+	if err := <-errc; err != nil || n != 4 {
+		t.Errorf("应收到4个结果且无错误, 得到%d个, %v", n, err)
+	}
+}
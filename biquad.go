@@ -0,0 +1,197 @@
+package webrtcvad
+
+import "math"
+
+// biquad.go 提供RBJ音频EQ公式设计的二阶IIR滤波器及其级联，用于检测前的音频调理
+// （如陷波去除工频干扰、带限）
+
+// AudioFilter 有状态的16位音频滤波器，Biquad与BiquadCascade实现此接口
+type AudioFilter interface {
+	// Filter 滤波input写入output，output长度须不小于input，二者可以是同一切片
+	Filter(input []int16, output []int16)
+	// Reset 清除滤波器状态
+	Reset()
+}
+
+// Biquad 二阶IIR滤波器（直接II型转置）
+//
+// Biquad有状态且不是并发安全的，每路音频流使用一个实例；
+// 流中断或切换音源时调用Reset清除状态
+type Biquad struct {
+	b0, b1, b2 float64 // 前馈系数
+	a1, a2     float64 // 反馈系数（a0归一化为1）
+	z1, z2     float64 // 滤波器状态
+}
+
+// rbjParams 校验参数并计算RBJ公式的cos(w0)与alpha
+func rbjParams(sampleRate int, freq, q float64) (cos, alpha float64, err error) {
+	if sampleRate <= 0 {
+		return 0, 0, ErrInvalidSampleRate
+	}
+	if !(freq > 0 && freq < float64(sampleRate)/2) || !(q > 0) {
+		return 0, 0, ErrInvalidCutoff
+	}
+	w0 := 2 * math.Pi * freq / float64(sampleRate)
+	return math.Cos(w0), math.Sin(w0) / (2 * q), nil
+}
+
+// newBiquad 以a0归一化系数
+func newBiquad(b0, b1, b2, a0, a1, a2 float64) *Biquad {
+	return &Biquad{b0: b0 / a0, b1: b1 / a0, b2: b2 / a0, a1: a1 / a0, a2: a2 / a0}
+}
+
+// NewHighPassBiquad 创建二阶高通滤波器（RBJ音频EQ公式）
+//
+// 参数:
+//   - sampleRate: 采样率（Hz）
+//   - cutoff: 截止频率（Hz），须在(0, sampleRate/2)内
+//   - q: 品质因数，1/√2为Butterworth响应（通带最平坦）
+//
+// 返回:
+//   - *Biquad: 滤波器实例
+//   - error: 错误信息
+func NewHighPassBiquad(sampleRate int, cutoff, q float64) (*Biquad, error) {
+	cos, alpha, err := rbjParams(sampleRate, cutoff, q)
+	if err != nil {
+		return nil, err
+	}
+	return newBiquad((1+cos)/2, -(1 + cos), (1+cos)/2, 1+alpha, -2*cos, 1-alpha), nil
+}
+
+// NewLowPassBiquad 创建二阶低通滤波器，参数含义同NewHighPassBiquad
+func NewLowPassBiquad(sampleRate int, cutoff, q float64) (*Biquad, error) {
+	cos, alpha, err := rbjParams(sampleRate, cutoff, q)
+	if err != nil {
+		return nil, err
+	}
+	return newBiquad((1-cos)/2, 1-cos, (1-cos)/2, 1+alpha, -2*cos, 1-alpha), nil
+}
+
+// NewBandPassBiquad 创建二阶带通滤波器，中心频率处增益为0dB
+//
+// 参数:
+//   - sampleRate: 采样率（Hz）
+//   - center: 中心频率（Hz），须在(0, sampleRate/2)内
+//   - q: 品质因数，带宽约为center/q
+func NewBandPassBiquad(sampleRate int, center, q float64) (*Biquad, error) {
+	cos, alpha, err := rbjParams(sampleRate, center, q)
+	if err != nil {
+		return nil, err
+	}
+	return newBiquad(alpha, 0, -alpha, 1+alpha, -2*cos, 1-alpha), nil
+}
+
+// NewNotchBiquad 创建二阶陷波滤波器，常用于去除50/60Hz工频干扰
+//
+// 参数:
+//   - sampleRate: 采样率（Hz）
+//   - center: 陷波频率（Hz），须在(0, sampleRate/2)内
+//   - q: 品质因数，越大陷波越窄（去除工频干扰可取10-30）
+func NewNotchBiquad(sampleRate int, center, q float64) (*Biquad, error) {
+	cos, alpha, err := rbjParams(sampleRate, center, q)
+	if err != nil {
+		return nil, err
+	}
+	return newBiquad(1, -2*cos, 1, 1+alpha, -2*cos, 1-alpha), nil
+}
+
+// NewLowShelfBiquad 创建低架滤波器：低于转折频率的部分增益gainDb，高频不变
+//
+// 参数:
+//   - sampleRate: 采样率（Hz）
+//   - freq: 转折频率（Hz），须在(0, sampleRate/2)内
+//   - q: 品质因数，1/√2时过渡最平滑
+//   - gainDb: 低频增益（dB），负值为衰减
+func NewLowShelfBiquad(sampleRate int, freq, q, gainDb float64) (*Biquad, error) {
+	cos, alpha, err := rbjParams(sampleRate, freq, q)
+	if err != nil {
+		return nil, err
+	}
+	a := math.Pow(10, gainDb/40)
+	beta := 2 * math.Sqrt(a) * alpha
+	return newBiquad(
+		a*((a+1)-(a-1)*cos+beta),
+		2*a*((a-1)-(a+1)*cos),
+		a*((a+1)-(a-1)*cos-beta),
+		(a+1)+(a-1)*cos+beta,
+		-2*((a-1)+(a+1)*cos),
+		(a+1)+(a-1)*cos-beta,
+	), nil
+}
+
+// NewHighShelfBiquad 创建高架滤波器：高于转折频率的部分增益gainDb，低频不变，参数含义同NewLowShelfBiquad
+func NewHighShelfBiquad(sampleRate int, freq, q, gainDb float64) (*Biquad, error) {
+	cos, alpha, err := rbjParams(sampleRate, freq, q)
+	if err != nil {
+		return nil, err
+	}
+	a := math.Pow(10, gainDb/40)
+	beta := 2 * math.Sqrt(a) * alpha
+	return newBiquad(
+		a*((a+1)+(a-1)*cos+beta),
+		-2*a*((a-1)+(a+1)*cos),
+		a*((a+1)+(a-1)*cos-beta),
+		(a+1)-(a-1)*cos+beta,
+		2*((a-1)-(a+1)*cos),
+		(a+1)-(a-1)*cos-beta,
+	), nil
+}
+
+// Filter 滤波input写入output，输出饱和到int16范围
+//
+// output长度须不小于input，二者可以是同一切片（就地滤波）
+func (b *Biquad) Filter(input []int16, output []int16) {
+	for i, v := range input {
+		output[i] = saturate16(b.step(float64(v)))
+	}
+}
+
+// step 滤波一个样本
+func (b *Biquad) step(x float64) float64 {
+	y := b.b0*x + b.z1
+	b.z1 = b.b1*x - b.a1*y + b.z2
+	b.z2 = b.b2*x - b.a2*y
+	return y
+}
+
+// Reset 清除滤波器状态
+func (b *Biquad) Reset() {
+	b.z1, b.z2 = 0, 0
+}
+
+// BiquadCascade 依次串联的多个Biquad，用于高阶滤波或组合多个处理（如陷波+带限）
+//
+// 级间以浮点传递，只在最后饱和到int16。BiquadCascade有状态且不是并发安全的
+type BiquadCascade struct {
+	stages []*Biquad
+}
+
+// NewBiquadCascade 创建级联滤波器，按参数顺序处理
+func NewBiquadCascade(stages ...*Biquad) *BiquadCascade {
+	return &BiquadCascade{stages: stages}
+}
+
+// Filter 依次经过各级滤波input写入output，输出饱和到int16范围
+//
+// output长度须不小于input，二者可以是同一切片（就地滤波）
+func (c *BiquadCascade) Filter(input []int16, output []int16) {
+	for i, v := range input {
+		y := float64(v)
+		for _, b := range c.stages {
+			y = b.step(y)
+		}
+		output[i] = saturate16(y)
+	}
+}
+
+// Reset 清除各级滤波器的状态
+func (c *BiquadCascade) Reset() {
+	for _, b := range c.stages {
+		b.Reset()
+	}
+}
+
+// saturate16 四舍五入并饱和到int16范围
+func saturate16(y float64) int16 {
+	return int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, math.Round(y))))
+}
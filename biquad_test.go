@@ -0,0 +1,102 @@
+package webrtcvad
+
+import (
+	"math"
+	"testing"
+)
+
+// TestBiquadDesigns 测试各RBJ设计的频率响应
+func TestBiquadDesigns(t *testing.T) {
+	lp, _ := NewLowPassBiquad(16000, 1000, math.Sqrt2/2)
+	bp, _ := NewBandPassBiquad(16000, 1000, 2)
+	notch, _ := NewNotchBiquad(8000, 50, 10)
+	lowShelf, _ := NewLowShelfBiquad(16000, 300, math.Sqrt2/2, 6)
+	highShelf, _ := NewHighShelfBiquad(16000, 3000, math.Sqrt2/2, -6)
+
+	cases := []struct {
+		name   string
+		filter *Biquad
+		rate   int
+		freq   float64
+		lo, hi float64 // 增益范围（dB）
+	}{
+		{"低通通带", lp, 16000, 200, -0.2, 0.2},
+		{"低通截止", lp, 16000, 1000, -3.3, -2.7},
+		{"低通阻带", lp, 16000, 4000, -100, -20},
+		{"带通中心", bp, 16000, 1000, -0.2, 0.2},
+		{"带通低频", bp, 16000, 200, -100, -10},
+		{"带通高频", bp, 16000, 4000, -100, -10},
+		{"陷波", notch, 8000, 50, -100, -30},
+		{"陷波以外", notch, 8000, 300, -0.5, 0.1},
+		{"低架低频", lowShelf, 16000, 50, 5.5, 6.1},
+		{"低架高频", lowShelf, 16000, 4000, -0.2, 0.2},
+		{"高架高频", highShelf, 16000, 7000, -6.1, -5.5},
+		{"高架低频", highShelf, 16000, 200, -0.2, 0.2},
+	}
+	for _, c := range cases {
+		c.filter.Reset()
+		if g := toneGain(c.filter, c.rate, c.freq); g < c.lo || g > c.hi {
+			t.Errorf("%s（%.0fHz）增益应在[%.1f, %.1f]dB, 得到%.2f", c.name, c.freq, c.lo, c.hi, g)
+		}
+	}
+
+	if _, err := NewNotchBiquad(8000, 4000, 10); err != ErrInvalidCutoff {
+		t.Errorf("应返回ErrInvalidCutoff, 得到%v", err)
+	}
+	if _, err := NewLowShelfBiquad(0, 100, 1, 6); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+}
+
+// TestBiquadCascade 测试级联的响应为各级之积、分块滤波一致，以及作为StreamVAD的前处理
+func TestBiquadCascade(t *testing.T) {
+	newCascade := func() *BiquadCascade {
+		notch, _ := NewNotchBiquad(16000, 50, 10)
+		lp1, _ := NewLowPassBiquad(16000, 3400, 0.54)
+		lp2, _ := NewLowPassBiquad(16000, 3400, 1.31)
+		return NewBiquadCascade(notch, lp1, lp2)
+	}
+	c := newCascade()
+	if g := toneGain(c, 16000, 50); g > -30 {
+		t.Errorf("级联应去除50Hz, 增益%.2fdB", g)
+	}
+	c.Reset()
+	if g := toneGain(c, 16000, 1000); math.Abs(g) > 0.3 {
+		t.Errorf("级联在1000Hz应近似无增益, 得到%.2fdB", g)
+	}
+	c.Reset()
+	// 四阶Butterworth低通在两倍截止频率处约衰减24dB
+	if g := toneGain(c, 16000, 6800); g > -22 {
+		t.Errorf("四阶低通在6800Hz应衰减约24dB, 得到%.2fdB", g)
+	}
+
+	in := make([]int16, 1600)
+	for i := range in {
+		in[i] = int16(8000 * math.Sin(float64(i)*0.3))
+	}
+	want := make([]int16, len(in))
+	newCascade().Filter(in, want)
+	chunked := newCascade()
+	for off := 0; off < len(in); off += 160 {
+		chunked.Filter(in[off:off+160], in[off:off+160])
+	}
+	for i := range want {
+		if in[i] != want[i] {
+			t.Fatalf("第%d个样本: 分块滤波%d, 整体滤波%d", i, in[i], want[i])
+		}
+	}
+
+	svad, err := NewStreamVADWithOptions(WithHighPass(), WithFilter(newCascade()), WithDenoise())
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if len(svad.preprocessors) != 3 {
+		t.Fatalf("应有3个前处理模块, 得到%d个", len(svad.preprocessors))
+	}
+	if f, ok := svad.preprocessors[1].(filterStage); !ok || f.AudioFilter == nil {
+		t.Error("自定义滤波应在高通滤波之后、降噪之前执行")
+	}
+	if _, err := NewStreamVADWithOptions(WithFilter(nil)); err != ErrInvalidFilter {
+		t.Errorf("应返回ErrInvalidFilter, 得到%v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package webrtcvad
+
+import "time"
+
+// chunker.go 提供面向ASR的音频分块：按最大时长与字节数切分连续音频，
+// 切分点落在块末尾窗口内最长的静音处，适合向Whisper或云端识别服务逐块提交
+
+// DefaultChunkDuration ChunkerConfig.MaxDuration与MaxBytes都为0时的最大块时长
+const DefaultChunkDuration = 15 * time.Second
+
+// ChunkerConfig 分块配置
+type ChunkerConfig struct {
+	// MaxDuration 块的最大时长，0表示只受MaxBytes限制（两者都为0时为DefaultChunkDuration）
+	MaxDuration time.Duration
+
+	// MaxBytes 块的最大PCM字节数（如云端接口的请求大小限制），0表示只受MaxDuration限制
+	MaxBytes int
+
+	// TailWindow 在块末尾的该时长内寻找切分点，默认为最大块时长的1/5。
+	// 窗口越大，越容易找到较长的停顿，但块可能越短
+	TailWindow time.Duration
+}
+
+// Chunk 一个音频块
+type Chunk struct {
+	Start  time.Duration // 开始时间
+	End    time.Duration // 结束时间
+	Speech time.Duration // 块内判为语音的时长，为0时可不提交识别
+
+	// Forced 末尾窗口内没有静音，在最大长度处强制切分（可能切断一个词）
+	Forced bool
+
+	// SampleRate 采样率（Hz），即Audio的采样率
+	SampleRate int
+
+	// Audio 块的PCM数据（16位，小端序），相邻块首尾相接、不重叠
+	Audio []byte
+}
+
+// Duration 获取块时长
+func (c Chunk) Duration() time.Duration {
+	return c.End - c.Start
+}
+
+// Chunker ASR分块器
+//
+// 写入的音频全部进入某个块，块与块首尾相接。当前块超过最大长度时，在其末尾
+// TailWindow内寻找最长的连续静音帧，在其中点切分，两侧各保留一半静音作为填充；
+// 窗口内没有静音时在最大长度处强制切分。逐帧判决由内部StreamVAD给出（含WithSmoother
+// 等判决平滑，不含最短时长等片段平滑）。
+// Chunker本身不是并发安全的，应由单个goroutine写入
+type Chunker struct {
+	svad *StreamVAD
+	cfg  ChunkerConfig
+
+	frameSize   int
+	frameDur    time.Duration
+	limitFrames int // 块的最大帧数
+	window      int // 末尾窗口的帧数
+
+	start     time.Duration // 当前块的开始时间
+	audio     []byte        // 当前块已写入的数据（可能含不足一帧的残余）
+	decisions []bool        // 当前块各帧的判决
+}
+
+// NewChunker 创建分块器
+//
+// 参数:
+//   - cfg: 分块配置
+//   - opts: 内部StreamVAD的配置选项（模式、采样率、帧长度等）
+//
+// 返回:
+//   - *Chunker: 分块器实例
+//   - error: 时长或字节数为负时返回ErrInvalidDuration / ErrBufferTooSmall，
+//     最大长度不足一帧时返回ErrBufferTooSmall
+func NewChunker(cfg ChunkerConfig, opts ...StreamVADOption) (*Chunker, error) {
+	if cfg.MaxDuration < 0 || cfg.TailWindow < 0 {
+		return nil, ErrInvalidDuration
+	}
+	if cfg.MaxBytes < 0 {
+		return nil, ErrBufferTooSmall
+	}
+	if cfg.MaxDuration == 0 && cfg.MaxBytes == 0 {
+		cfg.MaxDuration = DefaultChunkDuration
+	}
+
+	// 块的音频取自原始输入，丢弃数据的溢出策略会使两者错位
+	opts = append(opts, WithOverflowPolicy(OverflowBlock))
+	svad, err := NewStreamVADWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Chunker{
+		svad:      svad,
+		cfg:       cfg,
+		frameSize: svad.frameSize,
+		frameDur:  time.Duration(svad.frameMs) * time.Millisecond,
+	}
+	c.limitFrames = -1
+	if cfg.MaxDuration > 0 {
+		c.limitFrames = int(cfg.MaxDuration / c.frameDur)
+	}
+	if cfg.MaxBytes > 0 && (c.limitFrames < 0 || cfg.MaxBytes/c.frameSize < c.limitFrames) {
+		c.limitFrames = cfg.MaxBytes / c.frameSize
+	}
+	if c.limitFrames < 1 {
+		return nil, ErrBufferTooSmall
+	}
+
+	window := cfg.TailWindow
+	if window == 0 {
+		window = time.Duration(c.limitFrames) * c.frameDur / 5
+	}
+	c.window = min(max(int(window/c.frameDur), 1), c.limitFrames)
+
+	svad.onFrame = func(_ time.Duration, isSpeech bool) {
+		c.decisions = append(c.decisions, isSpeech)
+	}
+	return c, nil
+}
+
+// Process 写入音频数据，返回其间完成的块
+//
+// 参数:
+//   - data: 音频数据（16位PCM，小端序）
+//
+// 返回:
+//   - []Chunk: 完成的块
+//   - error: 错误信息
+func (c *Chunker) Process(data []byte) ([]Chunk, error) {
+	c.audio = append(c.audio, data...)
+	if _, err := c.svad.Process(data); err != nil {
+		return nil, err
+	}
+
+	// 只在确知后面还有数据时切分，恰好达到最大长度的块留待之后的写入或Flush
+	var chunks []Chunk
+	for len(c.decisions) > c.limitFrames {
+		chunks = append(chunks, c.cut())
+	}
+	return chunks, nil
+}
+
+// Flush 结束音频流，返回剩余的块
+//
+// 流可以继续写入，之后的块接着已写入的时间继续
+func (c *Chunker) Flush() ([]Chunk, error) {
+	if err := c.svad.Flush(); err != nil {
+		return nil, err
+	}
+
+	var chunks []Chunk
+	for len(c.audio) > c.limitFrames*c.frameSize {
+		chunks = append(chunks, c.cut())
+	}
+	if len(c.audio) > 0 {
+		chunks = append(chunks, c.emit(len(c.decisions), len(c.audio), false))
+	}
+	return chunks, nil
+}
+
+// Reset 清空当前块并重置内部StreamVAD，时间从0重新开始
+func (c *Chunker) Reset() error {
+	c.start = 0
+	c.audio = c.audio[:0]
+	c.decisions = c.decisions[:0]
+	return c.svad.Reset()
+}
+
+// StreamVAD 获取内部的StreamVAD（可用于查询片段和统计信息）
+func (c *Chunker) StreamVAD() *StreamVAD {
+	return c.svad
+}
+
+// cut 在最大长度之前的末尾窗口内最长的静音中点切分，返回切下的块
+func (c *Chunker) cut() Chunk {
+	end := c.limitFrames
+	bestStart, bestLen := 0, 0
+	run := 0
+	for i := end - c.window; i < end; i++ {
+		if c.decisions[i] {
+			run = 0
+			continue
+		}
+		run++
+		if run >= bestLen { // 等长时取靠后的，块尽量长
+			bestStart, bestLen = i-run+1, run
+		}
+	}
+	if bestLen == 0 {
+		return c.emit(end, end*c.frameSize, true)
+	}
+	at := bestStart + (bestLen+1)/2
+	return c.emit(at, at*c.frameSize, false)
+}
+
+// emit 以前frames帧（nbytes字节）组成块，并从当前块中移除
+func (c *Chunker) emit(frames, nbytes int, forced bool) Chunk {
+	chunk := Chunk{
+		Start:      c.start,
+		End:        c.start + c.svad.bytesToDuration(int64(nbytes)),
+		Forced:     forced,
+		SampleRate: c.svad.sampleRate,
+		Audio:      append([]byte(nil), c.audio[:nbytes]...),
+	}
+	for _, speech := range c.decisions[:frames] {
+		if speech {
+			chunk.Speech += c.frameDur
+		}
+	}
+
+	c.start = chunk.End
+	c.audio = c.audio[:copy(c.audio, c.audio[nbytes:])]
+	c.decisions = c.decisions[:copy(c.decisions, c.decisions[frames:])]
+	return chunk
+}
@@ -0,0 +1,121 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// speechWithPauses 生成16kHz的低电平噪声，在[0, total)中除pauses以外的部分叠加140Hz的谐波
+func speechWithPauses(total time.Duration, pauses ...[2]time.Duration) []byte {
+	rng := rand.New(rand.NewSource(5))
+	pcm := make([]int16, int(total.Seconds()*16000))
+	for i := range pcm {
+		v := rng.NormFloat64() * 30
+		at := time.Duration(i) * time.Second / 16000
+		speech := true
+		for _, p := range pauses {
+			speech = speech && (at < p[0] || at >= p[1])
+		}
+		if speech {
+			for h := 1; h <= 8; h++ {
+				v += 3000 / float64(h) * math.Sin(2*math.Pi*140*float64(h)*float64(i)/16000)
+			}
+		}
+		pcm[i] = int16(v)
+	}
+	return toPCM(pcm)
+}
+
+// TestChunker 测试块在末尾窗口内最长的停顿处切分，且首尾相接
+func TestChunker(t *testing.T) {
+	// 停顿：0.6-0.9s（窗口外）、1.1-1.35s与1.6-1.9s（窗口内，后者更长）、3.2-3.4s
+	pcm := speechWithPauses(5*time.Second,
+		[2]time.Duration{600 * time.Millisecond, 900 * time.Millisecond},
+		[2]time.Duration{1100 * time.Millisecond, 1350 * time.Millisecond},
+		[2]time.Duration{1600 * time.Millisecond, 1900 * time.Millisecond},
+		[2]time.Duration{3200 * time.Millisecond, 3400 * time.Millisecond},
+	)
+	c, err := NewChunker(ChunkerConfig{MaxDuration: 2 * time.Second, TailWindow: time.Second}, WithStreamMode(2))
+	if err != nil {
+		t.Fatalf("创建Chunker失败: %v", err)
+	}
+
+	var chunks []Chunk
+	for i := 0; i < len(pcm); i += 1000 { // 不按帧对齐地写入
+		got, err := c.Process(pcm[i:min(i+1000, len(pcm))])
+		if err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+		chunks = append(chunks, got...)
+	}
+	rest, err := c.Flush()
+	if err != nil {
+		t.Fatalf("Flush失败: %v", err)
+	}
+	chunks = append(chunks, rest...)
+
+	var joined []byte
+	var at time.Duration
+	for i, ch := range chunks {
+		if ch.Start != at || ch.Duration() > 2*time.Second || ch.SampleRate != 16000 {
+			t.Errorf("块%d: %v-%v 应从%v开始且不超过2秒", i, ch.Start, ch.End, at)
+		}
+		if ch.Forced || ch.Speech == 0 {
+			t.Errorf("块%d不应强制切分且应含语音: %+v", i, ch.Forced)
+		}
+		at = ch.End
+		joined = append(joined, ch.Audio...)
+	}
+	if !bytes.Equal(joined, pcm) || at != 5*time.Second {
+		t.Fatalf("块应首尾相接覆盖全部音频: %d/%d字节, 结束于%v", len(joined), len(pcm), at)
+	}
+
+	// 第1块在1.6-1.9s的停顿中切分，第2块在3.2-3.4s的停顿中切分
+	if len(chunks) != 3 {
+		t.Fatalf("应得到3个块, 得到%d", len(chunks))
+	}
+	if end := chunks[0].End; end <= 1600*time.Millisecond || end >= 1900*time.Millisecond {
+		t.Errorf("第1块应在最长的停顿中结束, 得到%v", end)
+	}
+	if end := chunks[1].End; end <= 3200*time.Millisecond || end >= 3400*time.Millisecond {
+		t.Errorf("第2块应在停顿中结束, 得到%v", end)
+	}
+}
+
+// TestChunkerForced 测试没有停顿时按最大字节数强制切分
+func TestChunkerForced(t *testing.T) {
+	pcm := speechWithPauses(3 * time.Second)
+	c, err := NewChunker(ChunkerConfig{MaxBytes: 32000}) // 1秒
+	if err != nil {
+		t.Fatalf("创建Chunker失败: %v", err)
+	}
+	chunks, _ := c.Process(pcm)
+	rest, _ := c.Flush()
+	chunks = append(chunks, rest...)
+
+	if len(chunks) != 3 {
+		t.Fatalf("应得到3个块, 得到%d", len(chunks))
+	}
+	for i, ch := range chunks[:2] {
+		if !ch.Forced || len(ch.Audio) != 32000 {
+			t.Errorf("块%d应在32000字节处强制切分, 得到%d字节 forced=%v", i, len(ch.Audio), ch.Forced)
+		}
+	}
+
+	// 流可继续写入，Reset后时间从0开始
+	c.Reset()
+	chunks, _ = c.Flush()
+	if len(chunks) != 0 {
+		t.Errorf("Reset后没有数据时不应产生块, 得到%d", len(chunks))
+	}
+
+	if _, err := NewChunker(ChunkerConfig{MaxBytes: 100}); err != ErrBufferTooSmall {
+		t.Errorf("不足一帧的上限应返回ErrBufferTooSmall, 得到%v", err)
+	}
+	if _, err := NewChunker(ChunkerConfig{TailWindow: -time.Second}); err != ErrInvalidDuration {
+		t.Errorf("负的窗口应返回ErrInvalidDuration, 得到%v", err)
+	}
+}
@@ -0,0 +1,157 @@
+// vad 检测音频中的语音片段并以机器可读的格式输出
+//
+// 用法:
+//
+//	vad [flags] [file]
+//
+// 未指定文件或文件为"-"时从标准输入读取，便于在shell管道中使用：
+//
+//	ffmpeg -i talk.mp3 -f s16le -ac 1 -ar 16000 - | vad -format raw -output labels
+//
+// 退出码与grep一致：0表示检测到语音，1表示没有语音，2表示参数或处理错误。
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// 退出码
+const (
+	exitSpeech   = 0 // 检测到语音
+	exitNoSpeech = 1 // 没有语音
+	exitError    = 2 // 参数或处理错误
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run 解析参数并处理音频，返回退出码
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("vad", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	rate := fs.Int("rate", 16000, "原始PCM的采样率（8000, 16000, 24000, 32000, 48000）；WAV输入使用头部中的采样率")
+	frameMs := fs.Int("frame-ms", 30, "帧长度（10, 20, 30毫秒）")
+	mode := fs.Int("mode", 2, "激进度模式（0-3，越大越激进）")
+	format := fs.String("format", "auto", "输入格式：wav、raw（16位单声道小端序PCM）或auto（按RIFF头部识别）")
+	output := fs.String("output", "json", "输出格式：json、labels（Audacity标签）、vtt或srt（字幕）")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "用法: vad [flags] [file]\n\n")
+		fmt.Fprintf(stderr, "检测音频中的语音片段。未指定文件或文件为\"-\"时读取标准输入。\n")
+		fmt.Fprintf(stderr, "退出码: 0=检测到语音, 1=没有语音, 2=错误\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSpeech
+		}
+		return exitError
+	}
+
+	usageError := func(format string, a ...any) int {
+		fmt.Fprintf(stderr, "vad: "+format+"\n", a...)
+		fs.Usage()
+		return exitError
+	}
+	if fs.NArg() > 1 {
+		return usageError("最多指定一个输入文件")
+	}
+	if *format != "auto" && *format != "wav" && *format != "raw" {
+		return usageError("未知的输入格式 %q", *format)
+	}
+	switch *output {
+	case "json", "labels", "vtt", "srt":
+	default:
+		return usageError("未知的输出格式 %q", *output)
+	}
+
+	// 打开输入
+	var in io.Reader = stdin
+	if name := fs.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "vad: %v\n", err)
+			return exitError
+		}
+		defer f.Close()
+		in = f
+	}
+
+	src, sampleRate, err := openInput(in, *format, *rate)
+	if err != nil {
+		fmt.Fprintf(stderr, "vad: %v\n", err)
+		return exitError
+	}
+
+	svad, err := webrtcvad.NewStreamVADWithOptions(
+		webrtcvad.WithStreamMode(*mode),
+		webrtcvad.WithSampleRate(sampleRate),
+		webrtcvad.WithFrameDuration(*frameMs),
+	)
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	var segments webrtcvad.Segments
+	speech := false
+	err = svad.ProcessReader(src, func(seg webrtcvad.VoiceSegment) error {
+		speech = speech || seg.IsSpeech
+		segments = append(segments, seg)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "vad: %v\n", err)
+		return exitError
+	}
+
+	if err := writeSegments(stdout, segments, *output); err != nil {
+		fmt.Fprintf(stderr, "vad: %v\n", err)
+		return exitError
+	}
+	if !speech {
+		return exitNoSpeech
+	}
+	return exitSpeech
+}
+
+// openInput 按格式返回PCM数据来源及其采样率
+func openInput(in io.Reader, format string, rate int) (io.Reader, int, error) {
+	br := bufio.NewReader(in)
+	if format == "auto" {
+		format = "raw"
+		if magic, _ := br.Peek(12); len(magic) == 12 && string(magic[0:4]) == "RIFF" && string(magic[8:12]) == "WAVE" {
+			format = "wav"
+		}
+	}
+	if format == "raw" {
+		return br, rate, nil
+	}
+
+	wr, err := wave.NewReader(br)
+	if err != nil {
+		return nil, 0, err
+	}
+	return wr, wr.SampleRate, nil
+}
+
+// writeSegments 以指定格式输出片段
+func writeSegments(w io.Writer, segments webrtcvad.Segments, output string) error {
+	switch output {
+	case "labels":
+		return segments.WriteAudacityLabels(w)
+	case "vtt":
+		return segments.WriteVTT(w)
+	case "srt":
+		return segments.WriteSRT(w)
+	default:
+		return segments.WriteJSON(w)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/godeps/webrtcvad-go/internal/testaudio"
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// TestRunJSON 测试从标准输入读取原始PCM并输出JSON
+func TestRunJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-mode", "1", "-format", "raw"}, bytes.NewReader(testaudio.Pattern("0110", 16000)), &stdout, &stderr)
+	if code != exitSpeech {
+		t.Fatalf("退出码应为%d, 得到%d: %s", exitSpeech, code, stderr.String())
+	}
+
+	var segments []struct {
+		Start  float64 `json:"start"`
+		End    float64 `json:"end"`
+		Speech bool    `json:"speech"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &segments); err != nil {
+		t.Fatalf("输出不是有效的JSON: %v\n%s", err, stdout.String())
+	}
+	speech := 0
+	for _, seg := range segments {
+		if seg.Speech {
+			speech++
+			if seg.Start > 0.5 || seg.End < 1.5 {
+				t.Errorf("语音片段应覆盖0.5s-1.5s, 得到%.2f-%.2f", seg.Start, seg.End)
+			}
+		}
+	}
+	if speech != 1 {
+		t.Errorf("应检测到1个语音片段, 得到%+v", segments)
+	}
+}
+
+// TestRunPartialFrame 测试以不足一帧的语音结尾的输入，最后一段话语不丢失
+func TestRunPartialFrame(t *testing.T) {
+	pcm := append(testaudio.Pattern("10", 16000), testaudio.Pattern("1", 16000)[:318]...)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-format", "raw", "-frame-ms", "10", "-output", "labels"}, bytes.NewReader(pcm), &stdout, &stderr)
+	if code != exitSpeech {
+		t.Fatalf("退出码应为%d, 得到%d: %s", exitSpeech, code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "1.000000\t") {
+		t.Errorf("应输出2个语音标签且最后一个从1s开始, 得到%q", stdout.String())
+	}
+}
+
+// TestRunLabelsWAV 测试自动识别WAV并输出Audacity标签
+func TestRunLabelsWAV(t *testing.T) {
+	var wav bytes.Buffer
+	if err := wave.Write(&wav, testaudio.Pattern("0110", 16000), 16000); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-mode", "1", "-rate", "8000", "-output", "labels", "-"}, &wav, &stdout, &stderr)
+	if code != exitSpeech {
+		t.Fatalf("退出码应为%d, 得到%d: %s", exitSpeech, code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 1 || !strings.HasSuffix(lines[0], "\tspeech") {
+		t.Errorf("应输出1个语音标签, 得到%q", stdout.String())
+	}
+}
+
+// TestRunExitCodes 测试无语音和参数错误的退出码
+func TestRunExitCodes(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-format", "raw"}, bytes.NewReader(make([]byte, 32000)), &stdout, &stderr); code != exitNoSpeech {
+		t.Errorf("静音输入的退出码应为%d, 得到%d", exitNoSpeech, code)
+	}
+
+	bad := [][]string{
+		{"-mode", "7"},
+		{"-rate", "44100"},
+		{"-frame-ms", "25"},
+		{"-format", "mp3"},
+		{"-output", "xml"},
+		{"-unknown"},
+		{"a.pcm", "b.pcm"},
+		{"/nonexistent/input.pcm"},
+	}
+	for _, args := range bad {
+		stderr.Reset()
+		if code := run(args, bytes.NewReader(nil), &stdout, &stderr); code != exitError {
+			t.Errorf("%v: 退出码应为%d, 得到%d", args, exitError, code)
+		}
+		if stderr.Len() == 0 {
+			t.Errorf("%v: 应输出错误信息", args)
+		}
+	}
+}
+
+// TestRunSubtitles 测试字幕输出
+func TestRunSubtitles(t *testing.T) {
+	for output, prefix := range map[string]string{"vtt": "WEBVTT\n\n00:00:0", "srt": "1\n00:00:0"} {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-mode", "1", "-format", "raw", "-output", output}, bytes.NewReader(testaudio.Pattern("0110", 16000)), &stdout, &stderr)
+		if code != exitSpeech {
+			t.Fatalf("%s: 退出码应为%d, 得到%d: %s", output, exitSpeech, code, stderr.String())
+		}
+		if !strings.HasPrefix(stdout.String(), prefix) || strings.Count(stdout.String(), "[speech]") != 1 {
+			t.Errorf("%s: 应输出1条字幕, 得到%q", output, stdout.String())
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/godeps/webrtcvad-go/internal/testaudio"
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// testPCM 生成0.5s静音 + 1s正弦波 + 1s静音 + 0.3s正弦波 + 1s静音 @16kHz
+func testPCM() []byte {
+	pcm := testaudio.Pattern("01100", 16000)
+	pcm = append(pcm, testaudio.Pattern("1", 16000)[:9600]...)
+	return append(pcm, testaudio.Pattern("00", 16000)...)
+}
+
+// TestRunCut 测试每段话语写为一个WAV文件
+func TestRunCut(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "talk.pcm")
+	if err := os.WriteFile(in, testPCM(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "clips")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-mode", "1", "-dir", out, "-name", "u%02d.wav", in}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("退出码应为%d, 得到%d: %s", exitOK, code, stderr.String())
+	}
+
+	want := []string{filepath.Join(out, "u01.wav"), filepath.Join(out, "u02.wav")}
+	if got := strings.Fields(stdout.String()); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("应写出%v, 得到%v", want, got)
+	}
+	for _, path := range want {
+		f, err := wave.Open(path)
+		if err != nil {
+			t.Fatalf("%s: 不是有效的WAV: %v", path, err)
+		}
+		if f.SampleRate != 16000 || f.DataSize() == 0 {
+			t.Errorf("%s: 采样率%d, 数据%d字节", path, f.SampleRate, f.DataSize())
+		}
+		f.Close()
+	}
+
+	// 最短时长过滤掉第二段话语（0.3s语音加填充不足1s）
+	stdout.Reset()
+	code = run([]string{"-mode", "1", "-dir", out, "-min-duration", "1s", in}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("退出码应为%d, 得到%d: %s", exitOK, code, stderr.String())
+	}
+	if got := strings.Fields(stdout.String()); len(got) != 1 || got[0] != filepath.Join(out, "out_0001.wav") {
+		t.Errorf("应只写出out_0001.wav, 得到%v", got)
+	}
+}
+
+// TestRunExitCodes 测试无话语和参数错误的退出码
+func TestRunExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	silence := filepath.Join(dir, "silence.pcm")
+	if err := os.WriteFile(silence, make([]byte, 32000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-dir", dir, silence}, &stdout, &stderr); code != exitNoSpeech {
+		t.Errorf("静音输入的退出码应为%d, 得到%d", exitNoSpeech, code)
+	}
+
+	bad := [][]string{
+		{},
+		{"-name", "clip.wav", silence},
+		{"-name", "clip_%s.wav", silence},
+		{"-padding", "-1s", silence},
+		{"-mode", "9", silence},
+		{"-frame-ms", "25", silence},
+		{filepath.Join(dir, "missing.pcm")},
+	}
+	for _, args := range bad {
+		stderr.Reset()
+		if code := run(append([]string{"-dir", dir}, args...), &stdout, &stderr); code != exitError {
+			t.Errorf("%v: 退出码应为%d, 得到%d", args, exitError, code)
+		}
+		if stderr.Len() == 0 {
+			t.Errorf("%v: 应输出错误信息", args)
+		}
+	}
+}
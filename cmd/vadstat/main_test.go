@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/godeps/webrtcvad-go/internal/testaudio"
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// TestRunDirectory 测试遍历目录并行统计
+func TestRunDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var wav bytes.Buffer
+	if err := wave.Write(&wav, testaudio.Pattern("0110000110", 16000), 16000); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"a.wav":         wav.Bytes(),
+		"sub/b.pcm":     make([]byte, 32000),
+		"sub/c.wav":     []byte("RIFF\x00\x00\x00\x00WAVEjunk"),
+		"sub/notes.txt": []byte("not audio"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-mode", "1", "-workers", "3", dir}, &stdout, &stderr)
+	if code != exitFailed {
+		t.Errorf("损坏的文件应使退出码为%d, 得到%d", exitFailed, code)
+	}
+
+	var results []fileStats
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		var r fileStats
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("输出不是有效的JSON行: %v\n%s", err, scanner.Text())
+		}
+		results = append(results, r)
+	}
+
+	want := []string{"a.wav", "sub/b.pcm", "sub/c.wav"}
+	if len(results) != len(want) {
+		t.Fatalf("应输出%d行, 得到%+v", len(want), results)
+	}
+	for i, r := range results {
+		if r.Path != filepath.Join(dir, want[i]) {
+			t.Errorf("第%d行应为%s, 得到%s", i, want[i], r.Path)
+		}
+	}
+
+	a := results[0]
+	if a.Error != "" || a.Segments != 2 || a.Duration != 5 || a.SampleRate != 16000 {
+		t.Errorf("a.wav: 应有2段语音、时长5s, 得到%+v", a)
+	}
+	if a.SpeechRatio < 0.35 || a.SpeechRatio > 0.5 || a.LongestSilence < 1.5 {
+		t.Errorf("a.wav: 语音占比或最长静音错误: %+v", a)
+	}
+	if a.MeanUtterance != a.MedianUtterance || a.MeanUtterance < 0.9 {
+		t.Errorf("a.wav: 两段等长话语的均值与中位数应相等: %+v", a)
+	}
+
+	if b := results[1]; b.Error != "" || b.Segments != 0 || b.SpeechRatio != 0 || b.LongestSilence != 1 {
+		t.Errorf("sub/b.pcm: 应为1s静音, 得到%+v", b)
+	}
+	if c := results[2]; c.Error == "" {
+		t.Errorf("sub/c.wav: 损坏的WAV应报告错误, 得到%+v", c)
+	}
+}
+
+// TestRunPartialFrame 测试长度不是整帧的文件，末尾不足一帧的语音计入统计
+func TestRunPartialFrame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tail.pcm")
+	pcm := append(testaudio.Pattern("10", 16000), testaudio.Pattern("1", 16000)[:318]...)
+	if err := os.WriteFile(path, pcm, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-rate", "16000", "-frame-ms", "10", "-mode", "1", path}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("退出码应为%d, 得到%d: %s", exitOK, code, stderr.String())
+	}
+	var r fileStats
+	if err := json.Unmarshal(stdout.Bytes(), &r); err != nil {
+		t.Fatalf("输出不是有效的JSON: %v\n%s", err, stdout.String())
+	}
+	if r.Segments != 2 || r.Duration <= 1 {
+		t.Errorf("应统计2段语音（含末尾不足一帧的语音）且时长超过1s, 得到%+v", r)
+	}
+}
+
+// TestRunUsage 测试参数错误
+func TestRunUsage(t *testing.T) {
+	bad := [][]string{
+		{},
+		{"-workers", "0", "."},
+		{"-mode", "5", "."},
+		{"-frame-ms", "15", "."},
+		{"/nonexistent/corpus"},
+	}
+	for _, args := range bad {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != exitUsage {
+			t.Errorf("%v: 退出码应为%d, 得到%d", args, exitUsage, code)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package webrtcvad
+
+import "time"
+
+// collector.go 提供带前后填充的话语收集器
+// 移植自py-webrtcvad示例中的环形缓冲收集器，适合按句切分音频
+
+// CollectorConfig 话语收集器配置
+type CollectorConfig struct {
+	SampleRate   int           // 采样率（8000, 16000, 24000, 32000, 48000）
+	FrameMs      int           // 帧长度（毫秒，10/20/30）
+	Padding      time.Duration // 填充窗口长度，默认300ms
+	TriggerRatio float64       // 窗口内语音帧占比超过该值时开始话语，取值(0, 1)，默认0.9
+	EndSilence   time.Duration // 连续静音达到该时长时结束话语，默认等于Padding
+}
+
+// Utterance 收集到的一段话语
+type Utterance struct {
+	Start time.Duration // 开始时间（含前置填充）
+	End   time.Duration // 结束时间（含尾部静音）
+	Audio []byte        // 话语的PCM数据（16位，小端序）
+}
+
+// Duration 获取话语时长
+func (u Utterance) Duration() time.Duration {
+	return u.End - u.Start
+}
+
+// Collector 带填充的话语收集器
+//
+// 未触发时，最近Padding时长的帧保存在环形缓冲中；窗口内语音帧占比超过
+// TriggerRatio时开始话语，并把环形缓冲中的帧作为前置填充一并收入。
+// 触发后连续静音达到EndSilence时结束话语，尾部静音保留为后置填充。
+type Collector struct {
+	vad       *VAD
+	cfg       CollectorConfig
+	frameSize int // 单帧字节数
+	frameDur  time.Duration
+
+	buffer     []byte // 未凑满一帧的输入
+	totalBytes int64  // 已处理的总字节数
+
+	// 环形缓冲（未触发时使用）
+	ring      []byte // 容量为 ringSize*frameSize
+	ringVoice []bool
+	ringHead  int // 最早一帧的位置
+	ringLen   int
+	numVoiced int
+
+	triggered    bool
+	start        time.Duration
+	audio        []byte
+	silentFrames int // 触发后的连续静音帧数
+}
+
+// NewCollector 创建话语收集器
+//
+// 参数:
+//   - vad: 用于逐帧判决的VAD实例
+//   - cfg: 收集器配置，Padding/TriggerRatio/EndSilence为零值时使用默认值
+//
+// 返回:
+//   - *Collector: 收集器实例
+//   - error: 错误信息
+func NewCollector(vad *VAD, cfg CollectorConfig) (*Collector, error) {
+	if vad == nil {
+		return nil, ErrNotInitialized
+	}
+	if !isValidSampleRate(cfg.SampleRate) {
+		return nil, ErrInvalidSampleRate
+	}
+	if cfg.FrameMs != 10 && cfg.FrameMs != 20 && cfg.FrameMs != 30 {
+		return nil, ErrInvalidFrameLength
+	}
+	if cfg.Padding < 0 || cfg.EndSilence < 0 {
+		return nil, ErrInvalidDuration
+	}
+	// 占比须严格超过TriggerRatio才触发，为1时永远不会触发；同时拒绝NaN
+	if cfg.TriggerRatio != 0 && !(cfg.TriggerRatio > 0 && cfg.TriggerRatio < 1) {
+		return nil, ErrInvalidRatio
+	}
+
+	if cfg.Padding == 0 {
+		cfg.Padding = 300 * time.Millisecond
+	}
+	if cfg.TriggerRatio == 0 {
+		cfg.TriggerRatio = 0.9
+	}
+	if cfg.EndSilence == 0 {
+		cfg.EndSilence = cfg.Padding
+	}
+
+	frameDur := time.Duration(cfg.FrameMs) * time.Millisecond
+	ringSize := int(cfg.Padding / frameDur)
+	if ringSize < 1 {
+		ringSize = 1
+	}
+	frameSize := cfg.SampleRate * cfg.FrameMs / 1000 * 2
+
+	return &Collector{
+		vad:       vad,
+		cfg:       cfg,
+		frameSize: frameSize,
+		frameDur:  frameDur,
+		buffer:    make([]byte, 0, frameSize*2),
+		ring:      make([]byte, ringSize*frameSize),
+		ringVoice: make([]bool, ringSize),
+	}, nil
+}
+
+// Write 写入任意长度的音频数据，返回其间结束的话语
+func (c *Collector) Write(data []byte) ([]Utterance, error) {
+	c.buffer = append(c.buffer, data...)
+
+	var done []Utterance
+	for len(c.buffer) >= c.frameSize {
+		frame := c.buffer[:c.frameSize]
+
+		isSpeech, err := c.vad.IsSpeech(frame, c.cfg.SampleRate)
+		if err != nil {
+			return done, err
+		}
+		if u := c.pushFrame(frame, isSpeech); u != nil {
+			done = append(done, *u)
+		}
+
+		c.buffer = c.buffer[c.frameSize:]
+	}
+
+	return done, nil
+}
+
+// pushFrame 将一帧及其判决送入状态机，话语结束时返回该话语
+func (c *Collector) pushFrame(frame []byte, isSpeech bool) *Utterance {
+	frameStart := c.bytesToDuration(c.totalBytes)
+	c.totalBytes += int64(len(frame))
+
+	if c.triggered {
+		c.audio = append(c.audio, frame...)
+		if isSpeech {
+			c.silentFrames = 0
+			return nil
+		}
+		c.silentFrames++
+		if time.Duration(c.silentFrames)*c.frameDur < c.cfg.EndSilence {
+			return nil
+		}
+		return c.finish()
+	}
+
+	c.ringPush(frame, isSpeech)
+	if float64(c.numVoiced) <= c.cfg.TriggerRatio*float64(len(c.ringVoice)) {
+		return nil
+	}
+
+	// 触发：环形缓冲中的帧作为前置填充
+	c.triggered = true
+	c.silentFrames = 0
+	c.start = frameStart - time.Duration(c.ringLen-1)*c.frameDur
+	c.audio = c.audio[:0]
+	for i := 0; i < c.ringLen; i++ {
+		c.audio = append(c.audio, c.ringFrame(i)...)
+	}
+	c.ringClear()
+	return nil
+}
+
+// Flush 结束当前未完成的话语并返回（没有进行中的话语时返回nil）
+//
+// 未凑满一帧的残余数据被丢弃
+func (c *Collector) Flush() *Utterance {
+	c.buffer = c.buffer[:0]
+	if !c.triggered {
+		return nil
+	}
+	return c.finish()
+}
+
+// Reset 重置收集器状态（不重置VAD实例）
+func (c *Collector) Reset() {
+	c.buffer = c.buffer[:0]
+	c.totalBytes = 0
+	c.triggered = false
+	c.audio = nil
+	c.silentFrames = 0
+	c.ringClear()
+}
+
+// finish 结束当前话语
+func (c *Collector) finish() *Utterance {
+	u := &Utterance{
+		Start: c.start,
+		End:   c.bytesToDuration(c.totalBytes),
+		Audio: c.audio,
+	}
+	c.triggered = false
+	c.audio = nil
+	c.silentFrames = 0
+	return u
+}
+
+// ringPush 向环形缓冲追加一帧，满时覆盖最早一帧
+func (c *Collector) ringPush(frame []byte, isSpeech bool) {
+	size := len(c.ringVoice)
+	if c.ringLen == size {
+		if c.ringVoice[c.ringHead] {
+			c.numVoiced--
+		}
+		c.ringHead = (c.ringHead + 1) % size
+		c.ringLen--
+	}
+
+	pos := (c.ringHead + c.ringLen) % size
+	copy(c.ring[pos*c.frameSize:(pos+1)*c.frameSize], frame)
+	c.ringVoice[pos] = isSpeech
+	if isSpeech {
+		c.numVoiced++
+	}
+	c.ringLen++
+}
+
+// ringFrame 获取环形缓冲中第i帧（0为最早）
+func (c *Collector) ringFrame(i int) []byte {
+	pos := (c.ringHead + i) % len(c.ringVoice)
+	return c.ring[pos*c.frameSize : (pos+1)*c.frameSize]
+}
+
+// ringClear 清空环形缓冲
+func (c *Collector) ringClear() {
+	c.ringHead = 0
+	c.ringLen = 0
+	c.numVoiced = 0
+}
+
+// bytesToDuration 将字节数转换为时长
+func (c *Collector) bytesToDuration(bytes int64) time.Duration {
+	samples := bytes / 2 // 16位 = 2字节
+	return time.Duration(samples) * time.Second / time.Duration(c.cfg.SampleRate)
+}
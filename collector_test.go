@@ -0,0 +1,122 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestCollectorPadding 测试前后填充与触发逻辑
+func TestCollectorPadding(t *testing.T) {
+	vad, err := New(1)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	c, err := NewCollector(vad, CollectorConfig{
+		SampleRate:   8000,
+		FrameMs:      10,
+		Padding:      30 * time.Millisecond,
+		TriggerRatio: 0.5,
+		EndSilence:   20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("创建Collector失败: %v", err)
+	}
+
+	// 每帧填充其序号，便于核对收集到的音频
+	pattern := "0011101000"
+	var done []Utterance
+	for i, ch := range pattern {
+		frame := bytes.Repeat([]byte{byte(i)}, c.frameSize)
+		if u := c.pushFrame(frame, ch == '1'); u != nil {
+			done = append(done, *u)
+		}
+	}
+
+	if len(done) != 1 {
+		t.Fatalf("应收集到1段话语, 得到%d", len(done))
+	}
+	u := done[0]
+	// 第3帧触发，前置填充为第1-3帧；第8帧时连续静音达到20ms
+	if u.Start != 10*time.Millisecond || u.End != 90*time.Millisecond {
+		t.Errorf("话语时间错误: 得到%v-%v, 期望10ms-90ms", u.Start, u.End)
+	}
+	if len(u.Audio) != 8*c.frameSize {
+		t.Fatalf("话语音频长度错误: 期望%d, 得到%d", 8*c.frameSize, len(u.Audio))
+	}
+	for i := 0; i < 8; i++ {
+		if got := u.Audio[i*c.frameSize]; got != byte(i+1) {
+			t.Errorf("第%d帧音频错误: 期望帧%d, 得到帧%d", i, i+1, got)
+		}
+	}
+
+	if c.Flush() != nil {
+		t.Error("没有进行中的话语时Flush应返回nil")
+	}
+}
+
+// TestCollectorFlush 测试流结束时的话语收尾
+func TestCollectorFlush(t *testing.T) {
+	vad, _ := New(1)
+	c, err := NewCollector(vad, CollectorConfig{SampleRate: 8000, FrameMs: 10})
+	if err != nil {
+		t.Fatalf("创建Collector失败: %v", err)
+	}
+
+	frame := make([]byte, c.frameSize)
+	for i := 0; i < 40; i++ {
+		if u := c.pushFrame(frame, true); u != nil {
+			t.Fatal("持续语音不应结束话语")
+		}
+	}
+	u := c.Flush()
+	if u == nil {
+		t.Fatal("Flush应返回进行中的话语")
+	}
+	if u.Start != 0 || u.Duration() != 400*time.Millisecond {
+		t.Errorf("话语时间错误: %v-%v", u.Start, u.End)
+	}
+}
+
+// TestCollectorSilence 测试静音输入不产生话语
+func TestCollectorSilence(t *testing.T) {
+	vad, _ := New(3)
+	c, err := NewCollector(vad, CollectorConfig{SampleRate: 16000, FrameMs: 20})
+	if err != nil {
+		t.Fatalf("创建Collector失败: %v", err)
+	}
+
+	done, err := c.Write(make([]byte, 16000*2))
+	if err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if len(done) != 0 || c.Flush() != nil {
+		t.Error("静音输入不应产生话语")
+	}
+}
+
+// TestCollectorInvalidConfig 测试无效配置
+func TestCollectorInvalidConfig(t *testing.T) {
+	vad, _ := New(1)
+	tests := []struct {
+		name string
+		cfg  CollectorConfig
+		want error
+	}{
+		{"采样率", CollectorConfig{SampleRate: 11025, FrameMs: 10}, ErrInvalidSampleRate},
+		{"帧长度", CollectorConfig{SampleRate: 8000, FrameMs: 15}, ErrInvalidFrameLength},
+		{"填充", CollectorConfig{SampleRate: 8000, FrameMs: 10, Padding: -1}, ErrInvalidDuration},
+		{"比例", CollectorConfig{SampleRate: 8000, FrameMs: 10, TriggerRatio: 1.5}, ErrInvalidRatio},
+		{"比例为1", CollectorConfig{SampleRate: 8000, FrameMs: 10, TriggerRatio: 1}, ErrInvalidRatio},
+		{"比例为NaN", CollectorConfig{SampleRate: 8000, FrameMs: 10, TriggerRatio: math.NaN()}, ErrInvalidRatio},
+	}
+	for _, tt := range tests {
+		if _, err := NewCollector(vad, tt.cfg); err != tt.want {
+			t.Errorf("%s: 期望%v, 得到%v", tt.name, tt.want, err)
+		}
+	}
+	if _, err := NewCollector(nil, CollectorConfig{SampleRate: 8000, FrameMs: 10}); err != ErrNotInitialized {
+		t.Errorf("nil VAD应返回ErrNotInitialized, 得到%v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// comfort_noise.go 提供非语音帧的细分：区分对端处于DTX时接收端生成的舒适噪声
+// （低电平、频谱平坦）与信道中断造成的数字静音
+
+// NonSpeechClass 帧的电平与频谱类别
+type NonSpeechClass int
+
+const (
+	// ClassDigitalSilence 数字静音：全零或只有最低位抖动，通常表示信道中断
+	ClassDigitalSilence NonSpeechClass = iota
+	// ClassComfortNoise 舒适噪声：低电平且频谱平坦，通常表示对端处于DTX
+	ClassComfortNoise
+	// ClassActive 其他音频：语音、音乐或有明显频谱结构的背景声
+	ClassActive
+)
+
+// String 返回类别名称
+func (c NonSpeechClass) String() string {
+	switch c {
+	case ClassDigitalSilence:
+		return "silence"
+	case ClassComfortNoise:
+		return "comfort_noise"
+	case ClassActive:
+		return "active"
+	default:
+		return fmt.Sprintf("NonSpeechClass(%d)", int(c))
+	}
+}
+
+// flatnessOrder 估计频谱平坦度的LPC阶数
+const flatnessOrder = 10
+
+// ComfortNoiseDetector 舒适噪声检测器
+//
+// 频谱平坦度以LPC预测误差与信号能量之比估计（白噪声接近1，语音通常低于0.1）。
+// 检测器无状态，可并发使用；字段在使用前按需调整
+type ComfortNoiseDetector struct {
+	// SilenceLevel RMS电平低于此值（dBFS）视为数字静音，默认-84（RMS约2）
+	SilenceLevel float64
+
+	// MaxLevel 舒适噪声的最大RMS电平（dBFS），默认-45
+	MaxLevel float64
+
+	// MinFlatness 舒适噪声的最小频谱平坦度（0-1），默认0.25
+	MinFlatness float64
+}
+
+// NewComfortNoiseDetector 创建使用默认门限的舒适噪声检测器
+func NewComfortNoiseDetector() *ComfortNoiseDetector {
+	return &ComfortNoiseDetector{
+		SilenceLevel: -84,
+		MaxLevel:     -45,
+		MinFlatness:  0.25,
+	}
+}
+
+// Classify 判断一帧的类别
+//
+// 参数:
+//   - frame: 16位小端序PCM，长度须对应10ms、20ms或30ms
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
+//
+// 返回:
+//   - NonSpeechClass: 帧的类别
+//   - error: 参数无效时返回错误
+//
+// 本方法只依据电平与频谱平坦度分类，不做语音判决；低电平的语音尾音
+// 频谱不平坦，归为ClassActive。通常只对VAD判为非语音的帧调用
+func (d *ComfortNoiseDetector) Classify(frame []byte, sampleRate int) (NonSpeechClass, error) {
+	if !isValidSampleRate(sampleRate) {
+		return 0, ErrInvalidSampleRate
+	}
+	n := len(frame) / 2
+	if len(frame)%2 != 0 || !ValidRateAndFrameLength(sampleRate, n) {
+		return 0, ErrInvalidFrameLength
+	}
+
+	samples := make([]int16, n)
+	var sum float64
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(frame[i*2:]))
+		sum += float64(samples[i]) * float64(samples[i])
+	}
+
+	level := 10 * math.Log10(sum/float64(n)/(32768*32768)+1e-20)
+	if level < d.SilenceLevel {
+		return ClassDigitalSilence, nil
+	}
+	if level > d.MaxLevel {
+		return ClassActive, nil
+	}
+
+	// 预测增益为sqrt(预测误差/能量)
+	_, gain := LPCAnalysis(samples, n, flatnessOrder)
+	if gain*gain >= d.MinFlatness {
+		return ClassComfortNoise, nil
+	}
+	return ClassActive, nil
+}
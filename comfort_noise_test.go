@@ -0,0 +1,56 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestComfortNoiseDetector 测试数字静音、舒适噪声与其他音频的区分
+func TestComfortNoiseDetector(t *testing.T) {
+	d := NewComfortNoiseDetector()
+	rng := rand.New(rand.NewSource(1))
+
+	for _, rate := range []int{8000, 16000, 48000} {
+		n := rate / 50
+		frame := func(gen func(i int) float64) []byte {
+			buf := make([]byte, 0, n*2)
+			for i := 0; i < n; i++ {
+				buf = binary.LittleEndian.AppendUint16(buf, uint16(int16(math.Round(gen(i)))))
+			}
+			return buf
+		}
+
+		cases := []struct {
+			name string
+			buf  []byte
+			want NonSpeechClass
+		}{
+			{"全零", frame(func(int) float64 { return 0 }), ClassDigitalSilence},
+			{"最低位抖动", frame(func(int) float64 { return float64(rng.Intn(3) - 1) }), ClassDigitalSilence},
+			{"低电平白噪声", frame(func(int) float64 { return rng.NormFloat64() * 50 }), ClassComfortNoise},
+			{"低电平正弦波", frame(func(i int) float64 { return 100 * math.Sin(2*math.Pi*440*float64(i)/float64(rate)) }), ClassActive},
+			{"高电平白噪声", frame(func(int) float64 { return rng.NormFloat64() * 3000 }), ClassActive},
+		}
+		for _, c := range cases {
+			got, err := d.Classify(c.buf, rate)
+			if err != nil {
+				t.Fatalf("%dHz %s: 分类失败: %v", rate, c.name, err)
+			}
+			if got != c.want {
+				t.Errorf("%dHz %s: 期望%v, 得到%v", rate, c.name, c.want, got)
+			}
+		}
+	}
+
+	if _, err := d.Classify(make([]byte, 320), 44100); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := d.Classify(make([]byte, 300), 16000); err != ErrInvalidFrameLength {
+		t.Errorf("应返回ErrInvalidFrameLength, 得到%v", err)
+	}
+	if s := ClassComfortNoise.String(); s != "comfort_noise" {
+		t.Errorf("String()应为comfort_noise, 得到%s", s)
+	}
+}
@@ -23,8 +23,18 @@ import "math"
 func CrossCorrelationTo(seq1, seq2 []int16, dimSeq int,
 	dimCrossCorrelation int, rightShifts int, stepSeq2 int, result []int32) {
 
+	// 序列完整在范围内时使用SIMD点积内核，结果与标量累加逐位一致
+	useSIMD := simdEnabled && dimSeq >= simdBlock && dimSeq <= len(seq1) &&
+		rightShifts >= 0 && rightShifts < 32
+
 	seq2Ptr := 0
 	for i := 0; i < dimCrossCorrelation; i++ {
+		if useSIMD && seq2Ptr >= 0 && seq2Ptr+dimSeq <= len(seq2) {
+			result[i] = dotProductW16(seq1[:dimSeq], seq2[seq2Ptr:seq2Ptr+dimSeq], uint(rightShifts))
+			seq2Ptr += stepSeq2
+			continue
+		}
+
 		var corr int32 = 0
 		
 		// 4路循环展开优化
@@ -0,0 +1,76 @@
+package webrtcvad
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// debug.go 提供逐帧输出内部状态的调试跟踪，无需重新编译即可在现场定位定点运算的回归
+
+// DebugFrame 调试跟踪中一帧的内部状态，WithDebugWriter按JSON Lines逐帧输出
+type DebugFrame struct {
+	// Frame 帧序号（从0开始，不含Prime的预热帧，与Stats().Frames一致）
+	Frame int64 `json:"frame"`
+	// SampleRate 采样率（Hz）
+	SampleRate int `json:"rate"`
+	// Samples 帧长度（样本数）
+	Samples int `json:"samples"`
+	// Speech 最终判决（含迟滞平滑）
+	Speech bool `json:"speech"`
+	// Energetic 帧能量是否超过最小阈值；为false时未做GMM判决，以下判决字段均为零值
+	Energetic bool `json:"energetic"`
+	// TotalPower 总能量指示
+	TotalPower int16 `json:"total_power"`
+	// Features 各频带对数能量（10*log10(能量)的Q4定点数）
+	Features [NumChannels]int16 `json:"features"`
+	// LLR 频谱加权的对数似然比之和
+	LLR int32 `json:"llr"`
+	// BandLLR 各频带的对数似然比（log2）
+	BandLLR [NumChannels]int16 `json:"band_llr"`
+	// BandActive 各频带的局部判决
+	BandActive [NumChannels]bool `json:"band_active"`
+	// NoiseMeans 模型更新后GMM噪声分量的均值（Q7），布局同Model
+	NoiseMeans [kTableSize]int16 `json:"noise_means"`
+	// SpeechMeans 模型更新后GMM语音分量的均值（Q7），布局同Model
+	SpeechMeans [kTableSize]int16 `json:"speech_means"`
+	// NoiseFloor 各频带的噪声底估计（单位同Features）
+	NoiseFloor [NumChannels]int16 `json:"noise_floor"`
+}
+
+// WithDebugWriter 每检测一帧向w写入一行JSON（见DebugFrame），包含特征、对数似然比、
+// 各频带判决与GMM均值
+//
+// 用于在现场收集定点运算回归的诊断信息，每帧一次序列化，不宜在生产环境长期开启。
+// 写入错误被忽略，不影响检测；w不是并发安全时不要在多个VAD之间共享
+func WithDebugWriter(w io.Writer) Option {
+	return func(v *VAD) error {
+		if w == nil {
+			v.debug = nil
+			return nil
+		}
+		v.debug = json.NewEncoder(w)
+		return nil
+	}
+}
+
+// writeDebug 输出最近一帧的内部状态
+func (v *VAD) writeDebug(sampleRate, samples int, speech bool) {
+	inst := v.inst
+	frame := DebugFrame{
+		Frame:       v.debugFrames,
+		SampleRate:  sampleRate,
+		Samples:     samples,
+		Speech:      speech,
+		Energetic:   inst.totalPower > kMinEnergy,
+		TotalPower:  inst.totalPower,
+		Features:    inst.features,
+		LLR:         inst.sumLLR,
+		BandLLR:     inst.bandLLR,
+		BandActive:  inst.bandActive,
+		NoiseMeans:  inst.noiseMeans,
+		SpeechMeans: inst.speechMeans,
+		NoiseFloor:  inst.meanValue,
+	}
+	v.debugFrames++
+	_ = v.debug.Encode(&frame)
+}
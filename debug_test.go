@@ -0,0 +1,56 @@
+package webrtcvad
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestWithDebugWriter 测试每帧输出一行JSON，内容与ProcessDetailed一致
+func TestWithDebugWriter(t *testing.T) {
+	var buf bytes.Buffer
+	traced, err := NewWithOptions(WithMode(2), WithDebugWriter(&buf))
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	plain, _ := New(2)
+
+	pcm := harmonicNoise(16000, time.Second, 500*time.Millisecond, time.Second)
+	traced.Prime(pcm[:1600], 16000) // 5帧预热，不输出跟踪
+	plain.Prime(pcm[:1600], 16000)
+	var want []FrameResult
+	for i := 0; i+320 <= len(pcm); i += 320 {
+		traced.IsSpeech(pcm[i:i+320], 16000)
+		r, _ := plain.ProcessDetailed(pcm[i:i+320], 16000)
+		want = append(want, r)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+	var n int
+	for ; scanner.Scan(); n++ {
+		var f DebugFrame
+		if err := json.Unmarshal(scanner.Bytes(), &f); err != nil {
+			t.Fatalf("第%d行不是有效的JSON: %v", n, err)
+		}
+		if f.Frame != int64(n) || f.SampleRate != 16000 || f.Samples != 160 {
+			t.Fatalf("第%d行的帧信息错误: %+v", n, f)
+		}
+		w := want[n]
+		if f.Speech != w.IsSpeech || f.LLR != w.LogLikelihoodRatio || f.BandLLR != w.BandLLR || f.BandActive != w.BandActive {
+			t.Fatalf("第%d帧的跟踪与ProcessDetailed不一致: %+v / %+v", n, f, w)
+		}
+	}
+	if n != len(want) {
+		t.Errorf("应输出%d行（不含预热帧）, 得到%d", len(want), n)
+	}
+
+	feats, _ := traced.Features()
+	var last DebugFrame
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	json.Unmarshal(lines[len(lines)-1], &last)
+	if last.Features != feats || last.NoiseFloor != traced.NoiseFloor() || last.NoiseMeans != traced.Model().NoiseMeans {
+		t.Error("最后一行的特征、噪声底与噪声均值应与VAD当前状态一致")
+	}
+}
@@ -0,0 +1,87 @@
+package webrtcvad
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// delay.go 提供基于归一化互相关的两路信号时延估计，用于麦克风与参考信号
+// （如回声消除的远端信号、两路录音）的对齐
+
+// EstimateDelay 估计信号b相对于信号a的时延
+//
+// 在±maxLagMs范围内逐个延迟计算互相关（CrossCorrelationWithLag），按重叠部分的能量
+// 归一化后取峰值，以抛物线插值得到亚样本精度的时延。延迟范围不超过两路信号公共长度
+// 的一半，保证每个延迟至少有一半样本参与计算。
+//
+// 参数:
+//   - a: 参考信号
+//   - b: 待对齐的信号（如麦克风信号）
+//   - rate: 采样率（Hz）
+//   - maxLagMs: 最大搜索延迟（毫秒）
+//
+// 返回:
+//   - delay: b相对于a的时延，为正时b滞后于a（b[i+delay] ≈ a[i]）
+//   - confidence: 峰值处的归一化相关系数（0-1），越接近1越可信；
+//     参数无效、信号为静音或没有正相关时时延与置信度都为0
+func EstimateDelay(a, b []int16, rate int, maxLagMs int) (delay time.Duration, confidence float64) {
+	if rate <= 0 || maxLagMs <= 0 {
+		return 0, 0
+	}
+	n := min(len(a), len(b))
+	maxLag := min(rate*maxLagMs/1000, n/2)
+	if maxLag < 1 {
+		return 0, 0
+	}
+
+	// 逐项右移防止int32累加溢出
+	maxA := int(maxAbsValueW16(a, n)) + 1
+	maxB := int(maxAbsValueW16(b, n)) + 1
+	shifts := bits.Len(uint(maxA)) + bits.Len(uint(maxB)) + bits.Len(uint(n)) - 31
+	if shifts < 0 {
+		shifts = 0
+	}
+	scale := math.Ldexp(1, shifts)
+
+	// 前缀能量，用于计算各延迟下重叠部分的能量
+	energyA := make([]float64, n+1)
+	energyB := make([]float64, n+1)
+	for i := 0; i < n; i++ {
+		energyA[i+1] = energyA[i] + float64(a[i])*float64(a[i])
+		energyB[i+1] = energyB[i] + float64(b[i])*float64(b[i])
+	}
+
+	// nr[lag+maxLag]为延迟lag的归一化互相关
+	nr := make([]float64, 2*maxLag+1)
+	best := -1
+	for lag := -maxLag; lag <= maxLag; lag++ {
+		// 重叠部分：lag ≥ 0 时为a[0:n-lag]与b[lag:n]，否则为a[-lag:n]与b[0:n+lag]
+		var ea, eb float64
+		if lag >= 0 {
+			ea, eb = energyA[n-lag], energyB[n]-energyB[lag]
+		} else {
+			ea, eb = energyA[n]-energyA[-lag], energyB[n+lag]
+		}
+		i := lag + maxLag
+		if d := ea * eb; d > 0 {
+			nr[i] = float64(CrossCorrelationWithLag(a, b, n, lag, shifts)) * scale / math.Sqrt(d)
+		}
+		if best < 0 || nr[i] > nr[best] {
+			best = i
+		}
+	}
+	if nr[best] <= 0 {
+		return 0, 0
+	}
+
+	// 抛物线插值
+	lag := float64(best - maxLag)
+	if best > 0 && best < len(nr)-1 {
+		l, c, r := nr[best-1], nr[best], nr[best+1]
+		if den := l - 2*c + r; den < 0 {
+			lag += 0.5 * (l - r) / den
+		}
+	}
+	return time.Duration(lag * float64(time.Second) / float64(rate)), math.Min(nr[best], 1)
+}
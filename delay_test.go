@@ -0,0 +1,67 @@
+package webrtcvad
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestEstimateDelay 测试整数与亚样本时延的估计精度及置信度
+func TestEstimateDelay(t *testing.T) {
+	// 低通噪声作为参考信号，b为其延迟版本并叠加少量独立噪声
+	rng := rand.New(rand.NewSource(3))
+	ref := make([]float64, 16000)
+	var y float64
+	for i := range ref {
+		y = 0.7*y + rng.NormFloat64()*1000
+		ref[i] = y
+	}
+	delayed := func(samples float64) []int16 {
+		// 线性插值实现分数延迟
+		out := make([]int16, len(ref))
+		for i := range out {
+			at := float64(i) - samples
+			j := int(math.Floor(at))
+			if j < 0 || j+1 >= len(ref) {
+				continue
+			}
+			frac := at - float64(j)
+			out[i] = int16(ref[j]*(1-frac) + ref[j+1]*frac + rng.NormFloat64()*50)
+		}
+		return out
+	}
+	a := delayed(0)
+
+	for _, samples := range []float64{0, 37, -120, 80.5, 12.25} {
+		b := delayed(samples)
+		delay, confidence := EstimateDelay(a, b, 16000, 20)
+		want := time.Duration(samples * float64(time.Second) / 16000)
+		if diff := delay - want; diff < -16*time.Microsecond || diff > 16*time.Microsecond { // 0.25个样本
+			t.Errorf("延迟%.2f样本: 应为%v, 得到%v", samples, want, delay)
+		}
+		if confidence < 0.9 {
+			t.Errorf("延迟%.2f样本: 置信度应不低于0.9, 得到%.3f", samples, confidence)
+		}
+	}
+
+	// 超出搜索范围的延迟与不相关的信号置信度低
+	if _, confidence := EstimateDelay(a, delayed(400), 16000, 20); confidence > 0.5 {
+		t.Errorf("超出搜索范围时置信度应较低, 得到%.3f", confidence)
+	}
+	noise := make([]int16, len(a))
+	for i := range noise {
+		noise[i] = int16(rng.NormFloat64() * 1000)
+	}
+	if _, confidence := EstimateDelay(a, noise, 16000, 20); confidence > 0.2 {
+		t.Errorf("不相关信号的置信度应较低, 得到%.3f", confidence)
+	}
+
+	// 无效参数与静音
+	if delay, confidence := EstimateDelay(a, a, 0, 20); delay != 0 || confidence != 0 {
+		t.Errorf("无效采样率应返回0, 得到%v %.3f", delay, confidence)
+	}
+	if delay, confidence := EstimateDelay(make([]int16, 320), a, 16000, 20); delay != 0 || confidence != 0 {
+		t.Errorf("静音应返回0, 得到%v %.3f", delay, confidence)
+	}
+}
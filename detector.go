@@ -0,0 +1,88 @@
+package webrtcvad
+
+import "fmt"
+
+// detector.go 定义逐帧语音判决器的公共接口，以及StreamVAD可选的检测算法
+
+// Detector 逐帧语音判决器
+//
+// *VAD（WebRTC的GMM）、EntropyVAD、EnergyVAD、HybridVAD、LTSDVAD与ModelDetector实现此接口，可以互相替换。
+// 有自适应状态的实现可另外提供Reset()方法，StreamVAD.Reset时调用；
+// 支持激进度模式的实现可另外提供SetMode(int) error方法，StreamVAD.SetMode时调用。
+// 自定义实现可通过WithDetector用于StreamVAD
+type Detector interface {
+	// IsSpeech 判断一帧16位小端序PCM是否为语音，帧长度须对应10ms、20ms或30ms
+	IsSpeech(frame []byte, sampleRate int) (bool, error)
+}
+
+// resetter 有自适应状态的Detector
+type resetter interface {
+	Reset()
+}
+
+// modeSetter 支持运行时修改激进度模式的Detector
+type modeSetter interface {
+	SetMode(mode int) error
+}
+
+// Algorithm StreamVAD的检测算法（见WithAlgorithm）
+type Algorithm int
+
+const (
+	// GMM WebRTC的高斯混合模型（默认）
+	GMM Algorithm = iota
+	// Entropy 谱熵检测（EntropyVAD），在部分非平稳噪声下优于GMM
+	Entropy
+	// Energy 自适应能量门限（EnergyVAD），计算量最小
+	Energy
+	// Hybrid GMM、能量门限与过零率加权投票（HybridVAD），抑制冲击噪声误检
+	Hybrid
+	// LTSD 长时谱散度检测（LTSDVAD），在低信噪比的平稳噪声下优于GMM
+	LTSD
+)
+
+// String 返回算法名称
+func (a Algorithm) String() string {
+	switch a {
+	case GMM:
+		return "gmm"
+	case Entropy:
+		return "entropy"
+	case Energy:
+		return "energy"
+	case Hybrid:
+		return "hybrid"
+	case LTSD:
+		return "ltsd"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", int(a))
+	}
+}
+
+// newDetector 按配置的自定义检测器、外部模型或算法创建判决器，GMM返回nil（使用StreamVAD内置的VAD）
+func newDetector(cfg *streamVADConfig) (Detector, error) {
+	if cfg.detector != nil {
+		return cfg.detector, nil
+	}
+	if cfg.model != nil {
+		if cfg.model.cfg.SampleRate != cfg.sampleRate {
+			return nil, fmt.Errorf("%w: model expects %d Hz, stream is %d Hz", ErrInvalidSampleRate, cfg.model.cfg.SampleRate, cfg.sampleRate)
+		}
+		return cfg.model, nil
+	}
+	mode := cfg.mode
+	switch cfg.algorithm {
+	case GMM:
+		return nil, nil
+	case Entropy:
+		return NewEntropyVAD(mode)
+	case Energy:
+		return NewEnergyVAD(mode)
+	case Hybrid:
+		return NewHybridVAD(mode, cfg.hybrid)
+	case LTSD:
+		return NewLTSDVAD(mode)
+	default:
+		return nil, ErrInvalidAlgorithm
+	}
+}
@@ -0,0 +1,307 @@
+package webrtcvad
+
+import "fmt"
+
+// downsample.go 实现公开的多级降采样器，与Upsampler对应
+//
+// 2倍降采样移植WebRtcSpl_DownsampleBy2，3倍移植WebRtcSpl_Resample48khzTo16khz
+
+// DownsampleState DownsampleBy2的滤波器状态，零值即为初始状态
+//
+// 每路音频流使用一个实例，跨调用保存两个全通滤波器的状态
+type DownsampleState struct {
+	s [2]int32
+}
+
+// Reset 清除滤波器状态
+func (s *DownsampleState) Reset() {
+	clear(s.s[:])
+}
+
+// DownsampleBy2 2倍降采样（VAD内部使用的分割滤波器）
+//
+// 偶数与奇数样本分别经过一阶全通滤波器后相加，即VAD特征提取中32->16、16->8kHz的降采样。
+// 计算量远小于Downsampler的三阶全通滤波器对，但阻带衰减较弱，适合自行组合的多速率处理或特征提取。
+// 状态跨调用保存；输入长度为奇数时最后一个样本被忽略
+//
+// 参数:
+//   - in: 输入样本
+//   - state: 滤波器状态
+//
+// 返回:
+//   - []int16: len(in)/2个输出样本
+func DownsampleBy2(in []int16, state *DownsampleState) []int16 {
+	out := make([]int16, len(in)/2)
+	DownsampleBy2To(in, out, state)
+	return out
+}
+
+// DownsampleBy2To 零分配的2倍降采样，out长度须不小于len(in)/2
+func DownsampleBy2To(in []int16, out []int16, state *DownsampleState) {
+	downsampling(in, out, state.s[:], len(in))
+}
+
+// downsampleBy2Allpass 2倍降采样（WebRtcSpl_DownsampleBy2），in长度须为偶数
+//
+// 参数:
+//   - in: 输入样本
+//   - out: 输出样本（长度len(in)/2）
+//   - state: 滤波器状态
+func downsampleBy2Allpass(in []int16, out []int16, state *[8]int32) {
+	s := state
+	for i := range len(in) / 2 {
+		// 下侧全通滤波器（偶数输入）
+		in32 := int32(in[2*i]) << 10
+		diff := in32 - s[1]
+		tmp1 := scaleDiff32(kUpsampleAllpass2[0], diff, s[0])
+		s[0] = in32
+		diff = tmp1 - s[2]
+		tmp2 := scaleDiff32(kUpsampleAllpass2[1], diff, s[1])
+		s[1] = tmp1
+		diff = tmp2 - s[3]
+		s[3] = scaleDiff32(kUpsampleAllpass2[2], diff, s[2])
+		s[2] = tmp2
+
+		// 上侧全通滤波器（奇数输入）
+		in32 = int32(in[2*i+1]) << 10
+		diff = in32 - s[5]
+		tmp1 = scaleDiff32(kUpsampleAllpass1[0], diff, s[4])
+		s[4] = in32
+		diff = tmp1 - s[6]
+		tmp2 = scaleDiff32(kUpsampleAllpass1[1], diff, s[5])
+		s[5] = tmp1
+		diff = tmp2 - s[7]
+		s[7] = scaleDiff32(kUpsampleAllpass1[2], diff, s[6])
+		s[6] = tmp2
+
+		// 两个全通滤波器输出相加、除以2并舍入
+		out[i] = sat16((s[3] + s[7] + 1024) >> 11)
+	}
+}
+
+// allpassStep 一个三阶全通滤波器节（resample_by_2_internal.c的公共结构），
+// 返回更新后的输出状态
+func allpassStep(tmp0 int32, state []int32, coef *[3]int16) int32 {
+	diff := tmp0 - state[1]
+	// 缩放和舍入
+	diff = (diff + (1 << 13)) >> 14
+	tmp1 := state[0] + diff*int32(coef[0])
+	state[0] = tmp0
+	diff = tmp1 - state[2]
+	// 缩放和截断
+	diff = diff >> 14
+	if diff < 0 {
+		diff += 1
+	}
+	tmp0 = state[1] + diff*int32(coef[1])
+	state[1] = tmp1
+	diff = tmp0 - state[3]
+	diff = diff >> 14
+	if diff < 0 {
+		diff += 1
+	}
+	state[3] = state[2] + diff*int32(coef[2])
+	state[2] = tmp0
+	return state[3]
+}
+
+// lpBy2ShortToInt 半带低通滤波，不改变采样率：int16 -> int32（WebRtcSpl_LPBy2ShortToInt）
+//
+// 参数:
+//   - in: 输入样本（int16，长度为偶数）
+//   - out: 输出样本（int32，未饱和，长度len(in)）
+//   - state: 滤波器状态（长度16）
+func lpBy2ShortToInt(in []int16, out []int32, state []int32) {
+	half := len(in) >> 1
+
+	// 下侧全通滤波器：奇数输入 -> 偶数输出，延迟单元的初值为上一块最后一个奇数输入
+	tmp0 := state[12]
+	for i := 0; i < half; i++ {
+		out[i<<1] = allpassStep(tmp0, state[0:4], &kResampleAllpass[1]) >> 1
+		tmp0 = (int32(in[(i<<1)+1]) << 15) + (1 << 14)
+	}
+
+	// 上侧全通滤波器：偶数输入 -> 偶数输出，与下侧输出平均
+	for i := 0; i < half; i++ {
+		tmp0 := (int32(in[i<<1]) << 15) + (1 << 14)
+		y := allpassStep(tmp0, state[4:8], &kResampleAllpass[0])
+		out[i<<1] = (out[i<<1] + (y >> 1)) >> 15
+	}
+
+	// 下侧全通滤波器：偶数输入 -> 奇数输出
+	for i := 0; i < half; i++ {
+		tmp0 := (int32(in[i<<1]) << 15) + (1 << 14)
+		out[(i<<1)+1] = allpassStep(tmp0, state[8:12], &kResampleAllpass[1]) >> 1
+	}
+
+	// 上侧全通滤波器：奇数输入 -> 奇数输出，与下侧输出平均
+	for i := 0; i < half; i++ {
+		tmp0 := (int32(in[(i<<1)+1]) << 15) + (1 << 14)
+		y := allpassStep(tmp0, state[12:16], &kResampleAllpass[0])
+		out[(i<<1)+1] = (out[(i<<1)+1] + (y >> 1)) >> 15
+	}
+}
+
+// state48khzTo16khz 48kHz到16kHz重采样状态
+type state48khzTo16khz struct {
+	S_48_48 [16]int32 // 48->48(LP)状态
+	S_48_32 [8]int32  // 48->32状态（上一块末尾的8个样本）
+	S_32_16 [8]int32  // 32->16状态
+}
+
+// resample48khzTo16khz 48kHz到16kHz重采样（WebRtcSpl_Resample48khzTo16khz）
+//
+//	阶段1: 48kHz -> 48kHz (半带低通)
+//	阶段2: 48kHz -> 32kHz (分数重采样 2/3)
+//	阶段3: 32kHz -> 16kHz (2倍降采样，全通滤波器)
+//
+// 参数:
+//   - in: 输入样本，长度须为6的整数倍
+//   - out: 输出样本，长度len(in)/3
+//   - state: 重采样状态
+//   - tmpMem: 临时内存（至少 len(in)+8 + 2*len(in)/3 个int32）
+func resample48khzTo16khz(in []int16, out []int16, state *state48khzTo16khz, tmpMem []int32) {
+	n := len(in)
+	t48 := tmpMem[:n+8]
+	t32 := tmpMem[n+8 : n+8+2*n/3]
+
+	// 阶段1，输出写在上一块的8个重叠样本之后
+	copy(t48[:8], state.S_48_32[:])
+	lpBy2ShortToInt(in, t48[8:], state.S_48_48[:])
+	copy(state.S_48_32[:], t48[n:])
+
+	// 阶段2，每3个输入样本生成2个输出样本
+	resample48khzTo32khz(t48, t32, n/3)
+
+	// 阶段3
+	downBy2IntToShort(t32, len(t32), out, state.S_32_16[:])
+}
+
+// Downsampler 多级降采样器，支持48kHz->24/16/8kHz、32kHz->16/8kHz、24kHz->8kHz与16kHz->8kHz
+//
+// 与WebRTC的Resampler一样按比例选择滤波器：2倍与4倍由全通滤波器对级联完成，
+// 3倍使用48kHz->16kHz的分数重采样流水线（24kHz->8kHz同样适用），6倍在其后再做一级2倍。
+// Downsampler有状态且不是并发安全的，每路音频流使用一个实例
+type Downsampler struct {
+	inRate, outRate int
+	by3             *state48khzTo16khz // 非nil时先执行3倍降采样
+	by2             [][8]int32         // 依次执行的2倍降采样级
+	buf             []int16            // 级间缓冲区
+	tmp             []int32            // 分数重采样的临时内存
+}
+
+// NewDownsampler 创建降采样器
+//
+// 参数:
+//   - inRate: 输入采样率（16000, 24000, 32000或48000）
+//   - outRate: 输出采样率，须为上述支持的组合
+//
+// 返回:
+//   - *Downsampler: 降采样器实例
+//   - error: 不支持的组合返回ErrUnsupportedConversion
+func NewDownsampler(inRate, outRate int) (*Downsampler, error) {
+	d := &Downsampler{inRate: inRate, outRate: outRate}
+	switch {
+	case inRate == 48000 && outRate == 24000,
+		inRate == 32000 && outRate == 16000,
+		inRate == 16000 && outRate == 8000:
+		d.by2 = make([][8]int32, 1)
+	case inRate == 32000 && outRate == 8000:
+		d.by2 = make([][8]int32, 2)
+	case inRate == 48000 && outRate == 16000,
+		inRate == 24000 && outRate == 8000:
+		d.by3 = &state48khzTo16khz{}
+	case inRate == 48000 && outRate == 8000:
+		d.by3 = &state48khzTo16khz{}
+		d.by2 = make([][8]int32, 1)
+	default:
+		return nil, fmt.Errorf("%w: %d Hz -> %d Hz", ErrUnsupportedConversion, inRate, outRate)
+	}
+	return d, nil
+}
+
+// InRate 返回输入采样率
+func (d *Downsampler) InRate() int { return d.inRate }
+
+// OutRate 返回输出采样率
+func (d *Downsampler) OutRate() int { return d.outRate }
+
+// BlockSize 返回每次输入样本数须为其整数倍的块长度
+//
+// 2倍为2、4倍为4；3倍与6倍的分数重采样以6个输入样本为一组，为6
+func (d *Downsampler) BlockSize() int {
+	if d.by3 != nil {
+		return 6
+	}
+	return 2 * len(d.by2)
+}
+
+// Process 降采样一段音频，返回新分配的输出
+//
+// 参数:
+//   - in: 输入样本，长度须为BlockSize()的整数倍
+//
+// 返回:
+//   - []int16: len(in)*OutRate()/InRate()个输出样本
+//   - error: 长度不是块长度的整数倍时返回ErrPartialBlock
+func (d *Downsampler) Process(in []int16) ([]int16, error) {
+	out := make([]int16, len(in)*d.outRate/d.inRate)
+	if _, err := d.ProcessTo(in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProcessTo 零分配的降采样（临时内存在首次调用或输入变长时分配），
+// out长度须不小于len(in)*OutRate()/InRate()
+//
+// 返回:
+//   - int: 写入out的样本数
+//   - error: 长度不是块长度的整数倍时返回ErrPartialBlock，out太短时返回ErrBufferTooSmall
+func (d *Downsampler) ProcessTo(in []int16, out []int16) (int, error) {
+	if len(in)%d.BlockSize() != 0 {
+		return 0, fmt.Errorf("%w: got %d samples, block size %d", ErrPartialBlock, len(in), d.BlockSize())
+	}
+	n := len(in) * d.outRate / d.inRate
+	if len(out) < n {
+		return 0, ErrBufferTooSmall
+	}
+
+	cur := in
+	if d.by3 != nil {
+		dst := out
+		if len(d.by2) > 0 {
+			if cap(d.buf) < len(cur)/3 {
+				d.buf = make([]int16, len(cur)/3)
+			}
+			dst = d.buf
+		}
+		size := len(cur) + 8 + 2*len(cur)/3
+		if cap(d.tmp) < size {
+			d.tmp = make([]int32, size)
+		}
+		resample48khzTo16khz(cur, dst[:len(cur)/3], d.by3, d.tmp[:size])
+		cur = dst[:len(cur)/3]
+	}
+	for i := range d.by2 {
+		dst := out
+		if i < len(d.by2)-1 {
+			// 中间结果写入级间缓冲区，最后一级直接写入out
+			if cap(d.buf) < len(cur)/2 {
+				d.buf = make([]int16, len(cur)/2)
+			}
+			dst = d.buf
+		}
+		downsampleBy2Allpass(cur, dst, &d.by2[i])
+		cur = dst[:len(cur)/2]
+	}
+	return n, nil
+}
+
+// Reset 清除所有级的滤波器状态
+func (d *Downsampler) Reset() {
+	clear(d.by2)
+	if d.by3 != nil {
+		*d.by3 = state48khzTo16khz{}
+	}
+}
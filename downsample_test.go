@@ -0,0 +1,124 @@
+package webrtcvad
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestDownsampler 测试各组合的输出长度、通带幅度与混叠抑制
+func TestDownsampler(t *testing.T) {
+	for _, c := range []struct{ in, out int }{
+		{48000, 24000}, {48000, 16000}, {48000, 8000},
+		{32000, 16000}, {32000, 8000}, {24000, 8000}, {16000, 8000},
+	} {
+		d, err := NewDownsampler(c.in, c.out)
+		if err != nil {
+			t.Fatalf("%d->%d: 创建降采样器失败: %v", c.in, c.out, err)
+		}
+		// 通带内的1kHz，以及会混叠到out/4处的0.75*out
+		alias := 0.75 * float64(c.out)
+		in := make([]int16, c.in/2)
+		for i := range in {
+			ts := float64(i) / float64(c.in)
+			in[i] = int16(8000*math.Sin(2*math.Pi*1000*ts) + 8000*math.Sin(2*math.Pi*alias*ts))
+		}
+		out, err := d.Process(in)
+		if err != nil {
+			t.Fatalf("%d->%d: 降采样失败: %v", c.in, c.out, err)
+		}
+		if want := len(in) * c.out / c.in; len(out) != want {
+			t.Fatalf("%d->%d: 输出长度应为%d, 得到%d", c.in, c.out, want, len(out))
+		}
+
+		steady := out[len(out)/4:]
+		if a := toneLevel(steady, c.out, 1000); math.Abs(a-8000) > 400 {
+			t.Errorf("%d->%d: 1kHz幅度应约为8000, 得到%.0f", c.in, c.out, a)
+		}
+		if a := toneLevel(steady, c.out, float64(c.out)/4); a > 200 {
+			t.Errorf("%d->%d: %.0fHz的混叠幅度应低于-32dB, 得到%.0f", c.in, c.out, alias, a)
+		}
+	}
+}
+
+// TestDownsamplerStreaming 测试分块处理与一次性处理结果一致，以及Reset
+func TestDownsamplerStreaming(t *testing.T) {
+	for _, c := range []struct{ in, out int }{{48000, 16000}, {32000, 8000}, {24000, 8000}} {
+		d, _ := NewDownsampler(c.in, c.out)
+		in := make([]int16, 4*d.BlockSize()*10)
+		for i := range in {
+			in[i] = int16(8000*math.Sin(float64(i)*0.11) + 3000*math.Cos(float64(i)*2.3))
+		}
+		whole, _ := d.Process(in)
+
+		d.Reset()
+		var chunked []int16
+		step := 3 * d.BlockSize()
+		for i := 0; i < len(in); i += step {
+			out, err := d.Process(in[i:min(i+step, len(in))])
+			if err != nil {
+				t.Fatalf("%d->%d: 降采样失败: %v", c.in, c.out, err)
+			}
+			chunked = append(chunked, out...)
+		}
+		for i := range whole {
+			if whole[i] != chunked[i] {
+				t.Fatalf("%d->%d: 分块处理第%d个输出应为%d, 得到%d", c.in, c.out, i, whole[i], chunked[i])
+			}
+		}
+	}
+}
+
+// TestDownsamplerErrors 测试不支持的组合与不完整的块
+func TestDownsamplerErrors(t *testing.T) {
+	for _, c := range []struct{ in, out int }{{8000, 16000}, {48000, 32000}, {44100, 16000}} {
+		if _, err := NewDownsampler(c.in, c.out); !errors.Is(err, ErrUnsupportedConversion) {
+			t.Errorf("%d->%d 应返回ErrUnsupportedConversion, 得到%v", c.in, c.out, err)
+		}
+	}
+	d, _ := NewDownsampler(48000, 16000)
+	if _, err := d.Process(make([]int16, 481)); !errors.Is(err, ErrPartialBlock) {
+		t.Errorf("不完整的块应返回ErrPartialBlock, 得到%v", err)
+	}
+	if _, err := d.ProcessTo(make([]int16, 480), make([]int16, 159)); err != ErrBufferTooSmall {
+		t.Errorf("输出过短应返回ErrBufferTooSmall, 得到%v", err)
+	}
+}
+
+// TestDownsampleBy2 测试与VAD内部的分割滤波器一致，以及分块处理
+func TestDownsampleBy2(t *testing.T) {
+	in := make([]int16, 320)
+	for i := range in {
+		in[i] = int16(8000*math.Sin(2*math.Pi*500*float64(i)/16000) + 2000*math.Cos(float64(i)*2.5))
+	}
+
+	want := make([]int16, len(in)/2)
+	filterState := make([]int32, 2)
+	downsampling(in, want, filterState, len(in))
+
+	var state DownsampleState
+	got := DownsampleBy2(in, &state)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("第%d个输出应为%d, 得到%d", i, want[i], got[i])
+		}
+	}
+	if state.s[0] != filterState[0] || state.s[1] != filterState[1] {
+		t.Errorf("滤波器状态应为%v, 得到%v", filterState, state.s)
+	}
+
+	state.Reset()
+	out := make([]int16, len(in)/2)
+	for i := 0; i < len(in); i += 20 {
+		DownsampleBy2To(in[i:i+20], out[i/2:], &state)
+	}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Fatalf("分块处理第%d个输出应为%d, 得到%d", i, want[i], out[i])
+		}
+	}
+
+	if n := len(DownsampleBy2(in[:7], &state)); n != 3 {
+		t.Errorf("奇数长度输入应输出3个样本, 得到%d", n)
+	}
+}
@@ -0,0 +1,208 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// dtmf.go 提供基于Goertzel算法的DTMF（双音多频）按键检测
+//
+// 按键音在VAD中会被判为语音，IVR应用可用DTMFDetector识别按键并据此剔除对应片段
+
+// dtmfRows / dtmfCols DTMF低频组与高频组频率（Hz）
+var (
+	dtmfRows = [4]float64{697, 770, 852, 941}
+	dtmfCols = [4]float64{1209, 1336, 1477, 1633}
+)
+
+// dtmfDigits 按[行][列]排列的按键
+var dtmfDigits = [4][4]byte{
+	{'1', '2', '3', 'A'},
+	{'4', '5', '6', 'B'},
+	{'7', '8', '9', 'C'},
+	{'*', '0', '#', 'D'},
+}
+
+// DTMF检测门限
+const (
+	// dtmfMinDuration 按键音的最短持续时间（ITU-T Q.24）
+	dtmfMinDuration = 40 * time.Millisecond
+	// dtmfMinLevel 每个单音的最小能量（以满幅正弦波为0dB，约-36dB）
+	dtmfMinLevel = 2.5e-4
+	// dtmfMinRatio 两个单音能量之和占帧能量的最小比例，用于排除语音与宽带噪声
+	dtmfMinRatio = 0.7
+	// dtmfMaxTwist 高频组相对低频组的最大能量差（正向8dB、反向4dB）
+	dtmfMaxTwist, dtmfMaxReverseTwist = 6.3, 2.5
+	// dtmfMinPeakRatio 组内最强频率与次强频率的最小能量比（6dB）
+	dtmfMinPeakRatio = 4.0
+)
+
+// DTMFEvent 检测到的一次按键
+type DTMFEvent struct {
+	Digit byte          // 按键：0-9、*、#、A-D
+	Start time.Duration // 按键音开始时间
+	End   time.Duration // 按键音结束时间
+}
+
+// DTMFDetector DTMF按键检测器
+//
+// 输入按与VAD相同的10/20/30ms帧逐帧检测，连续检测到同一按键且持续
+// 不短于40ms时报告一次按键。DTMFDetector不是并发安全的
+type DTMFDetector struct {
+	frameSize int // 每帧字节数
+	frameDur  time.Duration
+	coeffs    [8]float64 // 各频率的Goertzel系数
+
+	buffer  []byte
+	samples []int16
+	elapsed time.Duration // 已处理的时长
+
+	digit byte // 当前按键，0表示无
+	start time.Duration
+}
+
+// NewDTMFDetector 创建DTMF检测器
+//
+// 参数:
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
+//   - frameMs: 帧长度（10, 20, 30毫秒），帧越长频率分辨率越高
+//
+// 返回:
+//   - *DTMFDetector: 检测器实例
+//   - error: 错误信息
+func NewDTMFDetector(sampleRate, frameMs int) (*DTMFDetector, error) {
+	if !isValidSampleRate(sampleRate) {
+		return nil, ErrInvalidSampleRate
+	}
+	frameLength := sampleRate * frameMs / 1000
+	if !ValidRateAndFrameLength(sampleRate, frameLength) {
+		return nil, ErrInvalidFrameLength
+	}
+
+	d := &DTMFDetector{
+		frameSize: frameLength * 2,
+		frameDur:  time.Duration(frameMs) * time.Millisecond,
+		samples:   make([]int16, frameLength),
+	}
+	for i, f := range append(dtmfRows[:], dtmfCols[:]...) {
+		d.coeffs[i] = 2 * math.Cos(2*math.Pi*f/float64(sampleRate))
+	}
+	return d, nil
+}
+
+// Process 处理任意长度的16位小端序PCM数据
+//
+// 不完整的帧被缓存到下次调用。返回本次调用中结束的按键
+func (d *DTMFDetector) Process(data []byte) []DTMFEvent {
+	var events []DTMFEvent
+	d.buffer = append(d.buffer, data...)
+	for len(d.buffer) >= d.frameSize {
+		if ev, ok := d.processFrame(d.buffer[:d.frameSize]); ok {
+			events = append(events, ev)
+		}
+		d.buffer = d.buffer[d.frameSize:]
+	}
+	return events
+}
+
+// Flush 结束进行中的按键并丢弃不完整的帧
+//
+// 返回结束的按键（没有时为nil）
+func (d *DTMFDetector) Flush() []DTMFEvent {
+	d.buffer = d.buffer[:0]
+	if ev, ok := d.finish(d.elapsed); ok {
+		return []DTMFEvent{ev}
+	}
+	return nil
+}
+
+// Reset 重置检测器状态
+func (d *DTMFDetector) Reset() {
+	d.buffer = d.buffer[:0]
+	d.elapsed = 0
+	d.digit = 0
+}
+
+// processFrame 检测一帧并更新按键状态，按键结束时返回该按键
+func (d *DTMFDetector) processFrame(frame []byte) (DTMFEvent, bool) {
+	for i := range d.samples {
+		d.samples[i] = int16(binary.LittleEndian.Uint16(frame[i*2:]))
+	}
+	digit := d.detect(d.samples)
+	frameStart := d.elapsed
+	d.elapsed += d.frameDur
+	if digit == d.digit {
+		return DTMFEvent{}, false
+	}
+
+	// 按键在本帧开始处结束
+	ev, ok := d.finish(frameStart)
+	d.digit, d.start = digit, frameStart
+	return ev, ok
+}
+
+// finish 在end处结束当前按键，持续时间足够时返回该按键
+func (d *DTMFDetector) finish(end time.Duration) (DTMFEvent, bool) {
+	digit := d.digit
+	d.digit = 0
+	if digit == 0 || end-d.start < dtmfMinDuration {
+		return DTMFEvent{}, false
+	}
+	return DTMFEvent{Digit: digit, Start: d.start, End: end}, true
+}
+
+// detect 检测一帧中的按键，没有时返回0
+func (d *DTMFDetector) detect(samples []int16) byte {
+	var energy float64
+	for _, v := range samples {
+		energy += float64(v) * float64(v)
+	}
+	if energy == 0 {
+		return 0
+	}
+
+	// 各频率的能量，归一化为等效正弦波的帧能量（A²N/2）
+	var power [8]float64
+	n := float64(len(samples))
+	for i, c := range d.coeffs {
+		var s1, s2 float64
+		for _, v := range samples {
+			s1, s2 = float64(v)+c*s1-s2, s1
+		}
+		power[i] = (s1*s1 + s2*s2 - c*s1*s2) * 2 / n
+	}
+
+	row, rowSecond := strongest(power[:4])
+	col, colSecond := strongest(power[4:])
+	rowPower, colPower := power[row], power[4+col]
+
+	minPower := dtmfMinLevel * 32768 * 32768 / 2 * n
+	switch {
+	case rowPower < minPower || colPower < minPower:
+		return 0
+	case rowPower+colPower < dtmfMinRatio*energy:
+		return 0
+	case colPower > rowPower*dtmfMaxTwist || rowPower > colPower*dtmfMaxReverseTwist:
+		return 0
+	case rowPower < rowSecond*dtmfMinPeakRatio || colPower < colSecond*dtmfMinPeakRatio:
+		return 0
+	}
+	return dtmfDigits[row][col]
+}
+
+// strongest 返回能量最大的下标与次大的能量
+func strongest(power []float64) (int, float64) {
+	best, second := 0, 0.0
+	for i := 1; i < len(power); i++ {
+		if power[i] > power[best] {
+			best = i
+		}
+	}
+	for i, p := range power {
+		if i != best && p > second {
+			second = p
+		}
+	}
+	return best, second
+}
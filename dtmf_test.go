@@ -0,0 +1,103 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// dtmfTone 生成按键音的PCM数据，digit为0时生成静音
+func dtmfTone(digit byte, rate int, d time.Duration) []byte {
+	var f1, f2 float64
+	for r, row := range dtmfDigits {
+		for c, v := range row {
+			if v == digit {
+				f1, f2 = dtmfRows[r], dtmfCols[c]
+			}
+		}
+	}
+	n := int(d.Seconds() * float64(rate))
+	buf := make([]byte, 0, n*2)
+	for i := 0; i < n; i++ {
+		t := float64(i) / float64(rate)
+		v := 0.0
+		if digit != 0 {
+			v = 6000*math.Sin(2*math.Pi*f1*t) + 8000*math.Sin(2*math.Pi*f2*t)
+		}
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(int16(v)))
+	}
+	return buf
+}
+
+// TestDTMFDetector 测试按键序列的识别与时间戳
+func TestDTMFDetector(t *testing.T) {
+	for _, c := range []struct{ rate, frameMs int }{{8000, 20}, {16000, 10}, {48000, 30}} {
+		d, err := NewDTMFDetector(c.rate, c.frameMs)
+		if err != nil {
+			t.Fatalf("创建检测器失败: %v", err)
+		}
+
+		var pcm []byte
+		for _, digit := range []byte("159*0#D") {
+			pcm = append(pcm, dtmfTone(digit, c.rate, 90*time.Millisecond)...)
+			pcm = append(pcm, dtmfTone(0, c.rate, 60*time.Millisecond)...)
+		}
+		// 过短的按键音被忽略
+		pcm = append(pcm, dtmfTone('3', c.rate, 20*time.Millisecond)...)
+		pcm = append(pcm, dtmfTone(0, c.rate, 60*time.Millisecond)...)
+		// 结尾未结束的按键由Flush报告
+		pcm = append(pcm, dtmfTone('A', c.rate, 90*time.Millisecond)...)
+
+		// 以不对齐帧边界的块输入
+		var events []DTMFEvent
+		for off := 0; off < len(pcm); off += 1000 {
+			events = append(events, d.Process(pcm[off:min(off+1000, len(pcm))])...)
+		}
+		events = append(events, d.Flush()...)
+
+		var digits []byte
+		for _, ev := range events {
+			digits = append(digits, ev.Digit)
+		}
+		if string(digits) != "159*0#DA" {
+			t.Fatalf("%dHz/%dms: 期望按键159*0#DA, 得到%q", c.rate, c.frameMs, digits)
+		}
+		frame := time.Duration(c.frameMs) * time.Millisecond
+		for i, ev := range events[:7] {
+			start := time.Duration(i) * 150 * time.Millisecond
+			if ev.Start < start || ev.Start > start+frame || ev.End < start+90*time.Millisecond-frame || ev.End > start+90*time.Millisecond+frame {
+				t.Errorf("%dHz/%dms: 第%d个按键时间应约为%v-%v, 得到%v-%v", c.rate, c.frameMs, i, start, start+90*time.Millisecond, ev.Start, ev.End)
+			}
+		}
+	}
+}
+
+// TestDTMFDetectorRejects 测试语音类谐波信号与单音不被识别为按键
+func TestDTMFDetectorRejects(t *testing.T) {
+	d, _ := NewDTMFDetector(8000, 20)
+	n := 8000
+	pcm := make([]byte, 0, n*4)
+	// 基频150Hz、含多次谐波的浊音
+	for i := 0; i < n; i++ {
+		v := 0.0
+		for h := 1; h <= 20; h++ {
+			v += 3000 / float64(h) * math.Sin(2*math.Pi*150*float64(h)*float64(i)/8000)
+		}
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(v)))
+	}
+	// 只有低频组单音
+	for i := 0; i < n; i++ {
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(8000*math.Sin(2*math.Pi*697*float64(i)/8000))))
+	}
+	if events := append(d.Process(pcm), d.Flush()...); len(events) != 0 {
+		t.Errorf("不应检测到按键, 得到%v", events)
+	}
+
+	if _, err := NewDTMFDetector(44100, 20); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := NewDTMFDetector(8000, 25); err != ErrInvalidFrameLength {
+		t.Errorf("应返回ErrInvalidFrameLength, 得到%v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package webrtcvad
+
+import "time"
+
+// endpointer.go 提供基于StreamVAD的话语端点检测
+// 语音之后持续静音达到超时即判定话语结束，适合语音助手等交互场景
+
+// EndpointerConfig 端点检测配置
+type EndpointerConfig struct {
+	TrailingSilence time.Duration // 语音后持续静音达到该时长时判定话语结束，默认500ms
+	MinUtterance    time.Duration // 短于该时长的话语（咳嗽、按键声等）被丢弃，默认0
+	MaxUtterance    time.Duration // 话语达到该时长时强制结束，0表示不限制
+}
+
+// Endpoint 话语结束事件
+type Endpoint struct {
+	Start  time.Duration // 话语开始时间
+	End    time.Duration // 话语结束时间（最后一个语音帧的结束，不含尾部静音）
+	Forced bool          // 因达到MaxUtterance而强制结束
+}
+
+// Duration 获取话语时长
+func (e Endpoint) Duration() time.Duration {
+	return e.End - e.Start
+}
+
+// Endpointer 话语端点检测器
+//
+// 内部StreamVAD的最短静音时长即TrailingSilence，因此短于超时的停顿不会结束话语。
+// Endpointer本身不是并发安全的，应由单个goroutine写入
+type Endpointer struct {
+	svad *StreamVAD
+	cfg  EndpointerConfig
+
+	inSpeech bool
+	start    time.Duration // 当前话语开始时间
+}
+
+// NewEndpointer 创建端点检测器
+//
+// 参数:
+//   - cfg: 端点检测配置，TrailingSilence为0时使用默认值
+//   - opts: 内部StreamVAD的配置选项（模式、采样率、帧长度等）
+//
+// 返回:
+//   - *Endpointer: 端点检测器实例
+//   - error: 错误信息
+func NewEndpointer(cfg EndpointerConfig, opts ...StreamVADOption) (*Endpointer, error) {
+	if cfg.TrailingSilence < 0 || cfg.MinUtterance < 0 || cfg.MaxUtterance < 0 {
+		return nil, ErrInvalidDuration
+	}
+	if cfg.TrailingSilence == 0 {
+		cfg.TrailingSilence = 500 * time.Millisecond
+	}
+
+	opts = append(opts, WithMinSilenceDuration(cfg.TrailingSilence))
+	svad, err := NewStreamVADWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Endpointer{svad: svad, cfg: cfg}, nil
+}
+
+// Process 写入音频数据，返回其间结束的话语
+//
+// 参数:
+//   - data: 音频数据（16位PCM，小端序）
+//
+// 返回:
+//   - []Endpoint: 话语结束事件
+//   - error: 错误信息
+func (e *Endpointer) Process(data []byte) ([]Endpoint, error) {
+	segments, err := e.svad.Process(data)
+	if err != nil {
+		return nil, err
+	}
+	return e.update(segments, e.svad.GetTotalDuration()), nil
+}
+
+// Flush 结束音频流中进行中的话语并返回
+//
+// 流可以继续写入；没有进行中的话语时（如连续调用Flush）不产生事件
+func (e *Endpointer) Flush() ([]Endpoint, error) {
+	before := len(e.svad.GetSegments())
+	if err := e.svad.Flush(); err != nil {
+		return nil, err
+	}
+
+	// 残余数据可能开始或结束了片段，先按其中新开始的片段更新状态
+	segments := e.svad.GetSegments()
+	if len(segments) == 0 {
+		return nil, nil
+	}
+	end := segments[len(segments)-1].End
+	events := e.update(segments[before:], end)
+	if !e.inSpeech {
+		return events, nil
+	}
+	e.inSpeech = false
+	return e.finish(events, end, false), nil
+}
+
+// InSpeech 当前是否处于话语中
+func (e *Endpointer) InSpeech() bool {
+	return e.inSpeech
+}
+
+// StreamVAD 获取内部的StreamVAD（可用于查询片段和统计信息）
+func (e *Endpointer) StreamVAD() *StreamVAD {
+	return e.svad
+}
+
+// Reset 重置端点检测器及内部StreamVAD
+func (e *Endpointer) Reset() error {
+	e.inSpeech = false
+	e.start = 0
+	return e.svad.Reset()
+}
+
+// update 根据新开始的片段更新话语状态
+//
+// 参数:
+//   - segments: 本次新开始的片段
+//   - now: 已处理音频的总时长，用于检查最大话语长度
+func (e *Endpointer) update(segments []VoiceSegment, now time.Duration) []Endpoint {
+	var events []Endpoint
+	for _, seg := range segments {
+		switch {
+		case seg.IsSpeech && !e.inSpeech:
+			e.inSpeech = true
+			e.start = seg.Start
+		case !seg.IsSpeech && e.inSpeech:
+			// 静音片段开始于最后一个语音帧之后
+			e.inSpeech = false
+			events = e.finish(events, seg.Start, false)
+		}
+	}
+
+	// 超长话语按MaxUtterance切分，剩余部分作为新话语继续
+	if e.cfg.MaxUtterance > 0 {
+		for e.inSpeech && now-e.start >= e.cfg.MaxUtterance {
+			end := e.start + e.cfg.MaxUtterance
+			events = e.finish(events, end, true)
+			e.start = end
+		}
+	}
+
+	return events
+}
+
+// finish 以end结束当前话语，满足最短时长时追加到events
+func (e *Endpointer) finish(events []Endpoint, end time.Duration, forced bool) []Endpoint {
+	if end-e.start < e.cfg.MinUtterance {
+		return events
+	}
+	return append(events, Endpoint{Start: e.start, End: end, Forced: forced})
+}
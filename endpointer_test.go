@@ -0,0 +1,127 @@
+package webrtcvad
+
+import (
+	"testing"
+	"time"
+)
+
+// feedEndpointer 按10ms一帧将判决序列送入端点检测器，返回所有话语结束事件
+func feedEndpointer(e *Endpointer, pattern string) []Endpoint {
+	var events []Endpoint
+	frame := 10 * time.Millisecond
+	for _, c := range pattern {
+		start := e.svad.bytesToDuration(e.svad.totalBytes)
+		e.svad.totalBytes += int64(e.svad.frameSize)
+		var segments []VoiceSegment
+		if seg := e.svad.pushFrame(nil, c == '1', start, start+frame); seg != nil {
+			segments = append(segments, *seg)
+		}
+		events = append(events, e.update(segments, start+frame)...)
+	}
+	return events
+}
+
+// TestEndpointer 测试话语端点检测
+func TestEndpointer(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name    string
+		cfg     EndpointerConfig
+		pattern string
+		want    []Endpoint
+	}{
+		{
+			name:    "尾部静音超时",
+			cfg:     EndpointerConfig{TrailingSilence: 30 * ms},
+			pattern: "0111001100000",
+			want:    []Endpoint{{Start: 10 * ms, End: 80 * ms}},
+		},
+		{
+			name:    "丢弃短话语",
+			cfg:     EndpointerConfig{TrailingSilence: 20 * ms, MinUtterance: 30 * ms},
+			pattern: "0110001111000",
+			want:    []Endpoint{{Start: 60 * ms, End: 100 * ms}},
+		},
+		{
+			name:    "强制切分长话语",
+			cfg:     EndpointerConfig{TrailingSilence: 20 * ms, MaxUtterance: 40 * ms},
+			pattern: "11111111110000",
+			want: []Endpoint{
+				{Start: 0, End: 40 * ms, Forced: true},
+				{Start: 40 * ms, End: 80 * ms, Forced: true},
+				{Start: 80 * ms, End: 100 * ms},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEndpointer(tt.cfg, WithSampleRate(8000), WithFrameDuration(10))
+			if err != nil {
+				t.Fatalf("创建Endpointer失败: %v", err)
+			}
+
+			got := feedEndpointer(e, tt.pattern)
+			if len(got) != len(tt.want) {
+				t.Fatalf("事件错误: 期望%+v, 得到%+v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("事件%d错误: 期望%+v, 得到%+v", i, tt.want[i], got[i])
+				}
+			}
+			if e.InSpeech() {
+				t.Error("尾部静音后不应处于话语中")
+			}
+		})
+	}
+}
+
+// TestEndpointerFlush 测试流结束时进行中的话语
+func TestEndpointerFlush(t *testing.T) {
+	e, err := NewEndpointer(EndpointerConfig{}, WithSampleRate(8000), WithFrameDuration(10))
+	if err != nil {
+		t.Fatalf("创建Endpointer失败: %v", err)
+	}
+
+	if events := feedEndpointer(e, "00111"); len(events) != 0 {
+		t.Fatalf("话语未结束时不应产生事件: %+v", events)
+	}
+	if !e.InSpeech() {
+		t.Fatal("应处于话语中")
+	}
+
+	events, err := e.Flush()
+	if err != nil {
+		t.Fatalf("Flush失败: %v", err)
+	}
+	want := Endpoint{Start: 20 * time.Millisecond, End: 50 * time.Millisecond}
+	if len(events) != 1 || events[0] != want {
+		t.Errorf("Flush事件错误: 期望[%+v], 得到%+v", want, events)
+	}
+
+	// 再次Flush时话语已结束，不重复产生事件
+	if events, _ := e.Flush(); len(events) != 0 {
+		t.Errorf("再次Flush不应产生事件, 得到%+v", events)
+	}
+}
+
+// TestEndpointerProcess 测试端到端写入静音
+func TestEndpointerProcess(t *testing.T) {
+	e, err := NewEndpointer(EndpointerConfig{MaxUtterance: time.Second}, WithSampleRate(16000))
+	if err != nil {
+		t.Fatalf("创建Endpointer失败: %v", err)
+	}
+
+	events, err := e.Process(make([]byte, 16000*2))
+	if err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if len(events) != 0 || e.InSpeech() {
+		t.Errorf("静音不应产生话语: %+v", events)
+	}
+
+	if _, err := NewEndpointer(EndpointerConfig{MaxUtterance: -1}); err != ErrInvalidDuration {
+		t.Errorf("负的时长应返回ErrInvalidDuration, 得到%v", err)
+	}
+}
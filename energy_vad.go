@@ -0,0 +1,113 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// energy_vad.go 实现基于自适应能量门限的轻量语音检测器
+//
+// 每帧只需一次平方和，没有滤波器组与GMM，适合算力受限的嵌入式场景；
+// 代价是无法区分语音与同等响度的非平稳噪声
+
+// 能量检测的参数
+const (
+	// energyMinLevel 判为语音的最低平滑电平（dBFS），更安静的帧一律判为非语音
+	energyMinLevel = -55.0
+	// energySmoothTime 帧能量的平滑时间常数
+	energySmoothTime = 20 * time.Millisecond
+	// 噪声底的跟踪：低于估计时按energyFallTime快速下降，
+	// 高于估计时每经过energyRiseTime最多增大e倍（约4.3dB）
+	energyFallTime = 50 * time.Millisecond
+	energyRiseTime = time.Second
+)
+
+// energyMargins 各模式下判为语音所需的高于噪声底的幅度（dB，模式越高越严格）
+var energyMargins = [4]float64{6, 9, 12, 15}
+
+// EnergyVAD 能量门限语音检测器
+//
+// 跟踪平滑帧能量的下包络作为噪声底，平滑电平高于噪声底一定幅度（随模式增大）
+// 且不低于-55dBFS时判为语音。EnergyVAD有状态且不是并发安全的，实现Detector接口
+type EnergyVAD struct {
+	margin float64 // 功率比门限（线性）
+	minPow float64 // 最低平滑功率
+
+	power float64 // 平滑后的帧功率（均方值）
+	floor float64 // 噪声底估计，0表示尚未初始化
+}
+
+// NewEnergyVAD 创建能量门限语音检测器
+//
+// 参数:
+//   - mode: 激进度模式（0-3），与VAD的模式含义一致
+//
+// 返回:
+//   - *EnergyVAD: 检测器实例
+//   - error: 错误信息
+func NewEnergyVAD(mode int) (*EnergyVAD, error) {
+	if mode < 0 || mode > 3 {
+		return nil, ErrInvalidMode
+	}
+	return &EnergyVAD{
+		margin: math.Pow(10, energyMargins[mode]/10),
+		minPow: 32768 * 32768 * math.Pow(10, energyMinLevel/10),
+	}, nil
+}
+
+// IsSpeech 判断一帧是否为语音，实现Detector接口
+//
+// 参数:
+//   - frame: 16位小端序PCM，长度须对应10ms、20ms或30ms
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
+//
+// 返回:
+//   - bool: true表示检测到语音
+//   - error: 参数无效时返回错误
+func (e *EnergyVAD) IsSpeech(frame []byte, sampleRate int) (bool, error) {
+	if !isValidSampleRate(sampleRate) {
+		return false, ErrInvalidSampleRate
+	}
+	n := len(frame) / 2
+	if !ValidRateAndFrameLength(sampleRate, n) {
+		return false, fmt.Errorf("%w: got %d samples at %d Hz", ErrInvalidFrameLength, n, sampleRate)
+	}
+
+	var sum float64
+	for i := 0; i < n; i++ {
+		v := float64(int16(binary.LittleEndian.Uint16(frame[i*2:])))
+		sum += v * v
+	}
+	p := sum / float64(n)
+	dt := float64(n) / float64(sampleRate)
+
+	if e.floor == 0 {
+		// 第一帧：以其能量初始化，+1避免全零输入使噪声底停留在0
+		e.power, e.floor = p, p+1
+		return false, nil
+	}
+	e.power += smoothRate(dt, energySmoothTime) * (p - e.power)
+	if e.power < e.floor {
+		e.floor += smoothRate(dt, energyFallTime) * (e.power - e.floor)
+		e.floor = math.Max(e.floor, 1)
+	} else {
+		e.floor = math.Min(e.power, e.floor*math.Exp(dt/energyRiseTime.Seconds()))
+	}
+	return e.power >= e.minPow && e.power > e.floor*e.margin, nil
+}
+
+// SetMode 修改激进度模式（0-3，含义同NewEnergyVAD），只更新能量裕量，保留自适应状态
+func (e *EnergyVAD) SetMode(mode int) error {
+	if mode < 0 || mode > 3 {
+		return ErrInvalidMode
+	}
+	e.margin = math.Pow(10, energyMargins[mode]/10)
+	return nil
+}
+
+// Reset 清除平滑能量与噪声底估计
+func (e *EnergyVAD) Reset() {
+	e.power, e.floor = 0, 0
+}
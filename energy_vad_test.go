@@ -0,0 +1,78 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestEnergyVAD 测试能量检测器在低电平噪声中检测正弦波，并在噪声变大后重新适应
+func TestEnergyVAD(t *testing.T) {
+	// 2s噪声（标准差200），1s-1.5s叠加正弦波；之后4s噪声标准差升至1600
+	rng := rand.New(rand.NewSource(5))
+	pcm := make([]byte, 0, 6*16000*2)
+	for i := 0; i < 6*16000; i++ {
+		v := rng.NormFloat64() * 200
+		if i >= 2*16000 {
+			v *= 8
+		}
+		if i >= 16000 && i < 24000 {
+			v += 4000 * math.Sin(2*math.Pi*300*float64(i)/16000)
+		}
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(v)))
+	}
+
+	e, err := NewEnergyVAD(2)
+	if err != nil {
+		t.Fatalf("创建EnergyVAD失败: %v", err)
+	}
+	var speech, noise, late int
+	for i := 0; i+320 <= len(pcm); i += 320 {
+		ok, err := e.IsSpeech(pcm[i:i+320], 16000)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		if !ok {
+			continue
+		}
+		switch at := time.Duration(i/32) * time.Millisecond; {
+		case at >= time.Second && at < 1500*time.Millisecond:
+			speech++
+		case at >= 4*time.Second:
+			late++
+		default:
+			noise++
+		}
+	}
+	if speech < 48 {
+		t.Errorf("正弦波段的50帧应均为语音, 得到%d帧", speech)
+	}
+	// 噪声突增18dB，超出门限（12dB）的6dB约需1.4s才能适应
+	if noise > 200 {
+		t.Errorf("噪声变大后的误检应在2s内结束, 误检%d帧", noise)
+	}
+	if late != 0 {
+		t.Errorf("噪声底适应后不应检测到语音, 误检%d帧", late)
+	}
+
+	e.Reset()
+	if ok, _ := e.IsSpeech(make([]byte, 320), 16000); ok {
+		t.Error("静音不应判为语音")
+	}
+	if _, err := e.IsSpeech(make([]byte, 100), 16000); err == nil {
+		t.Error("无效帧长度应返回错误")
+	}
+	if _, err := NewEnergyVAD(-1); err != ErrInvalidMode {
+		t.Errorf("应返回ErrInvalidMode, 得到%v", err)
+	}
+
+	svad, err := NewStreamVADWithOptions(WithAlgorithm(Energy))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if _, ok := svad.detector.(*EnergyVAD); !ok {
+		t.Errorf("StreamVAD应使用EnergyVAD, 得到%T", svad.detector)
+	}
+}
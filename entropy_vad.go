@@ -0,0 +1,210 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// entropy_vad.go 实现基于谱熵的语音检测器
+//
+// 语音的能量集中在共振峰与谐波上，频谱熵明显低于噪声；与依赖能量分布先验的GMM相比，
+// 谱熵对电平变化和部分非平稳噪声（如交通、人群噪声）不敏感
+
+// 谱熵检测的参数
+const (
+	entropyLowHz  = 250.0  // 计算谱熵的频带下限
+	entropyHighHz = 3750.0 // 计算谱熵的频带上限
+	// entropyMinEnergy 每样本的最小平均能量（RMS约10），更安静的帧直接判为非语音
+	entropyMinEnergy = 100.0
+	// entropyInitTime 开头视为噪声、用于初始化噪声功率谱的时长
+	entropyInitTime = 100 * time.Millisecond
+	// entropyInitNoise 噪声谱熵的初始值下限
+	entropyInitNoise = 0.85
+	// 噪声谱熵的跟踪时间常数：上升快，下降慢
+	entropyRiseTime = 50 * time.Millisecond
+	entropyFallTime = 2 * time.Second
+	// spectrumSmoothTime 功率谱的时间平滑常数，降低单帧周期图的方差
+	spectrumSmoothTime = 20 * time.Millisecond
+	// 噪声功率谱跟踪平滑功率谱的下包络：低于估计时按noiseFallTime快速下降，
+	// 高于估计时每经过noiseRiseTime最多增大e倍（约4.3dB），既不追随语音，噪声变大后也能恢复
+	noiseFallTime = 20 * time.Millisecond
+	noiseRiseTime = time.Second
+)
+
+// entropyMargins 各模式下判为语音所需的谱熵下降量（模式越高越严格）
+var entropyMargins = [4]float64{0.02, 0.03, 0.045, 0.06}
+
+// EntropyVAD 谱熵语音检测器
+//
+// 对每帧加Hann窗做FFT，以噪声功率谱估计对250-3750Hz频带内的功率谱白化后
+// 计算其熵（0-1）：平稳噪声白化后接近均匀分布，熵接近1；语音的共振峰与谐波
+// 使熵明显下降。谱熵低于自适应噪声谱熵一定幅度时判为语音。
+// EntropyVAD有状态且不是并发安全的，实现Detector接口
+type EntropyVAD struct {
+	margin float64
+
+	noiseEntropy float64       // 噪声谱熵估计，0表示尚未初始化
+	elapsed      time.Duration // 已处理的时长
+	analyzed     int           // 参与噪声功率谱估计的帧数
+
+	// 按帧长度缓存的窗函数、FFT缓冲区与频带内的功率谱
+	frameLen int
+	window   []float64
+	fft      *RealFFT64
+	in, spec []float64 // FFT的输入与CCS格式输出
+	power    []float64 // 时间平滑后的功率谱
+	noise    []float64 // 噪声功率谱估计
+}
+
+// NewEntropyVAD 创建谱熵语音检测器
+//
+// 参数:
+//   - mode: 激进度模式（0-3），与VAD的模式含义一致
+//
+// 返回:
+//   - *EntropyVAD: 检测器实例
+//   - error: 错误信息
+func NewEntropyVAD(mode int) (*EntropyVAD, error) {
+	if mode < 0 || mode > 3 {
+		return nil, ErrInvalidMode
+	}
+	return &EntropyVAD{margin: entropyMargins[mode]}, nil
+}
+
+// IsSpeech 判断一帧是否为语音，实现Detector接口
+//
+// 参数:
+//   - frame: 16位小端序PCM，长度须对应10ms、20ms或30ms
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
+//
+// 返回:
+//   - bool: true表示检测到语音
+//   - error: 参数无效时返回错误
+func (e *EntropyVAD) IsSpeech(frame []byte, sampleRate int) (bool, error) {
+	if !isValidSampleRate(sampleRate) {
+		return false, ErrInvalidSampleRate
+	}
+	n := len(frame) / 2
+	if !ValidRateAndFrameLength(sampleRate, n) {
+		return false, fmt.Errorf("%w: got %d samples at %d Hz", ErrInvalidFrameLength, n, sampleRate)
+	}
+
+	frameDur := time.Duration(n) * time.Second / time.Duration(sampleRate)
+	h, ok := e.entropy(frame, sampleRate, frameDur.Seconds())
+	e.elapsed += frameDur
+	if !ok || e.elapsed <= entropyInitTime {
+		return false, nil
+	}
+
+	if e.noiseEntropy == 0 {
+		e.noiseEntropy = math.Max(h, entropyInitNoise)
+	}
+	speech := h < e.noiseEntropy-e.margin
+
+	rate := smoothRate(frameDur.Seconds(), entropyFallTime)
+	if h > e.noiseEntropy {
+		rate = smoothRate(frameDur.Seconds(), entropyRiseTime)
+	}
+	e.noiseEntropy += rate * (h - e.noiseEntropy)
+	return speech, nil
+}
+
+// SetMode 修改激进度模式（0-3，含义同NewEntropyVAD），只更新谱熵裕量，保留自适应状态
+func (e *EntropyVAD) SetMode(mode int) error {
+	if mode < 0 || mode > 3 {
+		return ErrInvalidMode
+	}
+	e.margin = entropyMargins[mode]
+	return nil
+}
+
+// Reset 清除噪声谱熵与噪声功率谱估计
+func (e *EntropyVAD) Reset() {
+	e.noiseEntropy = 0
+	e.elapsed = 0
+	e.analyzed = 0
+	e.frameLen = 0
+}
+
+// smoothRate 时长为dt的一帧对时间常数为tc的一阶平滑的更新系数
+func smoothRate(dt float64, tc time.Duration) float64 {
+	return 1 - math.Exp(-dt/tc.Seconds())
+}
+
+// entropy 计算一帧白化后的归一化谱熵，能量过低时返回false
+func (e *EntropyVAD) entropy(frame []byte, sampleRate int, frameDur float64) (float64, bool) {
+	n := len(frame) / 2
+	order := bits.Len(uint(n - 1))
+	size := 1 << order
+	lo := int(math.Ceil(entropyLowHz * float64(size) / float64(sampleRate)))
+	hi := min(int(entropyHighHz*float64(size)/float64(sampleRate)), size/2)
+	if n != e.frameLen {
+		e.frameLen = n
+		e.analyzed = 0
+		e.window = GenerateWindow(n, HannWindow)
+		e.fft = CreateRealFFT64(order)
+		e.in = make([]float64, size)
+		e.spec = make([]float64, size+2)
+		e.power = make([]float64, hi-lo+1)
+		e.noise = make([]float64, hi-lo+1)
+	}
+
+	var mean, energy float64
+	for i := 0; i < n; i++ {
+		mean += float64(int16(binary.LittleEndian.Uint16(frame[i*2:])))
+	}
+	mean /= float64(n)
+	for i := 0; i < n; i++ {
+		v := float64(int16(binary.LittleEndian.Uint16(frame[i*2:]))) - mean
+		energy += v * v
+		e.in[i] = v * e.window[i]
+	}
+	if energy/float64(n) < entropyMinEnergy {
+		return 0, false
+	}
+	clear(e.in[n:])
+	e.fft.RealForwardFFT(e.in, e.spec)
+
+	// 平滑功率谱并更新噪声估计（开头entropyInitTime内取平均）
+	smooth := smoothRate(frameDur, spectrumSmoothTime)
+	fall := smoothRate(frameDur, noiseFallTime)
+	rise := math.Exp(frameDur / noiseRiseTime.Seconds())
+	initializing := e.analyzed == 0 || e.elapsed < entropyInitTime
+	e.analyzed++
+	for i := range e.power {
+		k := lo + i
+		p := e.spec[2*k]*e.spec[2*k] + e.spec[2*k+1]*e.spec[2*k+1]
+		if e.analyzed == 1 {
+			e.power[i], e.noise[i] = p, p
+			continue
+		}
+		e.power[i] += smooth * (p - e.power[i])
+		switch {
+		case initializing:
+			e.noise[i] += (p - e.noise[i]) / float64(e.analyzed)
+		case e.power[i] < e.noise[i]:
+			e.noise[i] += fall * (e.power[i] - e.noise[i])
+		default:
+			e.noise[i] = math.Min(e.power[i], e.noise[i]*rise)
+		}
+	}
+
+	// 白化后的功率谱归一化为概率分布
+	var total float64
+	for i, p := range e.power {
+		total += p / (e.noise[i] + 1)
+	}
+	if total == 0 {
+		return 0, false
+	}
+	var h float64
+	for i, p := range e.power {
+		if q := p / (e.noise[i] + 1) / total; q > 0 {
+			h -= q * math.Log(q)
+		}
+	}
+	return h / math.Log(float64(len(e.power))), true
+}
@@ -0,0 +1,104 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// harmonicNoise 生成白噪声（标准差2000），并在[start, end)内叠加140Hz的谐波
+func harmonicNoise(rate int, total, start, end time.Duration) []byte {
+	rng := rand.New(rand.NewSource(3))
+	n := int(total.Seconds() * float64(rate))
+	from, to := int(start.Seconds()*float64(rate)), int(end.Seconds()*float64(rate))
+	pcm := make([]byte, 0, n*2)
+	for i := 0; i < n; i++ {
+		v := rng.NormFloat64() * 2000
+		if i >= from && i < to {
+			for h := 1; h <= 8; h++ {
+				v += 3000 / float64(h) * math.Sin(2*math.Pi*140*float64(h)*float64(i)/float64(rate))
+			}
+		}
+		v = math.Max(math.MinInt16, math.Min(math.MaxInt16, v))
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(v)))
+	}
+	return pcm
+}
+
+// TestEntropyVAD 测试谱熵检测器在白噪声中检测谐波，且不把噪声判为语音
+func TestEntropyVAD(t *testing.T) {
+	pcm := harmonicNoise(16000, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+	e, err := NewEntropyVAD(3)
+	if err != nil {
+		t.Fatalf("创建EntropyVAD失败: %v", err)
+	}
+
+	var noise, speech int
+	for i := 0; i+960 <= len(pcm); i += 960 {
+		ok, err := e.IsSpeech(pcm[i:i+960], 16000)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		switch at := time.Duration(i/32) * time.Millisecond; {
+		case ok && at < 2*time.Second:
+			noise++
+		case ok && at < 2500*time.Millisecond:
+			speech++
+		}
+	}
+	if noise > 2 {
+		t.Errorf("噪声中不应检测到语音, 误检%d帧", noise)
+	}
+	if speech < 12 {
+		t.Errorf("谐波段的16帧中应大部分为语音, 得到%d帧", speech)
+	}
+
+	if _, err := e.IsSpeech(make([]byte, 100), 16000); err == nil {
+		t.Error("无效帧长度应返回错误")
+	}
+	if _, err := e.IsSpeech(make([]byte, 960), 44100); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := NewEntropyVAD(4); err != ErrInvalidMode {
+		t.Errorf("应返回ErrInvalidMode, 得到%v", err)
+	}
+}
+
+// TestWithAlgorithm 测试StreamVAD按选项使用谱熵检测
+func TestWithAlgorithm(t *testing.T) {
+	pcm := harmonicNoise(16000, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+	svad, err := NewStreamVADWithOptions(WithStreamMode(3), WithAlgorithm(Entropy))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if _, err := svad.Process(pcm); err != nil {
+		t.Fatalf("处理失败: %v", err)
+	}
+	svad.Flush()
+
+	speech := svad.FilterSpeechSegments()
+	if len(speech) == 0 {
+		t.Fatal("应检测到2s-2.5s间的语音")
+	}
+	for _, seg := range speech {
+		if seg.Start < 1900*time.Millisecond || seg.End > 2700*time.Millisecond {
+			t.Errorf("只应在2s-2.5s间检测到语音, 得到%v-%v", seg.Start, seg.End)
+		}
+	}
+
+	if err := svad.Reset(); err != nil {
+		t.Fatalf("重置失败: %v", err)
+	}
+	if e := svad.detector.(*EntropyVAD); e.elapsed != 0 {
+		t.Error("重置后谱熵检测器应重新学习噪声")
+	}
+
+	if _, err := NewStreamVADWithOptions(WithAlgorithm(Algorithm(9))); err != ErrInvalidAlgorithm {
+		t.Errorf("应返回ErrInvalidAlgorithm, 得到%v", err)
+	}
+	if Entropy.String() != "entropy" || GMM.String() != "gmm" {
+		t.Errorf("算法名称错误: %v %v", GMM, Entropy)
+	}
+}
@@ -0,0 +1,127 @@
+package webrtcvad
+
+import "math"
+
+// environment.go 由GMM噪声模型的各频带均值判断环境噪声水平，
+// 便于应用提示"环境嘈杂"或自动选择预设
+
+// NoiseClass 环境噪声等级
+type NoiseClass int
+
+const (
+	// Quiet 安静：背景噪声很低（安静的房间、近讲麦克风），或尚未见过有效音频
+	Quiet NoiseClass = iota
+	// Moderate 一般：办公室、家庭等有一定背景噪声的环境
+	Moderate
+	// Noisy 嘈杂：街道、车内、开放办公区等，建议提高激进度或启用降噪
+	Noisy
+)
+
+// String 返回噪声等级的名称
+func (c NoiseClass) String() string {
+	switch c {
+	case Quiet:
+		return "quiet"
+	case Moderate:
+		return "moderate"
+	case Noisy:
+		return "noisy"
+	default:
+		return "unknown"
+	}
+}
+
+// 噪声总电平的分级门限（特征刻度，dB）。白噪声下约对应-57dBFS与-42dBFS
+const (
+	moderateNoiseLevel = 50
+	noisyNoiseLevel    = 65
+)
+
+// NoiseStats GMM噪声模型的各频带统计
+type NoiseStats struct {
+	// Means 各频带噪声高斯的加权均值，即10*log10(能量)，与Features同一刻度（dB，非Q4）。
+	// 频带依次为 80-250, 250-500, 500-1000, 1000-2000, 2000-3000, 3000-4000 Hz
+	Means [NumChannels]float64
+	// Frames 参与模型更新的帧数（能量超过最小阈值的帧），0表示尚未见过有效音频，
+	// 此时Means仍为初始模型的值
+	Frames int
+}
+
+// Environment 环境噪声的判断结果
+type Environment struct {
+	// Class 噪声等级
+	Class NoiseClass
+	// Level 各频带噪声能量之和（dB，特征刻度），Frames为0时为0
+	Level float64
+	// DominantBand 噪声均值最高的频带（0-5），低频带为主通常是空调、车辆等
+	// 低频轰鸣，高频带为主通常是风扇、嘶声
+	DominantBand int
+}
+
+// NoiseStats 返回GMM噪声模型的各频带统计，用于ClassifyEnvironment；未初始化时返回零值
+func (v *VAD) NoiseStats() NoiseStats {
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return NoiseStats{}
+	}
+	stats := NoiseStats{Frames: int(v.inst.frameCounter)}
+	for c := 0; c < kNumChannels; c++ {
+		var sum int32
+		for k := 0; k < kNumGaussians; k++ {
+			i := c + k*kNumChannels
+			sum += int32(v.inst.noiseMeans[i]) * int32(v.inst.noiseWeights[i])
+		}
+		stats.Means[c] = float64(sum) / (1 << 14) // 均值Q7 × 权重Q7
+	}
+	return stats
+}
+
+// NoiseStats 返回检测所用GMM噪声模型的各频带统计
+//
+// 检测算法不含GMM（谱熵、能量、LTSD或外部模型）时第二个返回值为false
+func (s *StreamVAD) NoiseStats() (NoiseStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gmm := s.gmm()
+	if gmm == nil {
+		return NoiseStats{}, false
+	}
+	return gmm.NoiseStats(), true
+}
+
+// ClassifyEnvironment 由噪声模型的各频带均值判断环境噪声等级与主导频带
+//
+// 以各频带噪声能量之和分级：低于50dB为Quiet，50-65dB为Moderate，不低于65dB为Noisy
+// （特征刻度，白噪声下约对应-57dBFS与-42dBFS）。噪声模型需要约1-2秒的音频才能收敛，
+// 在此之前的结果偏向初始模型；数字静音不更新模型，stats.Frames为0时判为Quiet。
+//
+// 参数:
+//   - stats: VAD.NoiseStats或StreamVAD.NoiseStats的返回值
+//
+// 返回:
+//   - Environment: 噪声等级、总电平与主导频带
+func ClassifyEnvironment(stats NoiseStats) Environment {
+	if stats.Frames == 0 {
+		return Environment{Class: Quiet}
+	}
+
+	var env Environment
+	var power float64
+	for c, m := range stats.Means {
+		power += math.Pow(10, m/10)
+		if m > stats.Means[env.DominantBand] {
+			env.DominantBand = c
+		}
+	}
+	env.Level = 10 * math.Log10(power)
+
+	switch {
+	case env.Level >= noisyNoiseLevel:
+		env.Class = Noisy
+	case env.Level >= moderateNoiseLevel:
+		env.Class = Moderate
+	default:
+		env.Class = Quiet
+	}
+	return env
+}
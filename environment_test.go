@@ -0,0 +1,73 @@
+package webrtcvad
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// gaussianNoise 生成标准差为amp的高斯噪声（16kHz），lowpass为true时经一阶低通使能量集中在低频
+func gaussianNoise(amp float64, samples int, lowpass bool) []byte {
+	rng := rand.New(rand.NewSource(1))
+	pcm := make([]int16, samples)
+	var y float64
+	for i := range pcm {
+		x := rng.NormFloat64() * amp
+		if lowpass {
+			y = 0.97*y + 0.15*x
+			x = y
+		}
+		pcm[i] = int16(x)
+	}
+	return toPCM(pcm)
+}
+
+// TestClassifyEnvironment 测试按噪声模型判断环境噪声等级与主导频带
+func TestClassifyEnvironment(t *testing.T) {
+	tests := []struct {
+		name string
+		amp  float64
+		want NoiseClass
+	}{
+		{"-70dBFS", 10, Quiet},
+		{"-50dBFS", 100, Moderate},
+		{"-30dBFS", 1000, Noisy},
+	}
+	for _, tt := range tests {
+		vad, _ := New(1)
+		pcm := gaussianNoise(tt.amp, 16000*5, false)
+		for i := 0; i+320 <= len(pcm); i += 320 {
+			vad.IsSpeech(pcm[i:i+320], 16000)
+		}
+		env := ClassifyEnvironment(vad.NoiseStats())
+		if env.Class != tt.want {
+			t.Errorf("%s: 应为%v, 得到%v（%.1f dB）", tt.name, tt.want, env.Class, env.Level)
+		}
+	}
+
+	// 低频噪声的主导频带为最低频带
+	svad, _ := NewStreamVADWithOptions()
+	svad.Process(gaussianNoise(300, 16000*5, true))
+	stats, ok := svad.NoiseStats()
+	if !ok {
+		t.Fatal("GMM算法应返回噪声统计")
+	}
+	if env := ClassifyEnvironment(stats); env.DominantBand != 0 || stats.Frames == 0 {
+		t.Errorf("低频噪声的主导频带应为0, 得到%+v", env)
+	}
+
+	// 数字静音不更新模型，判为安静
+	vad, _ := New(1)
+	vad.IsSpeech(make([]byte, 320), 16000)
+	if env := ClassifyEnvironment(vad.NoiseStats()); env.Class != Quiet || env.Level != 0 {
+		t.Errorf("数字静音应判为Quiet, 得到%+v", env)
+	}
+	if stats := (&VAD{}).NoiseStats(); stats != (NoiseStats{}) {
+		t.Errorf("未初始化时噪声统计应为零值, 得到%+v", stats)
+	}
+
+	if svad, _ := NewStreamVADWithOptions(WithAlgorithm(Energy)); svad != nil {
+		if _, ok := svad.NoiseStats(); ok {
+			t.Error("能量检测没有GMM噪声模型")
+		}
+	}
+}
@@ -8,15 +8,105 @@ var (
 	// ErrInvalidMode 无效的VAD模式
 	ErrInvalidMode = errors.New("mode must be 0-3")
 
+	// ErrModeUnsupported 检测器不支持激进度模式
+	ErrModeUnsupported = errors.New("detector does not support modes")
+
+	// ErrInvalidAlgorithm 无效的检测算法
+	ErrInvalidAlgorithm = errors.New("unknown detection algorithm")
+
+	// ErrInvalidDetector 无效的检测器
+	ErrInvalidDetector = errors.New("detector must not be nil")
+
+	// ErrInvalidHybridConfig 无效的组合检测器投票配置
+	ErrInvalidHybridConfig = errors.New("invalid hybrid detector config")
+
+	// ErrInvalidModelConfig 无效的外部模型检测器配置
+	ErrInvalidModelConfig = errors.New("invalid model detector config")
+
+	// ErrInvalidMinimumStats 无效的最小值统计配置
+	ErrInvalidMinimumStats = errors.New("invalid minimum statistics config")
+
+	// ErrInvalidSmoother 无效的判决平滑器配置
+	ErrInvalidSmoother = errors.New("invalid smoother config")
+
+	// ErrInvalidBatchConfig 无效的批量处理配置
+	ErrInvalidBatchConfig = errors.New("invalid batch processor config")
+
+	// ErrInvalidAggressiveness 无效的连续激进度
+	ErrInvalidAggressiveness = errors.New("aggressiveness must be within [0, 1]")
+
+	// ErrInvalidThresholds 无效的似然比检验阈值
+	ErrInvalidThresholds = errors.New("thresholds must not be negative")
+
+	// ErrInvalidHangover 无效的迟滞帧数
+	ErrInvalidHangover = errors.New("hangover frames must not be negative")
+
+	// ErrInvalidModel 无效的GMM模型参数
+	ErrInvalidModel = errors.New("invalid GMM model")
+
 	// ErrInvalidSampleRate 无效的采样率
-	ErrInvalidSampleRate = errors.New("sample rate must be 8000, 16000, 32000, or 48000 Hz")
+	ErrInvalidSampleRate = errors.New("sample rate must be 8000, 16000, 24000, 32000, or 48000 Hz")
 
 	// ErrInvalidFrameLength 无效的帧长度
 	ErrInvalidFrameLength = errors.New("frame length must correspond to 10, 20, or 30 ms")
 
+	// ErrInvalidRatio 无效的比例
+	ErrInvalidRatio = errors.New("ratio must be within [0, 1]")
+
+	// ErrInvalidEnergyGate 无效的能量门限
+	ErrInvalidEnergyGate = errors.New("energy gate must be a negative dBFS value or 0")
+
+	// ErrInvalidDuration 无效的时间长度
+	ErrInvalidDuration = errors.New("duration must not be negative")
+
+	// ErrInvalidFrameCount 无效的帧数
+	ErrInvalidFrameCount = errors.New("frame count must be at least 1")
+
+	// ErrInvalidCaptureLimit 无效的音频捕获上限
+	ErrInvalidCaptureLimit = errors.New("capture limit must be positive")
+
+	// ErrInvalidOverflowPolicy 无效的输入缓冲区溢出策略
+	ErrInvalidOverflowPolicy = errors.New("unknown overflow policy")
+
+	// ErrBufferOverflow 单次写入的数据超出输入缓冲区容量
+	ErrBufferOverflow = errors.New("input buffer overflow")
+
+	// ErrStreamClosed 流已关闭
+	ErrStreamClosed = errors.New("stream closed")
+
+	// ErrNoAudio 片段没有音频数据
+	ErrNoAudio = errors.New("segment has no captured audio")
+
+	// ErrUnsupportedFormat 音频不是16位单声道PCM
+	ErrUnsupportedFormat = errors.New("audio must be 16-bit mono PCM")
+
+	// ErrInvalidRTPPacket 无效的RTP包
+	ErrInvalidRTPPacket = errors.New("invalid RTP packet")
+
+	// ErrUnsupportedPayloadType 不支持的RTP负载类型
+	ErrUnsupportedPayloadType = errors.New("unsupported RTP payload type")
+
 	// ErrNotInitialized VAD未初始化
 	ErrNotInitialized = errors.New("VAD not initialized")
 
+	// ErrUnsupportedConversion 不支持的采样率转换
+	ErrUnsupportedConversion = errors.New("unsupported sample rate conversion")
+
+	// ErrPartialBlock 输入不是重采样块长度的整数倍
+	ErrPartialBlock = errors.New("input length must be a multiple of the resampler block size")
+
 	// ErrBufferTooSmall 缓冲区太小
 	ErrBufferTooSmall = errors.New("buffer too small")
+
+	// ErrInvalidCutoff 无效的截止频率
+	ErrInvalidCutoff = errors.New("cutoff frequency must be within (0, sampleRate/2)")
+
+	// ErrInvalidFilter 滤波器为nil
+	ErrInvalidFilter = errors.New("filter must not be nil")
+
+	// ErrInvalidTaps FIR滤波器系数为空
+	ErrInvalidTaps = errors.New("FIR filter needs at least one tap")
+
+	// ErrInvalidSTFTConfig 无效的STFT配置
+	ErrInvalidSTFTConfig = errors.New("invalid STFT config: need 0 < hop <= frame <= FFT size (power of 2, at least 4)")
 )
@@ -1,6 +0,0 @@
-module github.com/bytectlgo/webrtcvad-go/example
-
-go 1.25.1
-
-replace github.com/bytectlgo/webrtcvad-go => ../
-require github.com/bytectlgo/webrtcvad-go v0.0.0-00010101000000-000000000000
@@ -0,0 +1,115 @@
+package webrtcvad
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// export.go 提供检测结果的导出，便于其他语言编写的下游工具使用
+
+// Segments 片段列表，提供各种导出格式
+//
+// 可由GetSegments等返回的[]VoiceSegment直接转换：
+//
+//	webrtcvad.Segments(svad.GetSegments()).WriteJSON(os.Stdout)
+type Segments []VoiceSegment
+
+// segmentJSON 片段的JSON表示，时间单位为秒
+type segmentJSON struct {
+	Start  float64 `json:"start"`
+	End    float64 `json:"end"`
+	Speech bool    `json:"speech"`
+}
+
+// MarshalJSON 编码为JSON数组，实现json.Marshaler
+//
+// 格式为 [{"start":1.23,"end":4.56,"speech":true}]，时间以秒为单位；
+// 音频数据与绝对时间戳不输出，空列表编码为[]
+func (s Segments) MarshalJSON() ([]byte, error) {
+	out := make([]segmentJSON, len(s))
+	for i, seg := range s {
+		out[i] = segmentJSON{
+			Start:  seconds(seg.Start),
+			End:    seconds(seg.End),
+			Speech: seg.IsSpeech,
+		}
+	}
+	return json.Marshal(out)
+}
+
+// WriteJSON 将片段以JSON数组写入w，末尾附加换行
+func (s Segments) WriteJSON(w io.Writer) error {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteAudacityLabels 将语音片段写为Audacity标签文件
+//
+// 每个语音片段一行 "start\tend\tspeech"（秒，6位小数），非语音片段不输出。
+// 在Audacity中通过“文件 > 导入 > 标签”载入，即可在波形上核对VAD边界
+func (s Segments) WriteAudacityLabels(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, seg := range s {
+		if seg.IsSpeech {
+			fmt.Fprintf(bw, "%.6f\t%.6f\tspeech\n", seconds(seg.Start), seconds(seg.End))
+		}
+	}
+	return bw.Flush()
+}
+
+// CueText 字幕导出中语音片段使用的占位文本
+const CueText = "[speech]"
+
+// WriteVTT 将语音片段写为WebVTT字幕
+//
+// 每个语音片段对应一条文本为CueText的字幕，便于与转写结果对齐，
+// 或在播放器中显示“正在说话”提示
+func (s Segments) WriteVTT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n")
+	for _, seg := range s {
+		if seg.IsSpeech {
+			fmt.Fprintf(bw, "\n%s --> %s\n%s\n", cueTime(seg.Start, '.'), cueTime(seg.End, '.'), CueText)
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteSRT 将语音片段写为SubRip（SRT）字幕
+//
+// 字幕从1开始编号，文本为CueText
+func (s Segments) WriteSRT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	n := 0
+	for _, seg := range s {
+		if !seg.IsSpeech {
+			continue
+		}
+		if n > 0 {
+			bw.WriteByte('\n')
+		}
+		n++
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n", n, cueTime(seg.Start, ','), cueTime(seg.End, ','), CueText)
+	}
+	return bw.Flush()
+}
+
+// cueTime 将时长格式化为字幕时间戳 HH:MM:SS.mmm（sep为毫秒分隔符）
+func cueTime(d time.Duration, sep byte) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// seconds 将时长转换为秒
+//
+// 与Duration.Seconds不同，整体做一次除法，4560ms得到4.56而非4.5600000000000005
+func seconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Second)
+}
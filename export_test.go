@@ -0,0 +1,83 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestSegmentsJSON 测试片段的JSON导出
+func TestSegmentsJSON(t *testing.T) {
+	segs := Segments{
+		{Start: 0, End: 1230 * time.Millisecond, IsSpeech: false, Audio: []byte{1, 2}},
+		{Start: 1230 * time.Millisecond, End: 4560 * time.Millisecond, IsSpeech: true},
+	}
+
+	var buf bytes.Buffer
+	if err := segs.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"start":0,"end":1.23,"speech":false},{"start":1.23,"end":4.56,"speech":true}]` + "\n"
+	if buf.String() != want {
+		t.Errorf("JSON应为%s, 得到%s", want, buf.String())
+	}
+
+	// 嵌入其他结构时同样使用该格式
+	data, err := json.Marshal(struct {
+		Segments Segments `json:"segments"`
+	}{Segments(nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"segments":[]}` {
+		t.Errorf("空列表应编码为[], 得到%s", data)
+	}
+}
+
+// TestSegmentsAudacityLabels 测试Audacity标签导出
+func TestSegmentsAudacityLabels(t *testing.T) {
+	segs := Segments{
+		{Start: 0, End: 500 * time.Millisecond},
+		{Start: 500 * time.Millisecond, End: 1730 * time.Millisecond, IsSpeech: true},
+		{Start: 1730 * time.Millisecond, End: 2 * time.Second},
+		{Start: 2 * time.Second, End: 3010 * time.Millisecond, IsSpeech: true},
+	}
+
+	var buf bytes.Buffer
+	if err := segs.WriteAudacityLabels(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "0.500000\t1.730000\tspeech\n2.000000\t3.010000\tspeech\n"
+	if buf.String() != want {
+		t.Errorf("标签应为%q, 得到%q", want, buf.String())
+	}
+}
+
+// TestSegmentsSubtitles 测试WebVTT与SRT字幕导出
+func TestSegmentsSubtitles(t *testing.T) {
+	segs := Segments{
+		{Start: 0, End: 1230 * time.Millisecond},
+		{Start: 1230 * time.Millisecond, End: 4560 * time.Millisecond, IsSpeech: true},
+		{Start: 4560 * time.Millisecond, End: time.Hour + 2*time.Minute},
+		{Start: time.Hour + 2*time.Minute, End: time.Hour + 2*time.Minute + 3005*time.Millisecond, IsSpeech: true},
+	}
+
+	var vtt bytes.Buffer
+	if err := segs.WriteVTT(&vtt); err != nil {
+		t.Fatal(err)
+	}
+	want := "WEBVTT\n\n00:00:01.230 --> 00:00:04.560\n[speech]\n\n01:02:00.000 --> 01:02:03.005\n[speech]\n"
+	if vtt.String() != want {
+		t.Errorf("WebVTT应为%q, 得到%q", want, vtt.String())
+	}
+
+	var srt bytes.Buffer
+	if err := segs.WriteSRT(&srt); err != nil {
+		t.Fatal(err)
+	}
+	want = "1\n00:00:01,230 --> 00:00:04,560\n[speech]\n\n2\n01:02:00,000 --> 01:02:03,005\n[speech]\n"
+	if srt.String() != want {
+		t.Errorf("SRT应为%q, 得到%q", want, srt.String())
+	}
+}
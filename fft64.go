@@ -0,0 +1,207 @@
+package webrtcvad
+
+import (
+	"math"
+	"math/bits"
+)
+
+// fft64.go 实现与定点FFT接口一致的浮点FFT
+//
+// 定点FFT受int16精度的限制，适合VAD核心；谱熵检测、频谱分析等浮点域功能使用本文件的实现。
+// 与定点版本不同，正变换不缩放，逆变换除以N，因此正逆变换互为精确的逆
+
+// kMaxFFT64Order 浮点FFT的最大阶数
+const kMaxFFT64Order = 24
+
+// ComplexFFT64 执行浮点复数FFT
+//
+// frfi: 输入/输出数组，交替存储实部和虚部 [re0, im0, re1, im1, ...]
+// stages: FFT阶数（2^stages = FFT长度，最大2^24）
+//
+// 返回：0=成功，-1=失败
+func ComplexFFT64(frfi []float64, stages int) int {
+	if stages < 0 || stages > kMaxFFT64Order || len(frfi) < 2<<uint(stages) {
+		return -1
+	}
+	fft64(frfi[:2<<uint(stages)], -1)
+	return 0
+}
+
+// ComplexIFFT64 执行浮点复数逆FFT（结果已除以FFT长度）
+//
+// frfi: 输入/输出数组，交替存储实部和虚部
+// stages: FFT阶数
+//
+// 返回：0=成功（无需额外缩放，对应定点版本的缩放因子0），-1=失败
+func ComplexIFFT64(frfi []float64, stages int) int {
+	if stages < 0 || stages > kMaxFFT64Order || len(frfi) < 2<<uint(stages) {
+		return -1
+	}
+	n := 1 << uint(stages)
+	data := frfi[:2*n]
+	fft64(data, 1)
+	scale := 1 / float64(n)
+	for i := range data {
+		data[i] *= scale
+	}
+	return 0
+}
+
+// fft64 交织格式的就地FFT，sign为-1时为正变换，为1时为逆变换（不缩放）
+//
+// 位反转重排后以基4蝶形每次合并两级基2，阶数为奇数时先做一级基2。
+// 每个基4蝶形只需3次复数乘法（两级基2需要4次），且遍历数据的次数减半
+func fft64(data []float64, sign float64) {
+	n := len(data) / 2
+	// 位反转重排
+	for i, j := 0, 0; i < n; i++ {
+		if j > i {
+			data[2*i], data[2*j] = data[2*j], data[2*i]
+			data[2*i+1], data[2*j+1] = data[2*j+1], data[2*i+1]
+		}
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+	}
+
+	l := 1
+	if bits.TrailingZeros(uint(n))%2 == 1 {
+		// 旋转因子均为1的基2级
+		for i := 0; i < n; i += 2 {
+			j := i + 1
+			qr, qi := data[2*i], data[2*i+1]
+			data[2*i], data[2*i+1] = qr+data[2*j], qi+data[2*j+1]
+			data[2*j], data[2*j+1] = qr-data[2*j], qi-data[2*j+1]
+		}
+		l = 2
+	}
+
+	// 基4级：x0..x3位于i、i+l、i+2l、i+3l，W = e^{sign·2πi/(4l)}，
+	// c1 = x1·W^{2m}，c2 = x2·W^m，c3 = x3·W^{3m}，
+	// 输出为x0+c1±(c2+c3)与x0-c1±j(c2-c3)，其中j = e^{sign·iπ/2}
+	for ; l < n; l <<= 2 {
+		step := sign * math.Pi / float64(2*l)
+		for m := 0; m < l; m++ {
+			w1i, w1r := math.Sincos(2 * step * float64(m))
+			w2i, w2r := math.Sincos(step * float64(m))
+			w3i, w3r := math.Sincos(3 * step * float64(m))
+			for i := m; i < n; i += 4 * l {
+				i1, i2, i3 := i+l, i+2*l, i+3*l
+				x0r, x0i := data[2*i], data[2*i+1]
+				c1r := w1r*data[2*i1] - w1i*data[2*i1+1]
+				c1i := w1r*data[2*i1+1] + w1i*data[2*i1]
+				c2r := w2r*data[2*i2] - w2i*data[2*i2+1]
+				c2i := w2r*data[2*i2+1] + w2i*data[2*i2]
+				c3r := w3r*data[2*i3] - w3i*data[2*i3+1]
+				c3i := w3r*data[2*i3+1] + w3i*data[2*i3]
+
+				ar, ai := x0r+c1r, x0i+c1i               // x0 + c1
+				br, bi := x0r-c1r, x0i-c1i               // x0 - c1
+				sr, si := c2r+c3r, c2i+c3i               // c2 + c3
+				dr, di := sign*(c3i-c2i), sign*(c2r-c3r) // j·(c2 - c3)
+
+				data[2*i], data[2*i+1] = ar+sr, ai+si
+				data[2*i2], data[2*i2+1] = ar-sr, ai-si
+				data[2*i1], data[2*i1+1] = br+dr, bi+di
+				data[2*i3], data[2*i3+1] = br-dr, bi-di
+			}
+		}
+	}
+}
+
+// RealFFT64 浮点实数FFT结构
+type RealFFT64 struct {
+	order  int       // FFT阶数
+	n      int       // FFT长度 = 2^order
+	work   []float64 // 工作缓冲区（n/2个复数）
+	wr, wi []float64 // 后处理的旋转因子 e^{-2πik/n}，k < n/2
+}
+
+// CreateRealFFT64 创建浮点实数FFT对象
+//
+// order: FFT阶数（1 < order <= 24）
+// 返回：RealFFT64对象或nil
+func CreateRealFFT64(order int) *RealFFT64 {
+	if order <= 1 || order > kMaxFFT64Order {
+		return nil
+	}
+	n := 1 << uint(order)
+	f := &RealFFT64{
+		order: order,
+		n:     n,
+		work:  make([]float64, n),
+		wr:    make([]float64, n/2),
+		wi:    make([]float64, n/2),
+	}
+	for k := range f.wr {
+		f.wi[k], f.wr[k] = math.Sincos(-2 * math.Pi * float64(k) / float64(n))
+	}
+	return f
+}
+
+// RealForwardFFT 浮点实数前向FFT
+//
+// realDataIn: 实数输入 (长度 2^order)
+// complexDataOut: 复数输出 (长度 2^order + 2)，使用CCS格式（频点0到N/2的实部、虚部交替）
+//
+// 返回：0=成功，-1=失败
+func (fft *RealFFT64) RealForwardFFT(realDataIn []float64, complexDataOut []float64) int {
+	if len(realDataIn) < fft.n || len(complexDataOut) < fft.n+2 {
+		return -1
+	}
+
+	// 偶、奇样本分别作为实部、虚部做N/2点复数FFT
+	half := fft.n / 2
+	copy(fft.work, realDataIn[:fft.n])
+	fft64(fft.work, -1)
+
+	// 拆分：X[k] = E[k] + W^k·O[k]，E、O由Z[k]与Z[N/2-k]的共轭得到
+	for k := 0; k <= half; k++ {
+		zr, zi := fft.work[2*(k%half)], fft.work[2*(k%half)+1]
+		cr, ci := fft.work[2*((half-k)%half)], -fft.work[2*((half-k)%half)+1]
+		er, ei := (zr+cr)/2, (zi+ci)/2
+		or, oi := (zi-ci)/2, -(zr-cr)/2
+		wr, wi := 1.0, 0.0
+		if k < half {
+			wr, wi = fft.wr[k], fft.wi[k]
+		} else {
+			wr = -1
+		}
+		complexDataOut[2*k] = er + wr*or - wi*oi
+		complexDataOut[2*k+1] = ei + wr*oi + wi*or
+	}
+	return 0
+}
+
+// RealInverseFFT 浮点实数逆FFT（结果已除以FFT长度）
+//
+// complexDataIn: 复数输入（CCS格式，长度 2^order + 2）
+// realDataOut: 实数输出（长度 2^order）
+//
+// 返回：0=成功（无需额外缩放），-1=失败
+func (fft *RealFFT64) RealInverseFFT(complexDataIn []float64, realDataOut []float64) int {
+	if len(complexDataIn) < fft.n+2 || len(realDataOut) < fft.n {
+		return -1
+	}
+
+	// 合并：Z[k] = E[k] + i·O[k]，E = (X[k] + X*[N/2-k])/2，O = (X[k] - X*[N/2-k])·W^{-k}/2
+	half := fft.n / 2
+	for k := 0; k < half; k++ {
+		xr, xi := complexDataIn[2*k], complexDataIn[2*k+1]
+		cr, ci := complexDataIn[2*(half-k)], -complexDataIn[2*(half-k)+1]
+		er, ei := (xr+cr)/2, (xi+ci)/2
+		dr, di := (xr-cr)/2, (xi-ci)/2
+		wr, wi := fft.wr[k], -fft.wi[k]
+		or, oi := dr*wr-di*wi, dr*wi+di*wr
+		fft.work[2*k] = er - oi
+		fft.work[2*k+1] = ei + or
+	}
+	fft64(fft.work, 1)
+	scale := 1 / float64(half)
+	for i := 0; i < fft.n; i++ {
+		realDataOut[i] = fft.work[i] * scale
+	}
+	return 0
+}
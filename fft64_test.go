@@ -0,0 +1,157 @@
+package webrtcvad
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// dft 直接计算交织格式的复数DFT，作为参考
+func dft(frfi []float64) []float64 {
+	n := len(frfi) / 2
+	out := make([]float64, 2*n)
+	for k := 0; k < n; k++ {
+		for j := 0; j < n; j++ {
+			s, c := math.Sincos(-2 * math.Pi * float64(j*k) / float64(n))
+			out[2*k] += frfi[2*j]*c - frfi[2*j+1]*s
+			out[2*k+1] += frfi[2*j]*s + frfi[2*j+1]*c
+		}
+	}
+	return out
+}
+
+// TestComplexFFT64 测试浮点复数FFT与DFT一致，逆变换精确还原
+func TestComplexFFT64(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, stages := range []int{0, 1, 2, 3, 4, 7} {
+		n := 1 << stages
+		data := make([]float64, 2*n)
+		for i := range data {
+			data[i] = rng.NormFloat64() * 1000
+		}
+		orig := append([]float64(nil), data...)
+		want := dft(data)
+
+		if ComplexFFT64(data, stages) != 0 {
+			t.Fatalf("%d点FFT失败", n)
+		}
+		for i := range want {
+			if math.Abs(data[i]-want[i]) > 1e-7 {
+				t.Fatalf("%d点FFT第%d个值应为%.4f, 得到%.4f", n, i, want[i], data[i])
+			}
+		}
+		if ComplexIFFT64(data, stages) != 0 {
+			t.Fatalf("%d点IFFT失败", n)
+		}
+		for i := range orig {
+			if math.Abs(data[i]-orig[i]) > 1e-9 {
+				t.Fatalf("%d点IFFT第%d个值应为%.4f, 得到%.4f", n, i, orig[i], data[i])
+			}
+		}
+	}
+
+	// 浮点版本不受1024点限制
+	if ComplexFFT64(make([]float64, 2<<12), 12) != 0 {
+		t.Error("4096点FFT应成功")
+	}
+	if ComplexFFT64(make([]float64, 8), 3) != -1 || ComplexIFFT64(nil, -1) != -1 {
+		t.Error("缓冲区不足或阶数无效时应返回-1")
+	}
+}
+
+// TestRealFFT64 测试浮点实数FFT的CCS输出与往返还原
+func TestRealFFT64(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, order := range []int{2, 4, 9} {
+		fft := CreateRealFFT64(order)
+		if fft == nil {
+			t.Fatalf("创建%d阶RealFFT64失败", order)
+		}
+		n := 1 << order
+		in := make([]float64, n)
+		frfi := make([]float64, 2*n)
+		for i := range in {
+			in[i] = rng.NormFloat64()
+			frfi[2*i] = in[i]
+		}
+		want := dft(frfi)
+
+		out := make([]float64, n+2)
+		if fft.RealForwardFFT(in, out) != 0 {
+			t.Fatal("RealForwardFFT失败")
+		}
+		for i := range out {
+			if math.Abs(out[i]-want[i]) > 1e-9 {
+				t.Fatalf("%d点实数FFT第%d个值应为%.6f, 得到%.6f", n, i, want[i], out[i])
+			}
+		}
+
+		back := make([]float64, n)
+		if fft.RealInverseFFT(out, back) != 0 {
+			t.Fatal("RealInverseFFT失败")
+		}
+		for i := range in {
+			if math.Abs(back[i]-in[i]) > 1e-12 {
+				t.Fatalf("%d点逆变换第%d个样本应为%.6f, 得到%.6f", n, i, in[i], back[i])
+			}
+		}
+		if fft.RealForwardFFT(in[:n-1], out) != -1 {
+			t.Error("输入长度不足时应返回-1")
+		}
+	}
+	if CreateRealFFT64(1) != nil || CreateRealFFT64(25) != nil {
+		t.Error("无效阶数应返回nil")
+	}
+}
+
+// fft64Radix2 逐级基2的浮点FFT，作为基4实现的对照
+func fft64Radix2(data []float64, sign float64) {
+	n := len(data) / 2
+	for i, j := 0, 0; i < n; i++ {
+		if j > i {
+			data[2*i], data[2*j] = data[2*j], data[2*i]
+			data[2*i+1], data[2*j+1] = data[2*j+1], data[2*i+1]
+		}
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+	}
+	for l := 1; l < n; l <<= 1 {
+		step := sign * math.Pi / float64(l)
+		for m := 0; m < l; m++ {
+			wi, wr := math.Sincos(step * float64(m))
+			for i := m; i < n; i += 2 * l {
+				j := i + l
+				tr := wr*data[2*j] - wi*data[2*j+1]
+				ti := wr*data[2*j+1] + wi*data[2*j]
+				qr, qi := data[2*i], data[2*i+1]
+				data[2*j], data[2*j+1] = qr-tr, qi-ti
+				data[2*i], data[2*i+1] = qr+tr, qi+ti
+			}
+		}
+	}
+}
+
+// BenchmarkComplexFFT64 比较基4与逐级基2的浮点FFT
+func BenchmarkComplexFFT64(b *testing.B) {
+	for _, stages := range []int{10, 12, 14} {
+		n := 1 << stages
+		data := make([]float64, 2*n)
+		for i := range data {
+			data[i] = float64(i % 1000)
+		}
+		b.Run(fmt.Sprintf("radix4/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fft64(data, -1)
+			}
+		})
+		b.Run(fmt.Sprintf("radix2/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fft64Radix2(data, -1)
+			}
+		})
+	}
+}
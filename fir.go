@@ -0,0 +1,179 @@
+package webrtcvad
+
+import "math"
+
+// fir.go 实现窗函数法设计的线性相位FIR滤波器，以及浮点与Q15定点的流式FIR滤波
+
+// DesignLowpassFIR 窗函数法设计线性相位低通FIR滤波器
+//
+// 以理想低通的sinc冲激响应乘以窗函数截断，并将直流增益归一化为1。
+// 过渡带宽度与阻带衰减由taps与窗函数决定（如Hamming约-53dB，Blackman约-74dB）
+//
+// 参数:
+//   - cutoff: 截止频率（Hz），须在(0, rate/2)内
+//   - rate: 采样率（Hz）
+//   - taps: 系数个数，取奇数时群延迟为整数个样本（(taps-1)/2）
+//   - win: 窗函数，nil表示HammingWindow
+//
+// 返回:
+//   - []float64: 对称的滤波器系数，参数无效时返回nil
+func DesignLowpassFIR(cutoff float64, rate, taps int, win WindowFunc) []float64 {
+	if rate <= 0 || taps <= 0 || !(cutoff > 0 && cutoff < float64(rate)/2) {
+		return nil
+	}
+	if win == nil {
+		win = HammingWindow
+	}
+
+	fc := cutoff / float64(rate) // 归一化截止频率（周期/样本）
+	center := float64(taps-1) / 2
+	h := make([]float64, taps)
+	var sum float64
+	for i := range h {
+		x := float64(i) - center
+		v := 2 * fc
+		if x != 0 {
+			v = math.Sin(2*math.Pi*fc*x) / (math.Pi * x)
+		}
+		h[i] = v * win(i, taps)
+		sum += h[i]
+	}
+	for i := range h {
+		h[i] /= sum
+	}
+	return h
+}
+
+// FIRFilter 浮点流式FIR滤波器
+//
+// 跨调用保存最近len(taps)-1个输入样本，分块滤波的结果与一次性滤波相同。
+// FIRFilter有状态且不是并发安全的，实现AudioFilter接口
+type FIRFilter struct {
+	taps []float64 // 系数按时间倒序存放，便于与历史缓冲区顺序点积
+	hist []float64 // 长度2*len(taps)的历史缓冲区，每个样本写两份使窗口连续
+	pos  int       // 下一个样本在hist前半部分的写入位置
+}
+
+// NewFIRFilter 创建浮点FIR滤波器
+//
+// 参数:
+//   - taps: 滤波器系数（如DesignLowpassFIR的结果），内部复制
+//
+// 返回:
+//   - *FIRFilter: 滤波器实例
+//   - error: taps为空时返回ErrInvalidTaps
+func NewFIRFilter(taps []float64) (*FIRFilter, error) {
+	if len(taps) == 0 {
+		return nil, ErrInvalidTaps
+	}
+	rev := make([]float64, len(taps))
+	for i, v := range taps {
+		rev[len(taps)-1-i] = v
+	}
+	return &FIRFilter{taps: rev, hist: make([]float64, 2*len(taps))}, nil
+}
+
+// Process 滤波input写入output
+//
+// output长度须不小于input，二者可以是同一切片（就地滤波）
+func (f *FIRFilter) Process(input []float64, output []float64) {
+	for i, v := range input {
+		output[i] = f.step(v)
+	}
+}
+
+// Filter 滤波16位音频，输出饱和到int16范围，实现AudioFilter接口
+//
+// output长度须不小于input，二者可以是同一切片（就地滤波）
+func (f *FIRFilter) Filter(input []int16, output []int16) {
+	for i, v := range input {
+		output[i] = saturate16(f.step(float64(v)))
+	}
+}
+
+// step 滤波一个样本
+func (f *FIRFilter) step(x float64) float64 {
+	n := len(f.taps)
+	f.hist[f.pos] = x
+	f.hist[f.pos+n] = x
+	f.pos++
+	if f.pos == n {
+		f.pos = 0
+	}
+	// hist[pos:pos+n]为按时间顺序排列的最近n个样本
+	var y float64
+	window := f.hist[f.pos : f.pos+n]
+	for k, h := range f.taps {
+		y += h * window[k]
+	}
+	return y
+}
+
+// Reset 清除历史样本
+func (f *FIRFilter) Reset() {
+	clear(f.hist)
+	f.pos = 0
+}
+
+// FIRFilterInt16 Q15定点流式FIR滤波器
+//
+// 系数量化为Q15（饱和到[-1, 1)），乘积以64位累加后四舍五入右移15位，
+// 适合没有浮点单元或要求逐位可复现的场景。系数量化使阻带衰减受限于约-90dB。
+// FIRFilterInt16有状态且不是并发安全的，实现AudioFilter接口
+type FIRFilterInt16 struct {
+	taps []int16 // Q15系数，按时间倒序存放
+	hist []int16 // 同FIRFilter
+	pos  int
+}
+
+// NewFIRFilterInt16 创建Q15定点FIR滤波器
+//
+// 参数:
+//   - taps: 浮点滤波器系数，量化为Q15
+//
+// 返回:
+//   - *FIRFilterInt16: 滤波器实例
+//   - error: taps为空时返回ErrInvalidTaps
+func NewFIRFilterInt16(taps []float64) (*FIRFilterInt16, error) {
+	if len(taps) == 0 {
+		return nil, ErrInvalidTaps
+	}
+	q := make([]int16, len(taps))
+	for i, v := range taps {
+		q[len(taps)-1-i] = saturate16(v * 32768)
+	}
+	return &FIRFilterInt16{taps: q, hist: make([]int16, 2*len(taps))}, nil
+}
+
+// Filter 滤波input写入output，输出饱和到int16范围
+//
+// output长度须不小于input，二者可以是同一切片（就地滤波）
+func (f *FIRFilterInt16) Filter(input []int16, output []int16) {
+	n := len(f.taps)
+	for i, x := range input {
+		f.hist[f.pos] = x
+		f.hist[f.pos+n] = x
+		f.pos++
+		if f.pos == n {
+			f.pos = 0
+		}
+		var acc int64
+		window := f.hist[f.pos : f.pos+n]
+		for k, h := range f.taps {
+			acc += int64(h) * int64(window[k])
+		}
+		acc = (acc + 1<<14) >> 15
+		if acc > math.MaxInt16 {
+			acc = math.MaxInt16
+		} else if acc < math.MinInt16 {
+			acc = math.MinInt16
+		}
+		output[i] = int16(acc)
+	}
+}
+
+// Reset 清除历史样本
+func (f *FIRFilterInt16) Reset() {
+	clear(f.hist)
+	f.pos = 0
+}
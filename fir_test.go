@@ -0,0 +1,131 @@
+package webrtcvad
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDesignLowpassFIR 测试系数的对称性、直流增益与参数校验
+func TestDesignLowpassFIR(t *testing.T) {
+	h := DesignLowpassFIR(1000, 16000, 101, BlackmanWindow)
+	if len(h) != 101 {
+		t.Fatalf("系数个数应为101, 得到%d", len(h))
+	}
+	var sum float64
+	for i, v := range h {
+		sum += v
+		if math.Abs(v-h[len(h)-1-i]) > 1e-12 {
+			t.Fatalf("系数应对称: h[%d]=%g, h[%d]=%g", i, v, len(h)-1-i, h[len(h)-1-i])
+		}
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("直流增益应为1, 得到%g", sum)
+	}
+
+	for _, c := range []struct {
+		cutoff     float64
+		rate, taps int
+	}{
+		{0, 16000, 31},
+		{8000, 16000, 31},
+		{1000, 0, 31},
+		{1000, 16000, 0},
+	} {
+		if DesignLowpassFIR(c.cutoff, c.rate, c.taps, nil) != nil {
+			t.Errorf("cutoff=%g rate=%d taps=%d 应返回nil", c.cutoff, c.rate, c.taps)
+		}
+	}
+	if _, err := NewFIRFilter(nil); err != ErrInvalidTaps {
+		t.Errorf("空系数应返回ErrInvalidTaps, 得到%v", err)
+	}
+	if _, err := NewFIRFilterInt16(nil); err != ErrInvalidTaps {
+		t.Errorf("空系数应返回ErrInvalidTaps, 得到%v", err)
+	}
+}
+
+// TestFIRFilterResponse 测试浮点与定点滤波器的通带与阻带增益
+func TestFIRFilterResponse(t *testing.T) {
+	h := DesignLowpassFIR(1000, 16000, 101, BlackmanWindow)
+	f, err := NewFIRFilter(h)
+	if err != nil {
+		t.Fatalf("创建滤波器失败: %v", err)
+	}
+	q, err := NewFIRFilterInt16(h)
+	if err != nil {
+		t.Fatalf("创建定点滤波器失败: %v", err)
+	}
+
+	for _, filter := range []AudioFilter{f, q} {
+		for _, c := range []struct{ freq, lo, hi float64 }{
+			{300, -0.1, 0.1},          // 通带
+			{3000, math.Inf(-1), -60}, // 阻带（输出可能全部舍入为0）
+		} {
+			filter.Reset()
+			if g := toneGain(filter, 16000, c.freq); g < c.lo || g > c.hi {
+				t.Errorf("%T %gHz增益 %.2fdB 不在 [%g, %g] 内", filter, c.freq, g, c.lo, c.hi)
+			}
+		}
+	}
+}
+
+// TestFIRFilterStreaming 测试分块滤波与一次性滤波结果一致，以及Reset
+func TestFIRFilterStreaming(t *testing.T) {
+	h := DesignLowpassFIR(2000, 8000, 15, nil)
+	in := make([]float64, 200)
+	for i := range in {
+		in[i] = math.Sin(float64(i)*0.3) + 0.5*math.Cos(float64(i)*2.1)
+	}
+
+	f, _ := NewFIRFilter(h)
+	whole := make([]float64, len(in))
+	f.Process(in, whole)
+
+	// 与直接卷积比较
+	for n := range in {
+		var want float64
+		for k, v := range h {
+			if n-k >= 0 {
+				want += v * in[n-k]
+			}
+		}
+		if math.Abs(whole[n]-want) > 1e-12 {
+			t.Fatalf("第%d个输出应为%g, 得到%g", n, want, whole[n])
+		}
+	}
+
+	f.Reset()
+	chunked := make([]float64, 0, len(in))
+	for i := 0; i < len(in); i += 7 {
+		chunk := append([]float64(nil), in[i:min(i+7, len(in))]...)
+		f.Process(chunk, chunk) // 就地滤波
+		chunked = append(chunked, chunk...)
+	}
+	for i := range whole {
+		if whole[i] != chunked[i] {
+			t.Fatalf("分块滤波第%d个输出应为%g, 得到%g", i, whole[i], chunked[i])
+		}
+	}
+}
+
+// BenchmarkFIRFilter 基准测试64阶FIR滤波（10ms@16kHz）
+func BenchmarkFIRFilter(b *testing.B) {
+	h := DesignLowpassFIR(3400, 16000, 64, nil)
+	in := make([]int16, 160)
+	for i := range in {
+		in[i] = int16(i * 100)
+	}
+	out := make([]int16, len(in))
+	f, _ := NewFIRFilter(h)
+	q, _ := NewFIRFilterInt16(h)
+
+	b.Run("float64", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			f.Filter(in, out)
+		}
+	})
+	b.Run("int16", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			q.Filter(in, out)
+		}
+	})
+}
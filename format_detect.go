@@ -0,0 +1,317 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// format_detect.go 提供音频格式识别：RIFF/WAV、AIFF/AIFF-C头部解析，
+// 以及无头部原始PCM的采样率与字节序估计
+
+// Container 音频容器类型
+type Container int
+
+const (
+	// ContainerRaw 无头部的原始16位PCM
+	ContainerRaw Container = iota
+	// ContainerWAV RIFF/WAVE文件
+	ContainerWAV
+	// ContainerAIFF AIFF或AIFF-C文件
+	ContainerAIFF
+)
+
+// String 返回容器类型名称
+func (c Container) String() string {
+	switch c {
+	case ContainerRaw:
+		return "raw"
+	case ContainerWAV:
+		return "wav"
+	case ContainerAIFF:
+		return "aiff"
+	default:
+		return fmt.Sprintf("Container(%d)", int(c))
+	}
+}
+
+// Format 检测到的音频格式
+type Format struct {
+	Container  Container // 容器类型
+	SampleRate int       // 采样率（Hz）
+	BigEndian  bool      // 样本为大端序（AIFF，或估计为大端序的原始PCM）
+	DataOffset int64     // PCM数据在输入中的起始偏移
+	DataSize   int64     // PCM数据的字节数，-1表示直到输入末尾
+	Estimated  bool      // 采样率与字节序由原始PCM的能量统计估计，而非读取自头部
+}
+
+// 原始PCM估计参数
+const (
+	// detectProbeBytes 用于估计的最大字节数（48kHz下2秒）
+	detectProbeBytes = 48000 * 2 * 2
+	// speechMeanFreq 语音能量加权的平均频率（Hz），用于由归一化频率反推采样率
+	speechMeanFreq = 700.0
+)
+
+// DetectFormat 识别音频数据的格式
+//
+// 依次尝试RIFF/WAV与AIFF/AIFF-C头部；都不匹配时按原始16位单声道PCM处理，
+// 由一阶差分能量与总能量之比估计信号的归一化平均频率，与语音的典型平均频率
+// 对照后选出最接近的候选采样率（8000, 16000, 24000, 32000, 48000），并比较两种
+// 字节序下的统计量判断是否为大端序。估计只是启发式的，全静音时返回
+// DefaultRawSampleRate。
+//
+// 参数:
+//   - r: 音频数据来源（如*os.File或*bytes.Reader）
+//
+// 返回:
+//   - Format: 检测到的格式，可用NewReader读取其中的PCM数据
+//   - error: 头部损坏、不是16位单声道PCM或采样率不受支持时的错误
+func DetectFormat(r io.ReaderAt) (Format, error) {
+	var magic [12]byte
+	n, err := r.ReadAt(magic[:], 0)
+	if err != nil && err != io.EOF {
+		return Format{}, err
+	}
+
+	switch {
+	case n == len(magic) && string(magic[0:4]) == "RIFF" && string(magic[8:12]) == "WAVE":
+		return detectWAV(r)
+	case n == len(magic) && string(magic[0:4]) == "FORM" &&
+		(string(magic[8:12]) == "AIFF" || string(magic[8:12]) == "AIFC"):
+		return detectAIFF(r, string(magic[8:12]) == "AIFC")
+	default:
+		return detectRaw(r)
+	}
+}
+
+// NewReader 返回读取r中PCM数据的io.Reader
+//
+// 输出总是16位小端序PCM，大端序数据在读取时转换，可直接送入VAD
+func (f Format) NewReader(r io.ReaderAt) io.Reader {
+	end := int64(math.MaxInt64)
+	if f.DataSize >= 0 {
+		end = f.DataOffset + f.DataSize
+	}
+	return &pcmReader{r: r, off: f.DataOffset, end: end, swap: f.BigEndian}
+}
+
+// detectWAV 借助wave包解析WAV头部，wave包的错误转换为与AIFF相同的错误
+func detectWAV(r io.ReaderAt) (Format, error) {
+	sr := io.NewSectionReader(r, 0, math.MaxInt64)
+	wr, err := wave.NewReader(sr)
+	switch {
+	case errors.Is(err, wave.ErrUnsupportedRate):
+		return Format{}, fmt.Errorf("%w: %v", ErrInvalidSampleRate, err)
+	case errors.Is(err, wave.ErrUnsupportedFormat), errors.Is(err, wave.ErrNotWAV):
+		return Format{}, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	case err != nil:
+		return Format{}, err
+	}
+	offset, err := sr.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return Format{}, err
+	}
+	return Format{
+		Container:  ContainerWAV,
+		SampleRate: wr.SampleRate,
+		DataOffset: offset,
+		DataSize:   wr.DataSize(),
+	}, nil
+}
+
+// detectAIFF 解析AIFF/AIFF-C的COMM与SSND块
+func detectAIFF(r io.ReaderAt, compressed bool) (Format, error) {
+	f := Format{Container: ContainerAIFF, BigEndian: true, DataSize: -1}
+	var channels, bits int
+	haveComm := false
+
+	off := int64(12)
+	for f.DataSize < 0 || !haveComm {
+		var hdr [8]byte
+		if _, err := r.ReadAt(hdr[:], off); err != nil {
+			if err == io.EOF {
+				return Format{}, fmt.Errorf("%w: missing COMM or SSND chunk in AIFF", ErrUnsupportedFormat)
+			}
+			return Format{}, err
+		}
+		id := string(hdr[0:4])
+		size := int64(binary.BigEndian.Uint32(hdr[4:8]))
+		body := off + 8
+
+		switch id {
+		case "COMM":
+			if size < 18 || compressed && size < 22 {
+				return Format{}, fmt.Errorf("%w: COMM chunk too short", ErrUnsupportedFormat)
+			}
+			buf := make([]byte, 22)
+			if _, err := r.ReadAt(buf[:min(int(size), 22)], body); err != nil {
+				return Format{}, err
+			}
+			channels = int(binary.BigEndian.Uint16(buf[0:2]))
+			bits = int(binary.BigEndian.Uint16(buf[6:8]))
+			f.SampleRate = extendedToInt(buf[8:18])
+			if compressed {
+				switch string(buf[18:22]) {
+				case "NONE", "twos":
+				case "sowt":
+					f.BigEndian = false
+				default:
+					return Format{}, fmt.Errorf("%w: AIFF-C compression %q", ErrUnsupportedFormat, buf[18:22])
+				}
+			}
+			haveComm = true
+		case "SSND":
+			if size < 8 {
+				return Format{}, fmt.Errorf("%w: SSND chunk too short", ErrUnsupportedFormat)
+			}
+			var ssnd [4]byte
+			if _, err := r.ReadAt(ssnd[:], body); err != nil {
+				return Format{}, err
+			}
+			dataOffset := int64(binary.BigEndian.Uint32(ssnd[:]))
+			f.DataOffset = body + 8 + dataOffset
+			if f.DataSize = size - 8 - dataOffset; f.DataSize < 0 {
+				f.DataSize = 0
+			}
+		}
+		// 块长度为奇数时有1字节填充
+		off = body + size + size&1
+	}
+
+	if channels != 1 || bits != 16 {
+		return Format{}, fmt.Errorf("%w: channels=%d, bits=%d", ErrUnsupportedFormat, channels, bits)
+	}
+	if !isValidSampleRate(f.SampleRate) {
+		return Format{}, fmt.Errorf("%w: got %d Hz", ErrInvalidSampleRate, f.SampleRate)
+	}
+	return f, nil
+}
+
+// detectRaw 由能量统计估计原始PCM的采样率与字节序
+func detectRaw(r io.ReaderAt) (Format, error) {
+	buf := make([]byte, detectProbeBytes)
+	n, err := r.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return Format{}, err
+	}
+	buf = buf[:n&^1]
+
+	f := Format{
+		Container:  ContainerRaw,
+		SampleRate: DefaultRawSampleRate,
+		DataSize:   -1,
+		Estimated:  true,
+	}
+
+	le := diffEnergyRatio(buf, false)
+	be := diffEnergyRatio(buf, true)
+	if le < 0 {
+		// 全静音或数据过短
+		return f, nil
+	}
+	ratio := le
+	if be >= 0 && be*4 < le {
+		// 字节序错误的信号近似白噪声（比值接近2），正确字节序下的比值小得多
+		f.BigEndian = true
+		ratio = be
+	}
+
+	// 对频率为v（归一化）的正弦，差分能量比为4sin²(πv)
+	nu := math.Asin(math.Min(1, math.Sqrt(ratio)/2)) / math.Pi
+	if nu <= 0 {
+		return f, nil
+	}
+	estimate := speechMeanFreq / nu
+
+	best := math.Inf(1)
+	for _, rate := range []int{8000, 16000, 24000, 32000, 48000} {
+		if d := math.Abs(math.Log(estimate / float64(rate))); d < best {
+			best = d
+			f.SampleRate = rate
+		}
+	}
+	return f, nil
+}
+
+// diffEnergyRatio 计算一阶差分能量与信号能量之比，能量为0时返回-1
+func diffEnergyRatio(pcm []byte, bigEndian bool) float64 {
+	var energy, diff float64
+	var prev float64
+	for i := 0; i+1 < len(pcm); i += 2 {
+		var s int16
+		if bigEndian {
+			s = int16(binary.BigEndian.Uint16(pcm[i:]))
+		} else {
+			s = int16(binary.LittleEndian.Uint16(pcm[i:]))
+		}
+		x := float64(s)
+		if i > 0 {
+			d := x - prev
+			diff += d * d
+			energy += x * x
+		}
+		prev = x
+	}
+	if energy == 0 {
+		return -1
+	}
+	return diff / energy
+}
+
+// extendedToInt 将80位IEEE扩展精度浮点数（AIFF采样率）转换为整数
+func extendedToInt(b []byte) int {
+	exp := int(binary.BigEndian.Uint16(b[0:2]) & 0x7FFF)
+	mant := binary.BigEndian.Uint64(b[2:10])
+	if b[0]&0x80 != 0 || mant == 0 {
+		return 0
+	}
+	return int(math.Round(math.Ldexp(float64(mant), exp-16383-63)))
+}
+
+// pcmReader 从ReaderAt的指定范围读取PCM，可选转换为小端序
+type pcmReader struct {
+	r    io.ReaderAt
+	off  int64
+	end  int64
+	swap bool
+}
+
+// Read 实现io.Reader
+func (p *pcmReader) Read(b []byte) (int, error) {
+	if p.off >= p.end {
+		return 0, io.EOF
+	}
+	if remaining := p.end - p.off; int64(len(b)) > remaining {
+		b = b[:remaining]
+	}
+	if p.swap {
+		// 只读取完整样本，保证交换不会跨越两次Read
+		b = b[:len(b)&^1]
+		if len(b) == 0 {
+			return 0, io.ErrShortBuffer
+		}
+	}
+
+	n, err := p.r.ReadAt(b, p.off)
+	if p.swap {
+		// 截断数据末尾的半个样本被丢弃
+		n &^= 1
+		for i := 0; i < n; i += 2 {
+			b[i], b[i+1] = b[i+1], b[i]
+		}
+	}
+	p.off += int64(n)
+	if err == io.EOF {
+		// 数据被截断或读到输入末尾：以已有数据结束
+		p.end = p.off
+		if n > 0 {
+			err = nil
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,199 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+	"os"
+	"testing"
+
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// buildAIFF 构造AIFF（compression为空）或AIFF-C文件，pcm为小端序样本
+func buildAIFF(pcm []byte, rate, channels int, compression string) []byte {
+	be := binary.BigEndian
+	comm := make([]byte, 18)
+	be.PutUint16(comm[0:2], uint16(channels))
+	be.PutUint32(comm[2:6], uint32(len(pcm)/2/channels))
+	be.PutUint16(comm[6:8], 16)
+	// 80位扩展精度：整数位显式存储在尾数最高位
+	shift := bits.LeadingZeros64(uint64(rate))
+	be.PutUint16(comm[8:10], uint16(16383+63-shift))
+	be.PutUint64(comm[10:18], uint64(rate)<<shift)
+
+	data := make([]byte, len(pcm))
+	copy(data, pcm)
+	if compression != "sowt" {
+		for i := 0; i+1 < len(data); i += 2 {
+			data[i], data[i+1] = data[i+1], data[i]
+		}
+	}
+
+	formType := "AIFF"
+	if compression != "" {
+		formType = "AIFC"
+		comm = append(comm, compression...)
+	}
+
+	var body bytes.Buffer
+	body.WriteString(formType)
+	body.WriteString("COMM")
+	binary.Write(&body, be, uint32(len(comm)))
+	body.Write(comm)
+	body.WriteString("SSND")
+	binary.Write(&body, be, uint32(8+len(data)))
+	body.Write(make([]byte, 8)) // offset与blockSize
+	body.Write(data)
+
+	var out bytes.Buffer
+	out.WriteString("FORM")
+	binary.Write(&out, be, uint32(body.Len()))
+	out.Write(body.Bytes())
+	return out.Bytes()
+}
+
+// readPCM 按检测到的格式读出全部PCM
+func readPCM(t *testing.T, f Format, r io.ReaderAt) []byte {
+	t.Helper()
+	pcm, err := io.ReadAll(f.NewReader(r))
+	if err != nil {
+		t.Fatalf("读取PCM失败: %v", err)
+	}
+	return pcm
+}
+
+// TestDetectFormatHeaders 测试WAV与AIFF头部的识别
+func TestDetectFormatHeaders(t *testing.T) {
+	pcm := sineFrame(440, 8000, 16000, 1600)
+
+	var wav bytes.Buffer
+	if err := wave.Write(&wav, pcm, 16000); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+		want Format
+	}{
+		{"wav", wav.Bytes(), Format{Container: ContainerWAV, SampleRate: 16000, DataOffset: 44, DataSize: int64(len(pcm))}},
+		{"aiff", buildAIFF(pcm, 16000, 1, ""), Format{Container: ContainerAIFF, SampleRate: 16000, BigEndian: true, DataOffset: 54, DataSize: int64(len(pcm))}},
+		{"aifc", buildAIFF(pcm, 48000, 1, "NONE"), Format{Container: ContainerAIFF, SampleRate: 48000, BigEndian: true, DataOffset: 58, DataSize: int64(len(pcm))}},
+		{"aifc-sowt", buildAIFF(pcm, 8000, 1, "sowt"), Format{Container: ContainerAIFF, SampleRate: 8000, DataOffset: 58, DataSize: int64(len(pcm))}},
+	}
+
+	for _, tc := range cases {
+		r := bytes.NewReader(tc.data)
+		f, err := DetectFormat(r)
+		if err != nil {
+			t.Fatalf("%s: 检测失败: %v", tc.name, err)
+		}
+		if f != tc.want {
+			t.Errorf("%s: 格式应为%+v, 得到%+v", tc.name, tc.want, f)
+		}
+		if got := readPCM(t, f, r); !bytes.Equal(got, pcm) {
+			t.Errorf("%s: 读出的PCM与原始数据不一致（%d字节）", tc.name, len(got))
+		}
+	}
+}
+
+// TestDetectFormatUnsupported 测试不支持的头部参数
+func TestDetectFormatUnsupported(t *testing.T) {
+	pcm := make([]byte, 3200)
+
+	if _, err := DetectFormat(bytes.NewReader(buildAIFF(pcm, 16000, 2, ""))); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("立体声AIFF应返回ErrUnsupportedFormat, 得到%v", err)
+	}
+	if _, err := DetectFormat(bytes.NewReader(buildAIFF(pcm, 44100, 1, ""))); !errors.Is(err, ErrInvalidSampleRate) {
+		t.Errorf("44.1kHz AIFF应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := DetectFormat(bytes.NewReader(buildAIFF(pcm, 16000, 1, "ulaw"))); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("压缩的AIFF-C应返回ErrUnsupportedFormat, 得到%v", err)
+	}
+
+	var wav bytes.Buffer
+	wave.Write(&wav, pcm, 44100)
+	if _, err := DetectFormat(bytes.NewReader(wav.Bytes())); !errors.Is(err, ErrInvalidSampleRate) {
+		t.Errorf("44.1kHz WAV应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	wav.Reset()
+	wave.Write(&wav, pcm, 16000)
+	stereo := wav.Bytes()
+	binary.LittleEndian.PutUint16(stereo[22:], 2)
+	if _, err := DetectFormat(bytes.NewReader(stereo)); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("立体声WAV应返回ErrUnsupportedFormat, 得到%v", err)
+	}
+}
+
+// TestDetectFormatRaw 测试原始PCM的采样率与字节序估计
+func TestDetectFormatRaw(t *testing.T) {
+	files := map[string]int{
+		"./test/test-audio.raw": 8000,
+		"./test/test.pcm":       16000,
+	}
+	for path, rate := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Skipf("测试音频文件不存在: %v", err)
+		}
+
+		f, err := DetectFormat(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: 检测失败: %v", path, err)
+		}
+		if f.Container != ContainerRaw || !f.Estimated || f.BigEndian || f.SampleRate != rate {
+			t.Errorf("%s: 应估计为%dHz小端序原始PCM, 得到%+v", path, rate, f)
+		}
+
+		// 大端序版本
+		swapped := make([]byte, len(data))
+		for i := 0; i+1 < len(data); i += 2 {
+			swapped[i], swapped[i+1] = data[i+1], data[i]
+		}
+		r := bytes.NewReader(swapped)
+		f, err = DetectFormat(r)
+		if err != nil {
+			t.Fatalf("%s: 检测失败: %v", path, err)
+		}
+		if !f.BigEndian || f.SampleRate != rate {
+			t.Errorf("%s: 应估计为%dHz大端序原始PCM, 得到%+v", path, rate, f)
+		}
+		if got := readPCM(t, f, r); !bytes.Equal(got, data[:len(data)&^1]) {
+			t.Errorf("%s: 大端序数据应转换为小端序", path)
+		}
+	}
+
+	// 全静音无法估计，使用默认采样率
+	f, err := DetectFormat(bytes.NewReader(make([]byte, 3200)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.SampleRate != DefaultRawSampleRate || !f.Estimated || f.DataSize != -1 {
+		t.Errorf("静音应使用默认采样率, 得到%+v", f)
+	}
+}
+
+// TestProcessFileAIFF 测试ProcessFile处理AIFF文件
+func TestProcessFileAIFF(t *testing.T) {
+	var pcm []byte
+	pcm = append(pcm, make([]byte, 16000)...)
+	for i := 0; i < 100; i++ {
+		pcm = append(pcm, sineFrame(440, 8000, 16000, 160)...)
+	}
+	pcm = append(pcm, make([]byte, 32000)...)
+
+	path := t.TempDir() + "/speech.aiff"
+	if err := os.WriteFile(path, buildAIFF(pcm, 16000, 1, ""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	segments, err := ProcessFile(path, WithMode(1))
+	if err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+	if len(segments) != 1 || segments[0].SampleRate != 16000 {
+		t.Fatalf("应检测到1个16kHz语音片段, 得到%+v", segments)
+	}
+}
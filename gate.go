@@ -0,0 +1,251 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// gate.go 提供语音门控：去除或清零非语音部分
+// GateWriter / GateReader 分别以io.Writer和io.Reader的形式插入音频处理管线
+
+// GateMode 非语音帧的处理方式
+type GateMode int
+
+const (
+	// GateZero 非语音帧清零（或填充舒适噪声），输出与输入等长，时间轴不变
+	GateZero GateMode = iota
+	// GateRemove 丢弃非语音帧，只输出语音部分的拼接
+	GateRemove
+)
+
+// GateConfig 语音门控配置
+type GateConfig struct {
+	SampleRate int      // 采样率（8000, 16000, 24000, 32000, 48000）
+	FrameMs    int      // 帧长度（毫秒，10/20/30）
+	Mode       GateMode // 非语音帧的处理方式
+	// ComfortNoise 非语音帧替换为幅度不超过该值的白噪声（仅GateZero），0表示补零
+	ComfortNoise int16
+}
+
+// gate 门控核心：按帧判决并生成输出
+type gate struct {
+	vad       *VAD
+	cfg       GateConfig
+	frameSize int
+	in        []byte // 未凑满一帧的输入
+	out       []byte // 已生成、尚未交付的输出
+	noise     uint32 // 舒适噪声的xorshift状态
+}
+
+// newGate 校验配置并创建门控核心
+func newGate(vad *VAD, cfg GateConfig) (*gate, error) {
+	if vad == nil {
+		return nil, ErrNotInitialized
+	}
+	if !isValidSampleRate(cfg.SampleRate) {
+		return nil, ErrInvalidSampleRate
+	}
+	if cfg.FrameMs != 10 && cfg.FrameMs != 20 && cfg.FrameMs != 30 {
+		return nil, ErrInvalidFrameLength
+	}
+	if cfg.ComfortNoise < 0 {
+		cfg.ComfortNoise = -cfg.ComfortNoise
+	}
+
+	frameSize := cfg.SampleRate * cfg.FrameMs / 1000 * 2
+	return &gate{
+		vad:       vad,
+		cfg:       cfg,
+		frameSize: frameSize,
+		in:        make([]byte, 0, frameSize*2),
+		noise:     0x9e3779b9,
+	}, nil
+}
+
+// write 接收输入，处理所有完整的帧
+func (g *gate) write(p []byte) error {
+	g.in = append(g.in, p...)
+	for len(g.in) >= g.frameSize {
+		if err := g.frame(g.in[:g.frameSize]); err != nil {
+			return err
+		}
+		g.in = g.in[g.frameSize:]
+	}
+	return nil
+}
+
+// flush 补零处理残余的不完整帧，输出只保留实际数据的长度
+func (g *gate) flush() error {
+	if len(g.in) == 0 {
+		return nil
+	}
+	frame := make([]byte, g.frameSize)
+	copy(frame, g.in)
+	isSpeech, err := g.vad.IsSpeech(frame, g.cfg.SampleRate)
+	if err != nil {
+		return err
+	}
+	g.emit(g.in, isSpeech)
+	g.in = g.in[:0]
+	return nil
+}
+
+// frame 判决一帧并生成输出
+func (g *gate) frame(frame []byte) error {
+	isSpeech, err := g.vad.IsSpeech(frame, g.cfg.SampleRate)
+	if err != nil {
+		return err
+	}
+	g.emit(frame, isSpeech)
+	return nil
+}
+
+// emit 按判决结果输出一帧
+func (g *gate) emit(frame []byte, isSpeech bool) {
+	switch {
+	case isSpeech:
+		g.out = append(g.out, frame...)
+	case g.cfg.Mode == GateRemove:
+		// 丢弃
+	case g.cfg.ComfortNoise == 0:
+		g.out = append(g.out, make([]byte, len(frame))...)
+	default:
+		start := len(g.out)
+		g.out = append(g.out, make([]byte, len(frame))...)
+		g.fillNoise(g.out[start:])
+	}
+}
+
+// fillNoise 以均匀分布白噪声填充buf
+func (g *gate) fillNoise(buf []byte) {
+	span := uint32(g.cfg.ComfortNoise)*2 + 1
+	for i := 0; i+1 < len(buf); i += 2 {
+		// xorshift32
+		g.noise ^= g.noise << 13
+		g.noise ^= g.noise >> 17
+		g.noise ^= g.noise << 5
+		v := int16(int32(g.noise%span) - int32(g.cfg.ComfortNoise))
+		binary.LittleEndian.PutUint16(buf[i:], uint16(v))
+	}
+}
+
+// GateWriter 语音门控写入端
+//
+// 写入的PCM按帧判决，门控后的结果写入下游w
+type GateWriter struct {
+	w io.Writer
+	g *gate
+}
+
+// NewGateWriter 创建语音门控写入端
+//
+// 参数:
+//   - w: 门控结果的下游
+//   - vad: 用于逐帧判决的VAD实例
+//   - cfg: 门控配置
+//
+// 返回:
+//   - *GateWriter: 写入端实例
+//   - error: 错误信息
+func NewGateWriter(w io.Writer, vad *VAD, cfg GateConfig) (*GateWriter, error) {
+	g, err := newGate(vad, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &GateWriter{w: w, g: g}, nil
+}
+
+// Write 写入PCM数据，实现io.Writer
+//
+// 不完整的帧保留到下次写入；返回值表示接受的输入字节数。下游写入失败时输入已被接受
+// （返回len(p)与错误），未交付的输出保留到下次Write或Close时重新写入下游，重试时不应再次写入p
+func (gw *GateWriter) Write(p []byte) (int, error) {
+	if err := gw.g.write(p); err != nil {
+		return 0, err
+	}
+	if err := gw.drain(); err != nil {
+		return len(p), err
+	}
+	return len(p), nil
+}
+
+// Close 处理残余的不完整帧并写入下游，实现io.Closer
+//
+// 不会关闭下游w
+func (gw *GateWriter) Close() error {
+	if err := gw.g.flush(); err != nil {
+		return err
+	}
+	return gw.drain()
+}
+
+// drain 将已生成的输出写入下游，未写入的部分保留在out中
+func (gw *GateWriter) drain() error {
+	if len(gw.g.out) == 0 {
+		return nil
+	}
+	n, err := gw.w.Write(gw.g.out)
+	if n < 0 || n > len(gw.g.out) {
+		n = 0
+	}
+	gw.g.out = gw.g.out[:copy(gw.g.out, gw.g.out[n:])]
+	if err == nil && len(gw.g.out) > 0 {
+		err = io.ErrShortWrite
+	}
+	return err
+}
+
+// GateReader 语音门控读取端
+//
+// 从上游r读取PCM，Read返回门控后的结果
+type GateReader struct {
+	r   io.Reader
+	g   *gate
+	buf []byte
+	err error // 上游返回的错误（含io.EOF）
+}
+
+// NewGateReader 创建语音门控读取端
+//
+// 参数:
+//   - r: PCM数据来源
+//   - vad: 用于逐帧判决的VAD实例
+//   - cfg: 门控配置
+//
+// 返回:
+//   - *GateReader: 读取端实例
+//   - error: 错误信息
+func NewGateReader(r io.Reader, vad *VAD, cfg GateConfig) (*GateReader, error) {
+	g, err := newGate(vad, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &GateReader{r: r, g: g, buf: make([]byte, g.frameSize*16)}, nil
+}
+
+// Read 读取门控后的PCM数据，实现io.Reader
+//
+// 上游读到EOF时残余的不完整帧也被处理，全部输出读完后返回io.EOF
+func (gr *GateReader) Read(p []byte) (int, error) {
+	for len(gr.g.out) == 0 && gr.err == nil {
+		n, err := gr.r.Read(gr.buf)
+		if werr := gr.g.write(gr.buf[:n]); werr != nil {
+			return 0, werr
+		}
+		if err != nil {
+			gr.err = err
+			if err == io.EOF {
+				if ferr := gr.g.flush(); ferr != nil {
+					return 0, ferr
+				}
+			}
+		}
+	}
+
+	if len(gr.g.out) == 0 {
+		return 0, gr.err
+	}
+	n := copy(p, gr.g.out)
+	gr.g.out = gr.g.out[:copy(gr.g.out, gr.g.out[n:])]
+	return n, nil
+}
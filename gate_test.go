@@ -0,0 +1,150 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// gateInput 5帧静音后接5帧正弦波（8kHz, 10ms）
+func gateInput() (silence, speech []byte) {
+	silence = make([]byte, 5*160)
+	for i := 0; i < 5; i++ {
+		speech = append(speech, sineFrame(440, 8000, 8000, 80)...)
+	}
+	return silence, speech
+}
+
+// TestGateWriter 测试写入端的清零与去除模式
+func TestGateWriter(t *testing.T) {
+	silence, speech := gateInput()
+	input := append(append([]byte(nil), silence...), speech...)
+
+	tests := []struct {
+		mode GateMode
+		want []byte
+	}{
+		{GateZero, input},
+		{GateRemove, speech},
+	}
+	for _, tt := range tests {
+		vad, _ := New(0)
+		var out bytes.Buffer
+		gw, err := NewGateWriter(&out, vad, GateConfig{SampleRate: 8000, FrameMs: 10, Mode: tt.mode})
+		if err != nil {
+			t.Fatalf("创建GateWriter失败: %v", err)
+		}
+
+		n, err := io.Copy(gw, iotest.OneByteReader(bytes.NewReader(input)))
+		if err != nil || n != int64(len(input)) {
+			t.Fatalf("写入失败: n=%d, err=%v", n, err)
+		}
+		if err := gw.Close(); err != nil {
+			t.Fatalf("Close失败: %v", err)
+		}
+		if !bytes.Equal(out.Bytes(), tt.want) {
+			t.Errorf("模式%d输出错误: 期望%d字节, 得到%d字节", tt.mode, len(tt.want), out.Len())
+		}
+	}
+}
+
+// shortWriter 每次最多接受limit字节且不返回错误
+type shortWriter struct {
+	bytes.Buffer
+	limit int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	return w.Buffer.Write(p[:min(len(p), w.limit)])
+}
+
+// TestGateWriterDownstreamError 测试下游失败或短写时输入仍被接受，未交付的输出在之后补发
+func TestGateWriterDownstreamError(t *testing.T) {
+	silence, speech := gateInput()
+	input := append(append([]byte(nil), silence...), speech...)
+
+	var failing flakyWriter
+	vad, _ := New(0)
+	gw, _ := NewGateWriter(&failing, vad, GateConfig{SampleRate: 8000, FrameMs: 10, Mode: GateZero})
+	if n, err := gw.Write(input[:800]); err == nil || n != 800 {
+		t.Fatalf("下游失败时应返回800与错误, 得到%d, %v", n, err)
+	}
+	if n, err := gw.Write(input[800:]); err != nil || n != len(input)-800 {
+		t.Fatalf("写入失败: %d, %v", n, err)
+	}
+	if !bytes.Equal(failing.Bytes(), input) {
+		t.Errorf("补发后的输出应与输入一致: 期望%d字节, 得到%d字节", len(input), failing.Len())
+	}
+
+	short := &shortWriter{limit: 100}
+	vad, _ = New(0)
+	gw, _ = NewGateWriter(short, vad, GateConfig{SampleRate: 8000, FrameMs: 10, Mode: GateZero})
+	if n, err := gw.Write(input); err != io.ErrShortWrite || n != len(input) {
+		t.Fatalf("短写应返回%d与io.ErrShortWrite, 得到%d, %v", len(input), n, err)
+	}
+	short.limit = len(input)
+	if err := gw.Close(); err != nil {
+		t.Fatalf("Close失败: %v", err)
+	}
+	if !bytes.Equal(short.Bytes(), input) {
+		t.Errorf("Close应补发短写剩余的输出: 期望%d字节, 得到%d字节", len(input), short.Len())
+	}
+}
+
+// TestGateReader 测试读取端与舒适噪声
+func TestGateReader(t *testing.T) {
+	silence, speech := gateInput()
+	input := append(append([]byte(nil), silence...), speech...)
+	input = append(input, 1, 2, 3) // 末尾残余3字节
+
+	vad, _ := New(0)
+	gr, err := NewGateReader(bytes.NewReader(input), vad, GateConfig{
+		SampleRate:   8000,
+		FrameMs:      10,
+		Mode:         GateZero,
+		ComfortNoise: 20,
+	})
+	if err != nil {
+		t.Fatalf("创建GateReader失败: %v", err)
+	}
+
+	out, err := io.ReadAll(iotest.OneByteReader(gr))
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if len(out) != len(input) {
+		t.Fatalf("清零模式输出长度应与输入相同: 期望%d, 得到%d", len(input), len(out))
+	}
+
+	// 静音部分为幅度不超过20的非零噪声
+	nonZero := false
+	for i := 0; i < len(silence); i += 2 {
+		v := int16(binary.LittleEndian.Uint16(out[i:]))
+		if v > 20 || v < -20 {
+			t.Fatalf("舒适噪声超出幅度: %d", v)
+		}
+		nonZero = nonZero || v != 0
+	}
+	if !nonZero {
+		t.Error("舒适噪声不应全为零")
+	}
+	if !bytes.Equal(out[len(silence):len(silence)+len(speech)], speech) {
+		t.Error("语音部分应原样输出")
+	}
+}
+
+// TestGateInvalidConfig 测试无效配置
+func TestGateInvalidConfig(t *testing.T) {
+	vad, _ := New(0)
+	if _, err := NewGateWriter(io.Discard, vad, GateConfig{SampleRate: 11025, FrameMs: 10}); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := NewGateReader(bytes.NewReader(nil), vad, GateConfig{SampleRate: 8000, FrameMs: 15}); err != ErrInvalidFrameLength {
+		t.Errorf("应返回ErrInvalidFrameLength, 得到%v", err)
+	}
+	if _, err := NewGateWriter(io.Discard, nil, GateConfig{SampleRate: 8000, FrameMs: 10}); err != ErrNotInitialized {
+		t.Errorf("应返回ErrNotInitialized, 得到%v", err)
+	}
+}
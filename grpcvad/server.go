@@ -0,0 +1,157 @@
+// Package grpcvad 提供基于StreamVAD的gRPC流式语音活动检测服务
+//
+// 服务定义见vadpb/vad.proto：客户端以双向流发送可选的Config及任意长度的
+// PCM音频块，服务端在每个片段结束时返回SegmentEvent。Server可直接注册到
+// 任意grpc.Server上：
+//
+//	gs := grpc.NewServer()
+//	vadpb.RegisterVADServer(gs, grpcvad.NewServer(webrtcvad.WithStreamMode(2)))
+//	gs.Serve(lis)
+//
+// 本包是独立的Go模块，使用核心库时不会引入gRPC依赖。
+package grpcvad
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+	"github.com/godeps/webrtcvad-go/grpcvad/vadpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server VAD服务实现
+//
+// 每个Detect调用使用独立的StreamVAD，Server可被任意多个流并发使用
+type Server struct {
+	vadpb.UnimplementedVADServer
+	opts []webrtcvad.StreamVADOption
+}
+
+// NewServer 创建VAD服务
+//
+// 参数:
+//   - opts: 每个流的默认StreamVAD选项，客户端Config中的非零字段覆盖这些默认值
+//
+// 返回:
+//   - *Server: 服务实例
+func NewServer(opts ...webrtcvad.StreamVADOption) *Server {
+	return &Server{opts: opts}
+}
+
+// Detect 处理一个双向检测流，实现vadpb.VADServer
+func (s *Server) Detect(stream vadpb.VAD_DetectServer) error {
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	opts := s.opts
+	if cfg := req.GetConfig(); cfg != nil {
+		opts = append(opts[:len(opts):len(opts)], configOptions(cfg)...)
+		req = nil
+	}
+	svad, err := webrtcvad.NewStreamVADWithOptions(opts...)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// 片段通道由单独的goroutine发送，gRPC要求同一时刻只有一个goroutine调用Send。
+	// 通道须在写入音频之前创建，否则此前结束的片段不会发送
+	segments := svad.Segments()
+	sendErr := make(chan error, 1)
+	go func() {
+		var err error
+		for seg := range segments {
+			if err == nil {
+				err = stream.Send(segmentEvent(seg))
+			}
+			// 出错后继续读取直到通道关闭，避免写入端阻塞
+		}
+		sendErr <- err
+	}()
+
+	recvErr := receive(stream, svad, req)
+	closeErr := svad.Close()
+	if err := <-sendErr; err != nil {
+		return err
+	}
+	if recvErr != nil {
+		return recvErr
+	}
+	if closeErr != nil {
+		return status.Error(codes.Internal, closeErr.Error())
+	}
+	return nil
+}
+
+// receive 将客户端的音频写入svad直到客户端关闭发送端
+//
+// first为已接收但尚未处理的第一条消息（可为nil）
+func receive(stream vadpb.VAD_DetectServer, svad *webrtcvad.StreamVAD, first *vadpb.DetectRequest) error {
+	req := first
+	for {
+		if req != nil {
+			if req.GetConfig() != nil {
+				return status.Error(codes.InvalidArgument, "config must be the first message of the stream")
+			}
+			if _, err := svad.Write(req.GetAudio()); err != nil {
+				if errors.Is(err, webrtcvad.ErrStreamClosed) {
+					return status.Error(codes.Canceled, err.Error())
+				}
+				return status.Error(codes.Internal, err.Error())
+			}
+		}
+
+		var err error
+		req, err = stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// configOptions 将客户端配置转换为StreamVAD选项，零值字段不产生选项
+func configOptions(cfg *vadpb.Config) []webrtcvad.StreamVADOption {
+	var opts []webrtcvad.StreamVADOption
+	if cfg.SampleRate != 0 {
+		opts = append(opts, webrtcvad.WithSampleRate(int(cfg.SampleRate)))
+	}
+	if cfg.FrameMs != 0 {
+		opts = append(opts, webrtcvad.WithFrameDuration(int(cfg.FrameMs)))
+	}
+	if cfg.Mode != nil {
+		opts = append(opts, webrtcvad.WithStreamMode(int(*cfg.Mode)))
+	}
+	if cfg.MinSpeechMs != 0 {
+		opts = append(opts, webrtcvad.WithMinSpeechDuration(time.Duration(cfg.MinSpeechMs)*time.Millisecond))
+	}
+	if cfg.MinSilenceMs != 0 {
+		opts = append(opts, webrtcvad.WithMinSilenceDuration(time.Duration(cfg.MinSilenceMs)*time.Millisecond))
+	}
+	if cfg.MaxSegmentMs != 0 {
+		opts = append(opts, webrtcvad.WithMaxSegmentDuration(time.Duration(cfg.MaxSegmentMs)*time.Millisecond))
+	}
+	if cfg.CaptureAudio {
+		opts = append(opts, webrtcvad.WithCaptureAudio(true))
+	}
+	return opts
+}
+
+// segmentEvent 将片段转换为事件消息
+func segmentEvent(seg webrtcvad.VoiceSegment) *vadpb.SegmentEvent {
+	return &vadpb.SegmentEvent{
+		StartMs: seg.Start.Milliseconds(),
+		EndMs:   seg.End.Milliseconds(),
+		Speech:  seg.IsSpeech,
+		Audio:   seg.Audio,
+	}
+}
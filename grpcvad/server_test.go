@@ -0,0 +1,136 @@
+package grpcvad
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+	"github.com/godeps/webrtcvad-go/grpcvad/vadpb"
+	"github.com/godeps/webrtcvad-go/internal/testaudio"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// newClient 启动内存中的服务端并返回客户端
+func newClient(t *testing.T, srv *Server) vadpb.VADClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	vadpb.RegisterVADServer(gs, srv)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return vadpb.NewVADClient(conn)
+}
+
+// TestDetect 测试配置、分块发送音频并接收片段事件
+func TestDetect(t *testing.T) {
+	client := newClient(t, NewServer(webrtcvad.WithSampleRate(8000)))
+	stream, err := client.Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &vadpb.Config{SampleRate: 16000, FrameMs: 20, Mode: proto.Int32(1), CaptureAudio: true}
+	if err := stream.Send(&vadpb.DetectRequest{Payload: &vadpb.DetectRequest_Config{Config: cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	pcm := testaudio.Pattern("01100", 16000)
+	for len(pcm) > 0 {
+		n := min(len(pcm), 1234) // 不与帧对齐的块
+		if err := stream.Send(&vadpb.DetectRequest{Payload: &vadpb.DetectRequest_Audio{Audio: pcm[:n]}}); err != nil {
+			t.Fatal(err)
+		}
+		pcm = pcm[n:]
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []*vadpb.SegmentEvent
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("接收事件失败: %v", err)
+		}
+		events = append(events, ev)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("应收到静音、语音、静音3个事件, 得到%v", events)
+	}
+	speech := events[1]
+	if !speech.Speech || speech.StartMs != 500 || speech.EndMs < 1500 {
+		t.Errorf("语音事件应从500ms开始并覆盖到1500ms, 得到%v", speech)
+	}
+	if len(speech.Audio) != int(speech.EndMs-speech.StartMs)*32 {
+		t.Errorf("语音事件应携带%d字节音频, 得到%d", (speech.EndMs-speech.StartMs)*32, len(speech.Audio))
+	}
+	if last := events[2]; last.Speech || last.EndMs != 2500 {
+		t.Errorf("最后一个事件应为结束于2500ms的静音, 得到%v", last)
+	}
+}
+
+// TestDetectInvalidConfig 测试无效配置与配置位置错误
+func TestDetectInvalidConfig(t *testing.T) {
+	client := newClient(t, NewServer())
+
+	cases := []struct {
+		name string
+		reqs []*vadpb.DetectRequest
+	}{
+		{"invalid rate", []*vadpb.DetectRequest{
+			{Payload: &vadpb.DetectRequest_Config{Config: &vadpb.Config{SampleRate: 44100}}},
+		}},
+		{"invalid mode", []*vadpb.DetectRequest{
+			{Payload: &vadpb.DetectRequest_Config{Config: &vadpb.Config{Mode: proto.Int32(4)}}},
+		}},
+		{"late config", []*vadpb.DetectRequest{
+			{Payload: &vadpb.DetectRequest_Audio{Audio: make([]byte, 320)}},
+			{Payload: &vadpb.DetectRequest_Config{Config: &vadpb.Config{}}},
+		}},
+	}
+	for _, tc := range cases {
+		stream, err := client.Detect(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, req := range tc.reqs {
+			stream.Send(req)
+		}
+		stream.CloseSend()
+
+		for err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: 应返回InvalidArgument, 得到%v", tc.name, err)
+		}
+	}
+}
+
+// TestConfigOptions 测试未设置的模式不产生选项，显式的模式0产生选项
+func TestConfigOptions(t *testing.T) {
+	if opts := configOptions(&vadpb.Config{}); len(opts) != 0 {
+		t.Errorf("空配置不应产生选项, 得到%d个", len(opts))
+	}
+	if opts := configOptions(&vadpb.Config{Mode: proto.Int32(0)}); len(opts) != 1 {
+		t.Errorf("显式的模式0应产生1个选项, 得到%d个", len(opts))
+	}
+}
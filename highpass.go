@@ -0,0 +1,79 @@
+package webrtcvad
+
+import "math"
+
+// highpass.go 提供公开的有状态高通滤波器，用于在检测前去除采集设备的直流偏置与低频隆隆声
+//
+// 内部的highPassFilter是滤波器组中针对500Hz以下分支的定点实现，不适合直接用于原始音频
+
+// HighPass80Hz 创建截止频率80Hz的Butterworth高通滤波器
+//
+// 可去除直流偏置和空调、风扇、机械振动等低频噪声，对语音基本无影响。
+//
+// 参数:
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
+//
+// 返回:
+//   - *Biquad: 滤波器实例
+//   - error: 错误信息
+func HighPass80Hz(sampleRate int) (*Biquad, error) {
+	if !isValidSampleRate(sampleRate) {
+		return nil, ErrInvalidSampleRate
+	}
+	return NewHighPassBiquad(sampleRate, 80, math.Sqrt2/2)
+}
+
+// DCBlocker 一阶直流阻断滤波器
+//
+//	y[n] = x[n] - x[n-1] + R*y[n-1]，R = exp(-2π*cutoff/sampleRate)
+//
+// 每样本一次乘法，比HighPass80Hz更便宜，截止频率可低至几Hz，只去除采集硬件的直流偏置，
+// 不影响语音的低频成分。DCBlocker有状态且不是并发安全的，实现AudioFilter接口
+type DCBlocker struct {
+	r      float64 // 极点
+	x1, y1 float64 // 上一个输入与输出
+}
+
+// NewDCBlocker 创建直流阻断滤波器
+//
+// 参数:
+//   - sampleRate: 采样率（Hz）
+//   - cutoff: -3dB截止频率（Hz），须在(0, sampleRate/2)内，通常取10-30Hz
+//
+// 返回:
+//   - *DCBlocker: 滤波器实例
+//   - error: 错误信息
+func NewDCBlocker(sampleRate int, cutoff float64) (*DCBlocker, error) {
+	if sampleRate <= 0 {
+		return nil, ErrInvalidSampleRate
+	}
+	if !(cutoff > 0 && cutoff < float64(sampleRate)/2) {
+		return nil, ErrInvalidCutoff
+	}
+	return &DCBlocker{r: math.Exp(-2 * math.Pi * cutoff / float64(sampleRate))}, nil
+}
+
+// Filter 滤波input写入output，输出饱和到int16范围
+//
+// output长度须不小于input，二者可以是同一切片（就地滤波）
+func (d *DCBlocker) Filter(input []int16, output []int16) {
+	for i, v := range input {
+		x := float64(v)
+		d.y1 = x - d.x1 + d.r*d.y1
+		d.x1 = x
+		output[i] = saturate16(d.y1)
+	}
+}
+
+// Reset 清除滤波器状态
+func (d *DCBlocker) Reset() {
+	d.x1, d.y1 = 0, 0
+}
+
+// filterStage 将AudioFilter适配为StreamVAD的检测前处理模块（见WithHighPass、WithFilter）
+type filterStage struct{ AudioFilter }
+
+func (s filterStage) Process(frame []int16) error {
+	s.Filter(frame, frame)
+	return nil
+}
@@ -0,0 +1,142 @@
+package webrtcvad
+
+import (
+	"math"
+	"testing"
+)
+
+// toneGain 返回滤波器对某频率正弦波的稳态增益（dB）
+func toneGain(b AudioFilter, rate int, freq float64) float64 {
+	in := make([]int16, rate)
+	for i := range in {
+		in[i] = int16(10000 * math.Sin(2*math.Pi*freq*float64(i)/float64(rate)))
+	}
+	out := make([]int16, len(in))
+	b.Filter(in, out)
+
+	// 跳过前半秒的暂态
+	var ein, eout float64
+	for i := rate / 2; i < rate; i++ {
+		ein += float64(in[i]) * float64(in[i])
+		eout += float64(out[i]) * float64(out[i])
+	}
+	return 10 * math.Log10(eout/ein)
+}
+
+// TestHighPass80Hz 测试各采样率下的频率响应与直流去除
+func TestHighPass80Hz(t *testing.T) {
+	for _, rate := range []int{8000, 16000, 32000, 48000} {
+		b, err := HighPass80Hz(rate)
+		if err != nil {
+			t.Fatalf("创建滤波器失败: %v", err)
+		}
+
+		for _, c := range []struct{ freq, lo, hi float64 }{
+			{20, -26, -22},    // 截止频率以下两个倍频程，约-24dB
+			{80, -3.3, -2.7},  // 截止频率处-3dB
+			{1000, -0.1, 0.1}, // 通带
+		} {
+			b.Reset()
+			if g := toneGain(b, rate, c.freq); g < c.lo || g > c.hi {
+				t.Errorf("%dHz: %.0fHz处增益应在[%.1f, %.1f]dB内, 得到%.2fdB", rate, c.freq, c.lo, c.hi, g)
+			}
+		}
+
+		// 直流偏置
+		b.Reset()
+		dc := make([]int16, rate)
+		for i := range dc {
+			dc[i] = 5000
+		}
+		b.Filter(dc, dc)
+		if v := dc[len(dc)-1]; v < -1 || v > 1 {
+			t.Errorf("%dHz: 直流应被滤除, 末尾样本为%d", rate, v)
+		}
+	}
+}
+
+// TestBiquadState 测试分块滤波与整体滤波结果一致
+func TestBiquadState(t *testing.T) {
+	in := make([]int16, 1600)
+	for i := range in {
+		in[i] = int16(3000*math.Sin(float64(i)*0.05)) + 2000
+	}
+	whole, _ := HighPass80Hz(16000)
+	want := make([]int16, len(in))
+	whole.Filter(in, want)
+
+	chunked, _ := HighPass80Hz(16000)
+	got := append([]int16(nil), in...)
+	for off := 0; off < len(got); off += 160 {
+		chunked.Filter(got[off:off+160], got[off:off+160])
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("第%d个样本: 分块滤波%d, 整体滤波%d", i, got[i], want[i])
+		}
+	}
+}
+
+// TestHighPassErrors 测试参数校验
+func TestHighPassErrors(t *testing.T) {
+	if _, err := HighPass80Hz(44100); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := NewHighPassBiquad(16000, 8000, math.Sqrt2/2); err != ErrInvalidCutoff {
+		t.Errorf("截止频率达到奈奎斯特频率时应返回ErrInvalidCutoff, 得到%v", err)
+	}
+	if _, err := NewHighPassBiquad(16000, 100, 0); err != ErrInvalidCutoff {
+		t.Errorf("Q为0时应返回ErrInvalidCutoff, 得到%v", err)
+	}
+	if _, err := NewHighPassBiquad(0, 100, 1); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+
+	svad, err := NewStreamVADWithOptions(WithHighPass(), WithDenoise())
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if _, ok := svad.preprocessors[0].(filterStage); !ok || len(svad.preprocessors) != 2 {
+		t.Error("高通滤波应在降噪之前执行")
+	}
+}
+
+// TestDCBlocker 测试直流偏置去除与通带增益
+func TestDCBlocker(t *testing.T) {
+	d, err := NewDCBlocker(16000, 20)
+	if err != nil {
+		t.Fatalf("创建滤波器失败: %v", err)
+	}
+
+	// 带3000直流偏置的1kHz正弦
+	in := make([]int16, 16000)
+	for i := range in {
+		in[i] = int16(3000 + 5000*math.Sin(2*math.Pi*1000*float64(i)/16000))
+	}
+	out := make([]int16, len(in))
+	d.Filter(in, out)
+	var mean float64
+	for _, v := range out[8000:] {
+		mean += float64(v)
+	}
+	if mean /= 8000; math.Abs(mean) > 1 {
+		t.Errorf("直流偏置应被去除, 均值%.2f", mean)
+	}
+
+	for _, c := range []struct{ freq, lo, hi float64 }{
+		{20, -3.3, -2.7},   // 截止频率处-3dB
+		{300, -0.05, 0.05}, // 语音频带不受影响
+	} {
+		d.Reset()
+		if g := toneGain(d, 16000, c.freq); g < c.lo || g > c.hi {
+			t.Errorf("%gHz增益 %.2fdB 不在 [%g, %g] 内", c.freq, g, c.lo, c.hi)
+		}
+	}
+
+	if _, err := NewDCBlocker(16000, 0); err != ErrInvalidCutoff {
+		t.Errorf("应返回ErrInvalidCutoff, 得到%v", err)
+	}
+	if _, err := NewDCBlocker(0, 20); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package webrtcvad
+
+import "encoding/binary"
+
+// hybrid_vad.go 实现GMM、能量门限与过零率加权投票的组合检测器
+//
+// 键盘敲击、关门等冲击噪声能量高、频带宽，GMM与能量门限都容易误判为语音；
+// 这类噪声的过零率通常远高于浊音，加入过零率一票可以抑制这类误检
+
+// HybridConfig 组合检测器的投票配置
+//
+// 每帧三个判决各投一票：GMM判为语音、EnergyVAD判为语音、过零率不超过MaxZCR。
+// 投赞成票的权重之和不低于总权重的Threshold倍时判为语音
+type HybridConfig struct {
+	GMMWeight    float64 // GMM判决的权重
+	EnergyWeight float64 // 能量门限判决的权重
+	ZCRWeight    float64 // 过零率判决的权重
+	Threshold    float64 // 判为语音所需的赞成权重占比，范围(0, 1]
+	MaxZCR       float64 // 语音帧的最大过零率（次/秒）
+}
+
+// DefaultHybridConfig 返回默认投票配置
+//
+// 过零率权重为2、门限0.75：过零率一票与GMM或能量门限之一同时赞成时判为语音，
+// 仅GMM与能量门限赞成（冲击噪声的典型情形）时不判为语音。
+// 3000次/秒可以覆盖浊音与大部分擦音，而宽带噪声在16kHz下约为8000次/秒
+func DefaultHybridConfig() HybridConfig {
+	return HybridConfig{
+		GMMWeight:    1,
+		EnergyWeight: 1,
+		ZCRWeight:    2,
+		Threshold:    0.75,
+		MaxZCR:       3000,
+	}
+}
+
+// HybridVAD 组合语音检测器
+//
+// 内部包含一个VAD与一个EnergyVAD，两者都按每帧更新自适应状态。
+// HybridVAD有状态且不是并发安全的，实现Detector接口
+type HybridVAD struct {
+	cfg    HybridConfig
+	mode   int
+	gmm    *VAD
+	energy *EnergyVAD
+}
+
+// NewHybridVAD 创建组合语音检测器
+//
+// 参数:
+//   - mode: GMM与能量门限的激进度模式（0-3）
+//   - cfg: 投票配置
+//
+// 返回:
+//   - *HybridVAD: 检测器实例
+//   - error: 模式无效返回ErrInvalidMode，权重为负、总权重为0或门限、过零率越界时返回ErrInvalidHybridConfig
+func NewHybridVAD(mode int, cfg HybridConfig) (*HybridVAD, error) {
+	if mode < 0 || mode > 3 {
+		return nil, ErrInvalidMode
+	}
+	if cfg.GMMWeight < 0 || cfg.EnergyWeight < 0 || cfg.ZCRWeight < 0 ||
+		cfg.GMMWeight+cfg.EnergyWeight+cfg.ZCRWeight <= 0 ||
+		cfg.Threshold <= 0 || cfg.Threshold > 1 || cfg.MaxZCR <= 0 {
+		return nil, ErrInvalidHybridConfig
+	}
+	gmm, err := New(mode)
+	if err != nil {
+		return nil, err
+	}
+	energy, err := NewEnergyVAD(mode)
+	if err != nil {
+		return nil, err
+	}
+	return &HybridVAD{cfg: cfg, mode: mode, gmm: gmm, energy: energy}, nil
+}
+
+// IsSpeech 判断一帧是否为语音，实现Detector接口
+//
+// 参数:
+//   - frame: 16位小端序PCM，长度须对应10ms、20ms或30ms
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
+//
+// 返回:
+//   - bool: true表示检测到语音
+//   - error: 参数无效时返回错误
+func (h *HybridVAD) IsSpeech(frame []byte, sampleRate int) (bool, error) {
+	gmm, err := h.gmm.IsSpeech(frame, sampleRate)
+	if err != nil {
+		return false, err
+	}
+	energy, err := h.energy.IsSpeech(frame, sampleRate)
+	if err != nil {
+		return false, err
+	}
+
+	var votes float64
+	if gmm {
+		votes += h.cfg.GMMWeight
+	}
+	if energy {
+		votes += h.cfg.EnergyWeight
+	}
+	if zeroCrossingRate(frame, sampleRate) <= h.cfg.MaxZCR {
+		votes += h.cfg.ZCRWeight
+	}
+	total := h.cfg.GMMWeight + h.cfg.EnergyWeight + h.cfg.ZCRWeight
+	return votes >= h.cfg.Threshold*total, nil
+}
+
+// SetMode 修改激进度模式（0-3，含义同NewHybridVAD），只更新GMM与能量门限的模式，保留自适应状态
+func (h *HybridVAD) SetMode(mode int) error {
+	if err := h.gmm.SetMode(mode); err != nil {
+		return err
+	}
+	h.mode = mode
+	return h.energy.SetMode(mode)
+}
+
+// Reset 重新初始化GMM与能量门限的自适应状态，保留GMM的阈值与迟滞设置
+func (h *HybridVAD) Reset() {
+	reinitCore(h.gmm.inst)
+	h.energy.Reset()
+}
+
+// zeroCrossingRate 计算一帧16位小端序PCM的过零率（次/秒）
+//
+// 不去除直流：帧均值会被冲击噪声的大幅样本带偏，反而使其余样本不再过零
+func zeroCrossingRate(frame []byte, sampleRate int) float64 {
+	n := len(frame) / 2
+	if n < 2 {
+		return 0
+	}
+	crossings := 0
+	prev := int16(binary.LittleEndian.Uint16(frame)) < 0
+	for i := 1; i < n; i++ {
+		neg := int16(binary.LittleEndian.Uint16(frame[i*2:])) < 0
+		if neg != prev {
+			crossings++
+		}
+		prev = neg
+	}
+	return float64(crossings) * float64(sampleRate) / float64(n-1)
+}
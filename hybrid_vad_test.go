@@ -0,0 +1,96 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// clickTrack 生成低电平噪声中的键盘敲击声（每200ms一次、5ms衰减的宽带脉冲），
+// 并在[start, end)内叠加140Hz谐波
+func clickTrack(rate int, total, start, end time.Duration) []byte {
+	rng := rand.New(rand.NewSource(7))
+	n := int(total.Seconds() * float64(rate))
+	period, decay := rate/5, float64(rate)/1000
+	from, to := int(start.Seconds()*float64(rate)), int(end.Seconds()*float64(rate))
+	pcm := make([]byte, 0, n*2)
+	for i := 0; i < n; i++ {
+		v := rng.NormFloat64() * 50
+		if k := i % period; k < rate/200 {
+			v += rng.NormFloat64() * 12000 * math.Exp(-float64(k)/decay)
+		}
+		if i >= from && i < to {
+			for h := 1; h <= 8; h++ {
+				v += 3000 / float64(h) * math.Sin(2*math.Pi*140*float64(h)*float64(i)/float64(rate))
+			}
+		}
+		v = math.Max(math.MinInt16, math.Min(math.MaxInt16, v))
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(v)))
+	}
+	return pcm
+}
+
+// TestHybridVAD 测试组合检测器抑制键盘敲击的误检，同时保留浊音
+func TestHybridVAD(t *testing.T) {
+	pcm := clickTrack(16000, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+	count := func(d Detector) (clicks, voiced int) {
+		for i := 0; i+320 <= len(pcm); i += 320 {
+			ok, err := d.IsSpeech(pcm[i:i+320], 16000)
+			if err != nil {
+				t.Fatalf("检测失败: %v", err)
+			}
+			switch at := time.Duration(i/32) * time.Millisecond; {
+			case ok && at < 2*time.Second:
+				clicks++
+			case ok && at < 2500*time.Millisecond:
+				voiced++
+			}
+		}
+		return clicks, voiced
+	}
+
+	gmm, _ := New(1)
+	if clicks, _ := count(gmm); clicks == 0 {
+		t.Fatal("测试信号应使GMM误检敲击声")
+	}
+	h, err := NewHybridVAD(1, DefaultHybridConfig())
+	if err != nil {
+		t.Fatalf("创建HybridVAD失败: %v", err)
+	}
+	clicks, voiced := count(h)
+	if clicks != 0 {
+		t.Errorf("组合检测器不应把敲击声判为语音, 误检%d帧", clicks)
+	}
+	if voiced < 45 {
+		t.Errorf("谐波段的50帧应大部分为语音, 得到%d帧", voiced)
+	}
+
+	// 重置后结果可复现
+	h.Reset()
+	if c, v := count(h); c != clicks || v != voiced {
+		t.Errorf("重置后结果应相同, 得到%d/%d, 期望%d/%d", c, v, clicks, voiced)
+	}
+
+	for _, cfg := range []HybridConfig{
+		{GMMWeight: -1, ZCRWeight: 1, Threshold: 0.5, MaxZCR: 3000},
+		{Threshold: 0.5, MaxZCR: 3000},
+		{GMMWeight: 1, Threshold: 1.5, MaxZCR: 3000},
+		{GMMWeight: 1, Threshold: 0.5},
+	} {
+		if _, err := NewHybridVAD(1, cfg); err != ErrInvalidHybridConfig {
+			t.Errorf("配置%+v应返回ErrInvalidHybridConfig, 得到%v", cfg, err)
+		}
+	}
+	if _, err := NewStreamVADWithOptions(WithHybrid(HybridConfig{})); err != ErrInvalidHybridConfig {
+		t.Errorf("应返回ErrInvalidHybridConfig, 得到%v", err)
+	}
+	svad, err := NewStreamVADWithOptions(WithAlgorithm(Hybrid))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if d, ok := svad.detector.(*HybridVAD); !ok || d.cfg != DefaultHybridConfig() {
+		t.Errorf("StreamVAD应使用默认配置的HybridVAD, 得到%T", svad.detector)
+	}
+}
@@ -0,0 +1,24 @@
+// Package testaudio 生成各子包测试共用的合成音频
+package testaudio
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Pattern 按"1"为440Hz正弦波、"0"为静音生成每字符0.5s的音频
+//
+// 返回rate采样率的16位PCM（小端序），VAD会把正弦波判为语音
+func Pattern(p string, rate int) []byte {
+	var pcm []byte
+	for _, c := range p {
+		for i := 0; i < rate/2; i++ {
+			var v int16
+			if c == '1' {
+				v = int16(8000 * math.Sin(2*math.Pi*440*float64(i)/float64(rate)))
+			}
+			pcm = binary.LittleEndian.AppendUint16(pcm, uint16(v))
+		}
+	}
+	return pcm
+}
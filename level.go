@@ -0,0 +1,108 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// level.go 提供输入电平测量，便于界面在VAD状态旁显示电平表
+
+// LevelFloorDBFS 电平的下限（16位PCM的动态范围），静音帧的电平取此值
+const LevelFloorDBFS = -96.0
+
+// LevelMeter 逐帧测量RMS与峰值电平，并以指数平滑得到稳定的显示电平
+//
+// 电平以dBFS表示，满幅正弦波的RMS电平约为-3dBFS。LevelMeter不是并发安全的
+type LevelMeter struct {
+	sampleRate   int
+	timeConstant float64 // 平滑时间常数（秒）
+
+	rms   float64 // 上一帧的RMS（线性，0-32768）
+	peak  int     // 上一帧的峰值绝对值
+	power float64 // 平滑后的均方值
+	init  bool
+}
+
+// NewLevelMeter 创建电平表
+//
+// 参数:
+//   - sampleRate: 采样率（Hz）
+//   - timeConstant: 平滑时间常数，电平每经过该时长向当前值靠近约63%；0表示不平滑
+//
+// 返回:
+//   - *LevelMeter: 电平表实例
+//   - error: 错误信息
+func NewLevelMeter(sampleRate int, timeConstant time.Duration) (*LevelMeter, error) {
+	if sampleRate <= 0 {
+		return nil, ErrInvalidSampleRate
+	}
+	if timeConstant < 0 {
+		return nil, ErrInvalidDuration
+	}
+	return &LevelMeter{sampleRate: sampleRate, timeConstant: timeConstant.Seconds()}, nil
+}
+
+// Update 测量一帧16位小端序PCM并更新平滑电平
+//
+// 帧长度任意（奇数个字节时忽略最后一个字节），平滑系数按帧时长计算
+func (m *LevelMeter) Update(frame []byte) {
+	n := len(frame) / 2
+	if n == 0 {
+		return
+	}
+	var sum float64
+	peak := 0
+	for i := 0; i < n; i++ {
+		v := int(int16(binary.LittleEndian.Uint16(frame[i*2:])))
+		sum += float64(v * v)
+		if v < 0 {
+			v = -v
+		}
+		peak = max(peak, v)
+	}
+	power := sum / float64(n)
+	m.rms = math.Sqrt(power)
+	m.peak = peak
+
+	if !m.init || m.timeConstant == 0 {
+		m.power, m.init = power, true
+		return
+	}
+	dt := float64(n) / float64(m.sampleRate)
+	alpha := 1 - math.Exp(-dt/m.timeConstant)
+	m.power += alpha * (power - m.power)
+}
+
+// RMS 返回上一帧的RMS（线性，0-32768）
+func (m *LevelMeter) RMS() float64 {
+	return m.rms
+}
+
+// RMSDBFS 返回上一帧的RMS电平（dBFS）
+func (m *LevelMeter) RMSDBFS() float64 {
+	return toDBFS(m.rms)
+}
+
+// PeakDBFS 返回上一帧的峰值电平（dBFS）
+func (m *LevelMeter) PeakDBFS() float64 {
+	return toDBFS(float64(m.peak))
+}
+
+// Level 返回平滑后的RMS电平（dBFS）
+func (m *LevelMeter) Level() float64 {
+	return toDBFS(math.Sqrt(m.power))
+}
+
+// Reset 重置电平表状态
+func (m *LevelMeter) Reset() {
+	m.rms, m.peak, m.power, m.init = 0, 0, 0, false
+}
+
+// toDBFS 将线性幅度转换为dBFS，不低于LevelFloorDBFS
+func toDBFS(amplitude float64) float64 {
+	if amplitude <= 0 {
+		return LevelFloorDBFS
+	}
+	return math.Max(LevelFloorDBFS, 20*math.Log10(amplitude/32768))
+}
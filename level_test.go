@@ -0,0 +1,58 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestLevelMeter 测试RMS、峰值电平与平滑电平
+func TestLevelMeter(t *testing.T) {
+	m, err := NewLevelMeter(16000, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("创建电平表失败: %v", err)
+	}
+
+	sine := make([]byte, 0, 320*2)
+	for i := 0; i < 320; i++ {
+		sine = binary.LittleEndian.AppendUint16(sine, uint16(int16(16384*math.Sin(2*math.Pi*400*float64(i)/16000))))
+	}
+	m.Update(sine)
+	if math.Abs(m.RMS()-16384/math.Sqrt2) > 10 {
+		t.Errorf("RMS应约为%.0f, 得到%.1f", 16384/math.Sqrt2, m.RMS())
+	}
+	if got := m.RMSDBFS(); math.Abs(got+9.03) > 0.05 {
+		t.Errorf("RMS电平应约为-9.03dBFS, 得到%.2f", got)
+	}
+	if got := m.PeakDBFS(); math.Abs(got+6.02) > 0.05 {
+		t.Errorf("峰值电平应约为-6.02dBFS, 得到%.2f", got)
+	}
+	if m.Level() != m.RMSDBFS() {
+		t.Errorf("第一帧的平滑电平应等于该帧电平, 得到%.2f", m.Level())
+	}
+
+	// 静音后平滑电平按时间常数下降：10帧共100ms后均方值衰减到e^-1（约-4.3dB）
+	silence := make([]byte, 320)
+	for i := 0; i < 10; i++ {
+		m.Update(silence)
+	}
+	if got := m.Level(); math.Abs(got-(-9.03-4.34)) > 0.1 {
+		t.Errorf("100ms静音后平滑电平应约为-13.4dBFS, 得到%.2f", got)
+	}
+	if m.RMSDBFS() != LevelFloorDBFS || m.PeakDBFS() != LevelFloorDBFS {
+		t.Errorf("静音帧的电平应为%v, 得到%.2f/%.2f", LevelFloorDBFS, m.RMSDBFS(), m.PeakDBFS())
+	}
+
+	m.Reset()
+	if m.Level() != LevelFloorDBFS {
+		t.Errorf("重置后电平应为%v, 得到%.2f", LevelFloorDBFS, m.Level())
+	}
+
+	if _, err := NewLevelMeter(0, 0); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := NewLevelMeter(16000, -time.Second); err != ErrInvalidDuration {
+		t.Errorf("应返回ErrInvalidDuration, 得到%v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"log/slog"
+	"math"
+)
+
+// logging.go 提供结构化日志钩子：削波输入、被丢弃的音频与自适应状态重置等原本静默的行为
+// 通过slog报告，便于服务将VAD异常与其他事件关联。未设置Logger时不输出任何日志
+
+// WithLogger 设置VAD的日志，nil表示不输出（默认）
+//
+// 输出的日志:
+//   - Warn "input clipped": 帧中出现满幅样本（仅在削波开始时输出一次，恢复后再次出现时重新输出）
+//   - Warn "sample rate changed, buffered audio discarded": Feed的采样率变化时丢弃了缓冲数据
+//   - Info "model replaced": SetModel替换了GMM参数
+//   - Debug "primed": Prime完成预热并清除迟滞状态
+func WithLogger(logger *slog.Logger) Option {
+	return func(v *VAD) error {
+		v.logger = logger
+		return nil
+	}
+}
+
+// WithStreamLogger 设置StreamVAD的日志，nil表示不输出（默认）
+//
+// 输出的日志:
+//   - Warn "input clipped": 帧中出现满幅样本（仅在削波开始时输出一次），附带流内时间
+//   - Warn "write after close, audio dropped": Close之后写入的数据被丢弃
+//   - Warn "input buffer overflow, audio dropped": WithOverflowPolicy(OverflowDropOldest)丢弃了积压的数据
+//   - Info "stream reset": Reset清除了片段并重新开始噪声自适应
+//   - Info "mode changed": SetMode请求的模式在帧边界生效
+func WithStreamLogger(logger *slog.Logger) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		cfg.logger = logger
+		return nil
+	}
+}
+
+// clipDetector 边沿触发的削波检测，避免持续削波时每帧都输出日志
+type clipDetector struct {
+	clipping bool // 上一帧是否有满幅样本
+}
+
+// check 统计帧中的满幅样本数，返回本帧是否开始削波（上一帧无削波）
+func (c *clipDetector) check(frame []byte) (int, bool) {
+	var clipped int
+	for i := 0; i+1 < len(frame); i += 2 {
+		if v := int16(binary.LittleEndian.Uint16(frame[i:])); v == math.MaxInt16 || v == math.MinInt16 {
+			clipped++
+		}
+	}
+	started := clipped > 0 && !c.clipping
+	c.clipping = clipped > 0
+	return clipped, started
+}
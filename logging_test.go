@@ -0,0 +1,90 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+)
+
+// clippedFrame 生成一帧满幅削波的方波
+func clippedFrame(samples int) []byte {
+	frame := make([]int16, samples)
+	for i := range frame {
+		frame[i] = math.MaxInt16
+		if i%20 >= 10 {
+			frame[i] = math.MinInt16
+		}
+	}
+	return toPCM(frame)
+}
+
+// TestWithLogger 测试VAD在削波开始、Feed丢弃缓冲数据与替换模型时输出日志
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	vad, err := NewWithOptions(WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+
+	clipped := clippedFrame(160)
+	for i := 0; i < 3; i++ {
+		vad.IsSpeech(clipped, 16000)
+	}
+	vad.IsSpeech(make([]byte, 320), 16000)
+	vad.IsSpeech(clipped, 16000)
+	if n := strings.Count(buf.String(), "input clipped"); n != 2 {
+		t.Errorf("削波只应在开始时各输出一次, 共2次, 得到%d次:\n%s", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), "samples=160") {
+		t.Errorf("日志应包含满幅样本数:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	vad.Feed(make([]byte, 100), 16000)
+	vad.Feed(make([]byte, 100), 8000)
+	if !strings.Contains(buf.String(), "buffered audio discarded") || !strings.Contains(buf.String(), "bytes=100") {
+		t.Errorf("采样率变化时应报告丢弃的100字节:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	vad.SetModel(DefaultModel())
+	if !strings.Contains(buf.String(), "model replaced") {
+		t.Errorf("替换模型时应输出日志:\n%s", buf.String())
+	}
+
+	quiet, _ := New(1)
+	quiet.IsSpeech(clipped, 16000) // 未设置Logger时不输出
+}
+
+// TestWithStreamLogger 测试StreamVAD报告削波、关闭后写入与重置
+func TestWithStreamLogger(t *testing.T) {
+	var buf bytes.Buffer
+	svad, err := NewStreamVADWithOptions(WithFrameDuration(10), WithStreamLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	pcm := append(make([]byte, 3200), clippedFrame(800)...) // 100ms静音后50ms削波
+	svad.Process(pcm)
+	if n := strings.Count(buf.String(), "input clipped"); n != 1 {
+		t.Errorf("持续削波只应输出一次, 得到%d次:\n%s", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), "at=100ms") {
+		t.Errorf("日志应包含削波开始的流内时间:\n%s", buf.String())
+	}
+
+	svad.Close()
+	if _, err := svad.Process(make([]byte, 320)); err != ErrStreamClosed {
+		t.Fatalf("关闭后写入应返回ErrStreamClosed, 得到%v", err)
+	}
+	if !strings.Contains(buf.String(), "audio dropped") || !strings.Contains(buf.String(), "bytes=320") {
+		t.Errorf("关闭后写入应报告丢弃的数据:\n%s", buf.String())
+	}
+
+	svad.Reset()
+	if !strings.Contains(buf.String(), "stream reset") {
+		t.Errorf("重置时应输出日志:\n%s", buf.String())
+	}
+}
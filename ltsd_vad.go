@@ -0,0 +1,204 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// ltsd_vad.go 实现长时谱散度（LTSD, Long-Term Spectral Divergence）语音检测器
+//
+// Ramírez等(2004)提出：取最近若干帧各频点幅度的最大值作为长时谱包络，与噪声幅度谱比较。
+// 跨帧取最大值使语音的谐波在帧间抖动时仍保持高值，而噪声的随机起伏被平均掉，
+// 在低信噪比的平稳噪声下优于GMM
+
+// LTSD检测的参数
+const (
+	ltsdLowHz  = 150.0  // 计算谱散度的频带下限
+	ltsdHighHz = 4000.0 // 计算谱散度的频带上限
+	// ltsdOrder 长时谱包络跨越的帧数（含当前帧）
+	ltsdOrder = 6
+	// ltsdMinEnergy 每样本的最小平均能量（RMS约10），更安静的帧直接判为非语音
+	ltsdMinEnergy = 100.0
+	// ltsdInitTime 开头视为噪声、用于初始化噪声幅度谱的时长
+	ltsdInitTime = 100 * time.Millisecond
+	// ltsdNoiseTime 非语音帧更新噪声幅度谱的时间常数
+	ltsdNoiseTime = time.Second
+	// ltsdRiseTime 语音帧中噪声幅度谱每经过该时长最多增大e倍（功率约8.7dB），
+	// 使噪声突然变大后不会一直判为语音，又不至于在正常长度的话语中追上语音
+	ltsdRiseTime = 5 * time.Second
+)
+
+// ltsdThresholds 各模式下判为语音的LTSD门限（dB，模式越高越严格）
+//
+// 平稳噪声中6帧最大值相对噪声均值的散度约为4-5dB
+var ltsdThresholds = [4]float64{6, 6.5, 7, 7.5}
+
+// LTSDVAD 长时谱散度语音检测器
+//
+// 对每帧加Hann窗做FFT，在150-4000Hz频带内取最近6帧幅度谱的逐频点最大值（长时谱包络），
+// LTSD = 10·log10(mean(包络²/噪声²))，高于门限时判为语音。开头100ms的平均幅度谱作为
+// 噪声初值，之后在非语音帧中平滑更新。LTSDVAD有状态且不是并发安全的，实现Detector接口
+type LTSDVAD struct {
+	threshold float64
+
+	elapsed time.Duration // 已处理的时长
+	ltsd    float64       // 最近一帧的LTSD（dB）
+
+	// 按帧长度缓存的窗函数、FFT缓冲区与频带内的幅度谱
+	frameLen int
+	window   []float64
+	fft      *RealFFT64
+	in, spec []float64   // FFT的输入与CCS格式输出
+	history  [][]float64 // 最近ltsdOrder帧的幅度谱（环形）
+	next     int         // history中下一帧的写入位置
+	frames   int         // history中的有效帧数
+	noise    []float64   // 噪声幅度谱估计
+	analyzed int         // 参与噪声初始化的帧数
+}
+
+// NewLTSDVAD 创建长时谱散度语音检测器
+//
+// 参数:
+//   - mode: 激进度模式（0-3），与VAD的模式含义一致
+//
+// 返回:
+//   - *LTSDVAD: 检测器实例
+//   - error: 错误信息
+func NewLTSDVAD(mode int) (*LTSDVAD, error) {
+	if mode < 0 || mode > 3 {
+		return nil, ErrInvalidMode
+	}
+	return &LTSDVAD{threshold: ltsdThresholds[mode]}, nil
+}
+
+// IsSpeech 判断一帧是否为语音，实现Detector接口
+//
+// 参数:
+//   - frame: 16位小端序PCM，长度须对应10ms、20ms或30ms
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
+//
+// 返回:
+//   - bool: true表示检测到语音
+//   - error: 参数无效时返回错误
+func (l *LTSDVAD) IsSpeech(frame []byte, sampleRate int) (bool, error) {
+	if !isValidSampleRate(sampleRate) {
+		return false, ErrInvalidSampleRate
+	}
+	n := len(frame) / 2
+	if !ValidRateAndFrameLength(sampleRate, n) {
+		return false, fmt.Errorf("%w: got %d samples at %d Hz", ErrInvalidFrameLength, n, sampleRate)
+	}
+
+	frameDur := time.Duration(n) * time.Second / time.Duration(sampleRate)
+	mag, energetic := l.spectrum(frame, sampleRate)
+	l.elapsed += frameDur
+
+	// 开头ltsdInitTime内取平均作为噪声幅度谱
+	if l.elapsed <= ltsdInitTime || l.analyzed == 0 {
+		l.analyzed++
+		for k, m := range mag {
+			l.noise[k] += (m - l.noise[k]) / float64(l.analyzed)
+		}
+		l.ltsd = 0
+		return false, nil
+	}
+
+	l.ltsd = l.divergence()
+	speech := energetic && l.ltsd > l.threshold
+
+	if speech {
+		rise := math.Exp(frameDur.Seconds() / ltsdRiseTime.Seconds() / 2) // 幅度为功率的平方根
+		for k, m := range mag {
+			l.noise[k] = math.Max(l.noise[k], math.Min(m, l.noise[k]*rise))
+		}
+	} else {
+		rate := smoothRate(frameDur.Seconds(), ltsdNoiseTime)
+		for k, m := range mag {
+			l.noise[k] += rate * (m - l.noise[k])
+		}
+	}
+	return speech, nil
+}
+
+// LTSD 返回最近一帧的长时谱散度（dB），噪声初始化期间为0
+func (l *LTSDVAD) LTSD() float64 {
+	return l.ltsd
+}
+
+// SetMode 修改激进度模式（0-3，含义同NewLTSDVAD），只更新判决门限，保留自适应状态
+func (l *LTSDVAD) SetMode(mode int) error {
+	if mode < 0 || mode > 3 {
+		return ErrInvalidMode
+	}
+	l.threshold = ltsdThresholds[mode]
+	return nil
+}
+
+// Reset 清除噪声幅度谱与长时谱包络
+func (l *LTSDVAD) Reset() {
+	l.elapsed = 0
+	l.ltsd = 0
+	l.frameLen = 0
+}
+
+// spectrum 计算一帧频带内的幅度谱并写入history，返回该幅度谱与能量是否足够
+func (l *LTSDVAD) spectrum(frame []byte, sampleRate int) ([]float64, bool) {
+	n := len(frame) / 2
+	order := bits.Len(uint(n - 1))
+	size := 1 << order
+	lo := int(math.Ceil(ltsdLowHz * float64(size) / float64(sampleRate)))
+	hi := min(int(ltsdHighHz*float64(size)/float64(sampleRate)), size/2)
+	if n != l.frameLen {
+		l.frameLen = n
+		l.window = GenerateWindow(n, HannWindow)
+		l.fft = CreateRealFFT64(order)
+		l.in = make([]float64, size)
+		l.spec = make([]float64, size+2)
+		l.history = make([][]float64, ltsdOrder)
+		for i := range l.history {
+			l.history[i] = make([]float64, hi-lo+1)
+		}
+		l.next, l.frames = 0, 0
+		l.noise = make([]float64, hi-lo+1)
+		l.analyzed = 0
+	}
+
+	var mean, energy float64
+	for i := 0; i < n; i++ {
+		mean += float64(int16(binary.LittleEndian.Uint16(frame[i*2:])))
+	}
+	mean /= float64(n)
+	for i := 0; i < n; i++ {
+		v := float64(int16(binary.LittleEndian.Uint16(frame[i*2:]))) - mean
+		energy += v * v
+		l.in[i] = v * l.window[i]
+	}
+	clear(l.in[n:])
+	l.fft.RealForwardFFT(l.in, l.spec)
+
+	mag := l.history[l.next]
+	for i := range mag {
+		k := lo + i
+		mag[i] = math.Hypot(l.spec[2*k], l.spec[2*k+1])
+	}
+	l.next = (l.next + 1) % ltsdOrder
+	l.frames = min(l.frames+1, ltsdOrder)
+	return mag, energy/float64(n) >= ltsdMinEnergy
+}
+
+// divergence 计算长时谱包络相对噪声幅度谱的散度（dB）
+func (l *LTSDVAD) divergence() float64 {
+	var sum float64
+	for k, nk := range l.noise {
+		var env float64
+		for _, h := range l.history[:l.frames] {
+			env = math.Max(env, h[k])
+		}
+		r := env / (nk + 1)
+		sum += r * r
+	}
+	return 10 * math.Log10(sum/float64(len(l.noise))+1e-12)
+}
@@ -0,0 +1,84 @@
+package webrtcvad
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLTSDVAD 测试长时谱散度检测器在白噪声中检测谐波，且不把噪声判为语音
+func TestLTSDVAD(t *testing.T) {
+	for _, rate := range []int{16000, 48000} {
+		pcm := harmonicNoise(rate, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+		l, err := NewLTSDVAD(3)
+		if err != nil {
+			t.Fatalf("创建LTSDVAD失败: %v", err)
+		}
+
+		step := rate * 3 / 50 // 30ms
+		var noise, speech int
+		for i := 0; i+step <= len(pcm); i += step {
+			ok, err := l.IsSpeech(pcm[i:i+step], rate)
+			if err != nil {
+				t.Fatalf("%dHz: 检测失败: %v", rate, err)
+			}
+			switch at := time.Duration(i/2) * time.Second / time.Duration(rate); {
+			case ok && at < 2*time.Second:
+				noise++
+			case ok && at < 2500*time.Millisecond:
+				speech++
+			}
+		}
+		if noise > 2 {
+			t.Errorf("%dHz: 噪声中不应检测到语音, 误检%d帧", rate, noise)
+		}
+		if speech < 14 {
+			t.Errorf("%dHz: 谐波段的17帧中应大部分为语音, 得到%d帧", rate, speech)
+		}
+		if l.LTSD() <= 0 {
+			t.Errorf("%dHz: 初始化后LTSD应为正, 得到%g", rate, l.LTSD())
+		}
+
+		l.Reset()
+		l.IsSpeech(pcm[:step], rate)
+		if l.LTSD() != 0 {
+			t.Errorf("%dHz: 重置后应重新初始化噪声谱, LTSD应为0, 得到%g", rate, l.LTSD())
+		}
+	}
+
+	l, _ := NewLTSDVAD(0)
+	if _, err := l.IsSpeech(make([]byte, 100), 16000); err == nil {
+		t.Error("无效帧长度应返回错误")
+	}
+	if _, err := l.IsSpeech(make([]byte, 960), 44100); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := NewLTSDVAD(4); err != ErrInvalidMode {
+		t.Errorf("应返回ErrInvalidMode, 得到%v", err)
+	}
+}
+
+// TestWithAlgorithmLTSD 测试StreamVAD按选项使用长时谱散度检测
+func TestWithAlgorithmLTSD(t *testing.T) {
+	pcm := harmonicNoise(16000, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+	svad, err := NewStreamVADWithOptions(WithStreamMode(3), WithAlgorithm(LTSD))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if _, err := svad.Process(pcm); err != nil {
+		t.Fatalf("处理失败: %v", err)
+	}
+	svad.Flush()
+
+	speech := svad.FilterSpeechSegments()
+	if len(speech) == 0 {
+		t.Fatal("应检测到2s-2.5s间的语音")
+	}
+	for _, seg := range speech {
+		if seg.Start < 1900*time.Millisecond || seg.End > 2700*time.Millisecond {
+			t.Errorf("只应在2s-2.5s间检测到语音, 得到%v-%v", seg.Start, seg.End)
+		}
+	}
+	if LTSD.String() != "ltsd" {
+		t.Errorf("算法名称错误: %v", LTSD)
+	}
+}
@@ -0,0 +1,117 @@
+package webrtcvad
+
+import (
+	"math"
+	"sync"
+)
+
+// mel.go 实现Mel滤波器组，是MFCC等语音特征的基础
+
+// melFilter 一个三角滤波器：从第start个频点开始的非零权重
+type melFilter struct {
+	start   int
+	weights []float64
+}
+
+// melKey 滤波器组的缓存键
+type melKey struct {
+	bins, rate, nMels int
+}
+
+// melCacheSize 缓存的滤波器组个数上限，实际使用的参数组合通常只有一两个
+const melCacheSize = 8
+
+// melCache 按频点数、采样率与滤波器数缓存的滤波器组，超出上限时淘汰最早加入的
+var melCache struct {
+	mu      sync.Mutex
+	filters map[melKey][]melFilter
+	order   []melKey // 加入顺序
+}
+
+// HzToMel 将频率（Hz）转换为Mel刻度（HTK公式：2595·log10(1 + f/700)）
+func HzToMel(hz float64) float64 {
+	return 2595 * math.Log10(1+hz/700)
+}
+
+// MelToHz 将Mel刻度转换为频率（Hz），HzToMel的逆
+func MelToHz(mel float64) float64 {
+	return 700 * (math.Pow(10, mel/2595) - 1)
+}
+
+// MelFilterbank 计算一帧频谱在Mel滤波器组上的输出
+//
+// nMels个三角滤波器的中心在0到rate/2之间按Mel刻度均匀分布，相邻滤波器半重叠，峰值为1。
+// 滤波器矩阵按(len(fftMagnitudes), rate, nMels)缓存，至多8组，超出时淘汰最早创建的，可并发调用。
+//
+// 参数:
+//   - fftMagnitudes: 实数FFT的前N/2+1个频点（幅度或功率谱均可，输出与其线性相关）
+//   - rate: 采样率（Hz）
+//   - nMels: 滤波器个数
+//
+// 返回:
+//   - []float64: 各滤波器的加权和（长度nMels），参数无效时返回nil
+func MelFilterbank(fftMagnitudes []float64, rate, nMels int) []float64 {
+	bins := len(fftMagnitudes)
+	if bins < 2 || rate <= 0 || nMels <= 0 {
+		return nil
+	}
+	filters := melFilters(bins, rate, nMels)
+
+	out := make([]float64, nMels)
+	for m, f := range filters {
+		var sum float64
+		for i, w := range f.weights {
+			sum += w * fftMagnitudes[f.start+i]
+		}
+		out[m] = sum
+	}
+	return out
+}
+
+// melFilters 返回缓存的滤波器组，不存在时创建
+func melFilters(bins, rate, nMels int) []melFilter {
+	key := melKey{bins, rate, nMels}
+	melCache.mu.Lock()
+	defer melCache.mu.Unlock()
+	if f, ok := melCache.filters[key]; ok {
+		return f
+	}
+
+	// nMels+2个边界点：第m个滤波器为(edges[m], edges[m+1], edges[m+2])上的三角形
+	maxMel := HzToMel(float64(rate) / 2)
+	edges := make([]float64, nMels+2)
+	for i := range edges {
+		edges[i] = MelToHz(maxMel * float64(i) / float64(nMels+1))
+	}
+	binHz := float64(rate) / float64(2*(bins-1))
+
+	filters := make([]melFilter, nMels)
+	for m := range filters {
+		lo, center, hi := edges[m], edges[m+1], edges[m+2]
+		start := int(math.Ceil(lo / binHz))
+		end := min(int(math.Floor(hi/binHz)), bins-1)
+		f := melFilter{start: start}
+		for k := start; k <= end; k++ {
+			hz := float64(k) * binHz
+			var w float64
+			if hz <= center {
+				w = (hz - lo) / (center - lo)
+			} else {
+				w = (hi - hz) / (hi - center)
+			}
+			f.weights = append(f.weights, math.Max(w, 0))
+		}
+		filters[m] = f
+	}
+
+	if melCache.filters == nil {
+		melCache.filters = make(map[melKey][]melFilter, melCacheSize)
+	}
+	if len(melCache.order) == melCacheSize {
+		delete(melCache.filters, melCache.order[0])
+		melCache.order = append(melCache.order[:0], melCache.order[1:]...)
+	}
+	melCache.filters[key] = filters
+	melCache.order = append(melCache.order, key)
+	return filters
+}
@@ -0,0 +1,67 @@
+package webrtcvad
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMelFilterbank 测试Mel滤波器组的响应、缓存与参数校验
+func TestMelFilterbank(t *testing.T) {
+	if got := MelToHz(HzToMel(1000)); math.Abs(got-1000) > 1e-9 {
+		t.Errorf("Mel与Hz转换应可逆, 得到%v", got)
+	}
+	if got := HzToMel(1000); math.Abs(got-1000) > 0.1 {
+		t.Errorf("1000Hz应约为1000mel, 得到%v", got)
+	}
+
+	// 512点FFT（257个频点）、16kHz、26个滤波器
+	spectrum := make([]float64, 257)
+	for i := range spectrum {
+		spectrum[i] = 1
+	}
+	out := MelFilterbank(spectrum, 16000, 26)
+	if len(out) != 26 {
+		t.Fatalf("应输出26个值, 得到%d", len(out))
+	}
+	// 平坦频谱下，滤波器随频率变宽，输出单调增大
+	for m := 1; m < len(out); m++ {
+		if out[m] <= out[m-1] {
+			t.Errorf("平坦频谱的第%d个滤波器输出应大于前一个: %.2f <= %.2f", m, out[m], out[m-1])
+		}
+	}
+
+	// 单个频点只激活中心附近的两个相邻滤波器，权重之和为1
+	clear(spectrum)
+	spectrum[64] = 1 // 2000Hz
+	out = MelFilterbank(spectrum, 16000, 26)
+	var sum float64
+	active := 0
+	for _, v := range out {
+		if v > 0 {
+			active++
+			sum += v
+		}
+	}
+	if active == 0 || active > 2 || math.Abs(sum-1) > 1e-9 {
+		t.Errorf("2000Hz应激活1-2个滤波器且权重和为1, 得到%d个/%.3f", active, sum)
+	}
+
+	a := melFilters(257, 16000, 26)
+	b := melFilters(257, 16000, 26)
+	if &a[0] != &b[0] {
+		t.Error("相同参数应复用缓存的滤波器组")
+	}
+	for n := 1; n <= 2*melCacheSize; n++ {
+		melFilters(257, 16000, n)
+	}
+	if len(melCache.filters) != melCacheSize || len(melCache.order) != melCacheSize {
+		t.Errorf("缓存应不超过%d组, 得到%d", melCacheSize, len(melCache.filters))
+	}
+	if c := melFilters(257, 16000, 26); &c[0] == &a[0] {
+		t.Error("被淘汰的滤波器组应重新创建")
+	}
+
+	if MelFilterbank(spectrum, 0, 26) != nil || MelFilterbank(spectrum, 16000, 0) != nil || MelFilterbank(nil, 16000, 26) != nil {
+		t.Error("无效参数应返回nil")
+	}
+}
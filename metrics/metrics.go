@@ -0,0 +1,31 @@
+// Package metrics 定义VAD运行指标的上报接口
+//
+// StreamVAD通过WithMetrics接入Recorder，每检测一帧上报一次判决与处理耗时。
+// 本包不依赖任何监控系统，Prometheus的实现见独立模块metrics/prommetrics：
+//
+//	m := prommetrics.New("webrtcvad")
+//	prometheus.MustRegister(m)
+//	svad, _ := webrtcvad.NewStreamVADWithOptions(webrtcvad.WithMetrics(m))
+package metrics
+
+import "time"
+
+// Recorder 逐帧指标的接收方
+//
+// 同一个Recorder通常被多个StreamVAD共享（每个连接一个流），实现须并发安全，
+// 且不应阻塞：方法在检测路径上同步调用
+type Recorder interface {
+	// IncFrames 计入一帧的判决，speech为true表示判为语音
+	IncFrames(speech bool)
+	// ObserveLatency 记录一帧的处理耗时（预处理与检测）
+	ObserveLatency(d time.Duration)
+}
+
+// Nop 丢弃所有指标的Recorder
+type Nop struct{}
+
+// IncFrames 实现Recorder，不做任何事
+func (Nop) IncFrames(bool) {}
+
+// ObserveLatency 实现Recorder，不做任何事
+func (Nop) ObserveLatency(time.Duration) {}
@@ -0,0 +1,88 @@
+// Package prommetrics 以Prometheus指标实现metrics.Recorder
+//
+// Metrics同时实现prometheus.Collector，注册后即可通过/metrics导出：
+//
+//	m := prommetrics.New("webrtcvad")
+//	prometheus.MustRegister(m)
+//	svad, _ := webrtcvad.NewStreamVADWithOptions(webrtcvad.WithMetrics(m))
+//
+// 导出的指标（以namespace为前缀）:
+//   - <namespace>_frames_total{result="speech|silence"}: 已检测的帧数
+//   - <namespace>_frame_latency_seconds: 每帧处理耗时的直方图
+//
+// 本包是独立的Go模块，使用核心库时不会引入Prometheus依赖。
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/godeps/webrtcvad-go/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LatencyBuckets 处理耗时直方图的默认桶（秒）：1µs到约16ms，按4倍递增
+//
+// 单帧检测通常在几到几十微秒，启用降噪等预处理时可达毫秒级
+var LatencyBuckets = prometheus.ExponentialBuckets(1e-6, 4, 8)
+
+// Metrics Prometheus指标，实现metrics.Recorder与prometheus.Collector，并发安全
+type Metrics struct {
+	speech  prometheus.Counter
+	silence prometheus.Counter
+	frames  *prometheus.CounterVec
+	latency prometheus.Histogram
+}
+
+var _ metrics.Recorder = (*Metrics)(nil)
+
+// New 创建Prometheus指标
+//
+// 参数:
+//   - namespace: 指标名前缀，如"webrtcvad"
+//
+// 返回:
+//   - *Metrics: 指标实例，需注册到prometheus.Registerer后才会导出
+func New(namespace string) *Metrics {
+	frames := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "frames_total",
+		Help:      "Number of frames processed by the VAD, by decision.",
+	}, []string{"result"})
+	return &Metrics{
+		speech:  frames.WithLabelValues("speech"),
+		silence: frames.WithLabelValues("silence"),
+		frames:  frames,
+		latency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "frame_latency_seconds",
+			Help:      "Time spent preprocessing and classifying one frame.",
+			Buckets:   LatencyBuckets,
+		}),
+	}
+}
+
+// IncFrames 实现metrics.Recorder
+func (m *Metrics) IncFrames(speech bool) {
+	if speech {
+		m.speech.Inc()
+	} else {
+		m.silence.Inc()
+	}
+}
+
+// ObserveLatency 实现metrics.Recorder
+func (m *Metrics) ObserveLatency(d time.Duration) {
+	m.latency.Observe(d.Seconds())
+}
+
+// Describe 实现prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.frames.Describe(ch)
+	m.latency.Describe(ch)
+}
+
+// Collect 实现prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.frames.Collect(ch)
+	m.latency.Collect(ch)
+}
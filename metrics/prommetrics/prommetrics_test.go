@@ -0,0 +1,45 @@
+package prommetrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestMetrics 测试StreamVAD通过WithMetrics上报的帧数与耗时
+func TestMetrics(t *testing.T) {
+	m := New("webrtcvad")
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(m); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+
+	svad, err := webrtcvad.NewStreamVADWithOptions(webrtcvad.WithFrameDuration(10), webrtcvad.WithMetrics(m))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	svad.Process(make([]byte, 16000)) // 500ms静音，50帧
+
+	want := `
+# HELP webrtcvad_frames_total Number of frames processed by the VAD, by decision.
+# TYPE webrtcvad_frames_total counter
+webrtcvad_frames_total{result="silence"} 50
+webrtcvad_frames_total{result="speech"} 0
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "webrtcvad_frames_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(m, "webrtcvad_frame_latency_seconds"); n != 1 {
+		t.Errorf("应导出1个耗时直方图, 得到%d", n)
+	}
+
+	m.IncFrames(true)
+	m.ObserveLatency(time.Millisecond)
+	if v := testutil.ToFloat64(m.speech); v != 1 {
+		t.Errorf("语音帧计数应为1, 得到%g", v)
+	}
+}
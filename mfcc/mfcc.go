@@ -0,0 +1,194 @@
+// Package mfcc 提取梅尔频率倒谱系数（MFCC）
+//
+// 处理流程与常见语音识别前端一致：预加重、分帧加Hamming窗、FFT求功率谱、
+// Mel滤波器组、取对数、DCT-II（正交归一化），保留前NumCoeffs个系数。
+// 窗函数、FFT与Mel滤波器组复用webrtcvad包的GenerateWindow、RealFFT64与MelFilterbank。
+//
+// 使用示例:
+//
+//	e, err := mfcc.New(mfcc.DefaultConfig(16000))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	// samples: 任意长度的16位样本，返回本次凑满的各帧特征
+//	for _, vec := range e.Write(samples) {
+//	    fmt.Println(vec) // 13维MFCC
+//	}
+//
+// 样本按满幅归一化到[-1, 1)后计算，第0个系数反映帧的对数能量。
+package mfcc
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+)
+
+// ErrInvalidConfig 无效的配置
+var ErrInvalidConfig = errors.New("mfcc: invalid config")
+
+// logFloor Mel能量取对数前的下限，避免静音帧得到-Inf
+const logFloor = 1e-10
+
+// Config MFCC提取配置
+type Config struct {
+	SampleRate  int     // 采样率（Hz）
+	FrameSize   int     // 帧长度（样本数）
+	HopSize     int     // 帧移（样本数），不大于FrameSize
+	FFTSize     int     // FFT点数，2的幂且不小于FrameSize与4；0表示取满足条件的最小2的幂
+	NumMels     int     // Mel滤波器个数
+	NumCoeffs   int     // 输出的倒谱系数个数，不大于NumMels
+	PreEmphasis float64 // 预加重系数（0-1），0表示不预加重
+}
+
+// DefaultConfig 返回常用配置：25ms帧、10ms帧移、26个滤波器、13个系数、预加重0.97
+func DefaultConfig(sampleRate int) Config {
+	return Config{
+		SampleRate:  sampleRate,
+		FrameSize:   sampleRate / 40,
+		HopSize:     sampleRate / 100,
+		NumMels:     26,
+		NumCoeffs:   13,
+		PreEmphasis: 0.97,
+	}
+}
+
+// Extractor 流式MFCC提取器
+//
+// Extractor有状态且不是并发安全的，每路音频流使用一个实例
+type Extractor struct {
+	cfg    Config
+	window []float64
+	dct    [][]float64 // NumCoeffs×NumMels的DCT-II矩阵
+
+	prev    float64   // 预加重的上一个样本
+	pending []float64 // 预加重后尚未凑满一帧的样本
+	fft     *webrtcvad.RealFFT64
+	in, out []float64 // FFT的输入与CCS格式输出
+	power   []float64
+}
+
+// New 创建MFCC提取器
+//
+// 参数:
+//   - cfg: 提取配置
+//
+// 返回:
+//   - *Extractor: 提取器实例
+//   - error: 配置无效时返回ErrInvalidConfig
+func New(cfg Config) (*Extractor, error) {
+	if cfg.FFTSize == 0 && cfg.FrameSize > 0 {
+		cfg.FFTSize = 4
+		for cfg.FFTSize < cfg.FrameSize {
+			cfg.FFTSize <<= 1
+		}
+	}
+	if cfg.SampleRate <= 0 || cfg.FrameSize <= 0 || cfg.HopSize <= 0 || cfg.HopSize > cfg.FrameSize ||
+		cfg.FFTSize < max(cfg.FrameSize, 4) || cfg.FFTSize&(cfg.FFTSize-1) != 0 ||
+		cfg.NumMels <= 0 || cfg.NumCoeffs <= 0 || cfg.NumCoeffs > cfg.NumMels ||
+		cfg.PreEmphasis < 0 || cfg.PreEmphasis >= 1 {
+		return nil, ErrInvalidConfig
+	}
+
+	dct := make([][]float64, cfg.NumCoeffs)
+	for k := range dct {
+		scale := math.Sqrt(2 / float64(cfg.NumMels))
+		if k == 0 {
+			scale = math.Sqrt(1 / float64(cfg.NumMels))
+		}
+		dct[k] = make([]float64, cfg.NumMels)
+		for m := range dct[k] {
+			dct[k][m] = scale * math.Cos(math.Pi*float64(k)*(float64(m)+0.5)/float64(cfg.NumMels))
+		}
+	}
+
+	return &Extractor{
+		cfg:     cfg,
+		window:  webrtcvad.GenerateWindow(cfg.FrameSize, webrtcvad.HammingWindow),
+		dct:     dct,
+		pending: make([]float64, 0, 2*cfg.FrameSize),
+		fft:     webrtcvad.CreateRealFFT64(bits.Len(uint(cfg.FFTSize)) - 1),
+		in:      make([]float64, cfg.FFTSize),
+		out:     make([]float64, cfg.FFTSize+2),
+		power:   make([]float64, cfg.FFTSize/2+1),
+	}, nil
+}
+
+// Config 返回提取器的配置（FFTSize已填充）
+func (e *Extractor) Config() Config {
+	return e.cfg
+}
+
+// Write 追加样本并返回新凑满的各帧MFCC
+//
+// 第一帧在累计FrameSize个样本后产生，之后每HopSize个样本产生一帧；
+// 预加重在帧之间连续。返回的切片归调用方所有
+func (e *Extractor) Write(samples []int16) [][]float64 {
+	for _, s := range samples {
+		v := float64(s) / 32768
+		e.pending = append(e.pending, v-e.cfg.PreEmphasis*e.prev)
+		e.prev = v
+	}
+
+	var out [][]float64
+	consumed := 0
+	for len(e.pending)-consumed >= e.cfg.FrameSize {
+		out = append(out, e.compute(e.pending[consumed:consumed+e.cfg.FrameSize]))
+		consumed += e.cfg.HopSize
+	}
+	if consumed > 0 {
+		e.pending = e.pending[:copy(e.pending, e.pending[consumed:])]
+	}
+	return out
+}
+
+// Compute 计算单独一帧的MFCC，不影响流式状态
+//
+// frame长度须为FrameSize，预加重只在帧内进行。长度不符时返回nil
+func (e *Extractor) Compute(frame []int16) []float64 {
+	if len(frame) != e.cfg.FrameSize {
+		return nil
+	}
+	x := make([]float64, len(frame))
+	var prev float64
+	for i, s := range frame {
+		v := float64(s) / 32768
+		x[i] = v - e.cfg.PreEmphasis*prev
+		prev = v
+	}
+	return e.compute(x)
+}
+
+// Reset 清除缓冲的样本与预加重状态
+func (e *Extractor) Reset() {
+	e.pending = e.pending[:0]
+	e.prev = 0
+}
+
+// compute 对一帧预加重后的样本计算MFCC
+func (e *Extractor) compute(x []float64) []float64 {
+	for i, v := range x {
+		e.in[i] = v * e.window[i]
+	}
+	clear(e.in[len(x):])
+	e.fft.RealForwardFFT(e.in, e.out)
+	for k := range e.power {
+		e.power[k] = e.out[2*k]*e.out[2*k] + e.out[2*k+1]*e.out[2*k+1]
+	}
+
+	mels := webrtcvad.MelFilterbank(e.power, e.cfg.SampleRate, e.cfg.NumMels)
+	for m, v := range mels {
+		mels[m] = math.Log(math.Max(v, logFloor))
+	}
+	coeffs := make([]float64, e.cfg.NumCoeffs)
+	for k, row := range e.dct {
+		var sum float64
+		for m, w := range row {
+			sum += w * mels[m]
+		}
+		coeffs[k] = sum
+	}
+	return coeffs
+}
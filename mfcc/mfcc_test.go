@@ -0,0 +1,96 @@
+package mfcc
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// tone 生成n个样本的正弦波
+func tone(n int, hz, amp float64) []int16 {
+	out := make([]int16, n)
+	for i := range out {
+		out[i] = int16(amp * math.Sin(2*math.Pi*hz*float64(i)/16000))
+	}
+	return out
+}
+
+// distance 两个特征向量的欧氏距离
+func distance(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(sum)
+}
+
+// TestExtractor 测试流式分帧、单帧计算与特征的基本性质
+func TestExtractor(t *testing.T) {
+	e, err := New(DefaultConfig(16000))
+	if err != nil {
+		t.Fatalf("创建提取器失败: %v", err)
+	}
+	if e.Config().FFTSize != 512 {
+		t.Errorf("400样本的帧应使用512点FFT, 得到%d", e.Config().FFTSize)
+	}
+
+	samples := tone(16000, 440, 8000)
+	whole := e.Write(samples)
+	// 1 + (16000-400)/160 = 98帧
+	if len(whole) != 98 || len(whole[0]) != 13 {
+		t.Fatalf("1s音频应得到98帧13维特征, 得到%d帧", len(whole))
+	}
+
+	// 任意分块写入的结果与一次写入相同
+	e.Reset()
+	rng := rand.New(rand.NewSource(1))
+	var chunked [][]float64
+	for i := 0; i < len(samples); {
+		n := min(1+rng.Intn(300), len(samples)-i)
+		chunked = append(chunked, e.Write(samples[i:i+n])...)
+		i += n
+	}
+	if len(chunked) != len(whole) {
+		t.Fatalf("分块写入应得到%d帧, 得到%d帧", len(whole), len(chunked))
+	}
+	for i := range whole {
+		if d := distance(whole[i], chunked[i]); d > 1e-9 {
+			t.Fatalf("第%d帧分块写入结果不同, 距离%g", i, d)
+		}
+	}
+	if d := distance(e.Compute(samples[:400]), whole[0]); d > 1e-9 {
+		t.Errorf("第一帧的Compute结果应与Write相同, 距离%g", d)
+	}
+
+	// 音量增大6dB主要改变第0个系数（对数能量），其余系数只受量化误差影响
+	loud := e.Compute(tone(400, 440, 16000))
+	quiet := e.Compute(tone(400, 440, 8000))
+	if diff := loud[0] - quiet[0]; math.Abs(diff-math.Log(4)*math.Sqrt(26)) > 0.05 {
+		t.Errorf("幅度加倍时c0应增大ln4·√26, 得到%.3f", diff)
+	}
+	if d := distance(loud[1:], quiet[1:]); d > 0.05 {
+		t.Errorf("幅度变化几乎不应影响c1以后的系数, 距离%g", d)
+	}
+	if d := distance(e.Compute(tone(400, 300, 8000)), quiet); d < 1 {
+		t.Errorf("不同频率的特征应明显不同, 距离%g", d)
+	}
+	for _, v := range e.Compute(make([]int16, 400)) {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			t.Fatal("静音帧的特征应为有限值")
+		}
+	}
+	if e.Compute(make([]int16, 100)) != nil {
+		t.Error("长度不符的帧应返回nil")
+	}
+
+	for _, cfg := range []Config{
+		{},
+		{SampleRate: 16000, FrameSize: 400, HopSize: 500, NumMels: 26, NumCoeffs: 13},
+		{SampleRate: 16000, FrameSize: 400, HopSize: 160, FFTSize: 300, NumMels: 26, NumCoeffs: 13},
+		{SampleRate: 16000, FrameSize: 400, HopSize: 160, NumMels: 10, NumCoeffs: 13},
+	} {
+		if _, err := New(cfg); err != ErrInvalidConfig {
+			t.Errorf("配置%+v应返回ErrInvalidConfig, 得到%v", cfg, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package webrtcvad
+
+import "math"
+
+// minimum_stats.go 实现VAD噪声估计所用的最小值统计（findMinimum），并以MinimumStatistics公开，
+// 可用于噪声底显示、自适应门限等
+
+// kMinimumCandidates 保存的最小值候选个数
+const kMinimumCandidates = 16
+
+// updateMinima 维护最近window次更新内按升序排列的最小值候选（findMinimum的核心）
+//
+// 每个候选的年龄加1，达到window的候选被移除，末尾以empty补齐；x小于某个候选时插入到
+// 第一个比它大的候选之前。移除时被前移的候选本次不再老化，与WebRTC一致
+//
+// 参数:
+//   - values: 按升序排列的候选
+//   - age: 每个候选的年龄（更新次数），与values等长
+//   - x: 新的值
+//   - window: 窗口长度
+//   - empty: 空位的值（大于任何有效值）
+func updateMinima[T int16 | float64](values []T, age []int16, x T, window int16, empty T) {
+	n := len(values)
+	for i := 0; i < n; i++ {
+		if age[i] != window {
+			age[i]++
+		} else {
+			// 值太旧，从内存中移除并向下移动较大的值
+			copy(values[i:n-1], values[i+1:])
+			copy(age[i:n-1], age[i+1:])
+			age[n-1] = window + 1
+			values[n-1] = empty
+		}
+	}
+
+	// 如果是新的小值，插入正确位置并向上移动较大的值
+	for pos, v := range values {
+		if x < v {
+			copy(values[pos+1:], values[pos:n-1])
+			copy(age[pos+1:], age[pos:n-1])
+			values[pos] = x
+			age[pos] = 1
+			return
+		}
+	}
+}
+
+// MinimumStatsConfig 最小值统计配置
+type MinimumStatsConfig struct {
+	// Window 跟踪最小值的窗口长度（更新次数），1-32766。VAD逐帧更新，取100
+	Window int
+	// SmoothDown 最小值估计低于平滑值时旧值的权重（0-1），越小跟随越快
+	SmoothDown float64
+	// SmoothUp 最小值估计高于平滑值时旧值的权重（0-1），越大上升越慢
+	SmoothUp float64
+}
+
+// DefaultMinimumStatsConfig 返回与VAD噪声估计相同的配置：窗口100次，平滑系数0.2/0.99
+func DefaultMinimumStatsConfig() MinimumStatsConfig {
+	return MinimumStatsConfig{Window: 100, SmoothDown: 0.2, SmoothUp: 0.99}
+}
+
+// MinimumStatistics 滑动窗口最小值跟踪器
+//
+// 保存最近Window次更新内最小的16个值，取第三小的值（五个最小值的中位数，比最小值稳健）
+// 作为当前估计，再做非对称平滑：估计下降时快速跟随，上升时缓慢。输入为噪声能量、
+// 特征值等时，输出即为跟踪噪声底的慢变估计，语音等短时上升不影响结果。
+// MinimumStatistics有状态且不是并发安全的
+type MinimumStatistics struct {
+	cfg    MinimumStatsConfig
+	values [kMinimumCandidates]float64
+	age    [kMinimumCandidates]int16
+	count  int     // 已更新的次数
+	value  float64 // 平滑后的最小值估计
+}
+
+// NewMinimumStatistics 创建最小值跟踪器
+//
+// 参数:
+//   - cfg: 配置，见DefaultMinimumStatsConfig
+//
+// 返回:
+//   - *MinimumStatistics: 跟踪器实例
+//   - error: 配置无效时返回ErrInvalidMinimumStats
+func NewMinimumStatistics(cfg MinimumStatsConfig) (*MinimumStatistics, error) {
+	if cfg.Window < 1 || cfg.Window >= math.MaxInt16 ||
+		!(cfg.SmoothDown >= 0 && cfg.SmoothDown <= 1) || !(cfg.SmoothUp >= 0 && cfg.SmoothUp <= 1) {
+		return nil, ErrInvalidMinimumStats
+	}
+	m := &MinimumStatistics{cfg: cfg}
+	m.Reset()
+	return m, nil
+}
+
+// Update 加入一个新值，返回更新后的平滑最小值估计
+func (m *MinimumStatistics) Update(x float64) float64 {
+	updateMinima(m.values[:], m.age[:], x, int16(m.cfg.Window), math.Inf(1))
+
+	// 前三次更新时候选不足，取最小值
+	current := m.values[0]
+	if m.count > 2 {
+		current = m.values[2]
+	}
+	switch {
+	case m.count == 0:
+		m.value = current
+	case current < m.value:
+		m.value = m.cfg.SmoothDown*m.value + (1-m.cfg.SmoothDown)*current
+	default:
+		m.value = m.cfg.SmoothUp*m.value + (1-m.cfg.SmoothUp)*current
+	}
+	m.count++
+	return m.value
+}
+
+// Value 返回当前的平滑最小值估计，尚未更新时为0
+func (m *MinimumStatistics) Value() float64 {
+	return m.value
+}
+
+// Min 返回窗口内的最小值，尚未更新时为+Inf
+func (m *MinimumStatistics) Min() float64 {
+	return m.values[0]
+}
+
+// Reset 清除所有候选与估计
+func (m *MinimumStatistics) Reset() {
+	for i := range m.values {
+		m.values[i] = math.Inf(1)
+		m.age[i] = 0
+	}
+	m.count = 0
+	m.value = 0
+}
@@ -0,0 +1,74 @@
+package webrtcvad
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMinimumStatistics 测试噪声底跟踪：忽略短时上升，窗口过后跟随底噪抬升
+func TestMinimumStatistics(t *testing.T) {
+	m, err := NewMinimumStatistics(DefaultMinimumStatsConfig())
+	if err != nil {
+		t.Fatalf("创建跟踪器失败: %v", err)
+	}
+	if m.Value() != 0 || !math.IsInf(m.Min(), 1) {
+		t.Errorf("初始估计应为0、最小值为+Inf, 得到%g, %g", m.Value(), m.Min())
+	}
+
+	// 底噪10附近，每50次更新出现20次的"语音"（100）
+	for i := 0; i < 330; i++ {
+		x := 10 + float64(i%3)
+		if i%50 >= 30 {
+			x = 100
+		}
+		m.Update(x)
+	}
+	if v := m.Value(); v < 10 || v > 12 {
+		t.Errorf("短时上升不应影响估计, 应在[10, 12]内, 得到%g", v)
+	}
+	if m.Min() != 10 {
+		t.Errorf("窗口内最小值应为10, 得到%g", m.Min())
+	}
+
+	// 底噪抬升到40：旧的最小值在100次更新后才移出窗口，之后估计缓慢上升
+	for i := 0; i < 80; i++ {
+		m.Update(40)
+	}
+	if v := m.Value(); v > 12 {
+		t.Errorf("窗口内仍有旧的最小值, 估计不应上升, 得到%g", v)
+	}
+	for i := 0; i < 400; i++ {
+		m.Update(40)
+	}
+	if v := m.Value(); v < 39 || v > 40 {
+		t.Errorf("估计应跟随到40附近, 得到%g", v)
+	}
+
+	// 底噪下降时快速跟随
+	for i := 0; i < 5; i++ {
+		m.Update(5)
+	}
+	if v := m.Value(); v > 5.5 {
+		t.Errorf("估计应快速下降到5附近, 得到%g", v)
+	}
+
+	m.Reset()
+	if got := m.Update(7); got != 7 {
+		t.Errorf("重置后第一次更新的估计应为7, 得到%g", got)
+	}
+}
+
+// TestMinimumStatisticsConfig 测试配置校验
+func TestMinimumStatisticsConfig(t *testing.T) {
+	for _, cfg := range []MinimumStatsConfig{
+		{Window: 0, SmoothDown: 0.2, SmoothUp: 0.99},
+		{Window: math.MaxInt16, SmoothDown: 0.2, SmoothUp: 0.99},
+		{Window: 100, SmoothDown: -0.1, SmoothUp: 0.99},
+		{Window: 100, SmoothDown: 0.2, SmoothUp: 1.5},
+		{Window: 100, SmoothDown: math.NaN(), SmoothUp: 0.99},
+	} {
+		if _, err := NewMinimumStatistics(cfg); err != ErrInvalidMinimumStats {
+			t.Errorf("%+v 应返回ErrInvalidMinimumStats, 得到%v", cfg, err)
+		}
+	}
+}
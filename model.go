@@ -0,0 +1,154 @@
+package webrtcvad
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// model.go 提供GMM模型参数的导出和导入
+// 允许针对特定环境（车载、呼叫中心、远场等）预训练模型，而不总是从内置表开始
+
+// 模型维度
+const (
+	// NumChannels VAD特征使用的频带数量
+	NumChannels = kNumChannels
+	// NumGaussians 每个频带的高斯分布数量
+	NumGaussians = kNumGaussians
+)
+
+// Model VAD使用的高斯混合模型参数
+//
+// 每个数组按 [高斯k*6 + 频带channel] 排列（6个频带 × 2个高斯），
+// 与WebRTC原始参数表布局一致。均值和标准差为Q7定点数，
+// 权重为Q7定点数且同一频带的两个高斯权重之和应为128。
+type Model struct {
+	NoiseMeans    [kTableSize]int16 `json:"noise_means"`
+	SpeechMeans   [kTableSize]int16 `json:"speech_means"`
+	NoiseStds     [kTableSize]int16 `json:"noise_stds"`
+	SpeechStds    [kTableSize]int16 `json:"speech_stds"`
+	NoiseWeights  [kTableSize]int16 `json:"noise_weights"`
+	SpeechWeights [kTableSize]int16 `json:"speech_weights"`
+}
+
+// DefaultModel 返回WebRTC内置的初始模型参数
+func DefaultModel() Model {
+	return Model{
+		NoiseMeans:    kNoiseDataMeans,
+		SpeechMeans:   kSpeechDataMeans,
+		NoiseStds:     kNoiseDataStds,
+		SpeechStds:    kSpeechDataStds,
+		NoiseWeights:  kNoiseDataWeights,
+		SpeechWeights: kSpeechDataWeights,
+	}
+}
+
+// minModelStd 模型标准差的下限（Q7）
+//
+// 自适应更新把标准差限制在kMinStd（384）以上，但WebRTC内置噪声表的第一个值为378，
+// 下限取二者中较小者，使DefaultModel与新建VAD的Model()快照仍然有效
+const minModelStd = 378
+
+// Validate 检查模型参数是否可用于VAD
+//
+// 标准差不得低于核心的下限（过小会使定点高斯概率计算溢出），权重必须非负，
+// 且同一频带两个高斯的权重之和必须为128（Q7的1.0）
+func (m Model) Validate() error {
+	for i := 0; i < kTableSize; i++ {
+		if m.NoiseStds[i] < minModelStd || m.SpeechStds[i] < minModelStd {
+			return fmt.Errorf("%w: std at index %d must be at least %d", ErrInvalidModel, i, minModelStd)
+		}
+		if m.NoiseWeights[i] < 0 || m.SpeechWeights[i] < 0 {
+			return fmt.Errorf("%w: weight at index %d must be non-negative", ErrInvalidModel, i)
+		}
+	}
+	for ch := 0; ch < kNumChannels; ch++ {
+		var noise, speech int
+		for k := 0; k < kNumGaussians; k++ {
+			noise += int(m.NoiseWeights[k*kNumChannels+ch])
+			speech += int(m.SpeechWeights[k*kNumChannels+ch])
+		}
+		if noise != 128 || speech != 128 {
+			return fmt.Errorf("%w: weights of channel %d must sum to 128, got %d (noise) and %d (speech)",
+				ErrInvalidModel, ch, noise, speech)
+		}
+	}
+	return nil
+}
+
+// ParseModel 从JSON解析模型参数并校验
+func ParseModel(data []byte) (Model, error) {
+	var m Model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Model{}, fmt.Errorf("%w: %v", ErrInvalidModel, err)
+	}
+	if err := m.Validate(); err != nil {
+		return Model{}, err
+	}
+	return m, nil
+}
+
+// Model 返回VAD当前的模型参数
+//
+// 未冻结模型时，均值和标准差会随处理的音频自适应变化，
+// 返回值是调用时刻的快照；未初始化时返回零值
+func (v *VAD) Model() Model {
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return Model{}
+	}
+	return Model{
+		NoiseMeans:    v.inst.noiseMeans,
+		SpeechMeans:   v.inst.speechMeans,
+		NoiseStds:     v.inst.noiseStds,
+		SpeechStds:    v.inst.speechStds,
+		NoiseWeights:  v.inst.noiseWeights,
+		SpeechWeights: v.inst.speechWeights,
+	}
+}
+
+// SetModel 替换VAD的模型参数
+//
+// 仅替换GMM参数，不影响滤波器状态和激进度设置。
+// 设置的模型在Reset后仍作为初始模型（未冻结时自适应的结果不保留）
+func (v *VAD) SetModel(m Model) error {
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return ErrNotInitialized
+	}
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	loadModel(v.inst, &m)
+
+	if v.logger != nil {
+		v.logger.Info("model replaced")
+	}
+	return nil
+}
+
+// Features 返回最近一帧的频带对数能量特征
+//
+// 6个频带依次为 80-250, 250-500, 500-1000, 1000-2000, 2000-3000, 3000-4000 Hz，
+// 值为 10*log10(能量) 的Q4定点数，与Model中均值的单位一致（Model为Q7）。
+//
+// 第二个返回值表示该帧能量是否超过最小阈值；低于阈值的帧不参与GMM判决和模型更新，
+// 离线训练时应当跳过。未初始化时返回零值和false。
+func (v *VAD) Features() ([NumChannels]int16, bool) {
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return [NumChannels]int16{}, false
+	}
+	return v.inst.features, v.inst.totalPower > kMinEnergy
+}
+
+// NoiseFloor 返回各频带的背景噪声底估计
+//
+// 值为最近约100帧内各频带特征最小值经平滑后的结果，单位与Features相同
+// （10*log10(能量)的Q4定点数），可用于显示/记录环境噪声水平或驱动自适应增益。
+//
+// 仅在能量超过最小阈值的帧上更新；冻结模型（WithFrozenModel）时不更新。
+// 未初始化时返回零值。
+func (v *VAD) NoiseFloor() [NumChannels]int16 {
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return [NumChannels]int16{}
+	}
+	return v.inst.meanValue
+}
@@ -0,0 +1,131 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// model_detector.go 将外部神经网络VAD（如Silero VAD的ONNX模型）适配为Detector
+//
+// 本包不依赖推理运行时：调用方以InferenceFunc包装自己的模型，
+// ModelDetector负责把10/20/30ms的帧拼接成模型要求的窗口、归一化样本并对概率做迟滞判决
+
+// InferenceFunc 外部模型的推理函数
+//
+// 输入为归一化到[-1, 1)的一个窗口的样本（调用返回后不再引用，切片会被复用），
+// 返回该窗口的语音概率（0-1）
+type InferenceFunc func(window []float32) float32
+
+// ModelConfig ModelDetector的配置
+type ModelConfig struct {
+	SampleRate   int     // 模型要求的采样率，输入帧必须为此采样率
+	WindowSize   int     // 每次推理的样本数
+	Threshold    float32 // 概率不低于此值时判为语音
+	NegThreshold float32 // 判为语音后，概率低于此值才恢复为非语音，须不高于Threshold
+	// Reset 可选，ModelDetector.Reset时调用，用于清除模型的内部状态（如RNN的隐状态）
+	Reset func()
+}
+
+// SileroConfig 返回Silero VAD的推荐配置（支持8000与16000Hz）
+//
+// 窗口为16kHz下512个样本、8kHz下256个样本（32ms），门限0.5，迟滞门限0.35
+func SileroConfig(sampleRate int) ModelConfig {
+	return ModelConfig{
+		SampleRate:   sampleRate,
+		WindowSize:   sampleRate / 8000 * 256,
+		Threshold:    0.5,
+		NegThreshold: 0.35,
+	}
+}
+
+// ModelDetector 以外部推理函数判决的语音检测器
+//
+// 窗口与帧长度不必对齐：每帧的样本追加到缓冲区，凑满窗口即推理一次，
+// 帧的判决为最近一次推理后的状态（尚未推理过时为非语音）。
+// ModelDetector有状态且不是并发安全的，实现Detector接口
+type ModelDetector struct {
+	infer InferenceFunc
+	cfg   ModelConfig
+
+	buf    []float32 // 未满一个窗口的样本
+	prob   float32   // 最近一次推理的概率
+	speech bool      // 迟滞判决的当前状态
+}
+
+// NewModelDetector 创建外部模型检测器
+//
+// 参数:
+//   - infer: 推理函数
+//   - cfg: 采样率、窗口与判决门限
+//
+// 返回:
+//   - *ModelDetector: 检测器实例
+//   - error: 采样率无效时返回ErrInvalidSampleRate，其他参数无效时返回ErrInvalidModelConfig
+func NewModelDetector(infer InferenceFunc, cfg ModelConfig) (*ModelDetector, error) {
+	if !isValidSampleRate(cfg.SampleRate) {
+		return nil, ErrInvalidSampleRate
+	}
+	if infer == nil || cfg.WindowSize <= 0 ||
+		cfg.Threshold <= 0 || cfg.Threshold > 1 ||
+		cfg.NegThreshold < 0 || cfg.NegThreshold > cfg.Threshold {
+		return nil, ErrInvalidModelConfig
+	}
+	return &ModelDetector{
+		infer: infer,
+		cfg:   cfg,
+		buf:   make([]float32, 0, 2*cfg.WindowSize),
+	}, nil
+}
+
+// IsSpeech 判断一帧是否为语音，实现Detector接口
+//
+// 参数:
+//   - frame: 16位小端序PCM，长度须对应10ms、20ms或30ms
+//   - sampleRate: 采样率，须与ModelConfig.SampleRate相同
+//
+// 返回:
+//   - bool: true表示检测到语音
+//   - error: 参数无效时返回错误
+func (m *ModelDetector) IsSpeech(frame []byte, sampleRate int) (bool, error) {
+	if sampleRate != m.cfg.SampleRate {
+		return false, fmt.Errorf("%w: model expects %d Hz, got %d", ErrInvalidSampleRate, m.cfg.SampleRate, sampleRate)
+	}
+	n := len(frame) / 2
+	if !ValidRateAndFrameLength(sampleRate, n) {
+		return false, fmt.Errorf("%w: got %d samples at %d Hz", ErrInvalidFrameLength, n, sampleRate)
+	}
+
+	for i := 0; i < n; i++ {
+		m.buf = append(m.buf, float32(int16(binary.LittleEndian.Uint16(frame[i*2:])))/32768)
+	}
+	w := m.cfg.WindowSize
+	consumed := 0
+	for ; len(m.buf)-consumed >= w; consumed += w {
+		m.prob = m.infer(m.buf[consumed : consumed+w])
+		switch {
+		case m.prob >= m.cfg.Threshold:
+			m.speech = true
+		case m.prob < m.cfg.NegThreshold:
+			m.speech = false
+		}
+	}
+	if consumed > 0 {
+		m.buf = m.buf[:copy(m.buf, m.buf[consumed:])]
+	}
+	return m.speech, nil
+}
+
+// Probability 返回最近一次推理的语音概率
+func (m *ModelDetector) Probability() float32 {
+	return m.prob
+}
+
+// Reset 清除缓冲的样本与判决状态，并调用ModelConfig.Reset（若设置）
+func (m *ModelDetector) Reset() {
+	m.buf = m.buf[:0]
+	m.prob = 0
+	m.speech = false
+	if m.cfg.Reset != nil {
+		m.cfg.Reset()
+	}
+}
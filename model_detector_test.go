@@ -0,0 +1,105 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestModelDetector 测试窗口拼接、归一化、迟滞判决与重置
+func TestModelDetector(t *testing.T) {
+	var windows int
+	var peak float32
+	probs := []float32{0.2, 0.6, 0.4, 0.3}
+	resets := 0
+	cfg := SileroConfig(16000)
+	cfg.Reset = func() { resets++ }
+	m, err := NewModelDetector(func(w []float32) float32 {
+		if len(w) != 512 {
+			t.Fatalf("窗口应为512个样本, 得到%d", len(w))
+		}
+		for _, v := range w {
+			if v > peak {
+				peak = v
+			}
+		}
+		p := probs[windows%len(probs)]
+		windows++
+		return p
+	}, cfg)
+	if err != nil {
+		t.Fatalf("创建ModelDetector失败: %v", err)
+	}
+
+	// 满幅的一半：归一化后为0.5
+	frame := make([]byte, 0, 640)
+	for i := 0; i < 320; i++ {
+		frame = binary.LittleEndian.AppendUint16(frame, uint16(16384))
+	}
+	// 20ms帧（320样本）：第2、4、5、7帧各凑满一个512样本的窗口
+	want := []bool{false, false, false, true, true, true, false}
+	for i, w := range want {
+		got, err := m.IsSpeech(frame, 16000)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		if got != w {
+			t.Errorf("第%d帧判决应为%v, 得到%v（概率%.1f）", i+1, w, got, m.Probability())
+		}
+	}
+	if windows != 4 || peak != 0.5 {
+		t.Errorf("应推理4次且样本归一化为0.5, 得到%d次/%v", windows, peak)
+	}
+
+	m.Reset()
+	if resets != 1 || m.Probability() != 0 || len(m.buf) != 0 {
+		t.Error("重置应清除状态并调用ModelConfig.Reset")
+	}
+	if _, err := m.IsSpeech(make([]byte, 320), 8000); !errors.Is(err, ErrInvalidSampleRate) {
+		t.Errorf("采样率不符应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := NewModelDetector(nil, cfg); err != ErrInvalidModelConfig {
+		t.Errorf("应返回ErrInvalidModelConfig, 得到%v", err)
+	}
+	cfg.NegThreshold = 0.6
+	if _, err := NewModelDetector(func([]float32) float32 { return 0 }, cfg); err != ErrInvalidModelConfig {
+		t.Errorf("迟滞门限高于门限应返回ErrInvalidModelConfig, 得到%v", err)
+	}
+}
+
+// TestWithInference 测试StreamVAD使用外部模型分段
+func TestWithInference(t *testing.T) {
+	// 以窗口RMS模拟模型：RMS超过0.05时概率为0.9
+	infer := func(w []float32) float32 {
+		var sum float64
+		for _, v := range w {
+			sum += float64(v) * float64(v)
+		}
+		if math.Sqrt(sum/float64(len(w))) > 0.05 {
+			return 0.9
+		}
+		return 0.1
+	}
+	svad, err := NewStreamVADWithOptions(WithInference(infer, SileroConfig(16000)), WithFrameDuration(30))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	pcm := make([]byte, 32000, 96000)
+	for i := 0; i < 16000; i++ {
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(8000*math.Sin(2*math.Pi*300*float64(i)/16000))))
+	}
+	pcm = append(pcm, make([]byte, 32000)...)
+	svad.Process(pcm)
+	svad.Flush()
+
+	speech := svad.FilterSpeechSegments()
+	if len(speech) != 1 || (speech[0].Start-time.Second).Abs() > 60*time.Millisecond || (speech[0].End-2*time.Second).Abs() > 60*time.Millisecond {
+		t.Fatalf("应检测到1s-2s的语音, 得到%v", speech)
+	}
+
+	if _, err := NewStreamVADWithOptions(WithInference(infer, SileroConfig(8000))); !errors.Is(err, ErrInvalidSampleRate) {
+		t.Errorf("模型与流的采样率不符应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package webrtcvad
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestModelRoundTrip 测试模型导出、JSON编解码和导入
+func TestModelRoundTrip(t *testing.T) {
+	vad, err := New(1)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+
+	if vad.Model() != DefaultModel() {
+		t.Fatal("新建VAD的模型应与内置模型一致")
+	}
+
+	m := DefaultModel()
+	m.NoiseMeans[0] += 128
+	m.SpeechWeights[0], m.SpeechWeights[6] = 64, 64
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("JSON编码失败: %v", err)
+	}
+	parsed, err := ParseModel(data)
+	if err != nil {
+		t.Fatalf("JSON解析失败: %v", err)
+	}
+	if parsed != m {
+		t.Fatal("JSON往返后模型不一致")
+	}
+
+	if err := vad.SetModel(parsed); err != nil {
+		t.Fatalf("设置模型失败: %v", err)
+	}
+	if vad.Model() != m {
+		t.Error("SetModel后Model()应返回设置的参数")
+	}
+
+	// 设置的模型应参与检测
+	if _, err := vad.IsSpeech(make([]byte, 320), 16000); err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+
+	if m := (&VAD{}).Model(); m != (Model{}) {
+		t.Error("未初始化时模型应为零值")
+	}
+	if features, ok := (&VAD{}).Features(); ok || features != ([NumChannels]int16{}) {
+		t.Errorf("未初始化时特征应为零值, 得到%v/%v", features, ok)
+	}
+}
+
+// TestModelSurvivesReset 测试Reset后保留设置的模型
+func TestModelSurvivesReset(t *testing.T) {
+	vad, err := New(1)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	m := DefaultModel()
+	m.NoiseMeans[0] += 128
+	if err := vad.SetModel(m); err != nil {
+		t.Fatalf("设置模型失败: %v", err)
+	}
+
+	svad, err := NewStreamVADWithOptions(WithDetector(vad))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	svad.Process(harmonicNoise(16000, time.Second, 300*time.Millisecond, 600*time.Millisecond))
+	if err := svad.Reset(); err != nil {
+		t.Fatalf("Reset失败: %v", err)
+	}
+	if vad.Model() != m {
+		t.Error("StreamVAD.Reset后应恢复SetModel设置的模型")
+	}
+
+	hybrid, err := NewHybridVAD(1, DefaultHybridConfig())
+	if err != nil {
+		t.Fatalf("创建HybridVAD失败: %v", err)
+	}
+	if err := hybrid.gmm.SetModel(m); err != nil {
+		t.Fatalf("设置模型失败: %v", err)
+	}
+	hybrid.Reset()
+	if hybrid.gmm.Model() != m {
+		t.Error("HybridVAD.Reset后应恢复SetModel设置的模型")
+	}
+}
+
+// TestModelValidate 测试无效模型被拒绝
+func TestModelValidate(t *testing.T) {
+	m := DefaultModel()
+	m.SpeechStds[3] = 0
+
+	vad, err := New(0)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	if err := vad.SetModel(m); !errors.Is(err, ErrInvalidModel) {
+		t.Errorf("期望ErrInvalidModel, 得到%v", err)
+	}
+
+	if err := DefaultModel().Validate(); err != nil {
+		t.Errorf("内置模型应有效: %v", err)
+	}
+	for name, modify := range map[string]func(*Model){
+		"标准差过小": func(m *Model) { m.NoiseStds[2] = 100 },
+		"权重为负":  func(m *Model) { m.SpeechWeights[0], m.SpeechWeights[6] = -10, 138 },
+		"权重之和":  func(m *Model) { m.NoiseWeights[1]++ },
+	} {
+		m := DefaultModel()
+		modify(&m)
+		if err := m.Validate(); !errors.Is(err, ErrInvalidModel) {
+			t.Errorf("%s: 期望ErrInvalidModel, 得到%v", name, err)
+		}
+	}
+
+	if _, err := ParseModel([]byte("{")); !errors.Is(err, ErrInvalidModel) {
+		t.Errorf("期望ErrInvalidModel, 得到%v", err)
+	}
+
+	if _, err := NewWithOptions(WithModel(m)); err == nil {
+		t.Error("WithModel应拒绝无效模型")
+	}
+}
+
+// TestNoiseFloor 测试噪声底估计跟随背景电平
+func TestNoiseFloor(t *testing.T) {
+	vad, err := New(0)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+
+	initial := vad.NoiseFloor()
+	for ch, v := range initial {
+		if v != 1600 {
+			t.Fatalf("频带%d初始噪声底应为1600, 得到%d", ch, v)
+		}
+	}
+
+	// 持续的低电平白噪声会把噪声底拉向其能量
+	noise := make([]byte, 160)
+	seed := uint32(1)
+	for i := 0; i < len(noise); i += 2 {
+		seed = seed*1103515245 + 12345
+		v := int16(seed>>16) >> 8 // 约±128
+		noise[i] = byte(v)
+		noise[i+1] = byte(v >> 8)
+	}
+	for i := 0; i < 200; i++ {
+		if _, err := vad.IsSpeech(noise, 8000); err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+	}
+
+	floor := vad.NoiseFloor()
+	if floor == initial {
+		t.Error("处理噪声后噪声底应更新")
+	}
+	features, _ := vad.Features()
+	for ch := range floor {
+		if floor[ch] > features[ch]+160 {
+			t.Errorf("频带%d噪声底%d远高于当前特征%d", ch, floor[ch], features[ch])
+		}
+	}
+
+	if floor := (&VAD{}).NoiseFloor(); floor != ([NumChannels]int16{}) {
+		t.Errorf("未初始化时噪声底应为零值, 得到%v", floor)
+	}
+}
@@ -0,0 +1,74 @@
+package ns
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// fft.go 提供长度为2^a·3^b的浮点复数FFT（按时间抽取的混合基递归实现），
+// 使各采样率下的分析长度都为块长度的1.6倍（如16kHz下256点、48kHz下768点）
+
+// fftPlan 固定长度的FFT
+type fftPlan struct {
+	n       int
+	twiddle []complex128 // e^{-2πik/n}
+}
+
+// newFFTPlan 创建长度为n的FFT，n只能含因子2和3
+func newFFTPlan(n int) *fftPlan {
+	tw := make([]complex128, n)
+	for k := range tw {
+		tw[k] = cmplx.Rect(1, -2*math.Pi*float64(k)/float64(n))
+	}
+	return &fftPlan{n: n, twiddle: tw}
+}
+
+// forward 计算in的DFT写入out，out与in不能重叠
+func (p *fftPlan) forward(out, in []complex128) {
+	p.transform(out, in, p.n, 1)
+}
+
+// inverse 计算in的逆DFT（含1/n归一化）写入out，in被改写为共轭
+func (p *fftPlan) inverse(out, in []complex128) {
+	for i := range in {
+		in[i] = cmplx.Conj(in[i])
+	}
+	p.transform(out, in, p.n, 1)
+	scale := 1 / float64(p.n)
+	for i := range out {
+		out[i] = cmplx.Conj(out[i]) * complex(scale, 0)
+	}
+}
+
+// transform 对in中步长为stride的n个样本做DFT，结果连续写入out[:n]
+func (p *fftPlan) transform(out, in []complex128, n, stride int) {
+	if n == 1 {
+		out[0] = in[0]
+		return
+	}
+	radix := 2
+	if n%2 != 0 {
+		radix = 3
+	}
+	m := n / radix
+	for r := 0; r < radix; r++ {
+		p.transform(out[r*m:(r+1)*m], in[r*stride:], m, stride*radix)
+	}
+
+	// 合并radix个长度为m的子变换
+	step := p.n / n
+	var x [3]complex128
+	for k := 0; k < m; k++ {
+		for r := 0; r < radix; r++ {
+			x[r] = out[r*m+k]
+		}
+		for q := 0; q < radix; q++ {
+			idx := k + q*m
+			sum := x[0]
+			for r := 1; r < radix; r++ {
+				sum += x[r] * p.twiddle[(r*idx*step)%p.n]
+			}
+			out[idx] = sum
+		}
+	}
+}
@@ -0,0 +1,438 @@
+// Package ns 移植WebRTC的噪声抑制（NS）模块
+//
+// 对每个10ms块做加窗FFT，以分位数估计噪声谱（启动阶段混合白/粉红噪声参数模型），
+// 按似然比特征估计各频点的语音概率并据此更新噪声，再以判决引导（decision-directed）
+// 的先验信噪比构造Wiener滤波器，重叠相加后输出。相比WebRTC的实现，语音概率只使用
+// 似然比特征，省略了频谱平坦度与频谱差异特征，其余常量与更新规则保持一致。
+//
+// 使用示例:
+//
+//	s, err := ns.New(16000, ns.Medium)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	// frame: 10ms整数倍的16位样本，就地降噪
+//	if err := s.Process(frame); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// 输出相对输入延迟分析窗的重叠长度（块长度的0.6倍，即6ms）。
+package ns
+
+import (
+	"errors"
+	"math"
+)
+
+// Policy 抑制强度
+type Policy int
+
+const (
+	// Mild 轻度抑制，噪声最多衰减6dB
+	Mild Policy = iota
+	// Medium 中度抑制，噪声最多衰减12dB
+	Medium
+	// Aggressive 强抑制，噪声最多衰减18dB
+	Aggressive
+	// VeryAggressive 最强抑制，噪声最多衰减21dB
+	VeryAggressive
+)
+
+var (
+	// ErrInvalidSampleRate 不支持的采样率
+	ErrInvalidSampleRate = errors.New("ns: sample rate must be 8000, 16000, 24000, 32000, or 48000 Hz")
+
+	// ErrInvalidPolicy 无效的抑制强度
+	ErrInvalidPolicy = errors.New("ns: policy must be Mild, Medium, Aggressive, or VeryAggressive")
+
+	// ErrInvalidFrameLength 帧长度不是10ms的整数倍
+	ErrInvalidFrameLength = errors.New("ns: frame length must be a multiple of 10 ms")
+)
+
+// 与WebRTC ns_core一致的常量
+const (
+	simult           = 3    // 同时运行的分位数估计器个数
+	endStartupLong   = 200  // 分位数估计器的更新周期（块）
+	endStartupShort  = 50   // 混合参数化噪声模型的启动阶段（块）
+	startBand        = 5    // 拟合粉红噪声模型的起始频点
+	quantileFactor   = 40.0 // 分位数更新步长
+	quantileWidth    = 0.01 // 密度估计的宽度
+	quantile         = 0.25 // 噪声估计使用的分位数
+	ddPrSNR          = 0.98 // 判决引导的平滑系数
+	lrtTavg          = 0.5  // 似然比特征的时间平滑系数
+	lrtFeatureThr    = 0.5  // 似然比特征的先验阈值
+	widthPrMap       = 4.0  // 先验模型sigmoid映射的宽度
+	priorUpdate      = 0.1  // 先验语音概率的更新系数
+	noiseUpdate      = 0.9  // 噪声的时间平滑系数
+	speechUpdate     = 0.99 // 可能为语音时的噪声平滑系数
+	probRange        = 0.2  // 语音概率门限
+	epsilon          = 1e-4 // 防止除零
+	magnitudeOffset  = 1.0  // 幅度谱的偏置，防止取对数时为零
+	analysisOverhead = 1.6  // 分析长度与块长度之比
+)
+
+// policyParams 各抑制强度的过估计系数与增益下限
+var policyParams = [...]struct{ overdrive, denoiseBound float64 }{
+	Mild:           {1.0, 0.5},
+	Medium:         {1.0, 0.25},
+	Aggressive:     {1.1, 0.125},
+	VeryAggressive: {1.25, 0.09},
+}
+
+// Suppressor 噪声抑制器
+//
+// Suppressor有状态且不是并发安全的，每路音频流使用一个实例
+type Suppressor struct {
+	blockLen int // 块长度（10ms样本数）
+	anaLen   int // 分析长度
+	magnLen  int // 频点数
+
+	overdrive    float64
+	denoiseBound float64
+
+	fft    *fftPlan
+	window []float64
+
+	analysisBuf []float64 // 最近anaLen个输入样本
+	synthBuf    []float64 // 重叠相加缓冲
+	spec        []complex128
+	time        []complex128
+	magn        []float64
+
+	// 分位数噪声估计
+	lquantile []float64 // simult*magnLen
+	density   []float64 // simult*magnLen
+	counter   [simult]int
+	updates   int
+	quantile  []float64
+
+	// 启动阶段的参数化（白/粉红）噪声模型
+	blockInd           int
+	whiteNoiseLevel    float64
+	pinkNoiseNumerator float64
+	pinkNoiseExp       float64
+
+	// 语音概率与噪声更新
+	noise           []float64
+	noisePrev       []float64
+	logLrtTimeAvg   []float64
+	speechProb      []float64
+	priorSpeechProb float64
+	magnPrevAnalyze []float64
+	magnPrevProcess []float64
+	smooth          []float64 // 上一块的滤波器增益
+}
+
+// New 创建噪声抑制器
+//
+// 参数:
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
+//   - policy: 抑制强度
+//
+// 返回:
+//   - *Suppressor: 噪声抑制器实例
+//   - error: 错误信息
+func New(sampleRate int, policy Policy) (*Suppressor, error) {
+	switch sampleRate {
+	case 8000, 16000, 24000, 32000, 48000:
+	default:
+		return nil, ErrInvalidSampleRate
+	}
+	if policy < Mild || policy > VeryAggressive {
+		return nil, ErrInvalidPolicy
+	}
+
+	blockLen := sampleRate / 100
+	anaLen := int(float64(blockLen) * analysisOverhead)
+	magnLen := anaLen/2 + 1
+	s := &Suppressor{
+		blockLen:     blockLen,
+		anaLen:       anaLen,
+		magnLen:      magnLen,
+		overdrive:    policyParams[policy].overdrive,
+		denoiseBound: policyParams[policy].denoiseBound,
+		fft:          newFFTPlan(anaLen),
+		window:       newWindow(anaLen, blockLen),
+
+		analysisBuf: make([]float64, anaLen),
+		synthBuf:    make([]float64, anaLen),
+		spec:        make([]complex128, anaLen),
+		time:        make([]complex128, anaLen),
+		magn:        make([]float64, magnLen),
+
+		lquantile: make([]float64, simult*magnLen),
+		density:   make([]float64, simult*magnLen),
+		quantile:  make([]float64, magnLen),
+
+		noise:           make([]float64, magnLen),
+		noisePrev:       make([]float64, magnLen),
+		logLrtTimeAvg:   make([]float64, magnLen),
+		speechProb:      make([]float64, magnLen),
+		magnPrevAnalyze: make([]float64, magnLen),
+		magnPrevProcess: make([]float64, magnLen),
+		smooth:          make([]float64, magnLen),
+	}
+	s.Reset()
+	return s, nil
+}
+
+// newWindow 生成分析/合成窗：正弦上升、平顶、正弦下降，
+// 以块长度为跳步时窗函数平方之和为1，保证完美重建
+func newWindow(anaLen, blockLen int) []float64 {
+	overlap := anaLen - blockLen
+	w := make([]float64, anaLen)
+	for i := range w {
+		switch {
+		case i < overlap:
+			w[i] = math.Sin(math.Pi / 2 * (float64(i) + 0.5) / float64(overlap))
+		case i < blockLen:
+			w[i] = 1
+		default:
+			w[i] = math.Cos(math.Pi / 2 * (float64(i-blockLen) + 0.5) / float64(overlap))
+		}
+	}
+	return w
+}
+
+// Reset 重置为初始状态
+func (s *Suppressor) Reset() {
+	clear(s.analysisBuf)
+	clear(s.synthBuf)
+	for i := range s.lquantile {
+		s.lquantile[i] = 8
+		s.density[i] = 0.3
+	}
+	for i := range s.counter {
+		s.counter[i] = endStartupLong * (i + 1) / simult
+	}
+	s.updates = 0
+	clear(s.quantile)
+	s.blockInd = 0
+	s.whiteNoiseLevel = 0
+	s.pinkNoiseNumerator = 0
+	s.pinkNoiseExp = 0
+
+	clear(s.noise)
+	clear(s.noisePrev)
+	for i := range s.logLrtTimeAvg {
+		s.logLrtTimeAvg[i] = lrtFeatureThr
+		s.smooth[i] = 1
+	}
+	clear(s.speechProb)
+	s.priorSpeechProb = 0.5
+	clear(s.magnPrevAnalyze)
+	clear(s.magnPrevProcess)
+}
+
+// Process 就地抑制一帧中的噪声
+//
+// 参数:
+//   - frame: 16位样本，长度须为10ms的整数倍
+//
+// 返回:
+//   - error: 帧长度无效时返回ErrInvalidFrameLength
+func (s *Suppressor) Process(frame []int16) error {
+	if len(frame) == 0 || len(frame)%s.blockLen != 0 {
+		return ErrInvalidFrameLength
+	}
+	for off := 0; off < len(frame); off += s.blockLen {
+		s.processBlock(frame[off : off+s.blockLen])
+	}
+	return nil
+}
+
+// processBlock 处理一个10ms块
+func (s *Suppressor) processBlock(block []int16) {
+	// 移入新块并加窗
+	copy(s.analysisBuf, s.analysisBuf[s.blockLen:])
+	tail := s.analysisBuf[s.anaLen-s.blockLen:]
+	for i, v := range block {
+		tail[i] = float64(v)
+	}
+	for i, v := range s.analysisBuf {
+		s.time[i] = complex(v*s.window[i], 0)
+	}
+	s.fft.forward(s.spec, s.time)
+	for i := range s.magn {
+		s.magn[i] = math.Hypot(real(s.spec[i]), imag(s.spec[i])) + magnitudeOffset
+	}
+
+	s.analyze()
+	s.wienerFilter()
+
+	// 施加增益（保持共轭对称），逆变换后加窗重叠相加
+	for i := 0; i < s.anaLen; i++ {
+		k := i
+		if k >= s.magnLen {
+			k = s.anaLen - i
+		}
+		s.spec[i] *= complex(s.smooth[k], 0)
+	}
+	s.fft.inverse(s.time, s.spec)
+	for i := range s.synthBuf {
+		s.synthBuf[i] += real(s.time[i]) * s.window[i]
+	}
+
+	for i := range block {
+		v := math.Round(s.synthBuf[i])
+		block[i] = int16(max(math.MinInt16, min(math.MaxInt16, v)))
+	}
+	copy(s.synthBuf, s.synthBuf[s.blockLen:])
+	clear(s.synthBuf[s.anaLen-s.blockLen:])
+}
+
+// analyze 更新噪声估计与语音概率（WebRTC的AnalyzeCore）
+func (s *Suppressor) analyze() {
+	s.estimateQuantile()
+	copy(s.noise, s.quantile)
+	if s.blockInd < endStartupShort {
+		s.blendParametricNoise()
+		s.blockInd++
+	}
+
+	// 后验与判决引导的先验信噪比，计算似然比特征
+	var lrtSum float64
+	for i, m := range s.magn {
+		prevStsa := s.magnPrevAnalyze[i] / (s.noisePrev[i] + epsilon) * s.smooth[i]
+		post := 0.0
+		if m > s.noise[i] {
+			post = m/(s.noise[i]+epsilon) - 1
+		}
+		prior := ddPrSNR*prevStsa + (1-ddPrSNR)*post
+
+		t1 := 1 + 2*prior
+		bessel := (post + 1) * 2 * prior / (t1 + epsilon)
+		s.logLrtTimeAvg[i] += lrtTavg * (bessel - math.Log(t1) - s.logLrtTimeAvg[i])
+		lrtSum += s.logLrtTimeAvg[i]
+	}
+	lrt := lrtSum / float64(s.magnLen)
+
+	// 先验语音概率：似然比特征的sigmoid映射，停顿段使用更宽的映射
+	width := widthPrMap
+	if lrt < lrtFeatureThr {
+		width = 2 * widthPrMap
+	}
+	indicator := 0.5 * (math.Tanh(width*(lrt-lrtFeatureThr)) + 1)
+	s.priorSpeechProb += priorUpdate * (indicator - s.priorSpeechProb)
+	s.priorSpeechProb = max(0.01, min(1, s.priorSpeechProb))
+
+	gainPrior := (1 - s.priorSpeechProb) / (s.priorSpeechProb + epsilon)
+	for i := range s.speechProb {
+		s.speechProb[i] = 1 / (1 + gainPrior*math.Exp(-s.logLrtTimeAvg[i]))
+	}
+
+	// 按语音概率更新噪声：可能为语音时更新更慢，噪声下降时总是允许
+	for i, m := range s.magn {
+		p := s.speechProb[i]
+		update := noiseUpdate*s.noisePrev[i] + (1-noiseUpdate)*((1-p)*m+p*s.noisePrev[i])
+		if p > probRange {
+			s.noise[i] = speechUpdate*s.noisePrev[i] + (1-speechUpdate)*((1-p)*m+p*s.noisePrev[i])
+			s.noise[i] = min(s.noise[i], update)
+		} else {
+			s.noise[i] = update
+		}
+	}
+	copy(s.noisePrev, s.noise)
+	copy(s.magnPrevAnalyze, s.magn)
+}
+
+// estimateQuantile 分位数噪声估计（WebRTC的NoiseEstimation）
+func (s *Suppressor) estimateQuantile() {
+	if s.updates < endStartupLong {
+		s.updates++
+	}
+
+	offset := 0
+	for k := 0; k < simult; k++ {
+		offset = k * s.magnLen
+		n := float64(s.counter[k] + 1)
+		for i, m := range s.magn {
+			lm := math.Log(m)
+			lq := &s.lquantile[offset+i]
+			d := &s.density[offset+i]
+
+			delta := quantileFactor
+			if *d > 1 {
+				delta = quantileFactor / *d
+			}
+			if lm > *lq {
+				*lq += quantile * delta / n
+			} else {
+				*lq -= (1 - quantile) * delta / n
+			}
+			if math.Abs(lm-*lq) < quantileWidth {
+				*d = (float64(s.counter[k])**d + 1/(2*quantileWidth)) / n
+			}
+		}
+
+		if s.counter[k] >= endStartupLong {
+			s.counter[k] = 0
+			if s.updates >= endStartupLong {
+				for i := range s.quantile {
+					s.quantile[i] = math.Exp(s.lquantile[offset+i])
+				}
+			}
+		}
+		s.counter[k]++
+	}
+
+	// 启动阶段使用最后一个估计器的当前值
+	if s.updates < endStartupLong {
+		for i := range s.quantile {
+			s.quantile[i] = math.Exp(s.lquantile[offset+i])
+		}
+	}
+}
+
+// blendParametricNoise 启动阶段将分位数噪声估计与参数化噪声模型加权混合
+//
+// 白噪声水平取幅度谱均值，粉红噪声按对数频点对对数幅度做最小二乘拟合，
+// 随块数增加逐渐过渡到分位数估计
+func (s *Suppressor) blendParametricNoise() {
+	var sumMagn, sumLogI, sumLogISquare, sumLogMagn, sumLogILogMagn float64
+	for i, m := range s.magn {
+		sumMagn += m
+		if i >= startBand {
+			li, lm := math.Log(float64(i)), math.Log(m)
+			sumLogI += li
+			sumLogISquare += li * li
+			sumLogMagn += lm
+			sumLogILogMagn += li * lm
+		}
+	}
+	bands := float64(s.magnLen - startBand)
+
+	s.whiteNoiseLevel += sumMagn / float64(s.magnLen) * s.overdrive
+	det := sumLogISquare*bands - sumLogI*sumLogI
+	s.pinkNoiseNumerator += max(0, (sumLogISquare*sumLogMagn-sumLogI*sumLogILogMagn)/det)
+	s.pinkNoiseExp += max(0, min(1, (sumLogI*sumLogMagn-bands*sumLogILogMagn)/det))
+
+	n := float64(s.blockInd + 1)
+	var num, exp float64
+	if s.pinkNoiseExp > 0 {
+		num = math.Exp(s.pinkNoiseNumerator/n) * n
+		exp = s.pinkNoiseExp / n
+	}
+	for i := range s.noise {
+		parametric := s.whiteNoiseLevel
+		if s.pinkNoiseExp > 0 {
+			parametric = num / math.Pow(float64(max(i, startBand)), exp)
+		}
+		s.noise[i] = (s.noise[i]*float64(s.blockInd) + parametric*float64(endStartupShort-s.blockInd)/n) / endStartupShort
+	}
+}
+
+// wienerFilter 计算判决引导的Wiener滤波器增益（WebRTC的ComputeDdBasedWienerFilter）
+func (s *Suppressor) wienerFilter() {
+	for i, m := range s.magn {
+		prevStsa := s.magnPrevProcess[i] / (s.noisePrev[i] + epsilon) * s.smooth[i]
+		current := 0.0
+		if m > s.noise[i] {
+			current = m/(s.noise[i]+epsilon) - 1
+		}
+		prior := ddPrSNR*prevStsa + (1-ddPrSNR)*current
+		gain := prior / (s.overdrive + prior)
+		s.smooth[i] = max(s.denoiseBound, min(1, gain))
+	}
+	copy(s.magnPrevProcess, s.magn)
+}
@@ -0,0 +1,101 @@
+package ns
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// TestFFT 测试混合基FFT与直接DFT一致，逆变换可还原
+func TestFFT(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{128, 256, 384, 512, 768} {
+		in := make([]complex128, n)
+		for i := range in {
+			in[i] = complex(rng.Float64()-0.5, rng.Float64()-0.5)
+		}
+		out := make([]complex128, n)
+		p := newFFTPlan(n)
+		p.forward(out, in)
+
+		for _, k := range []int{0, 1, n / 3, n - 1} {
+			var want complex128
+			for j, v := range in {
+				want += v * cmplx.Rect(1, -2*math.Pi*float64(j*k)/float64(n))
+			}
+			if cmplx.Abs(out[k]-want) > 1e-9 {
+				t.Errorf("n=%d k=%d: 期望%v, 得到%v", n, k, want, out[k])
+			}
+		}
+
+		back := make([]complex128, n)
+		p.inverse(back, out)
+		for i := range in {
+			if cmplx.Abs(back[i]-in[i]) > 1e-12 {
+				t.Fatalf("n=%d: 逆变换未还原第%d个样本", n, i)
+			}
+		}
+	}
+}
+
+// energy 计算样本能量
+func energy(x []int16) float64 {
+	var e float64
+	for _, v := range x {
+		e += float64(v) * float64(v)
+	}
+	return e
+}
+
+// TestSuppressor 测试噪声段被衰减、正弦波基本保留
+func TestSuppressor(t *testing.T) {
+	for _, rate := range []int{8000, 16000, 48000} {
+		s, err := New(rate, Medium)
+		if err != nil {
+			t.Fatalf("创建抑制器失败: %v", err)
+		}
+
+		// 4s白噪声，第3s叠加正弦波
+		rng := rand.New(rand.NewSource(2))
+		in := make([]int16, 4*rate)
+		for i := range in {
+			v := rng.NormFloat64() * 300
+			if i >= 2*rate && i < 3*rate {
+				v += 6000 * math.Sin(2*math.Pi*440*float64(i)/float64(rate))
+			}
+			in[i] = int16(v)
+		}
+		out := append([]int16(nil), in...)
+		for off := 0; off < len(out); off += rate / 50 {
+			if err := s.Process(out[off : off+rate/50]); err != nil {
+				t.Fatalf("处理失败: %v", err)
+			}
+		}
+
+		// 输出延迟重叠长度
+		delay := rate / 100 * 6 / 10
+		noiseIn, noiseOut := energy(in[rate:2*rate]), energy(out[rate+delay:2*rate+delay])
+		toneIn, toneOut := energy(in[2*rate+rate/4:3*rate-rate/4]), energy(out[2*rate+rate/4+delay:3*rate-rate/4+delay])
+		if noiseOut > noiseIn/4 {
+			t.Errorf("%dHz: 噪声段应衰减6dB以上, 输入%.3g 输出%.3g", rate, noiseIn, noiseOut)
+		}
+		if toneOut < toneIn*0.8 {
+			t.Errorf("%dHz: 正弦波段应基本保留, 输入%.3g 输出%.3g", rate, toneIn, toneOut)
+		}
+	}
+}
+
+// TestSuppressorErrors 测试参数校验
+func TestSuppressorErrors(t *testing.T) {
+	if _, err := New(44100, Medium); err != ErrInvalidSampleRate {
+		t.Errorf("应返回ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := New(16000, VeryAggressive+1); err != ErrInvalidPolicy {
+		t.Errorf("应返回ErrInvalidPolicy, 得到%v", err)
+	}
+	s, _ := New(16000, Mild)
+	if err := s.Process(make([]int16, 100)); err != ErrInvalidFrameLength {
+		t.Errorf("应返回ErrInvalidFrameLength, 得到%v", err)
+	}
+}
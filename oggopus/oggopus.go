@@ -0,0 +1,281 @@
+// Package oggopus 将Ogg Opus音频接入StreamVAD
+//
+// 本包只负责Ogg容器解析（页面重组为数据包、OpusHead解析、pre-skip与结尾裁剪），
+// Opus解码通过Decoder接口由调用方提供（如libopus的Go绑定），解码得到的
+// 48kHz PCM混合为单声道后写入内部的StreamVAD，录制的会议文件无需外部转码即可切分。
+//
+// 使用示例:
+//
+//	a, err := oggopus.New(func(channels int) (oggopus.Decoder, error) {
+//	    return opus.NewDecoder(48000, channels) // 任意实现了Decode的解码器
+//	}, webrtcvad.WithStreamMode(2))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if _, err := io.Copy(a, oggFile); err != nil {
+//	    log.Fatal(err)
+//	}
+//	a.Close()
+//	segments := a.StreamVAD().GetSegments()
+package oggopus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+)
+
+// SampleRate Opus解码输出的采样率
+const SampleRate = 48000
+
+// maxFrameSamples 单个Opus数据包每声道的最大样本数（120ms @48kHz）
+const maxFrameSamples = 5760
+
+var (
+	// ErrCorrupt Ogg页面损坏（捕获模式或校验和错误）
+	ErrCorrupt = errors.New("oggopus: corrupt Ogg page")
+
+	// ErrNotOpus 逻辑流的第一个数据包不是OpusHead
+	ErrNotOpus = errors.New("oggopus: stream is not Ogg Opus")
+)
+
+// Decoder Opus解码器
+type Decoder interface {
+	// Decode 将一个Opus数据包解码为48kHz交织样本写入pcm，返回每声道的样本数
+	Decode(packet []byte, pcm []int16) (int, error)
+}
+
+// Adapter Ogg Opus到StreamVAD的适配器
+//
+// Adapter不是并发安全的
+type Adapter struct {
+	svad       *webrtcvad.StreamVAD
+	newDecoder func(channels int) (Decoder, error)
+	decoder    Decoder
+
+	buf     []byte // 尚未组成完整页面的输入
+	serial  uint32 // 跟踪的逻辑流序列号
+	started bool   // 已遇到第一个页面
+	packet  []byte // 跨页面的未完成数据包
+	packets int    // 已处理的数据包数
+
+	channels   int
+	preSkip    int   // 尚需丢弃的每声道样本数
+	decoded    int64 // 已解码的每声道样本数（含pre-skip）
+	endGranule int64 // 最后一页的granule position，即流的总样本数；未到最后一页时为-1
+	pcm        []int16
+	out        []byte
+}
+
+// New 创建适配器
+//
+// 参数:
+//   - newDecoder: 解析OpusHead后按声道数创建解码器
+//   - opts: 内部StreamVAD的配置选项，采样率固定为48kHz
+//
+// 返回:
+//   - *Adapter: 适配器实例
+//   - error: 错误信息
+func New(newDecoder func(channels int) (Decoder, error), opts ...webrtcvad.StreamVADOption) (*Adapter, error) {
+	if newDecoder == nil {
+		return nil, errors.New("oggopus: nil decoder factory")
+	}
+	opts = append(opts, webrtcvad.WithSampleRate(SampleRate))
+	svad, err := webrtcvad.NewStreamVADWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Adapter{svad: svad, newDecoder: newDecoder, endGranule: -1}, nil
+}
+
+// StreamVAD 获取内部的StreamVAD
+func (a *Adapter) StreamVAD() *webrtcvad.StreamVAD {
+	return a.svad
+}
+
+// Write 写入任意长度的Ogg字节流，实现io.Writer
+//
+// 完整的页面立即解析，解码后的音频写入StreamVAD。
+// 出错时返回p中已消耗的字节数：出错的页面若已开始处理则计为消耗，
+// 其后的数据不会保留在缓冲中
+func (a *Adapter) Write(p []byte) (int, error) {
+	buffered := len(a.buf)
+	a.buf = append(a.buf, p...)
+	consumed := 0
+	for {
+		n, err := a.page(a.buf)
+		a.buf = a.buf[n:]
+		consumed += n
+		if err != nil {
+			// 丢弃p中未消耗的部分，之前写入的数据仍留在缓冲中
+			a.buf = a.buf[:max(0, buffered-consumed)]
+			return max(0, consumed-buffered), err
+		}
+		if n == 0 {
+			break
+		}
+	}
+	return len(p), nil
+}
+
+// Close 结束Ogg流并关闭内部的StreamVAD，实现io.Closer
+func (a *Adapter) Close() error {
+	if len(a.buf) > 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrCorrupt, len(a.buf))
+	}
+	return a.svad.Close()
+}
+
+// page 解析buf开头的一个Ogg页面，返回消耗的字节数（页面不完整或损坏时为0，
+// 页面中的数据包处理失败时仍计为消耗）
+func (a *Adapter) page(buf []byte) (int, error) {
+	const headerSize = 27
+	if len(buf) < headerSize {
+		return 0, nil
+	}
+	if string(buf[0:4]) != "OggS" || buf[4] != 0 {
+		return 0, ErrCorrupt
+	}
+	nsegs := int(buf[26])
+	if len(buf) < headerSize+nsegs {
+		return 0, nil
+	}
+	lacing := buf[headerSize : headerSize+nsegs]
+	size := headerSize + nsegs
+	for _, l := range lacing {
+		size += int(l)
+	}
+	if len(buf) < size {
+		return 0, nil
+	}
+
+	page := buf[:size]
+	if binary.LittleEndian.Uint32(page[22:26]) != crc(page) {
+		return 0, ErrCorrupt
+	}
+
+	// 只跟踪第一个逻辑流
+	serial := binary.LittleEndian.Uint32(page[14:18])
+	if !a.started {
+		a.started = true
+		a.serial = serial
+	}
+	if serial != a.serial {
+		return size, nil
+	}
+
+	// 流的最后一页（EOS）的granule position给出总样本数，超出的解码样本需裁剪
+	if page[5]&0x04 != 0 {
+		a.endGranule = int64(binary.LittleEndian.Uint64(page[6:14]))
+	}
+
+	// 按lacing值重组数据包，255表示数据包在下一段继续
+	continued := page[5]&0x01 != 0
+	if !continued {
+		a.packet = a.packet[:0]
+	}
+	data := page[headerSize+nsegs:]
+	for _, l := range lacing {
+		a.packet = append(a.packet, data[:l]...)
+		data = data[l:]
+		if l < 255 {
+			if err := a.handlePacket(a.packet); err != nil {
+				return size, err
+			}
+			a.packet = a.packet[:0]
+		}
+	}
+	return size, nil
+}
+
+// handlePacket 处理一个完整的数据包
+func (a *Adapter) handlePacket(packet []byte) error {
+	a.packets++
+	switch a.packets {
+	case 1:
+		return a.parseHead(packet)
+	case 2:
+		// OpusTags：元数据，忽略
+		return nil
+	}
+	if len(packet) == 0 {
+		return nil
+	}
+
+	n, err := a.decoder.Decode(packet, a.pcm)
+	if err != nil {
+		return err
+	}
+	if n > maxFrameSamples {
+		n = maxFrameSamples
+	}
+	// 结尾裁剪：最后一帧通常不是完整的，只保留到granule position为止
+	if a.endGranule >= 0 {
+		n = int(max(0, min(int64(n), a.endGranule-a.decoded)))
+	}
+	a.decoded += int64(n)
+
+	// 丢弃pre-skip样本，多声道平均为单声道
+	skip := min(a.preSkip, n)
+	a.preSkip -= skip
+	a.out = a.out[:0]
+	for i := skip; i < n; i++ {
+		var sum int32
+		for ch := 0; ch < a.channels; ch++ {
+			sum += int32(a.pcm[i*a.channels+ch])
+		}
+		a.out = binary.LittleEndian.AppendUint16(a.out, uint16(int16(sum/int32(a.channels))))
+	}
+	_, err = a.svad.Write(a.out)
+	return err
+}
+
+// parseHead 解析OpusHead并创建解码器
+func (a *Adapter) parseHead(packet []byte) error {
+	if len(packet) < 19 || string(packet[0:8]) != "OpusHead" {
+		return ErrNotOpus
+	}
+	a.channels = int(packet[9])
+	if a.channels == 0 {
+		return fmt.Errorf("%w: zero channels", ErrNotOpus)
+	}
+	a.preSkip = int(binary.LittleEndian.Uint16(packet[10:12]))
+
+	decoder, err := a.newDecoder(a.channels)
+	if err != nil {
+		return err
+	}
+	a.decoder = decoder
+	a.pcm = make([]int16, maxFrameSamples*a.channels)
+	return nil
+}
+
+// crcTable Ogg页面校验和表（多项式0x04c11db7，不反射）
+var crcTable = func() (t [256]uint32) {
+	for i := range t {
+		r := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if r&0x80000000 != 0 {
+				r = r<<1 ^ 0x04c11db7
+			} else {
+				r <<= 1
+			}
+		}
+		t[i] = r
+	}
+	return t
+}()
+
+// crc 计算页面校验和，校验和字段按0参与计算
+func crc(page []byte) uint32 {
+	var c uint32
+	for i, b := range page {
+		if i >= 22 && i < 26 {
+			b = 0
+		}
+		c = c<<8 ^ crcTable[byte(c>>24)^b]
+	}
+	return c
+}
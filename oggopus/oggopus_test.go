@@ -0,0 +1,183 @@
+package oggopus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// fakeDecoder 每个数据包解码为packet[0]*10ms的交织样本，左声道为packet[1]、右声道为-packet[1]
+type fakeDecoder struct{ channels int }
+
+func (d fakeDecoder) Decode(packet []byte, pcm []int16) (int, error) {
+	if packet[0] == 0 {
+		return 0, errors.New("bad packet")
+	}
+	n := int(packet[0]) * 480
+	for i := 0; i < n; i++ {
+		for ch := 0; ch < d.channels; ch++ {
+			v := int16(packet[1])
+			if ch == 1 {
+				v = -v
+			}
+			pcm[i*d.channels+ch] = v
+		}
+	}
+	return n, nil
+}
+
+// oggPage 构造一个Ogg页面
+func oggPage(serial uint32, seq uint32, flags byte, lacing []byte, data []byte) []byte {
+	page := make([]byte, 27, 27+len(lacing)+len(data))
+	copy(page, "OggS")
+	page[5] = flags
+	binary.LittleEndian.PutUint32(page[14:18], serial)
+	binary.LittleEndian.PutUint32(page[18:22], seq)
+	page[26] = byte(len(lacing))
+	page = append(page, lacing...)
+	page = append(page, data...)
+	binary.LittleEndian.PutUint32(page[22:26], crc(page))
+	return page
+}
+
+// opusStream 构造双声道Ogg Opus流：pre-skip为480样本，音频数据包各20ms
+func opusStream(packets int) []byte {
+	head := make([]byte, 19)
+	copy(head, "OpusHead")
+	head[8] = 1
+	head[9] = 2
+	binary.LittleEndian.PutUint16(head[10:12], 480)
+	binary.LittleEndian.PutUint32(head[12:16], 48000)
+
+	var s []byte
+	s = append(s, oggPage(1, 0, 0x02, []byte{19}, head)...)
+	s = append(s, oggPage(1, 1, 0, []byte{8}, []byte("OpusTags"))...)
+	// 其他逻辑流的页面应被忽略
+	s = append(s, oggPage(2, 0, 0x02, []byte{1}, []byte{0})...)
+
+	for i := 0; i < packets; i++ {
+		s = append(s, oggPage(1, uint32(i+2), 0, []byte{2}, []byte{2, 100})...)
+	}
+	return s
+}
+
+// TestAdapter 测试解析、pre-skip和写入StreamVAD
+func TestAdapter(t *testing.T) {
+	channels := 0
+	a, err := New(func(ch int) (Decoder, error) {
+		channels = ch
+		return fakeDecoder{ch}, nil
+	})
+	if err != nil {
+		t.Fatalf("创建适配器失败: %v", err)
+	}
+
+	if _, err := io.Copy(a, iotest.OneByteReader(bytes.NewReader(opusStream(10)))); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close失败: %v", err)
+	}
+
+	if channels != 2 {
+		t.Errorf("声道数错误: 期望2, 得到%d", channels)
+	}
+	// 10个20ms数据包，扣除10ms的pre-skip
+	if got := a.StreamVAD().GetTotalDuration(); got != 190*time.Millisecond {
+		t.Errorf("总时长错误: 期望190ms, 得到%v", got)
+	}
+}
+
+// TestAdapterEndTrim 测试按最后一页的granule position裁剪结尾不完整的帧
+func TestAdapterEndTrim(t *testing.T) {
+	a, _ := New(func(ch int) (Decoder, error) { return fakeDecoder{ch}, nil })
+
+	// 最后一个20ms数据包只有300个样本有效：granule = pre-skip + 9*960 + 300
+	stream := opusStream(9)
+	last := oggPage(1, 11, 0x04, []byte{2}, []byte{2, 100})
+	binary.LittleEndian.PutUint64(last[6:14], 480+9*960+300)
+	binary.LittleEndian.PutUint32(last[22:26], crc(last))
+	stream = append(stream, last...)
+
+	if _, err := a.Write(stream); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close失败: %v", err)
+	}
+	if got, want := a.StreamVAD().GetTotalProcessed(), int64(9*960+300)*2; got != want {
+		t.Errorf("应裁剪到granule position: 期望%d字节, 得到%d", want, got)
+	}
+}
+
+// TestAdapterSpanningPacket 测试跨页面的数据包
+func TestAdapterSpanningPacket(t *testing.T) {
+	var decoded [][]byte
+	a, _ := New(func(ch int) (Decoder, error) {
+		return decoderFunc(func(packet []byte, pcm []int16) (int, error) {
+			decoded = append(decoded, append([]byte(nil), packet...))
+			return 0, nil
+		}), nil
+	})
+
+	stream := opusStream(0)
+	big := bytes.Repeat([]byte{7}, 300)
+	stream = append(stream, oggPage(1, 2, 0, []byte{255}, big[:255])...)
+	stream = append(stream, oggPage(1, 3, 0x01, []byte{45}, big[255:])...)
+	if _, err := a.Write(stream); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if len(decoded) != 1 || !bytes.Equal(decoded[0], big) {
+		t.Errorf("跨页面数据包重组错误: %d个数据包", len(decoded))
+	}
+}
+
+// decoderFunc 函数形式的Decoder
+type decoderFunc func(packet []byte, pcm []int16) (int, error)
+
+func (f decoderFunc) Decode(packet []byte, pcm []int16) (int, error) { return f(packet, pcm) }
+
+// TestAdapterErrors 测试损坏与非Opus数据
+func TestAdapterErrors(t *testing.T) {
+	newDecoder := func(ch int) (Decoder, error) { return fakeDecoder{ch}, nil }
+
+	a, _ := New(newDecoder)
+	stream := opusStream(1)
+	stream[len(stream)-1] ^= 0xFF
+	if _, err := a.Write(stream); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("校验和错误应返回ErrCorrupt, 得到%v", err)
+	}
+
+	// 返回出错前已消耗的字节数：损坏的页面未消耗，解码失败的页面已消耗
+	a, _ = New(newDecoder)
+	good := opusStream(2)
+	a.Write(good[:10])
+	corrupt := oggPage(1, 4, 0, []byte{2}, []byte{2, 100})
+	corrupt[len(corrupt)-1] ^= 0xFF
+	if n, err := a.Write(append(good[10:len(good):len(good)], corrupt...)); !errors.Is(err, ErrCorrupt) || n != len(good)-10 {
+		t.Errorf("应消耗损坏页面之前的%d字节并返回ErrCorrupt, 得到%d %v", len(good)-10, n, err)
+	}
+	bad := oggPage(1, 4, 0, []byte{2}, []byte{0, 0})
+	if n, err := a.Write(append(bad, oggPage(1, 5, 0, []byte{2}, []byte{2, 100})...)); err == nil || n != len(bad) {
+		t.Errorf("解码失败的页面应计为消耗: 期望%d字节, 得到%d %v", len(bad), n, err)
+	}
+
+	a, _ = New(newDecoder)
+	if _, err := a.Write(oggPage(1, 0, 0x02, []byte{8}, []byte("OpusTags"))); !errors.Is(err, ErrNotOpus) {
+		t.Errorf("缺少OpusHead应返回ErrNotOpus, 得到%v", err)
+	}
+
+	a, _ = New(newDecoder)
+	a.Write(opusStream(1)[:30])
+	if err := a.Close(); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("不完整的页面应在Close时报告, 得到%v", err)
+	}
+
+	if _, err := New(nil); err == nil {
+		t.Error("nil解码器工厂应返回错误")
+	}
+}
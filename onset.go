@@ -0,0 +1,233 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// onset.go 提供低延迟的语音起始检测，用于TTS播放时的插话打断（barge-in）：
+// 固定10ms帧、短迟滞、可选的能量预门限，并在帧内插值定位第一个浊音样本
+
+// OnsetConfig 起始检测配置
+type OnsetConfig struct {
+	SampleRate int // 采样率（8000, 16000, 24000, 32000, 48000）
+
+	// ConfirmFrames 连续多少个10ms语音帧才确认起始，默认2（20ms）。
+	// 越小延迟越低，但更容易被咳嗽、点击声触发
+	ConfirmFrames int
+
+	// ReleaseFrames 确认起始后连续多少个10ms静音帧才重新准备检测下一次起始，默认20（200ms）
+	ReleaseFrames int
+
+	// EnergyGate 能量预门限（dBFS，须为负），帧RMS低于该值时直接判为静音而不送入VAD，
+	// 可挡住远低于说话电平的回声残余；0表示不启用。被挡住的帧不参与VAD的噪声自适应
+	EnergyGate float64
+}
+
+// Onset 语音起始事件
+type Onset struct {
+	Sample  int64         // 第一个浊音样本的序号（自创建或Reset起，帧内插值）
+	Time    time.Duration // 第一个浊音样本的时间
+	Latency time.Duration // 从第一个浊音样本到确认起始（所在帧结束）的延迟
+}
+
+// OnsetDetector 低延迟语音起始检测器
+//
+// 以10ms帧逐帧判决，连续ConfirmFrames个语音帧即确认起始。起始样本在第一个语音帧
+// 及其前一帧内按1ms子块的能量包络定位：取包络首次越过噪声与峰值（能量）几何平均的
+// 位置，并在相邻子块之间线性插值，精度远高于帧长。
+// OnsetDetector本身不是并发安全的，应由单个goroutine写入
+type OnsetDetector struct {
+	vad       *VAD
+	cfg       OnsetConfig
+	frameSize int // 单帧字节数
+
+	buffer  []byte // 未凑满一帧的残余数据
+	samples int64  // 已处理的样本数
+
+	active bool    // 已确认起始，等待ReleaseFrames个静音帧
+	run    int     // 未确认时为连续语音帧数，已确认时为连续静音帧数
+	cur    []int16 // 当前帧的样本
+	prev   []int16 // 上一帧的样本
+
+	window   []int16 // 第一个语音帧的前一帧与该帧
+	windowAt int64   // window第一个样本的序号
+}
+
+// NewOnsetDetector 创建起始检测器
+//
+// 参数:
+//   - vad: 用于逐帧判决的VAD实例，其模式决定激进度
+//   - cfg: 起始检测配置，ConfirmFrames与ReleaseFrames为0时使用默认值
+//
+// 返回:
+//   - *OnsetDetector: 起始检测器实例
+//   - error: 错误信息
+func NewOnsetDetector(vad *VAD, cfg OnsetConfig) (*OnsetDetector, error) {
+	if vad == nil {
+		return nil, ErrNotInitialized
+	}
+	if !isValidSampleRate(cfg.SampleRate) {
+		return nil, ErrInvalidSampleRate
+	}
+	if cfg.ConfirmFrames < 0 || cfg.ReleaseFrames < 0 {
+		return nil, ErrInvalidFrameCount
+	}
+	if cfg.EnergyGate > 0 {
+		return nil, ErrInvalidEnergyGate
+	}
+
+	if cfg.ConfirmFrames == 0 {
+		cfg.ConfirmFrames = 2
+	}
+	if cfg.ReleaseFrames == 0 {
+		cfg.ReleaseFrames = 20
+	}
+
+	n := cfg.SampleRate / 100
+	return &OnsetDetector{
+		vad:       vad,
+		cfg:       cfg,
+		frameSize: n * 2,
+		buffer:    make([]byte, 0, n*4),
+		cur:       make([]int16, n),
+		prev:      make([]int16, n),
+		window:    make([]int16, 2*n),
+	}, nil
+}
+
+// Write 写入任意长度的音频数据，返回其间确认的语音起始
+func (d *OnsetDetector) Write(data []byte) ([]Onset, error) {
+	d.buffer = append(d.buffer, data...)
+
+	var onsets []Onset
+	for len(d.buffer) >= d.frameSize {
+		frame := d.buffer[:d.frameSize]
+
+		var energy float64
+		for i := range d.cur {
+			v := int16(binary.LittleEndian.Uint16(frame[2*i:]))
+			d.cur[i] = v
+			energy += float64(v) * float64(v)
+		}
+
+		isSpeech := false
+		if d.cfg.EnergyGate == 0 || toDBFS(math.Sqrt(energy/float64(len(d.cur)))) >= d.cfg.EnergyGate {
+			var err error
+			if isSpeech, err = d.vad.IsSpeech(frame, d.cfg.SampleRate); err != nil {
+				return onsets, err
+			}
+		}
+		if o, ok := d.pushFrame(isSpeech); ok {
+			onsets = append(onsets, o)
+		}
+
+		d.buffer = d.buffer[d.frameSize:]
+	}
+
+	return onsets, nil
+}
+
+// pushFrame 将当前帧的判决送入状态机，确认起始时返回起始事件
+func (d *OnsetDetector) pushFrame(isSpeech bool) (Onset, bool) {
+	n := int64(len(d.cur))
+	frameStart := d.samples
+	d.samples += n
+	defer func() { d.prev, d.cur = d.cur, d.prev }()
+
+	if d.active {
+		if isSpeech {
+			d.run = 0
+		} else if d.run++; d.run >= d.cfg.ReleaseFrames {
+			d.active, d.run = false, 0
+		}
+		return Onset{}, false
+	}
+
+	if !isSpeech {
+		d.run = 0
+		return Onset{}, false
+	}
+	if d.run == 0 {
+		copy(d.window, d.prev)
+		copy(d.window[n:], d.cur)
+		d.windowAt = frameStart - n
+	}
+	if d.run++; d.run < d.cfg.ConfirmFrames {
+		return Onset{}, false
+	}
+
+	d.active, d.run = true, 0
+	offset := firstVoicedSample(d.window, d.cfg.SampleRate/1000, d.windowAt < 0)
+	sample := d.windowAt + int64(offset)
+	return Onset{
+		Sample:  sample,
+		Time:    d.samplesToDuration(sample),
+		Latency: d.samplesToDuration(d.samples - sample),
+	}, true
+}
+
+// Active 是否处于已确认的语音中（尚未经过ReleaseFrames个静音帧）
+func (d *OnsetDetector) Active() bool {
+	return d.active
+}
+
+// Reset 重置检测器状态（不重置VAD实例），样本序号从0重新开始
+//
+// 例如在每次TTS播放开始时调用，使起始时间相对于播放开始
+func (d *OnsetDetector) Reset() {
+	d.buffer = d.buffer[:0]
+	d.samples = 0
+	d.active = false
+	d.run = 0
+	clear(d.prev)
+}
+
+// samplesToDuration 将样本数转换为时长
+func (d *OnsetDetector) samplesToDuration(samples int64) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(d.cfg.SampleRate)
+}
+
+// firstVoicedSample 在window（第一个语音帧的前一帧与该帧）中定位第一个浊音样本
+//
+// 以block个样本为子块计算能量包络（dB），门限取包络最小值与语音帧内最大值的中点，
+// 返回包络首次越过门限处在相邻子块中心之间线性插值的位置。
+// noPrev为true时前一帧不存在（流的开头），只在语音帧内查找
+func firstVoicedSample(window []int16, block int, noPrev bool) int {
+	blocks := len(window) / block
+	level := make([]float64, blocks)
+	for b := range level {
+		var energy float64
+		for _, v := range window[b*block : (b+1)*block] {
+			energy += float64(v) * float64(v)
+		}
+		level[b] = 10 * math.Log10(energy/float64(block)+1)
+	}
+
+	from := 0
+	if noPrev {
+		from = blocks / 2
+	}
+	floor, peak := math.Inf(1), math.Inf(-1)
+	for b := from; b < blocks; b++ {
+		floor = math.Min(floor, level[b])
+		if b >= blocks/2 {
+			peak = math.Max(peak, level[b])
+		}
+	}
+	threshold := (floor + peak) / 2
+
+	for b := from; b < blocks; b++ {
+		if level[b] < threshold {
+			continue
+		}
+		if b == from {
+			return b * block
+		}
+		// 上一子块中心 + 越过门限的比例 × 子块长度
+		frac := (threshold - level[b-1]) / (level[b] - level[b-1])
+		return (b-1)*block + block/2 + int(frac*float64(block))
+	}
+	return blocks / 2 * block
+}
@@ -0,0 +1,97 @@
+package webrtcvad
+
+import (
+	"testing"
+	"time"
+)
+
+// TestOnsetDetector 测试起始样本在帧内的定位精度与确认延迟
+func TestOnsetDetector(t *testing.T) {
+	for _, at := range []time.Duration{1203 * time.Millisecond, 1207500 * time.Microsecond} {
+		// 1.2秒的低电平噪声后开始说话，起始不在帧边界上
+		pcm := speechWithPauses(2*time.Second, [2]time.Duration{0, at})
+
+		// 初始模型在开头的几帧会误判，先用背景噪声使VAD收敛
+		vad, _ := New(2)
+		warmup := speechWithPauses(time.Second, [2]time.Duration{0, time.Second})
+		for i := 0; i < len(warmup); i += 320 {
+			vad.IsSpeech(warmup[i:i+320], 16000)
+		}
+		d, err := NewOnsetDetector(vad, OnsetConfig{SampleRate: 16000})
+		if err != nil {
+			t.Fatalf("创建OnsetDetector失败: %v", err)
+		}
+		var onsets []Onset
+		for i := 0; i < len(pcm); i += 500 { // 不按帧对齐地写入
+			got, err := d.Write(pcm[i:min(i+500, len(pcm))])
+			if err != nil {
+				t.Fatalf("写入失败: %v", err)
+			}
+			onsets = append(onsets, got...)
+		}
+
+		if len(onsets) != 1 {
+			t.Fatalf("起始%v: 应检测到1次起始, 得到%d", at, len(onsets))
+		}
+		o := onsets[0]
+		if diff := o.Time - at; diff < -time.Millisecond || diff > time.Millisecond {
+			t.Errorf("起始%v: 定位误差应在1ms以内, 得到%v", at, o.Time)
+		}
+		if o.Sample != int64(o.Time*16000/time.Second) {
+			t.Errorf("Sample与Time不一致: %+v", o)
+		}
+		if o.Latency <= 0 || o.Latency > 40*time.Millisecond {
+			t.Errorf("起始%v: 确认延迟应在40ms以内, 得到%v", at, o.Latency)
+		}
+		if !d.Active() {
+			t.Error("语音中应处于Active状态")
+		}
+	}
+}
+
+// TestOnsetDetectorGate 测试能量预门限与重新准备
+func TestOnsetDetectorGate(t *testing.T) {
+	quiet := gaussianNoise(1000, 16000, false) // 约-30dBFS
+	speech := speechWithPauses(500 * time.Millisecond)
+
+	vad, _ := New(3)
+	d, _ := NewOnsetDetector(vad, OnsetConfig{SampleRate: 16000, ReleaseFrames: 10, EnergyGate: -26})
+	if onsets, _ := d.Write(quiet); len(onsets) != 0 {
+		t.Errorf("低于门限的音频不应触发起始, 得到%d次", len(onsets))
+	}
+
+	// 语音、200ms静音（超过ReleaseFrames）、语音：两次起始
+	var onsets []Onset
+	for _, pcm := range [][]byte{speech, make([]byte, 6400), speech} {
+		got, _ := d.Write(pcm)
+		onsets = append(onsets, got...)
+	}
+	if len(onsets) != 2 {
+		t.Fatalf("静音超过ReleaseFrames后应重新检测起始, 得到%d次", len(onsets))
+	}
+	if at := onsets[1].Time; at < 1700*time.Millisecond || at > 1720*time.Millisecond {
+		t.Errorf("第2次起始应在1.7秒附近, 得到%v", at)
+	}
+
+	d.Reset()
+	if d.Active() {
+		t.Error("Reset后不应处于Active状态")
+	}
+
+	tests := []struct {
+		name string
+		vad  *VAD
+		cfg  OnsetConfig
+		want error
+	}{
+		{"nil VAD", nil, OnsetConfig{SampleRate: 16000}, ErrNotInitialized},
+		{"采样率", vad, OnsetConfig{SampleRate: 44100}, ErrInvalidSampleRate},
+		{"确认帧数", vad, OnsetConfig{SampleRate: 16000, ConfirmFrames: -1}, ErrInvalidFrameCount},
+		{"门限", vad, OnsetConfig{SampleRate: 16000, EnergyGate: 6}, ErrInvalidEnergyGate},
+	}
+	for _, tt := range tests {
+		if _, err := NewOnsetDetector(tt.vad, tt.cfg); err != tt.want {
+			t.Errorf("%s: 应返回%v, 得到%v", tt.name, tt.want, err)
+		}
+	}
+}
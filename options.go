@@ -1,5 +1,15 @@
 package webrtcvad
 
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/godeps/webrtcvad-go/agc"
+	"github.com/godeps/webrtcvad-go/metrics"
+	"github.com/godeps/webrtcvad-go/ns"
+)
+
 // options.go 提供基于选项模式的VAD配置
 // 使API更灵活、可扩展，同时保持向后兼容性
 
@@ -20,6 +30,54 @@ func WithMode(mode int) Option {
 	}
 }
 
+// WithAggressiveness 以连续值设置VAD激进度（0.0-1.0）
+//
+// 详见 VAD.SetAggressiveness
+func WithAggressiveness(aggressiveness float64) Option {
+	return func(v *VAD) error {
+		return v.SetAggressiveness(aggressiveness)
+	}
+}
+
+// WithThresholds 设置局部与全局似然比检验阈值
+//
+// 详见 VAD.SetThresholds。须放在WithMode / WithAggressiveness之后，否则会被其覆盖
+func WithThresholds(local, global [3]int16) Option {
+	return func(v *VAD) error {
+		return v.SetThresholds(local, global)
+	}
+}
+
+// WithHangover 设置语音结束后的迟滞帧数
+//
+// 详见 VAD.SetHangover。须放在WithMode / WithAggressiveness之后，否则会被其覆盖
+func WithHangover(short, long [3]int16) Option {
+	return func(v *VAD) error {
+		return v.SetHangover(short, long)
+	}
+}
+
+// WithFrozenModel 冻结GMM模型，禁止逐帧自适应
+//
+// 噪声/语音的均值和标准差保持为初始值（或SetModel设置的值）不再更新，
+// 相同输入总能得到相同的判决，适合可复现的离线评估，
+// 以及自适应反而有害的极短音频片段。
+func WithFrozenModel() Option {
+	return func(v *VAD) error {
+		v.inst.frozenModel = true
+		return nil
+	}
+}
+
+// WithModel 使用指定的GMM模型参数替换内置模型
+//
+// 详见 VAD.SetModel
+func WithModel(m Model) Option {
+	return func(v *VAD) error {
+		return v.SetModel(m)
+	}
+}
+
 // NewWithOptions 使用选项模式创建VAD实例
 //
 // 示例:
@@ -57,8 +115,44 @@ type StreamVADOption func(*streamVADConfig) error
 // streamVADConfig StreamVAD内部配置
 type streamVADConfig struct {
 	mode       int
+	algorithm  Algorithm
+	hybrid     HybridConfig   // Hybrid算法的投票配置
+	model      *ModelDetector // 外部模型检测器（见WithInference）
+	detector   Detector       // 自定义检测器（见WithDetector）
 	sampleRate int
 	frameMs    int
+	minSpeech  time.Duration
+	minSilence time.Duration
+
+	captureAudio bool
+	maxCapture   int
+
+	highPass bool
+	filter   AudioFilter
+	denoise  bool
+	agc      *agc.Config
+
+	segmentBuffer   int
+	segmentCapacity int
+	bufferCapacity  int // 输入缓冲区容量（字节），0表示默认1秒
+	overflow        OverflowPolicy
+
+	clock   func() time.Time
+	metrics metrics.Recorder
+	timing  bool
+	logger  *slog.Logger
+
+	maxSegment time.Duration
+
+	enterFrames int
+	exitFrames  int
+
+	smoother *SmootherConfig
+
+	// GMM调优（见WithStreamThresholds等），nil表示使用模式预设
+	thresholds  *[2][3]int16
+	hangover    *[2][3]int16
+	frozenModel bool
 }
 
 // WithStreamMode 设置StreamVAD的激进度模式
@@ -72,6 +166,103 @@ func WithStreamMode(mode int) StreamVADOption {
 	}
 }
 
+// WithStreamThresholds 设置GMM的局部与全局似然比检验阈值（见VAD.SetThresholds）
+//
+// 与选项的顺序无关，总是覆盖WithStreamMode的预设；对GMM与组合检测（Hybrid）有效。
+// 之后调用StreamVAD.SetMode也保留此设置
+func WithStreamThresholds(local, global [3]int16) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		for i := range local {
+			if local[i] < 0 || global[i] < 0 {
+				return ErrInvalidThresholds
+			}
+		}
+		cfg.thresholds = &[2][3]int16{local, global}
+		return nil
+	}
+}
+
+// WithStreamHangover 设置GMM语音结束后的迟滞帧数（见VAD.SetHangover）
+//
+// 与选项的顺序无关，总是覆盖WithStreamMode的预设；对GMM与组合检测（Hybrid）有效。
+// 之后调用StreamVAD.SetMode也保留此设置
+func WithStreamHangover(short, long [3]int16) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		for i := range short {
+			if short[i] < 0 || long[i] < 0 {
+				return ErrInvalidHangover
+			}
+		}
+		cfg.hangover = &[2][3]int16{short, long}
+		return nil
+	}
+}
+
+// WithStreamFrozenModel 冻结GMM模型，禁止逐帧自适应（见WithFrozenModel）
+//
+// 对GMM与组合检测（Hybrid）有效
+func WithStreamFrozenModel() StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		cfg.frozenModel = true
+		return nil
+	}
+}
+
+// WithAlgorithm 设置StreamVAD的检测算法，默认GMM
+//
+// 激进度模式（WithStreamMode）对所有算法有效
+func WithAlgorithm(a Algorithm) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if a < GMM || a > LTSD {
+			return ErrInvalidAlgorithm
+		}
+		cfg.algorithm = a
+		return nil
+	}
+}
+
+// WithHybrid 使用组合检测（Hybrid）并设置投票配置
+//
+// WithAlgorithm(Hybrid)使用DefaultHybridConfig
+func WithHybrid(hc HybridConfig) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		cfg.algorithm = Hybrid
+		cfg.hybrid = hc
+		return nil
+	}
+}
+
+// WithInference 使用外部模型（如Silero VAD）判决，优先于WithAlgorithm
+//
+// 模型的采样率须与StreamVAD的采样率相同；激进度模式对外部模型无效，由ModelConfig的门限控制
+func WithInference(infer InferenceFunc, mc ModelConfig) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		m, err := NewModelDetector(infer, mc)
+		if err != nil {
+			return err
+		}
+		cfg.model = m
+		return nil
+	}
+}
+
+// WithDetector 使用任意Detector实现判决，优先于WithInference与WithAlgorithm
+//
+// StreamVAD的缓冲、分帧、预处理、平滑与片段生成照常工作，逐帧判决交给d。
+// d须支持StreamVAD的采样率与帧长度；激进度模式（WithStreamMode）不作用于d，
+// d实现SetMode(int) error时StreamVAD.SetMode转发给它，实现Reset()时StreamVAD.Reset
+// 调用它。传入*VAD时保留其模式、阈值与模型，其余行为与默认的GMM相同。
+// d有自适应状态，不要在多个StreamVAD之间共享
+func WithDetector(d Detector) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if d == nil {
+			return ErrInvalidDetector
+		}
+		cfg.detector = d
+		return nil
+	}
+}
+
 // WithSampleRate 设置StreamVAD的采样率
 func WithSampleRate(rate int) StreamVADOption {
 	return func(cfg *streamVADConfig) error {
@@ -94,6 +285,225 @@ func WithFrameDuration(ms int) StreamVADOption {
 	}
 }
 
+// WithMinSpeechDuration 设置语音片段的最短持续时间
+//
+// 持续时间短于d的语音（如单帧毛刺）不会开始新的语音片段，而是并入相邻的静音片段。
+// 语音片段的确认会因此延迟最多d。默认0，即每帧立即生效
+func WithMinSpeechDuration(d time.Duration) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if d < 0 {
+			return ErrInvalidDuration
+		}
+		cfg.minSpeech = d
+		return nil
+	}
+}
+
+// WithMinSilenceDuration 设置结束语音片段所需的最短静音时间
+//
+// 短于d的静音间隙会被桥接到所在的语音片段中，避免一句话被切成多个片段。
+// 默认0，即每帧立即生效
+func WithMinSilenceDuration(d time.Duration) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if d < 0 {
+			return ErrInvalidDuration
+		}
+		cfg.minSilence = d
+		return nil
+	}
+}
+
+// WithHysteresis 设置进入/退出语音状态的迟滞帧数
+//
+// 连续enter个语音帧才开始语音片段，连续exit个静音帧才结束语音片段，
+// 代替逐帧立即切换。与WithMinSpeechDuration / WithMinSilenceDuration同时
+// 设置时两个条件都须满足。默认均为1
+func WithHysteresis(enter, exit int) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if enter < 1 || exit < 1 {
+			return ErrInvalidFrameCount
+		}
+		cfg.enterFrames = enter
+		cfg.exitFrames = exit
+		return nil
+	}
+}
+
+// WithSmoother 在判决器与片段生成之间插入滑动平均平滑器（见Smoother）
+//
+// 片段按平滑后的判决生成，之后仍可叠加WithHysteresis与最短时长等平滑规则；
+// Stats与WithMetrics统计的是平滑前的判决
+func WithSmoother(sc SmootherConfig) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if _, err := NewSmoother(sc); err != nil {
+			return err
+		}
+		cfg.smoother = &sc
+		return nil
+	}
+}
+
+// WithVoteWindow 最近n个原始判决中至少k个为语音时才判为语音（多数表决）
+//
+// 与迟滞（WithHysteresis）要求连续帧不同，表决容忍窗口内零星的相反判决。
+// 等价于WithSmoother(SmootherConfig{Kind: VoteSmoothing, Frames: n, Threshold: k/n})，
+// 与WithSmoother同时设置时后设置的生效
+func WithVoteWindow(n, k int) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if k < 1 || n < k {
+			return ErrInvalidFrameCount
+		}
+		cfg.smoother = &SmootherConfig{Kind: VoteSmoothing, Frames: n, Threshold: float64(k) / float64(n)}
+		return nil
+	}
+}
+
+// WithMergeGap 实时合并间隔小于maxGap的语音片段，得到话语级而非帧级的片段
+//
+// 与WithMinSilenceDuration(maxGap)等价：短于maxGap的静音被桥接到语音片段中。
+// 对已有片段做同样的后处理见MergeSegments
+func WithMergeGap(maxGap time.Duration) StreamVADOption {
+	return WithMinSilenceDuration(maxGap)
+}
+
+// WithMaxSegmentDuration 设置语音片段的最大时长，超过时强制切分
+//
+// 切分点优先选在回看窗口（1秒，不超过d的一半）内能量最低的帧，尽量落在
+// 词间停顿处。适合向有输入长度限制的ASR服务送入音频。默认0，表示不限制
+func WithMaxSegmentDuration(d time.Duration) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if d < 0 {
+			return ErrInvalidDuration
+		}
+		cfg.maxSegment = d
+		return nil
+	}
+}
+
+// DefaultMaxCaptureBytes 启用音频捕获时单个语音片段默认最多保留的字节数（4MB）
+const DefaultMaxCaptureBytes = 4 << 20
+
+// WithCaptureAudio 设置是否在语音片段中保留PCM数据（VoiceSegment.Audio）
+//
+// 单个片段最多保留WithMaxCaptureBytes指定的字节数，默认DefaultMaxCaptureBytes
+func WithCaptureAudio(enabled bool) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		cfg.captureAudio = enabled
+		return nil
+	}
+}
+
+// WithMaxCaptureBytes 设置单个语音片段最多保留的音频字节数
+//
+// 仅在启用WithCaptureAudio时生效，超出部分被截断
+func WithMaxCaptureBytes(n int) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if n <= 0 {
+			return ErrInvalidCaptureLimit
+		}
+		cfg.maxCapture = n
+		return nil
+	}
+}
+
+// WithSegmentBuffer 设置Segments返回的通道容量（默认16）
+//
+// 通道满时写入阻塞，容量决定消费者可以落后多少个片段；0表示无缓冲
+func WithSegmentBuffer(n int) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if n < 0 {
+			return ErrBufferTooSmall
+		}
+		cfg.segmentBuffer = n
+		return nil
+	}
+}
+
+// WithSegmentCapacity 设置片段列表的初始容量（默认100）
+//
+// 预计片段较多的长时间流可预先分配，避免片段列表反复扩容复制；片段数超过容量后
+// 仍会正常增长。Reset保留已分配的容量
+func WithSegmentCapacity(n int) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if n < 0 {
+			return ErrBufferTooSmall
+		}
+		cfg.segmentCapacity = n
+		return nil
+	}
+}
+
+// WithClockFunc 注入流式组件读取墙上时间所用的时钟，nil表示不使用时钟
+//
+// 设置后片段携带绝对时间（VoiceSegment.StartTime / EndTime）：首次写入时读取一次
+// 时钟，将该批数据的最后一个样本对齐到此刻；之后的时间按音频时长推算，不受写入
+// 间隔抖动影响。通常传入time.Now。放入SessionConfig.Options时，SessionManager
+// 还以它判断会话的空闲过期（默认time.Now）。传入可手动推进的假时钟即可在测试与
+// 仿真中控制时间，无需真实等待
+func WithClockFunc(now func() time.Time) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		cfg.clock = now
+		return nil
+	}
+}
+
+// WithMetrics 每检测一帧向m上报判决与处理耗时（预处理与检测），nil表示不上报
+//
+// m在检测路径上同步调用，可被多个StreamVAD共享。Prometheus的实现见metrics/prommetrics
+func WithMetrics(m metrics.Recorder) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		cfg.metrics = m
+		return nil
+	}
+}
+
+// WithHighPass 检测前以80Hz高通滤波（HighPass80Hz）去除直流偏置与低频噪声
+//
+// 只影响检测输入，捕获的音频保持原样；与WithDenoise、WithGainControl同时使用时最先执行
+func WithHighPass() StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		cfg.highPass = true
+		return nil
+	}
+}
+
+// WithFilter 检测前以自定义滤波器（如陷波、带通或BiquadCascade）调理音频
+//
+// 只影响检测输入，捕获的音频保持原样；在WithHighPass之后、WithDenoise之前执行。
+// 滤波器有状态，不要在多个StreamVAD之间共享
+func WithFilter(f AudioFilter) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if f == nil {
+			return ErrInvalidFilter
+		}
+		cfg.filter = f
+		return nil
+	}
+}
+
+// WithDenoise 检测前对每帧做噪声抑制（ns子包，中度抑制）
+//
+// 改善风扇、交通等稳态噪声下的检测；只影响检测输入，捕获的音频（WithCaptureAudio）
+// 保持原样。降噪引入块长度0.6倍（6ms）的延迟，片段边界相应后移
+func WithDenoise() StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		cfg.denoise = true
+		return nil
+	}
+}
+
+// WithGainControl 检测前对每帧做自动增益控制（agc子包）
+//
+// 将远场、低电平麦克风的语音放大到检测器的工作范围；只影响检测输入，
+// 捕获的音频保持原样。与WithDenoise同时使用时先降噪再增益控制。
+// 默认配置可用agc.DefaultConfig()获得
+func WithGainControl(cfg agc.Config) StreamVADOption {
+	return func(c *streamVADConfig) error {
+		c.agc = &cfg
+		return nil
+	}
+}
+
 // NewStreamVADWithOptions 使用选项模式创建StreamVAD
 //
 // 示例:
@@ -116,6 +526,14 @@ func NewStreamVADWithOptions(opts ...StreamVADOption) (*StreamVAD, error) {
 		mode:       1,     // 默认模式1
 		sampleRate: 16000, // 默认16kHz
 		frameMs:    20,    // 默认20ms
+		maxCapture: DefaultMaxCaptureBytes,
+		hybrid:     DefaultHybridConfig(),
+
+		enterFrames: 1,
+		exitFrames:  1,
+
+		segmentBuffer:   defaultSegmentBuffer,
+		segmentCapacity: defaultSegmentCapacity,
 	}
 
 	// 应用所有选项
@@ -126,7 +544,81 @@ func NewStreamVADWithOptions(opts ...StreamVADOption) (*StreamVAD, error) {
 	}
 
 	// 创建StreamVAD实例
-	return NewStreamVAD(cfg.mode, cfg.sampleRate, cfg.frameMs)
+	svad, err := NewStreamVAD(cfg.mode, cfg.sampleRate, cfg.frameMs)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := cfg.detector.(*VAD); ok {
+		svad.vad = v // 传入的GMM检测器代替内部创建的实例
+	} else if svad.detector, err = newDetector(cfg); err != nil {
+		return nil, err
+	}
+	svad.thresholds = cfg.thresholds
+	svad.hangover = cfg.hangover
+	svad.applyOverrides()
+	if gmm := svad.gmm(); gmm != nil && cfg.frozenModel {
+		gmm.inst.frozenModel = true
+	}
+	svad.minSpeech = cfg.minSpeech
+	svad.minSilence = cfg.minSilence
+	svad.enterFrames = cfg.enterFrames
+	svad.exitFrames = cfg.exitFrames
+	svad.captureAudio = cfg.captureAudio
+	svad.maxCapture = cfg.maxCapture
+	svad.segChSize = cfg.segmentBuffer
+	if cfg.segmentCapacity != cap(svad.segments) {
+		svad.segments = make([]VoiceSegment, 0, cfg.segmentCapacity)
+	}
+	if cfg.bufferCapacity > 0 {
+		if cfg.bufferCapacity < svad.frameSize {
+			return nil, fmt.Errorf("%w: input buffer %d bytes, frame is %d bytes", ErrBufferTooSmall, cfg.bufferCapacity, svad.frameSize)
+		}
+		svad.input = newRingBuffer(cfg.bufferCapacity)
+	}
+	svad.overflow = cfg.overflow
+	svad.clock = cfg.clock
+	svad.metrics = cfg.metrics
+	if cfg.timing {
+		svad.timer = &frameTimer{}
+	}
+	svad.logger = cfg.logger
+	if cfg.smoother != nil {
+		svad.smoother, _ = NewSmoother(*cfg.smoother)
+	}
+	if cfg.highPass {
+		hpf, err := HighPass80Hz(cfg.sampleRate)
+		if err != nil {
+			return nil, err
+		}
+		svad.preprocessors = append(svad.preprocessors, filterStage{hpf})
+	}
+	if cfg.filter != nil {
+		svad.preprocessors = append(svad.preprocessors, filterStage{cfg.filter})
+	}
+	if cfg.denoise {
+		suppressor, err := ns.New(cfg.sampleRate, ns.Medium)
+		if err != nil {
+			return nil, err
+		}
+		svad.preprocessors = append(svad.preprocessors, suppressor)
+	}
+	if cfg.agc != nil {
+		controller, err := agc.New(cfg.sampleRate, *cfg.agc)
+		if err != nil {
+			return nil, err
+		}
+		svad.preprocessors = append(svad.preprocessors, controller)
+	}
+	if cfg.maxSegment > 0 {
+		frameDur := time.Duration(cfg.frameMs) * time.Millisecond
+		lookback := cfg.maxSegment / 2
+		if lookback > time.Second {
+			lookback = time.Second
+		}
+		svad.maxSegment = cfg.maxSegment
+		svad.energies = make([]int64, max(int(lookback/frameDur), 1))
+	}
+	return svad, nil
 }
 
 // 预定义的常用配置
@@ -158,3 +650,57 @@ func RealtimeStreamVAD() (*StreamVAD, error) {
 func HighQualityStreamVAD() (*StreamVAD, error) {
 	return NewStreamVAD(0, 48000, 30)
 }
+
+// TelephonyStreamVAD 创建适合电话语音的StreamVAD
+// 配置: mode=2, 8kHz, 20ms, 80Hz高通, 桥接300ms以内的停顿
+//
+// 8kHz与20ms对应G.711（μ-law/A-law）的采样率与常见RTP打包时长，PCMU/PCMA负载
+// 解码后（见RTPProcessor）一包正好一帧。话路噪声与回声较多，使用激进模式；
+// 高通滤波去除线路直流与工频干扰，电话中常见的短停顿不切分话语
+func TelephonyStreamVAD() (*StreamVAD, error) {
+	return NewStreamVADWithOptions(
+		WithStreamMode(2),
+		WithSampleRate(8000),
+		WithFrameDuration(20),
+		WithHighPass(),
+		WithMinSilenceDuration(300*time.Millisecond),
+	)
+}
+
+// ConferenceStreamVAD 创建适合多人会议发言检测的StreamVAD
+// 配置: mode=2, 48kHz, 10ms, 组合检测, 语音至少100ms, 桥接400ms以内的停顿
+//
+// 48kHz与10ms对应WebRTC/Opus解码输出的采样率与帧长度，无需重采样即可逐帧送入。
+// 会议中键盘敲击、鼠标点击等冲击噪声最常造成误判，组合检测（Hybrid）以过零率否决这类帧；
+// 最短语音与停顿桥接使发言指示不随单帧判决闪烁
+func ConferenceStreamVAD() (*StreamVAD, error) {
+	return NewStreamVADWithOptions(
+		WithStreamMode(2),
+		WithSampleRate(48000),
+		WithFrameDuration(10),
+		WithAlgorithm(Hybrid),
+		WithMinSpeechDuration(100*time.Millisecond),
+		WithMinSilenceDuration(400*time.Millisecond),
+	)
+}
+
+// ASRPreprocessStreamVAD 创建为语音识别切分音频的StreamVAD
+// 配置: mode=1, 16kHz, 30ms, 300ms尾部填充, 语音至少200ms, 桥接500ms以内的停顿,
+// 片段最长30秒, 捕获片段音频
+//
+// 16kHz是多数ASR模型的输入采样率。漏掉语音比多送静音代价更大，因此使用低激进度，
+// 并把长语音后的迟滞延长到300ms作为尾部填充，避免切掉词尾的弱辅音；短于200ms的
+// 咳嗽、点击声不单独成段，句内停顿不切分；30秒上限对应常见ASR模型的输入窗口，
+// 超长语音在停顿处切分。捕获的片段音频可直接提交识别（见VoiceSegment.SaveWAV）
+func ASRPreprocessStreamVAD() (*StreamVAD, error) {
+	return NewStreamVADWithOptions(
+		WithStreamMode(1),
+		WithSampleRate(16000),
+		WithFrameDuration(30),
+		WithStreamHangover([3]int16{8, 4, 3}, [3]int16{30, 15, 10}),
+		WithMinSpeechDuration(200*time.Millisecond),
+		WithMinSilenceDuration(500*time.Millisecond),
+		WithMaxSegmentDuration(30*time.Second),
+		WithCaptureAudio(true),
+	)
+}
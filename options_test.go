@@ -1,7 +1,14 @@
 package webrtcvad
 
 import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
 	"testing"
+	"time"
+
+	"github.com/godeps/webrtcvad-go/agc"
 )
 
 // TestNewWithOptions 测试选项模式创建VAD
@@ -76,6 +83,187 @@ func TestNewStreamVADWithOptions(t *testing.T) {
 	}
 }
 
+// TestWithFrozenModel 测试冻结模型选项
+func TestWithFrozenModel(t *testing.T) {
+	vad, err := NewWithOptions(WithFrozenModel())
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+
+	// 带噪声的输入会触发模型更新
+	frame := make([]byte, 320)
+	for i := range frame {
+		frame[i] = byte(i * 37)
+	}
+
+	noiseMeans := vad.inst.noiseMeans
+	speechStds := vad.inst.speechStds
+	for i := 0; i < 20; i++ {
+		if _, err := vad.IsSpeech(frame, 16000); err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+	}
+
+	if vad.inst.noiseMeans != noiseMeans || vad.inst.speechStds != speechStds {
+		t.Error("冻结模型后GMM参数不应变化")
+	}
+}
+
+// TestTuningOptions 测试阈值、迟滞与冻结模型可经两种选项接口设置
+func TestTuningOptions(t *testing.T) {
+	local, global := [3]int16{50, 50, 50}, [3]int16{300, 300, 300}
+	short, long := [3]int16{2, 1, 1}, [3]int16{4, 2, 1}
+
+	vad, err := NewWithOptions(WithMode(3), WithThresholds(local, global), WithHangover(short, long))
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	if l, g := vad.Thresholds(); l != local || g != global {
+		t.Errorf("阈值应为%v/%v, 得到%v/%v", local, global, l, g)
+	}
+	if s, l := vad.Hangover(); s != short || l != long {
+		t.Errorf("迟滞应为%v/%v, 得到%v/%v", short, long, s, l)
+	}
+	if _, err := NewWithOptions(WithHangover([3]int16{-1, 0, 0}, long)); !errors.Is(err, ErrInvalidHangover) {
+		t.Errorf("负的迟滞应返回ErrInvalidHangover, 得到%v", err)
+	}
+	if s, l := (&VAD{}).Hangover(); s != ([3]int16{}) || l != ([3]int16{}) {
+		t.Errorf("未初始化时迟滞应为零值, 得到%v/%v", s, l)
+	}
+
+	// StreamVAD：与选项顺序无关，GMM与组合检测均生效，Reset后保留
+	for _, alg := range []Algorithm{GMM, Hybrid} {
+		svad, err := NewStreamVADWithOptions(
+			WithStreamThresholds(local, global),
+			WithStreamHangover(short, long),
+			WithStreamFrozenModel(),
+			WithStreamMode(3),
+			WithAlgorithm(alg),
+		)
+		if err != nil {
+			t.Fatalf("%v: 创建StreamVAD失败: %v", alg, err)
+		}
+		gmm := svad.gmm()
+		if err := svad.Reset(); err != nil {
+			t.Fatalf("%v: 重置失败: %v", alg, err)
+		}
+		if l, g := gmm.Thresholds(); l != local || g != global {
+			t.Errorf("%v: 阈值应为%v/%v, 得到%v/%v", alg, local, global, l, g)
+		}
+		if s, l := gmm.Hangover(); s != short || l != long {
+			t.Errorf("%v: 迟滞应为%v/%v, 得到%v/%v", alg, short, long, s, l)
+		}
+		if !gmm.inst.frozenModel {
+			t.Errorf("%v: 模型应被冻结", alg)
+		}
+
+		// 运行时修改模式不丢弃显式设置的阈值与迟滞
+		if err := svad.SetMode(1); err != nil {
+			t.Fatalf("%v: SetMode失败: %v", alg, err)
+		}
+		svad.Process(make([]byte, 320))
+		if l, g := gmm.Thresholds(); l != local || g != global {
+			t.Errorf("%v: SetMode后阈值应为%v/%v, 得到%v/%v", alg, local, global, l, g)
+		}
+		if s, l := gmm.Hangover(); s != short || l != long {
+			t.Errorf("%v: SetMode后迟滞应为%v/%v, 得到%v/%v", alg, short, long, s, l)
+		}
+	}
+	if svad, _ := NewStreamVADWithOptions(WithAlgorithm(Energy), WithStreamFrozenModel()); svad.gmm() != nil {
+		t.Error("能量检测不使用GMM")
+	}
+	if _, err := NewStreamVADWithOptions(WithStreamThresholds(local, [3]int16{0, -5, 0})); !errors.Is(err, ErrInvalidThresholds) {
+		t.Errorf("负的阈值应返回ErrInvalidThresholds, 得到%v", err)
+	}
+}
+
+// alternatingDetector 每n帧切换一次判决的测试用检测器
+type alternatingDetector struct {
+	n, frames int
+	resets    int
+}
+
+func (d *alternatingDetector) IsSpeech(frame []byte, sampleRate int) (bool, error) {
+	d.frames++
+	return (d.frames-1)/d.n%2 == 1, nil
+}
+
+func (d *alternatingDetector) Reset() { d.resets++ }
+
+// TestWithDetector 测试StreamVAD使用自定义检测器分段
+func TestWithDetector(t *testing.T) {
+	d := &alternatingDetector{n: 10}
+	svad, err := NewStreamVADWithOptions(WithDetector(d), WithAlgorithm(Energy))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	svad.Process(make([]byte, 640*40)) // 40帧：静音、语音各10帧交替
+	svad.Flush()
+
+	segs := svad.GetSegments()
+	if d.frames != 40 || len(segs) != 4 {
+		t.Fatalf("应检测40帧得到4个片段, 得到%d帧%d个片段", d.frames, len(segs))
+	}
+	if !segs[1].IsSpeech || segs[1].Start != 200*time.Millisecond || segs[1].End != 400*time.Millisecond {
+		t.Errorf("第2个片段应为200-400ms的语音, 得到%+v", segs[1])
+	}
+	if err := svad.SetMode(2); !errors.Is(err, ErrModeUnsupported) {
+		t.Errorf("不支持模式的检测器应返回ErrModeUnsupported, 得到%v", err)
+	}
+	svad.Reset()
+	if d.resets != 1 {
+		t.Error("Reset应转发给检测器")
+	}
+
+	// 传入*VAD时保留其配置
+	vad, _ := NewWithOptions(WithMode(3))
+	svad, err = NewStreamVADWithOptions(WithDetector(vad), WithStreamFrozenModel())
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if svad.gmm() != vad || !vad.inst.frozenModel {
+		t.Error("应直接使用传入的VAD并应用GMM选项")
+	}
+	if l, _ := vad.Thresholds(); l != kLocalThresholdVAG {
+		t.Errorf("应保留传入VAD的模式3阈值, 得到%v", l)
+	}
+
+	if _, err := NewStreamVADWithOptions(WithDetector(nil)); err != ErrInvalidDetector {
+		t.Errorf("nil检测器应返回ErrInvalidDetector, 得到%v", err)
+	}
+}
+
+// TestCapacityOptions 测试输入缓冲区与片段列表的初始容量
+func TestCapacityOptions(t *testing.T) {
+	svad, err := NewStreamVADWithOptions(WithBufferCapacity(4097), WithSegmentCapacity(1000))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if svad.input.capacity() != 4096 || cap(svad.segments) != 1000 {
+		t.Errorf("容量应为4096字节/1000个片段, 得到%d/%d", svad.input.capacity(), cap(svad.segments))
+	}
+
+	// 容量为一帧时仍可处理任意大小的写入，结果与默认容量相同
+	pcm := harmonicNoise(16000, 2*time.Second, 500*time.Millisecond, 1500*time.Millisecond)
+	small, _ := NewStreamVADWithOptions(WithBufferCapacity(640), WithSegmentCapacity(0))
+	whole, _ := NewStreamVADWithOptions()
+	small.Process(pcm)
+	whole.Process(pcm)
+	if small.Stats() != whole.Stats() || len(small.GetSegments()) != len(whole.GetSegments()) {
+		t.Errorf("小容量的结果应与默认相同: %+v vs %+v", small.Stats(), whole.Stats())
+	}
+
+	if _, err := NewStreamVADWithOptions(WithBufferCapacity(600)); !errors.Is(err, ErrBufferTooSmall) {
+		t.Errorf("小于一帧的容量应返回ErrBufferTooSmall, 得到%v", err)
+	}
+	if _, err := NewStreamVADWithOptions(WithBufferCapacity(1)); err != ErrBufferTooSmall {
+		t.Errorf("不足一个样本的容量应返回ErrBufferTooSmall, 得到%v", err)
+	}
+	if _, err := NewStreamVADWithOptions(WithSegmentCapacity(-1)); err != ErrBufferTooSmall {
+		t.Errorf("负的片段容量应返回ErrBufferTooSmall, 得到%v", err)
+	}
+}
+
 // TestPresetConfigurations 测试预定义配置
 func TestPresetConfigurations(t *testing.T) {
 	tests := []struct {
@@ -117,6 +305,9 @@ func TestPresetStreamVADConfigurations(t *testing.T) {
 		{"DefaultStreamVAD", DefaultStreamVAD},
 		{"RealtimeStreamVAD", RealtimeStreamVAD},
 		{"HighQualityStreamVAD", HighQualityStreamVAD},
+		{"TelephonyStreamVAD", TelephonyStreamVAD},
+		{"ConferenceStreamVAD", ConferenceStreamVAD},
+		{"ASRPreprocessStreamVAD", ASRPreprocessStreamVAD},
 	}
 
 	for _, tt := range tests {
@@ -138,6 +329,12 @@ func TestPresetStreamVADConfigurations(t *testing.T) {
 			}
 		})
 	}
+
+	// 电话预设的一帧对应一个20ms的G.711包（160个样本）
+	svad, _ := TelephonyStreamVAD()
+	if svad.GetSampleRate() != 8000 || svad.frameSize != 160*2 {
+		t.Errorf("电话预设应为8kHz/20ms, 得到%dHz/%d字节", svad.GetSampleRate(), svad.frameSize)
+	}
 }
 
 // TestOptionsChaining 测试选项链式调用
@@ -195,3 +392,119 @@ func BenchmarkNewDirect(b *testing.B) {
 		_ = vad
 	}
 }
+
+// TestWithDenoise 测试降噪后强白噪声中只检测到正弦波段，且捕获的是原始音频
+func TestWithDenoise(t *testing.T) {
+	// 4s白噪声，第3s叠加正弦波
+	rng := rand.New(rand.NewSource(3))
+	pcm := make([]byte, 0, 128000)
+	for i := 0; i < 64000; i++ {
+		v := rng.NormFloat64() * 2000
+		if i >= 32000 && i < 48000 {
+			v += 6000 * math.Sin(2*math.Pi*440*float64(i)/16000)
+		}
+		v = math.Max(math.MinInt16, math.Min(math.MaxInt16, v))
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(v)))
+	}
+
+	svad, err := NewStreamVADWithOptions(WithStreamMode(3), WithFrameDuration(10), WithDenoise(), WithCaptureAudio(true))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if _, err := svad.Process(pcm); err != nil {
+		t.Fatalf("处理失败: %v", err)
+	}
+	svad.Flush()
+
+	speech := svad.FilterSpeechSegments()
+	if len(speech) != 1 || speech[0].Start < 2*time.Second || speech[0].End > 3100*time.Millisecond {
+		t.Fatalf("应只检测到2s-3s间的语音, 得到%v", speech)
+	}
+	off := int(speech[0].Start.Round(time.Millisecond).Milliseconds()) * 32
+	if string(speech[0].Audio[:320]) != string(pcm[off:off+320]) {
+		t.Error("捕获的音频应为降噪前的原始数据")
+	}
+
+	if _, err := NewStreamVADWithOptions(WithDenoise(), WithSampleRate(44100)); err == nil {
+		t.Error("无效采样率应返回错误")
+	}
+}
+
+// TestWithGainControl 测试增益控制选项的创建与参数校验
+func TestWithGainControl(t *testing.T) {
+	svad, err := NewStreamVADWithOptions(WithStreamMode(1), WithDenoise(), WithGainControl(agc.DefaultConfig()), WithCaptureAudio(true))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	if len(svad.preprocessors) != 2 {
+		t.Fatalf("应有降噪和增益控制两个预处理模块, 得到%d个", len(svad.preprocessors))
+	}
+
+	// 1s静音 + 1s低电平正弦波
+	pcm := make([]byte, 32000, 64000)
+	for i := 0; i < 16000; i++ {
+		v := 200 * math.Sin(2*math.Pi*440*float64(i)/16000)
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(v)))
+	}
+	svad.Process(pcm)
+	svad.Flush()
+	speech := svad.FilterSpeechSegments()
+	if len(speech) == 0 || speech[0].Start < time.Second {
+		t.Fatalf("应在1s后检测到语音, 得到%v", speech)
+	}
+	off := int(speech[0].Start.Round(time.Millisecond).Milliseconds()) * 32
+	if string(speech[0].Audio[:320]) != string(pcm[off:off+320]) {
+		t.Error("捕获的音频应为增益控制前的原始数据")
+	}
+
+	if _, err := NewStreamVADWithOptions(WithGainControl(agc.Config{TargetLevelDbfs: 40})); err != agc.ErrInvalidConfig {
+		t.Errorf("应返回agc.ErrInvalidConfig, 得到%v", err)
+	}
+}
+
+// countingRecorder 计数的metrics.Recorder
+type countingRecorder struct {
+	speech, silence int
+	latencies       []time.Duration
+}
+
+func (r *countingRecorder) IncFrames(speech bool) {
+	if speech {
+		r.speech++
+	} else {
+		r.silence++
+	}
+}
+
+func (r *countingRecorder) ObserveLatency(d time.Duration) {
+	r.latencies = append(r.latencies, d)
+}
+
+// TestWithMetrics 测试每帧上报一次判决与耗时，与Stats一致
+func TestWithMetrics(t *testing.T) {
+	rec := &countingRecorder{}
+	pcm := harmonicNoise(16000, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+	svad, err := NewStreamVADWithOptions(WithStreamMode(3), WithMetrics(rec))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	svad.Process(pcm)
+	svad.Flush()
+
+	st := svad.Stats()
+	if int64(rec.speech) != st.SpeechFrames || int64(rec.speech+rec.silence) != st.Frames {
+		t.Errorf("上报的帧数（语音%d, 静音%d）应与统计%+v一致", rec.speech, rec.silence, st)
+	}
+	if len(rec.latencies) != 150 {
+		t.Errorf("应上报150次耗时, 得到%d", len(rec.latencies))
+	}
+	for _, d := range rec.latencies {
+		if d < 0 {
+			t.Fatalf("耗时不应为负, 得到%v", d)
+		}
+	}
+
+	if _, err := NewStreamVADWithOptions(WithMetrics(nil)); err != nil {
+		t.Errorf("nil表示不上报, 不应返回错误: %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package webrtcvad
+
+import (
+	"context"
+	"time"
+)
+
+// pipe.go 提供通道管线构造函数：音频块通道输入，类型化事件通道输出，
+// 便于将VAD接入基于通道的Go音频管线
+
+// EventType 管线事件类型
+type EventType int
+
+const (
+	// EventFrameDecision 一帧的判决（平滑后，与片段所用的判决相同）
+	EventFrameDecision EventType = iota
+	// EventSpeechStart 语音片段开始
+	EventSpeechStart
+	// EventSpeechEnd 语音片段结束
+	EventSpeechEnd
+	// EventError 处理出错，之后通道关闭
+	EventError
+)
+
+// String 返回事件类型的名称
+func (t EventType) String() string {
+	switch t {
+	case EventFrameDecision:
+		return "frame"
+	case EventSpeechStart:
+		return "speech_start"
+	case EventSpeechEnd:
+		return "speech_end"
+	case EventError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Event 管线事件
+type Event struct {
+	// Type 事件类型
+	Type EventType
+	// Time 事件在流中的时间：帧的开始（EventFrameDecision）、片段的开始（EventSpeechStart）
+	// 或片段的结束（EventSpeechEnd）
+	Time time.Duration
+	// IsSpeech 帧的判决，仅EventFrameDecision有效
+	IsSpeech bool
+	// Segment 语音片段：EventSpeechStart时为片段开始时的状态，EventSpeechEnd时为完整片段
+	Segment VoiceSegment
+	// Err 错误，仅EventError有效
+	Err error
+}
+
+// Pipe 在后台goroutine中检测in中的音频块，返回事件通道
+//
+// 按检测顺序发送每帧的判决以及语音片段的开始与结束事件。由于WithMinSpeechDuration等
+// 平滑选项会延迟确认，片段事件在确认时发送，其Time可能早于之前发送的帧事件。
+// in关闭或ctx取消时，先结束最后一个片段并发送剩余事件，再关闭通道，因此消费者应读取
+// 通道直到其关闭。选项无效或处理出错时发送一个EventError事件后关闭通道；ctx取消不视为错误。
+//
+// 参数:
+//   - ctx: 取消处理
+//   - in: 音频块（16位PCM，小端序）
+//   - opts: StreamVAD的配置选项，通道容量见WithSegmentBuffer
+//
+// 返回:
+//   - <-chan Event: 事件通道
+func Pipe(ctx context.Context, in <-chan []byte, opts ...StreamVADOption) <-chan Event {
+	svad, err := NewStreamVADWithOptions(opts...)
+	if err != nil {
+		out := make(chan Event, 1)
+		out <- Event{Type: EventError, Err: err}
+		close(out)
+		return out
+	}
+
+	out := make(chan Event, svad.segChSize)
+
+	// 帧事件在检测过程中（持有svad.mu，位于本goroutine内）收集，Process返回后发送
+	var events []Event
+	started, completed := 0, 0 // 已发送开始/结束事件的片段数
+	collectSegments := func() {
+		for ; started < len(svad.segments); started++ {
+			if seg := svad.segments[started]; seg.IsSpeech {
+				events = append(events, Event{Type: EventSpeechStart, Time: seg.Start, Segment: seg})
+			}
+		}
+		for end := svad.completedCount(); completed < end; completed++ {
+			if seg := svad.segments[completed]; seg.IsSpeech {
+				events = append(events, Event{Type: EventSpeechEnd, Time: seg.End, Segment: seg})
+			}
+		}
+	}
+	svad.onFrame = func(start time.Duration, isSpeech bool) {
+		events = append(events, Event{Type: EventFrameDecision, Time: start, IsSpeech: isSpeech})
+		collectSegments()
+	}
+
+	go func() {
+		defer close(out)
+
+		// send 发送已收集的事件；cancellable时可被ctx取消，未发送的事件留待结束时发送，
+		// 返回是否全部发送
+		send := func(cancellable bool) bool {
+			for i, ev := range events {
+				if !cancellable {
+					out <- ev
+					continue
+				}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					events = append(events[:0], events[i:]...)
+					return false
+				}
+			}
+			events = events[:0]
+			return true
+		}
+
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case data, ok := <-in:
+				if !ok {
+					break loop
+				}
+				if _, err := svad.Process(data); err != nil {
+					send(true)
+					out <- Event{Type: EventError, Err: err}
+					return
+				}
+				if !send(true) {
+					break loop
+				}
+			}
+		}
+
+		// 结束：处理残余数据，发送剩余事件
+		err := svad.Close()
+		svad.mu.Lock()
+		collectSegments()
+		svad.mu.Unlock()
+		send(false)
+		if err != nil {
+			out <- Event{Type: EventError, Err: err}
+		}
+	}()
+	return out
+}
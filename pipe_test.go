@@ -0,0 +1,104 @@
+package webrtcvad
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestPipe 测试事件管线
+func TestPipe(t *testing.T) {
+	pcm := harmonicNoise(16000, 2*time.Second, 500*time.Millisecond, 1500*time.Millisecond)
+	in := make(chan []byte)
+	go func() {
+		for i := 0; i < len(pcm); i += 1000 { // 块长度与帧长度无关
+			in <- pcm[i:min(i+1000, len(pcm))]
+		}
+		close(in)
+	}()
+
+	var frames, speechFrames int
+	var starts, ends []Event
+	var last time.Duration = -1
+	for ev := range Pipe(context.Background(), in, WithStreamMode(2), WithFrameDuration(20)) {
+		switch ev.Type {
+		case EventFrameDecision:
+			if ev.Time <= last {
+				t.Fatalf("帧事件时间应递增: %v之后为%v", last, ev.Time)
+			}
+			last = ev.Time
+			frames++
+			if ev.IsSpeech {
+				speechFrames++
+			}
+		case EventSpeechStart:
+			starts = append(starts, ev)
+		case EventSpeechEnd:
+			ends = append(ends, ev)
+		case EventError:
+			t.Fatalf("处理失败: %v", ev.Err)
+		}
+	}
+
+	if frames != 100 || speechFrames == 0 {
+		t.Errorf("应有100个帧事件且含语音帧, 得到%d个（语音%d）", frames, speechFrames)
+	}
+	if len(starts) == 0 || len(starts) != len(ends) {
+		t.Fatalf("语音开始与结束事件应成对出现: %d个开始, %d个结束", len(starts), len(ends))
+	}
+	for i := range starts {
+		if starts[i].Segment.Start != ends[i].Segment.Start || ends[i].Time != ends[i].Segment.End || !ends[i].Segment.IsSpeech {
+			t.Errorf("第%d个片段事件不一致: 开始%+v, 结束%+v", i, starts[i], ends[i])
+		}
+	}
+	if starts[0].Time > 700*time.Millisecond || ends[len(ends)-1].Time < 1300*time.Millisecond {
+		t.Errorf("语音应覆盖0.5s-1.5s, 得到%v-%v", starts[0].Time, ends[len(ends)-1].Time)
+	}
+}
+
+// TestPipeErrors 测试无效选项与取消
+func TestPipeErrors(t *testing.T) {
+	events := Pipe(context.Background(), nil, WithStreamMode(9))
+	if ev := <-events; ev.Type != EventError || ev.Err != ErrInvalidMode {
+		t.Errorf("无效选项应发送EventError, 得到%+v", ev)
+	}
+	if _, ok := <-events; ok {
+		t.Error("EventError之后通道应关闭")
+	}
+
+	// 取消后通道关闭，语音片段仍以结束事件收尾
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan []byte, 1)
+	in <- sineFrame(440, 8000, 16000, 16000)
+	events = Pipe(ctx, in, WithStreamMode(0))
+	var starts, ends int
+	for ev := range events {
+		switch ev.Type {
+		case EventFrameDecision:
+			cancel()
+		case EventSpeechStart:
+			starts++
+		case EventSpeechEnd:
+			ends++
+		}
+	}
+	if starts != ends {
+		t.Errorf("取消后开始与结束事件应成对: %d个开始, %d个结束", starts, ends)
+	}
+}
+
+// TestEventTypeString 测试事件类型名称
+func TestEventTypeString(t *testing.T) {
+	for typ, want := range map[EventType]string{
+		EventFrameDecision: "frame",
+		EventSpeechStart:   "speech_start",
+		EventSpeechEnd:     "speech_end",
+		EventError:         "error",
+		EventType(99):      "unknown",
+	} {
+		if got := typ.String(); got != want {
+			t.Errorf("%d: 期望%q, 得到%q", int(typ), want, got)
+		}
+	}
+}
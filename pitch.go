@@ -0,0 +1,103 @@
+package webrtcvad
+
+import (
+	"math"
+	"math/bits"
+)
+
+// pitch.go 提供基于归一化自相关的基频（F0）估计
+
+// 基频搜索范围与浊音判决门限
+const (
+	minPitchHz = 60.0  // 最低基频
+	maxPitchHz = 400.0 // 最高基频
+	// voicedThreshold 浊音所需的最小归一化自相关（周期性）
+	voicedThreshold = 0.5
+	// octaveTolerance 较短周期的峰值不低于最高峰的该比例时优先选取，避免倍周期（半频）错误
+	octaveTolerance = 0.9
+	// minPitchEnergy 每样本的最小平均能量（RMS约30），更安静的帧视为清音
+	minPitchEnergy = 900.0
+)
+
+// EstimatePitch 估计一帧的基频
+//
+// 在60-400Hz范围内搜索归一化自相关的峰值，以抛物线插值得到亚样本精度的周期。
+// 帧至少需包含两个基音周期：30ms帧可估计60Hz以上的基频，10ms帧只能估计200Hz以上。
+//
+// 参数:
+//   - frame: 16位样本
+//   - rate: 采样率（Hz）
+//
+// 返回:
+//   - hz: 基频（Hz），非浊音时为0
+//   - voiced: 帧是否为浊音（周期性足够强且能量足够）
+func EstimatePitch(frame []int16, rate int) (hz float64, voiced bool) {
+	if rate <= 0 {
+		return 0, false
+	}
+	n := len(frame)
+	minLag := int(float64(rate) / maxPitchHz)
+	maxLag := min(int(math.Ceil(float64(rate)/minPitchHz)), n/2)
+	if minLag < 2 || maxLag <= minLag+1 {
+		return 0, false
+	}
+
+	// r[lag] = Σ frame[j]·frame[j+lag]，j < m，各延迟的求和长度相同；
+	// 多算一个延迟供插值使用
+	m := n - maxLag - 1
+	maxAbs := int(maxAbsValueW16(frame, n))
+	shifts := 2*bits.Len(uint(maxAbs)) + bits.Len(uint(m)) - 31
+	if shifts < 0 {
+		shifts = 0
+	}
+	r := CrossCorrelation(frame, frame, m, maxLag+2, shifts, 1)
+
+	// 各窗口能量，按与r相同的缩放
+	scale := math.Ldexp(1, -shifts)
+	energy := make([]float64, maxLag+2)
+	var e float64
+	for j := 0; j < m; j++ {
+		e += float64(frame[j]) * float64(frame[j])
+	}
+	if e/float64(m) < minPitchEnergy {
+		return 0, false
+	}
+	for lag := range energy {
+		energy[lag] = e * scale
+		if lag < maxLag+1 {
+			e += float64(frame[lag+m])*float64(frame[lag+m]) - float64(frame[lag])*float64(frame[lag])
+		}
+	}
+
+	nr := make([]float64, maxLag+2)
+	for lag := range nr {
+		if d := energy[0] * energy[lag]; d > 0 {
+			nr[lag] = float64(r[lag]) / math.Sqrt(d)
+		}
+	}
+
+	// 最高峰，以及不低于其octaveTolerance倍的最短周期局部峰
+	best := -1
+	for lag := minLag; lag <= maxLag; lag++ {
+		if nr[lag] >= nr[lag-1] && nr[lag] >= nr[lag+1] && (best < 0 || nr[lag] > nr[best]) {
+			best = lag
+		}
+	}
+	if best < 0 || nr[best] < voicedThreshold {
+		return 0, false
+	}
+	for lag := minLag; lag < best; lag++ {
+		if nr[lag] >= nr[lag-1] && nr[lag] >= nr[lag+1] && nr[lag] >= octaveTolerance*nr[best] {
+			best = lag
+			break
+		}
+	}
+
+	// 抛物线插值
+	period := float64(best)
+	a, b, c := nr[best-1], nr[best], nr[best+1]
+	if den := a - 2*b + c; den < 0 {
+		period += 0.5 * (a - c) / den
+	}
+	return float64(rate) / period, true
+}
@@ -0,0 +1,54 @@
+package webrtcvad
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestEstimatePitch 测试谐波信号的基频估计与噪声的清音判决
+func TestEstimatePitch(t *testing.T) {
+	for _, rate := range []int{8000, 16000, 48000} {
+		for _, f0 := range []float64{85, 120, 210.5, 330} {
+			// 含缺失基波的谐波信号（电话语音常见），考察倍频/半频错误
+			frame := make([]int16, rate*30/1000)
+			for i := range frame {
+				var v float64
+				for h := 2; h <= 6; h++ {
+					if f := f0 * float64(h); f < float64(rate)/2 {
+						v += 2000 / float64(h) * math.Sin(2*math.Pi*f*float64(i)/float64(rate))
+					}
+				}
+				frame[i] = int16(v)
+			}
+			hz, voiced := EstimatePitch(frame, rate)
+			if !voiced || math.Abs(hz-f0) > f0*0.01 {
+				t.Errorf("%dHz: 基频%.1fHz估计为%.2fHz（浊音=%v）", rate, f0, hz, voiced)
+			}
+		}
+	}
+
+	// 10ms帧只能估计200Hz以上的基频
+	frame := make([]int16, 160)
+	for i := range frame {
+		frame[i] = int16(5000 * math.Sin(2*math.Pi*250*float64(i)/16000))
+	}
+	if hz, voiced := EstimatePitch(frame, 16000); !voiced || math.Abs(hz-250) > 2.5 {
+		t.Errorf("10ms帧: 基频250Hz估计为%.2fHz（浊音=%v）", hz, voiced)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	noise := make([]int16, 480)
+	for i := range noise {
+		noise[i] = int16(rng.NormFloat64() * 3000)
+	}
+	if hz, voiced := EstimatePitch(noise, 16000); voiced || hz != 0 {
+		t.Errorf("白噪声应判为清音, 得到%.2fHz", hz)
+	}
+	if _, voiced := EstimatePitch(make([]int16, 480), 16000); voiced {
+		t.Error("静音应判为清音")
+	}
+	if _, voiced := EstimatePitch(frame[:20], 16000); voiced {
+		t.Error("过短的帧应判为清音")
+	}
+}
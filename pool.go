@@ -0,0 +1,50 @@
+package webrtcvad
+
+import "sync"
+
+// pool.go 管理跨VAD实例共享的临时缓冲区
+//
+// 服务端同时运行大量VAD实例时，重采样临时内存和字节->样本转换缓冲区
+// 只在单帧处理期间使用，通过sync.Pool共享可避免每个实例常驻持有，
+// 同时避免逐帧分配带来的GC压力。
+
+const (
+	// kResampleTmpMemSize 24/48kHz重采样临时内存大小（int32个数）
+	kResampleTmpMemSize = 480 + 256
+	// kMaxFrameSamples 单帧最大样本数（48kHz，30ms）
+	kMaxFrameSamples = 48000 * 30 / 1000
+)
+
+var (
+	tmpMemPool = sync.Pool{
+		New: func() any { return new([kResampleTmpMemSize]int32) },
+	}
+	samplePool = sync.Pool{
+		New: func() any { return new([kMaxFrameSamples]int16) },
+	}
+)
+
+// getTmpMem 从池中获取清零的重采样临时内存
+//
+// 重采样流水线会读取临时内存中本帧未写入的位置，池中缓冲区可能残留
+// 其他实例的数据，因此取出时清零，保持与逐帧新分配相同的结果
+func getTmpMem() *[kResampleTmpMemSize]int32 {
+	buf := tmpMemPool.Get().(*[kResampleTmpMemSize]int32)
+	clear(buf[:])
+	return buf
+}
+
+// putTmpMem 归还重采样临时内存
+func putTmpMem(buf *[kResampleTmpMemSize]int32) {
+	tmpMemPool.Put(buf)
+}
+
+// getSampleBuf 从池中获取样本转换缓冲区
+func getSampleBuf() *[kMaxFrameSamples]int16 {
+	return samplePool.Get().(*[kMaxFrameSamples]int16)
+}
+
+// putSampleBuf 归还样本转换缓冲区
+func putSampleBuf(buf *[kMaxFrameSamples]int16) {
+	samplePool.Put(buf)
+}
@@ -0,0 +1,64 @@
+package webrtcvad
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestIsSpeechPooledNoAlloc 测试复制路径借助缓冲池不产生分配
+func TestIsSpeechPooledNoAlloc(t *testing.T) {
+	vad, err := New(1)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+
+	frame := make([]byte, 1440*2) // 30ms @ 48kHz
+	vad.IsSpeech(frame, 48000)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		vad.IsSpeech(frame, 48000)
+	})
+	if allocs != 0 {
+		t.Errorf("期望0次分配, 得到%.1f", allocs)
+	}
+}
+
+// TestPoolConcurrentInstances 测试多个实例并发共享缓冲池时结果与串行一致
+func TestPoolConcurrentInstances(t *testing.T) {
+	frame := sineFrame(600, 9000, 48000, 480)
+
+	// 串行参考结果
+	ref, err := New(2)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	want := make([]bool, 50)
+	for i := range want {
+		want[i], _ = ref.IsSpeech(frame, 48000)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vad, err := New(2)
+			if err != nil {
+				t.Errorf("创建VAD失败: %v", err)
+				return
+			}
+			for i := range want {
+				got, err := vad.IsSpeech(frame, 48000)
+				if err != nil {
+					t.Errorf("检测失败: %v", err)
+					return
+				}
+				if got != want[i] {
+					t.Errorf("帧%d: 并发结果%v与串行结果%v不一致", i, got, want[i])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
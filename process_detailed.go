@@ -0,0 +1,148 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// process_detailed.go 提供带内部中间结果的逐帧检测接口
+
+// FrameResult 单帧检测的详细结果
+type FrameResult struct {
+	// IsSpeech 最终判决（含迟滞平滑），与IsSpeech返回值一致
+	IsSpeech bool
+
+	// Probability 全局检验的语音概率（0-1），0.5对应当前模式的全局阈值
+	//
+	// 由对数似然比之和相对全局阈值的余量按频谱权重平均（每频带的log2似然比）后
+	// 经logistic映射得到，不含局部判决与迟滞平滑，可用于自定义门限或置信度显示。
+	// 能量过低的帧为0
+	Probability float64
+
+	// Energy 帧的RMS电平（dBFS），不低于LevelFloorDBFS
+	Energy float64
+
+	// Timestamp 帧的开始时间，即此前IsSpeech处理过的音频总时长（不含Prime的预热帧）
+	Timestamp time.Duration
+
+	// BandActive 各频带的局部判决（未经迟滞平滑）
+	//
+	// 频带依次为 80-250, 250-500, 500-1000, 1000-2000, 2000-3000, 3000-4000 Hz。
+	// 例如只有BandActive[0]为true通常是低频嗡嗡声，而非宽带语音。
+	// 能量过低的帧所有频带均为false。
+	BandActive [NumChannels]bool
+
+	// LogLikelihoodRatio 频谱加权的对数似然比之和（全局检验统计量）
+	//
+	// 当其不小于当前模式的全局阈值时判为语音。能量过低的帧为0。
+	LogLikelihoodRatio int32
+
+	// BandLLR 各频带的对数似然比 log2(Pr{X|语音} / Pr{X|噪声})
+	//
+	// 当 BandLLR*4 大于当前模式的局部阈值时该频带局部判为语音
+	BandLLR [NumChannels]int16
+
+	// SNR 近似帧信噪比（dB）
+	//
+	// 由各频带能量之和与噪声底估计（见NoiseFloor）之和的比值得到。
+	// 该值包含噪声本身，纯背景噪声帧约为0 dB。
+	SNR float64
+}
+
+// ProcessDetailed 检测音频帧并返回详细结果
+//
+// 参数和帧长度要求与IsSpeech相同
+func (v *VAD) ProcessDetailed(buf []byte, sampleRate int) (FrameResult, error) {
+	start := v.elapsed
+	isSpeech, err := v.IsSpeech(buf, sampleRate)
+	if err != nil {
+		return FrameResult{}, err
+	}
+
+	var probability float64
+	if v.inst.totalPower > kMinEnergy {
+		frameMs := len(buf) / 2 * 1000 / sampleRate
+		probability = speechProbability(v.inst.sumLLR, v.inst.total[frameMs/10-1])
+	}
+
+	return FrameResult{
+		IsSpeech:           isSpeech,
+		Probability:        probability,
+		Energy:             frameEnergy(buf),
+		Timestamp:          start,
+		BandActive:         v.inst.bandActive,
+		LogLikelihoodRatio: v.inst.sumLLR,
+		BandLLR:            v.inst.bandLLR,
+		SNR:                estimateSNR(v.inst.features, v.inst.meanValue),
+	}, nil
+}
+
+// estimateSNR 由频带对数能量和噪声底估计近似帧信噪比
+//
+// features和noiseFloor均为10*log10(能量)的Q4定点数，先转回线性能量求和再取比值
+func estimateSNR(features, noiseFloor [kNumChannels]int16) float64 {
+	var signal, noise float64
+	for ch := 0; ch < kNumChannels; ch++ {
+		signal += math.Pow(10, float64(features[ch])/160)
+		noise += math.Pow(10, float64(noiseFloor[ch])/160)
+	}
+	return 10 * math.Log10(signal/noise)
+}
+
+// ProcessAll 将整段录音按frameMs分帧，依次检测并返回每帧的详细结果
+//
+// 省去调用方自行分帧与循环调用ProcessDetailed的代码。Timestamp为帧在buf中的开始时间，
+// 不受此前处理过的音频影响；末尾不足一帧的数据被忽略。检测状态随处理延续，
+// 对另一段录音调用前通常应使用新的VAD
+//
+// 参数:
+//   - buf: 16位小端序PCM音频数据
+//   - sampleRate: 采样率
+//   - frameMs: 帧长度（毫秒，10/20/30）
+//
+// 返回:
+//   - []FrameResult: 每帧的检测结果
+//   - error: 参数无效时返回错误；检测出错时同时返回已完成帧的结果
+func (v *VAD) ProcessAll(buf []byte, sampleRate, frameMs int) ([]FrameResult, error) {
+	if !isValidSampleRate(sampleRate) {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidSampleRate, sampleRate)
+	}
+	if frameMs != 10 && frameMs != 20 && frameMs != 30 {
+		return nil, fmt.Errorf("%w, got %d ms", ErrInvalidFrameLength, frameMs)
+	}
+
+	frameBytes := sampleRate * frameMs / 1000 * 2
+	frameDur := time.Duration(frameMs) * time.Millisecond
+	results := make([]FrameResult, 0, len(buf)/frameBytes)
+	for i := 0; i+frameBytes <= len(buf); i += frameBytes {
+		res, err := v.ProcessDetailed(buf[i:i+frameBytes], sampleRate)
+		if err != nil {
+			return results, fmt.Errorf("frame %d: %w", len(results), err)
+		}
+		res.Timestamp = time.Duration(len(results)) * frameDur
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// kSpectrumWeightSum 频谱权重之和，用于将对数似然比之和换算为每频带的平均值
+const kSpectrumWeightSum = 6 + 8 + 10 + 12 + 14 + 16
+
+// speechProbability 将对数似然比之和相对全局阈值的余量映射为0-1的概率
+func speechProbability(sumLLR int32, threshold int16) float64 {
+	margin := float64(sumLLR-int32(threshold)) / kSpectrumWeightSum
+	return 1 / (1 + math.Exp2(-margin))
+}
+
+// frameEnergy 计算一帧16位小端序PCM的RMS电平（dBFS）
+func frameEnergy(buf []byte) float64 {
+	n := len(buf) / 2
+	var sum float64
+	for i := 0; i < n; i++ {
+		v := float64(int16(binary.LittleEndian.Uint16(buf[i*2:])))
+		sum += v * v
+	}
+	return toDBFS(math.Sqrt(sum / float64(n)))
+}
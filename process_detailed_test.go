@@ -0,0 +1,191 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// sineFrame 生成指定频率的正弦波帧（16位小端序PCM）
+func sineFrame(freq float64, amplitude float64, sampleRate, samples int) []byte {
+	buf := make([]byte, samples*2)
+	for i := 0; i < samples; i++ {
+		v := amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(int16(v)))
+	}
+	return buf
+}
+
+// TestProcessDetailedBandActive 测试频带局部判决
+func TestProcessDetailedBandActive(t *testing.T) {
+	vad, err := New(0)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+
+	// 静音帧：所有频带均不活跃
+	detail, err := vad.ProcessDetailed(make([]byte, 160), 8000)
+	if err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+	if detail.IsSpeech || detail.BandActive != ([NumChannels]bool{}) {
+		t.Errorf("静音帧不应有活跃频带: %+v", detail)
+	}
+
+	// 强低频信号应至少触发一个频带，且判决与IsSpeech一致
+	frame := sineFrame(150, 12000, 8000, 80)
+	var anyActive bool
+	for i := 0; i < 10; i++ {
+		detail, err = vad.ProcessDetailed(frame, 8000)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		for _, active := range detail.BandActive {
+			anyActive = anyActive || active
+		}
+	}
+	if !anyActive {
+		t.Error("强低频信号应触发至少一个频带")
+	}
+
+	if _, err := vad.ProcessDetailed(make([]byte, 10), 8000); err == nil {
+		t.Error("应该拒绝无效帧长度")
+	}
+}
+
+// TestProcessDetailedLLR 测试似然比与判决阈值的一致性
+func TestProcessDetailedLLR(t *testing.T) {
+	vad, err := New(2)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+
+	frame := sineFrame(300, 10000, 8000, 80)
+	for i := 0; i < 20; i++ {
+		res, err := vad.ProcessDetailed(frame, 8000)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+
+		// 加权和应与频带似然比一致
+		var sum int32
+		for ch, llr := range res.BandLLR {
+			sum += int32(llr) * int32(kSpectrumWeight[ch])
+			if active := llr*4 > vad.inst.individual[0]; active != res.BandActive[ch] {
+				t.Fatalf("帧%d频带%d: 局部判决%v与似然比%d不一致", i, ch, res.BandActive[ch], llr)
+			}
+		}
+		if sum != res.LogLikelihoodRatio {
+			t.Fatalf("帧%d: 加权和%d与LogLikelihoodRatio %d不一致", i, sum, res.LogLikelihoodRatio)
+		}
+	}
+}
+
+// TestEstimateSNR 测试信噪比估计
+func TestEstimateSNR(t *testing.T) {
+	var floor, features [NumChannels]int16
+	for ch := range floor {
+		floor[ch] = 320          // 20 dB
+		features[ch] = 320 + 160 // 30 dB
+	}
+
+	if snr := estimateSNR(floor, floor); math.Abs(snr) > 1e-9 {
+		t.Errorf("信号等于噪声底时SNR应为0 dB, 得到%.3f", snr)
+	}
+	if snr := estimateSNR(features, floor); math.Abs(snr-10) > 1e-9 {
+		t.Errorf("期望SNR 10 dB, 得到%.3f", snr)
+	}
+
+	vad, err := New(0)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	res, err := vad.ProcessDetailed(sineFrame(500, 10000, 8000, 80), 8000)
+	if err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+	if math.IsNaN(res.SNR) || math.IsInf(res.SNR, 0) {
+		t.Errorf("SNR应为有限值, 得到%v", res.SNR)
+	}
+}
+
+// TestProcessDetailedProbability 测试语音概率、电平与时间戳
+func TestProcessDetailedProbability(t *testing.T) {
+	vad, err := New(2)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	vad.Prime(make([]byte, 3200), 16000)
+
+	pcm := harmonicNoise(16000, time.Second, 500*time.Millisecond, time.Second)
+	var speech int
+	for i := 0; i+640 <= len(pcm); i += 640 {
+		res, err := vad.ProcessDetailed(pcm[i:i+640], 16000)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		if want := time.Duration(i/640) * 20 * time.Millisecond; res.Timestamp != want {
+			t.Fatalf("时间戳应为%v（不含预热帧）, 得到%v", want, res.Timestamp)
+		}
+		if res.Probability < 0 || res.Probability > 1 {
+			t.Fatalf("概率应在[0, 1]内, 得到%g", res.Probability)
+		}
+		if global := res.LogLikelihoodRatio >= int32(vad.inst.total[1]); global != (res.Probability >= 0.5) {
+			t.Fatalf("%v: 概率%g与全局检验（LLR %d, 阈值%d）不一致", res.Timestamp, res.Probability, res.LogLikelihoodRatio, vad.inst.total[1])
+		}
+		if res.Probability >= 0.5 {
+			speech++
+		}
+	}
+	if speech == 0 {
+		t.Error("谐波段应有概率不低于0.5的帧")
+	}
+
+	res, _ := vad.ProcessDetailed(sineFrame(1000, 32767, 16000, 160), 16000)
+	if math.Abs(res.Energy+3.01) > 0.1 {
+		t.Errorf("满幅正弦波的电平应约为-3dBFS, 得到%.2f", res.Energy)
+	}
+	res, _ = vad.ProcessDetailed(make([]byte, 320), 16000)
+	if res.Energy != LevelFloorDBFS {
+		t.Errorf("静音帧的电平应为%g, 得到%g", LevelFloorDBFS, res.Energy)
+	}
+
+	fresh, _ := New(2)
+	if res, _ := fresh.ProcessDetailed(make([]byte, 320), 16000); res.Probability != 0 {
+		t.Errorf("能量过低的帧概率应为0, 得到%g", res.Probability)
+	}
+}
+
+// TestProcessAll 测试整段分帧处理与逐帧调用ProcessDetailed一致
+func TestProcessAll(t *testing.T) {
+	pcm := harmonicNoise(16000, time.Second, 500*time.Millisecond, time.Second)
+	pcm = append(pcm, make([]byte, 100)...) // 不足一帧的尾部被忽略
+
+	vad, _ := New(2)
+	vad.IsSpeech(make([]byte, 320), 16000) // 此前处理过的音频不影响时间戳
+	results, err := vad.ProcessAll(pcm, 16000, 30)
+	if err != nil {
+		t.Fatalf("处理失败: %v", err)
+	}
+	if len(results) != 33 {
+		t.Fatalf("1s音频应分为33个30ms帧, 得到%d", len(results))
+	}
+
+	ref, _ := New(2)
+	ref.IsSpeech(make([]byte, 320), 16000)
+	for i, res := range results {
+		want, _ := ref.ProcessDetailed(pcm[i*960:(i+1)*960], 16000)
+		want.Timestamp = time.Duration(i) * 30 * time.Millisecond
+		if res != want {
+			t.Fatalf("第%d帧应为%+v, 得到%+v", i, want, res)
+		}
+	}
+
+	if _, err := vad.ProcessAll(pcm, 16000, 25); err == nil {
+		t.Error("无效帧长度应返回错误")
+	}
+	if _, err := vad.ProcessAll(pcm, 44100, 10); err == nil {
+		t.Error("无效采样率应返回错误")
+	}
+}
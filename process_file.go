@@ -0,0 +1,95 @@
+package webrtcvad
+
+import (
+	"io"
+	"os"
+)
+
+// process_file.go 提供一次调用完成整个文件检测的便捷函数
+
+// DefaultRawSampleRate ProcessFile处理无头部的原始PCM文件时假定的采样率
+const DefaultRawSampleRate = 16000
+
+// ProcessFile 检测音频文件中的语音，返回话语级的语音片段
+//
+// 由DetectFormat识别WAV与AIFF文件，否则按DefaultRawSampleRate的16位单声道小端序
+// 原始PCM处理（不使用DetectFormat对原始PCM的启发式估计，需要时可自行调用）。
+// 以30ms帧运行带填充的话语收集器（Collector的默认参数），每段话语对应一个
+// 语音片段，Audio中包含该话语的PCM数据。
+//
+// 参数:
+//   - path: 音频文件路径
+//   - opts: VAD配置选项（如WithMode）
+//
+// 返回:
+//   - []VoiceSegment: 按时间排列的语音片段
+//   - error: 错误信息
+func ProcessFile(path string, opts ...Option) ([]VoiceSegment, error) {
+	vad, err := NewWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	src, sampleRate, err := openAudio(f)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := NewCollector(vad, CollectorConfig{SampleRate: sampleRate, FrameMs: 30})
+	if err != nil {
+		return nil, err
+	}
+
+	var utterances []Utterance
+	buf := make([]byte, c.frameSize*32)
+	for {
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			done, err := c.Write(buf[:n])
+			if err != nil {
+				return nil, err
+			}
+			utterances = append(utterances, done...)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+	if u := c.Flush(); u != nil {
+		utterances = append(utterances, *u)
+	}
+
+	segments := make([]VoiceSegment, len(utterances))
+	for i, u := range utterances {
+		segments[i] = VoiceSegment{
+			Start:      u.Start,
+			End:        u.End,
+			IsSpeech:   true,
+			SampleRate: sampleRate,
+			Audio:      u.Audio,
+		}
+	}
+	return segments, nil
+}
+
+// openAudio 识别文件格式，返回PCM数据来源及其采样率
+func openAudio(f io.ReaderAt) (io.Reader, int, error) {
+	format, err := DetectFormat(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	if format.Estimated {
+		format.SampleRate = DefaultRawSampleRate
+		format.BigEndian = false
+	}
+	return format.NewReader(f), format.SampleRate, nil
+}
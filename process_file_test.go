@@ -0,0 +1,58 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// TestProcessFileFormats 测试WAV与原始PCM文件的检测
+func TestProcessFileFormats(t *testing.T) {
+	// 0.5s静音 + 1s正弦波 + 1s静音 @16kHz
+	var pcm []byte
+	pcm = append(pcm, make([]byte, 16000)...)
+	for i := 0; i < 100; i++ {
+		pcm = append(pcm, sineFrame(440, 8000, 16000, 160)...)
+	}
+	pcm = append(pcm, make([]byte, 32000)...)
+
+	dir := t.TempDir()
+	var wav bytes.Buffer
+	if err := wave.Write(&wav, pcm, 16000); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"speech.wav": wav.Bytes(),
+		"speech.pcm": pcm,
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		segments, err := ProcessFile(path, WithMode(1))
+		if err != nil {
+			t.Fatalf("%s: 检测失败: %v", name, err)
+		}
+		if len(segments) != 1 {
+			t.Fatalf("%s: 应检测到1个语音片段, 得到%+v", name, segments)
+		}
+		seg := segments[0]
+		if seg.Start > 500*time.Millisecond || seg.End < 1500*time.Millisecond || !seg.IsSpeech {
+			t.Errorf("%s: 片段应覆盖0.5s-1.5s的语音, 得到%v-%v", name, seg.Start, seg.End)
+		}
+		if seg.SampleRate != 16000 || len(seg.Audio) != seg.SampleRate*2*int(seg.End-seg.Start)/int(time.Second) {
+			t.Errorf("%s: 片段音频错误: 采样率%d, %d字节", name, seg.SampleRate, len(seg.Audio))
+		}
+	}
+
+	if _, err := ProcessFile(filepath.Join(dir, "missing.wav")); !os.IsNotExist(err) {
+		t.Errorf("不存在的文件应返回不存在错误, 得到%v", err)
+	}
+}
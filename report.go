@@ -0,0 +1,119 @@
+package webrtcvad
+
+import (
+	"slices"
+	"time"
+)
+
+// report.go 根据片段生成说话时间报告与时长分布（会议分析、数据集质检等场景常用的汇总）
+
+// SpeechReport 说话时间报告
+type SpeechReport struct {
+	// Duration 报告覆盖的时长：从流开始（0）到最后一个片段的结束
+	Duration time.Duration
+	// Speech 语音总时长
+	Speech time.Duration
+	// Silence 非语音总时长（Duration - Speech），包括首个语音片段之前的部分
+	Silence time.Duration
+	// Utterances 语音片段数
+	Utterances int
+	// LongestUtterance 最长语音片段的时长
+	LongestUtterance time.Duration
+	// AveragePause 相邻语音片段之间停顿的平均时长，少于2个语音片段时为0
+	AveragePause time.Duration
+	// Timeline 每分钟的语音占比（0-1），第i项对应[i, i+1)分钟；
+	// 最后一项只按Duration覆盖的部分计算
+	Timeline []float64
+}
+
+// Report 根据片段生成说话时间报告
+//
+// 静音片段只用于确定Duration（末尾的静音计入Silence），其余各项只由语音片段决定，
+// 因此GetSegments与FilterSpeechSegments的结果仅在Duration、Silence与时间线长度上不同。
+// 片段的时间应相对于流开始。如需按话语统计，可先用MergeSegments合并短停顿
+//
+// 参数:
+//   - segs: 按时间排列、互不重叠的片段
+//
+// 返回:
+//   - SpeechReport: 报告，segs为空时各项为零值
+func Report(segs []VoiceSegment) SpeechReport {
+	var r SpeechReport
+	if len(segs) == 0 {
+		return r
+	}
+	r.Duration = segs[len(segs)-1].End
+
+	var (
+		pauses  time.Duration
+		lastEnd time.Duration
+		speech  []float64 // 每分钟的语音时长（纳秒）
+	)
+	if r.Duration > 0 {
+		speech = make([]float64, (r.Duration+time.Minute-1)/time.Minute)
+	}
+	for _, seg := range segs {
+		if !seg.IsSpeech || seg.End <= seg.Start {
+			continue
+		}
+		d := seg.End - seg.Start
+		r.Speech += d
+		if d > r.LongestUtterance {
+			r.LongestUtterance = d
+		}
+		if r.Utterances > 0 {
+			pauses += seg.Start - lastEnd
+		}
+		r.Utterances++
+		lastEnd = seg.End
+
+		// 将片段按分钟边界拆分计入时间线
+		for start := seg.Start; start < seg.End; {
+			minute := start / time.Minute
+			end := (minute + 1) * time.Minute
+			if end > seg.End {
+				end = seg.End
+			}
+			speech[minute] += float64(end - start)
+			start = end
+		}
+	}
+	r.Silence = r.Duration - r.Speech
+	if r.Utterances > 1 {
+		r.AveragePause = pauses / time.Duration(r.Utterances-1)
+	}
+
+	r.Timeline = make([]float64, len(speech))
+	for i := range speech {
+		span := r.Duration - time.Duration(i)*time.Minute
+		if span > time.Minute {
+			span = time.Minute
+		}
+		r.Timeline[i] = speech[i] / float64(span)
+	}
+	return r
+}
+
+// DurationHistogram 统计语音片段的时长分布，用于数据集质检及调整最短/最长片段选项
+//
+// 第i个计数为时长在(buckets[i-1], buckets[i]]内的语音片段数（第0个为不超过buckets[0]），
+// 最后一个额外的计数为超过所有边界的片段数。静音片段被忽略
+//
+// 参数:
+//   - segs: 片段
+//   - buckets: 升序排列的各区间上界
+//
+// 返回:
+//   - []int: len(buckets)+1个计数
+func DurationHistogram(segs []VoiceSegment, buckets []time.Duration) []int {
+	counts := make([]int, len(buckets)+1)
+	for _, seg := range segs {
+		if !seg.IsSpeech {
+			continue
+		}
+		d := seg.End - seg.Start
+		i, _ := slices.BinarySearch(buckets, d)
+		counts[i]++
+	}
+	return counts
+}
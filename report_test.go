@@ -0,0 +1,79 @@
+package webrtcvad
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestReport 测试说话时间报告
+func TestReport(t *testing.T) {
+	s := time.Second
+	segs := []VoiceSegment{
+		{Start: 0, End: 10 * s},
+		{Start: 10 * s, End: 40 * s, IsSpeech: true},
+		{Start: 40 * s, End: 50 * s},
+		{Start: 50 * s, End: 70 * s, IsSpeech: true}, // 跨越第1分钟边界
+		{Start: 70 * s, End: 100 * s},
+		{Start: 100 * s, End: 105 * s, IsSpeech: true},
+		{Start: 105 * s, End: 150 * s},
+	}
+
+	r := Report(segs)
+	if r.Duration != 150*s || r.Speech != 55*s || r.Silence != 95*s {
+		t.Errorf("时长错误: 总%v 语音%v 静音%v", r.Duration, r.Speech, r.Silence)
+	}
+	if r.Utterances != 3 || r.LongestUtterance != 30*s {
+		t.Errorf("片段统计错误: %d个, 最长%v", r.Utterances, r.LongestUtterance)
+	}
+	if r.AveragePause != 20*s { // (10s + 30s) / 2
+		t.Errorf("平均停顿应为20s, 得到%v", r.AveragePause)
+	}
+
+	// 第0分钟40s语音，第1分钟15s，第2分钟（30s）无语音
+	want := []float64{40.0 / 60, 15.0 / 60, 0}
+	if len(r.Timeline) != len(want) {
+		t.Fatalf("时间线应有%d项, 得到%v", len(want), r.Timeline)
+	}
+	for i := range want {
+		if math.Abs(r.Timeline[i]-want[i]) > 1e-9 {
+			t.Errorf("第%d分钟语音占比应为%.3f, 得到%.3f", i, want[i], r.Timeline[i])
+		}
+	}
+
+	// 只含语音片段时结果相同
+	var speech []VoiceSegment
+	for _, seg := range segs {
+		if seg.IsSpeech {
+			speech = append(speech, seg)
+		}
+	}
+	if got := Report(speech); got.Speech != r.Speech || got.AveragePause != r.AveragePause || got.Duration != 105*s {
+		t.Errorf("只含语音片段的报告错误: %+v", got)
+	}
+
+	if got := Report(nil); got.Duration != 0 || got.Timeline != nil {
+		t.Errorf("空输入应返回零值, 得到%+v", got)
+	}
+}
+
+// TestDurationHistogram 测试片段时长分布
+func TestDurationHistogram(t *testing.T) {
+	ms := time.Millisecond
+	segs := []VoiceSegment{
+		{Start: 0, End: 100 * ms, IsSpeech: true},           // 100ms，恰在边界上
+		{Start: 100 * ms, End: 5000 * ms},                   // 静音，忽略
+		{Start: 5000 * ms, End: 5150 * ms, IsSpeech: true},  // 150ms
+		{Start: 6000 * ms, End: 7000 * ms, IsSpeech: true},  // 1s
+		{Start: 8000 * ms, End: 20000 * ms, IsSpeech: true}, // 12s，超出所有边界
+	}
+
+	got := DurationHistogram(segs, []time.Duration{100 * ms, 500 * ms, 10 * time.Second})
+	if want := []int{1, 1, 1, 1}; !slices.Equal(got, want) {
+		t.Errorf("期望%v, 得到%v", want, got)
+	}
+	if got := DurationHistogram(segs, nil); !slices.Equal(got, []int{4}) {
+		t.Errorf("无边界时应全部计入溢出区间, 得到%v", got)
+	}
+}
@@ -319,3 +319,44 @@ func resample48khzTo8khzFull(in []int16, out []int16, state *state48khzTo8khzFul
 	// 输出: 80个int16样本
 	downBy2IntToShort(tmpMem[:160], 160, out, state.S_16_8[:])
 }
+
+// state24khzTo8khz 24kHz到8kHz重采样状态
+//
+// 复用48kHz->8kHz流水线的后三级，省去48->24的降采样
+type state24khzTo8khz struct {
+	S_24_24 [16]int32 // 24->24(LP)状态
+	S_24_16 [8]int32  // 24->16状态
+	S_16_8  [8]int32  // 16->8状态
+}
+
+// resetResample24khzTo8khz 重置24kHz到8kHz重采样状态
+func resetResample24khzTo8khz(state *state24khzTo8khz) {
+	clear(state.S_24_24[:])
+	clear(state.S_24_16[:])
+	clear(state.S_16_8[:])
+}
+
+// resample24khzTo8khz 将24kHz音频重采样到8kHz
+//
+// 参数:
+//   - in: 输入样本（240样本 @ 24kHz，10ms）
+//   - out: 输出样本（80样本 @ 8kHz，10ms）
+//   - state: 重采样状态
+//   - tmpMem: 临时内存（至少512个int32）
+func resample24khzTo8khz(in []int16, out []int16, state *state24khzTo8khz, tmpMem []int32) {
+	// 阶段0: int16 -> int32（移位15位+偏移16384，与downBy2ShortToInt的输出格式一致）
+	for i := 0; i < 240; i++ {
+		tmpMem[256+i] = (int32(in[i]) << 15) + (1 << 14)
+	}
+
+	// 阶段1: 24kHz -> 24kHz (低通滤波)
+	lpBy2IntToInt(tmpMem[256:256+240], 240, tmpMem[16:], state.S_24_24[:])
+
+	// 阶段2: 24kHz -> 16kHz (分数重采样 2/3)
+	copy(tmpMem[8:16], state.S_24_16[:8])
+	copy(state.S_24_16[:8], tmpMem[248:256])
+	resample48khzTo32khz(tmpMem[8:], tmpMem[:], 80)
+
+	// 阶段3: 16kHz -> 8kHz (2倍降采样)
+	downBy2IntToShort(tmpMem[:160], 160, out, state.S_16_8[:])
+}
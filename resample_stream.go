@@ -0,0 +1,282 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// resample_stream.go 将重采样器包装为io.Writer / io.Reader，处理不完整的块并跨调用保存滤波器状态，
+// 例如把48kHz的采集流接入16kHz的StreamVAD：
+//
+//	rw, _ := NewResampleWriter(svad, 48000, 16000)
+//	io.Copy(rw, capture)
+
+// Resampler 有状态的块重采样器，Upsampler与Downsampler实现此接口
+type Resampler interface {
+	// InRate 返回输入采样率
+	InRate() int
+	// OutRate 返回输出采样率
+	OutRate() int
+	// BlockSize 返回每次输入样本数须为其整数倍的块长度
+	BlockSize() int
+	// ProcessTo 重采样in写入out，返回写入的样本数
+	ProcessTo(in []int16, out []int16) (int, error)
+	// Reset 清除滤波器状态
+	Reset()
+}
+
+// NewResampler 按采样率组合创建升采样器或降采样器
+//
+// 参数:
+//   - inRate: 输入采样率
+//   - outRate: 输出采样率，与inRate相同时返回直通的重采样器
+//
+// 返回:
+//   - Resampler: 重采样器实例
+//   - error: 不支持的组合返回ErrUnsupportedConversion
+func NewResampler(inRate, outRate int) (Resampler, error) {
+	switch {
+	case inRate == outRate && inRate > 0:
+		return passthrough(inRate), nil
+	case inRate < outRate:
+		u, err := NewUpsampler(inRate, outRate)
+		if err != nil {
+			return nil, err
+		}
+		return u, nil
+	default:
+		d, err := NewDownsampler(inRate, outRate)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+}
+
+// passthrough 输入输出采样率相同的重采样器，原样复制
+type passthrough int
+
+func (p passthrough) InRate() int    { return int(p) }
+func (p passthrough) OutRate() int   { return int(p) }
+func (p passthrough) BlockSize() int { return 1 }
+func (p passthrough) Reset()         {}
+
+func (p passthrough) ProcessTo(in []int16, out []int16) (int, error) {
+	if len(out) < len(in) {
+		return 0, ErrBufferTooSmall
+	}
+	return copy(out, in), nil
+}
+
+// resampleStream 流式重采样核心：把字节流凑成整块交给重采样器
+type resampleStream struct {
+	r     Resampler
+	block int     // 块长度（字节）
+	in    []byte  // 未凑满一块的输入
+	out   []byte  // 已生成、尚未交付的输出
+	src   []int16 // 输入样本缓冲区
+	dst   []int16 // 输出样本缓冲区
+}
+
+// newResampleStream 创建流式重采样核心
+func newResampleStream(inRate, outRate int) (*resampleStream, error) {
+	r, err := NewResampler(inRate, outRate)
+	if err != nil {
+		return nil, err
+	}
+	return &resampleStream{r: r, block: r.BlockSize() * 2}, nil
+}
+
+// write 接收16位小端序PCM，处理所有完整的块
+func (s *resampleStream) write(p []byte) error {
+	s.in = append(s.in, p...)
+	n := len(s.in) / s.block * s.block
+	if n == 0 {
+		return nil
+	}
+	if err := s.process(s.in[:n]); err != nil {
+		return err
+	}
+	s.in = s.in[:copy(s.in, s.in[n:])]
+	return nil
+}
+
+// flush 补零处理残余的不完整块，输出只保留与实际输入对应的长度
+func (s *resampleStream) flush() error {
+	samples := len(s.in) / 2 // 奇数的末尾字节无法组成样本，丢弃
+	if samples == 0 {
+		s.in = s.in[:0]
+		return nil
+	}
+	start := len(s.out)
+	padded := make([]byte, s.block)
+	copy(padded, s.in[:samples*2])
+	if err := s.process(padded); err != nil {
+		return err
+	}
+	keep := samples * s.r.OutRate() / s.r.InRate()
+	s.out = s.out[:start+keep*2]
+	s.in = s.in[:0]
+	return nil
+}
+
+// reset 丢弃残余数据并清除滤波器状态
+func (s *resampleStream) reset() {
+	s.in = s.in[:0]
+	s.out = s.out[:0]
+	s.r.Reset()
+}
+
+// process 重采样整块的输入并追加到out
+func (s *resampleStream) process(p []byte) error {
+	n := len(p) / 2
+	if cap(s.src) < n {
+		s.src = make([]int16, n)
+	}
+	src := s.src[:n]
+	for i := range src {
+		src[i] = int16(binary.LittleEndian.Uint16(p[i*2:]))
+	}
+	m := n * s.r.OutRate() / s.r.InRate()
+	if cap(s.dst) < m {
+		s.dst = make([]int16, m)
+	}
+	written, err := s.r.ProcessTo(src, s.dst[:m])
+	if err != nil {
+		return fmt.Errorf("resample: %w", err)
+	}
+	for _, v := range s.dst[:written] {
+		s.out = binary.LittleEndian.AppendUint16(s.out, uint16(v))
+	}
+	return nil
+}
+
+// ResampleWriter 重采样写入端
+//
+// 写入inRate的16位小端序PCM，重采样为outRate后写入下游w。
+// 不完整的块保留到下次写入，滤波器状态跨调用保存
+type ResampleWriter struct {
+	w io.Writer
+	s *resampleStream
+}
+
+// NewResampleWriter 创建重采样写入端
+//
+// 参数:
+//   - w: 重采样结果的下游（如StreamVAD）
+//   - inRate: 写入数据的采样率
+//   - outRate: 下游的采样率
+//
+// 返回:
+//   - *ResampleWriter: 写入端实例
+//   - error: 不支持的组合返回ErrUnsupportedConversion
+func NewResampleWriter(w io.Writer, inRate, outRate int) (*ResampleWriter, error) {
+	s, err := newResampleStream(inRate, outRate)
+	if err != nil {
+		return nil, err
+	}
+	return &ResampleWriter{w: w, s: s}, nil
+}
+
+// Write 写入PCM数据，实现io.Writer
+//
+// 返回值表示接受的输入字节数。下游写入失败时输入已被接受（返回len(p)与错误），
+// 未交付的输出保留到下次Write或Close时重新写入下游，重试时不应再次写入p
+func (rw *ResampleWriter) Write(p []byte) (int, error) {
+	if err := rw.s.write(p); err != nil {
+		return 0, err
+	}
+	if err := rw.drain(); err != nil {
+		return len(p), err
+	}
+	return len(p), nil
+}
+
+// Close 补零处理残余的不完整块并写入下游，实现io.Closer
+//
+// 不会关闭下游w
+func (rw *ResampleWriter) Close() error {
+	if err := rw.s.flush(); err != nil {
+		return err
+	}
+	return rw.drain()
+}
+
+// Reset 丢弃残余数据并清除滤波器状态，用于切换到新的音频流
+func (rw *ResampleWriter) Reset() {
+	rw.s.reset()
+}
+
+// drain 将已生成的输出写入下游，未写入的部分保留在out中
+func (rw *ResampleWriter) drain() error {
+	if len(rw.s.out) == 0 {
+		return nil
+	}
+	n, err := rw.w.Write(rw.s.out)
+	if n < 0 || n > len(rw.s.out) {
+		n = 0
+	}
+	rw.s.out = rw.s.out[:copy(rw.s.out, rw.s.out[n:])]
+	if err == nil && len(rw.s.out) > 0 {
+		err = io.ErrShortWrite
+	}
+	return err
+}
+
+// ResampleReader 重采样读取端
+//
+// 从上游r读取inRate的16位小端序PCM，Read返回重采样为outRate的结果
+type ResampleReader struct {
+	r   io.Reader
+	s   *resampleStream
+	buf []byte
+	err error // 上游返回的错误（含io.EOF）
+}
+
+// NewResampleReader 创建重采样读取端
+//
+// 参数:
+//   - r: PCM数据来源
+//   - inRate: 来源的采样率
+//   - outRate: 读出数据的采样率
+//
+// 返回:
+//   - *ResampleReader: 读取端实例
+//   - error: 不支持的组合返回ErrUnsupportedConversion
+func NewResampleReader(r io.Reader, inRate, outRate int) (*ResampleReader, error) {
+	s, err := newResampleStream(inRate, outRate)
+	if err != nil {
+		return nil, err
+	}
+	// 每次读取约10ms且为整块
+	size := max(inRate/100*2/s.block, 1) * s.block
+	return &ResampleReader{r: r, s: s, buf: make([]byte, size)}, nil
+}
+
+// Read 读取重采样后的PCM数据，实现io.Reader
+//
+// 上游读到EOF时残余的不完整块补零处理，全部输出读完后返回io.EOF
+func (rr *ResampleReader) Read(p []byte) (int, error) {
+	for len(rr.s.out) == 0 && rr.err == nil {
+		n, err := rr.r.Read(rr.buf)
+		if werr := rr.s.write(rr.buf[:n]); werr != nil {
+			return 0, werr
+		}
+		if err != nil {
+			rr.err = err
+			if err == io.EOF {
+				if ferr := rr.s.flush(); ferr != nil {
+					return 0, ferr
+				}
+			}
+		}
+	}
+
+	if len(rr.s.out) == 0 {
+		return 0, rr.err
+	}
+	n := copy(p, rr.s.out)
+	rr.s.out = rr.s.out[:copy(rr.s.out, rr.s.out[n:])]
+	return n, nil
+}
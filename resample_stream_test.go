@@ -0,0 +1,159 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+)
+
+// toPCM 将样本编码为16位小端序PCM
+func toPCM(samples []int16) []byte {
+	pcm := make([]byte, 0, len(samples)*2)
+	for _, v := range samples {
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(v))
+	}
+	return pcm
+}
+
+// TestResampleWriter 测试任意长度的写入与一次性重采样结果一致，Close补齐不完整的块
+func TestResampleWriter(t *testing.T) {
+	for _, c := range []struct{ in, out int }{{48000, 16000}, {8000, 48000}, {16000, 16000}} {
+		samples := make([]int16, c.in/10+5) // 100ms外加不足一块的5个样本
+		for i := range samples {
+			samples[i] = int16(6000 * math.Sin(float64(i)*0.05))
+		}
+		pcm := toPCM(samples)
+
+		r, _ := NewResampler(c.in, c.out)
+		block := r.BlockSize()
+		full := len(samples) / block * block
+		want := make([]int16, full*c.out/c.in)
+		r.ProcessTo(samples[:full], want)
+
+		var buf bytes.Buffer
+		rw, err := NewResampleWriter(&buf, c.in, c.out)
+		if err != nil {
+			t.Fatalf("%d->%d: 创建写入端失败: %v", c.in, c.out, err)
+		}
+		// 以奇数字节数分块写入，跨越样本与块的边界
+		for i := 0; i < len(pcm); i += 77 {
+			if n, err := rw.Write(pcm[i:min(i+77, len(pcm))]); err != nil || n != min(77, len(pcm)-i) {
+				t.Fatalf("%d->%d: 写入失败: %d, %v", c.in, c.out, n, err)
+			}
+		}
+		if got := buf.Bytes(); !bytes.Equal(got, toPCM(want)) {
+			t.Fatalf("%d->%d: 完整块的输出与一次性重采样不一致（%d / %d字节）", c.in, c.out, len(got), len(want)*2)
+		}
+
+		if err := rw.Close(); err != nil {
+			t.Fatalf("%d->%d: Close失败: %v", c.in, c.out, err)
+		}
+		if want := len(samples) * c.out / c.in * 2; buf.Len() != want {
+			t.Errorf("%d->%d: Close后输出应为%d字节, 得到%d", c.in, c.out, want, buf.Len())
+		}
+	}
+
+	if _, err := NewResampleWriter(io.Discard, 44100, 16000); !errors.Is(err, ErrUnsupportedConversion) {
+		t.Errorf("应返回ErrUnsupportedConversion, 得到%v", err)
+	}
+}
+
+// flakyWriter 第一次写入只接受一半数据并返回错误，之后正常写入
+type flakyWriter struct {
+	bytes.Buffer
+	failed bool
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if !w.failed {
+		w.failed = true
+		n, _ := w.Buffer.Write(p[:len(p)/2])
+		return n, errors.New("downstream unavailable")
+	}
+	return w.Buffer.Write(p)
+}
+
+// TestResampleWriterDownstreamError 测试下游写入失败时输入仍被接受，未交付的输出在下次写入时补发
+func TestResampleWriterDownstreamError(t *testing.T) {
+	samples := make([]int16, 4800)
+	for i := range samples {
+		samples[i] = int16(6000 * math.Sin(float64(i)*0.05))
+	}
+	pcm := toPCM(samples)
+
+	var want bytes.Buffer
+	ref, _ := NewResampleWriter(&want, 48000, 16000)
+	ref.Write(pcm)
+
+	var got flakyWriter
+	rw, _ := NewResampleWriter(&got, 48000, 16000)
+	half := len(pcm) / 2
+	if n, err := rw.Write(pcm[:half]); err == nil || n != half {
+		t.Fatalf("下游失败时应返回%d与错误, 得到%d, %v", half, n, err)
+	}
+	if n, err := rw.Write(pcm[half:]); err != nil || n != len(pcm)-half {
+		t.Fatalf("写入失败: %d, %v", n, err)
+	}
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Errorf("补发后的输出应与正常写入一致（%d / %d字节）", got.Len(), want.Len())
+	}
+}
+
+// TestResampleReader 测试读取端与写入端输出一致
+func TestResampleReader(t *testing.T) {
+	samples := make([]int16, 4803)
+	for i := range samples {
+		samples[i] = int16(6000 * math.Sin(float64(i)*0.02))
+	}
+	pcm := toPCM(samples)
+
+	var want bytes.Buffer
+	rw, _ := NewResampleWriter(&want, 48000, 8000)
+	rw.Write(pcm)
+	rw.Close()
+
+	rr, err := NewResampleReader(bytes.NewReader(pcm), 48000, 8000)
+	if err != nil {
+		t.Fatalf("创建读取端失败: %v", err)
+	}
+	got, err := io.ReadAll(rr)
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("读取端输出（%d字节）应与写入端（%d字节）一致", len(got), want.Len())
+	}
+}
+
+// TestResampleWriterStreamVAD 测试将48kHz的采集流接入16kHz的StreamVAD
+func TestResampleWriterStreamVAD(t *testing.T) {
+	pcm := harmonicNoise(48000, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+	svad, err := NewStreamVADWithOptions(WithStreamMode(3), WithAlgorithm(Entropy))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	rw, _ := NewResampleWriter(svad, 48000, 16000)
+	if _, err := io.Copy(rw, bytes.NewReader(pcm)); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	rw.Close()
+	svad.Flush()
+
+	if d := svad.GetTotalDuration(); d != 3*time.Second {
+		t.Errorf("总时长应为3s, 得到%v", d)
+	}
+	speech := svad.FilterSpeechSegments()
+	if len(speech) == 0 {
+		t.Fatal("应检测到2s-2.5s间的语音")
+	}
+	for _, seg := range speech {
+		if seg.Start < 1900*time.Millisecond || seg.End > 2700*time.Millisecond {
+			t.Errorf("只应在2s-2.5s间检测到语音, 得到%v-%v", seg.Start, seg.End)
+		}
+	}
+}
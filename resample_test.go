@@ -45,6 +45,39 @@ func TestResample48khzTo8khzFull(t *testing.T) {
 	t.Logf("重采样成功: %d个非零样本/%d", nonZeroCount, len(output))
 }
 
+// TestResample24khzTo8khz 测试24kHz到8kHz重采样
+func TestResample24khzTo8khz(t *testing.T) {
+	// 创建测试输入（240样本 @ 24kHz = 10ms）
+	input := make([]int16, 240)
+	for i := range input {
+		input[i] = int16(10000.0 * fastSin(float64(i)*2*3.14159*1000/24000))
+	}
+
+	output := make([]int16, 80)
+	var state state24khzTo8khz
+	tmpMem := make([]int32, 512)
+
+	resample24khzTo8khz(input, output, &state, tmpMem)
+
+	nonZeroCount := 0
+	for _, v := range output {
+		if v != 0 {
+			nonZeroCount++
+		}
+	}
+	if nonZeroCount == 0 {
+		t.Error("输出全为零，重采样失败")
+	}
+
+	// 重置后状态应清零
+	resetResample24khzTo8khz(&state)
+	for _, v := range state.S_24_24 {
+		if v != 0 {
+			t.Fatal("重置后状态应为零")
+		}
+	}
+}
+
 // fastSin 快速正弦近似
 func fastSin(x float64) float64 {
 	// 简单的正弦近似
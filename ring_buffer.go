@@ -0,0 +1,114 @@
+package webrtcvad
+
+// ring_buffer.go 提供StreamVAD输入的定长环形缓冲区与溢出策略，
+// 无论单次写入多大，输入缓冲占用的内存都不超过固定容量
+
+// OverflowPolicy 写入的数据超出输入缓冲区容量时的处理方式
+//
+// 写入是同步处理的，每次写入返回后缓冲中只剩不足一帧的残余数据，
+// 因此容量实际上是单次写入的上限：策略只在一次写入的数据（加上残余）超出容量时生效。
+// 默认容量为1秒音频，8kHz时为16000字节，小于io.Copy每次写入的32KB；
+// 配合io.Copy使用OverflowDropOldest或OverflowError时，需用WithBufferCapacity把容量调到32KB加一帧以上
+type OverflowPolicy int
+
+const (
+	// OverflowBlock 分块处理全部数据，写入在处理完成后返回（默认）
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest 丢弃最早的数据，只处理最新的不超过容量的部分。
+	// 被丢弃的音频不参与检测，但流时间照常推进，时间戳与实时保持对齐；
+	// 该时段并入相邻的片段（状态不变时）或留作片段之间的空隙。适合处理积压后需要跟上实时的场景
+	OverflowDropOldest
+	// OverflowError 拒绝写入并返回ErrBufferOverflow，不处理其中任何数据
+	OverflowError
+)
+
+// defaultBufferSeconds 输入缓冲区的默认容量（秒）
+const defaultBufferSeconds = 1
+
+// WithOverflowPolicy 设置单次写入的数据超出输入缓冲区容量（默认1秒音频）时的处理方式
+func WithOverflowPolicy(policy OverflowPolicy) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if policy < OverflowBlock || policy > OverflowError {
+			return ErrInvalidOverflowPolicy
+		}
+		cfg.overflow = policy
+		return nil
+	}
+}
+
+// WithBufferCapacity 设置输入缓冲区的容量（字节），默认1秒音频
+//
+// 容量按样本向下对齐，且不能小于一帧；单次超出容量的写入按WithOverflowPolicy处理。
+// 高采样率的大块写入可调大以减少分块次数，内存受限的场景可调小到一帧，
+// 输入缓冲在创建时一次分配，之后不再增长
+func WithBufferCapacity(bytes int) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		if bytes < 2 { // 不足一个样本，对齐后为0
+			return ErrBufferTooSmall
+		}
+		cfg.bufferCapacity = bytes &^ 1
+		return nil
+	}
+}
+
+// ringBuffer 定长字节环形缓冲区
+type ringBuffer struct {
+	data  []byte
+	start int // 最早数据的位置
+	n     int // 数据字节数
+}
+
+// newRingBuffer 创建容量为capacity字节的环形缓冲区
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{data: make([]byte, capacity)}
+}
+
+// size 返回缓冲的字节数
+func (r *ringBuffer) size() int {
+	return r.n
+}
+
+// capacity 返回容量
+func (r *ringBuffer) capacity() int {
+	return len(r.data)
+}
+
+// write 写入p中不超过剩余空间的部分，返回写入的字节数
+func (r *ringBuffer) write(p []byte) int {
+	written := 0
+	for written < len(p) && r.n < len(r.data) {
+		end := (r.start + r.n) % len(r.data)
+		chunk := len(r.data) - end
+		if r.start > end || (r.start == end && r.n > 0) {
+			chunk = r.start - end
+		}
+		c := copy(r.data[end:end+chunk], p[written:])
+		written += c
+		r.n += c
+	}
+	return written
+}
+
+// read 取出最早的len(dst)字节（不超过缓冲的字节数）复制到dst，返回复制的字节数
+func (r *ringBuffer) read(dst []byte) int {
+	n := min(len(dst), r.n)
+	c := copy(dst[:n], r.data[r.start:])
+	copy(dst[c:n], r.data)
+	r.discard(n)
+	return n
+}
+
+// discard 丢弃最早的n字节（不超过缓冲的字节数）
+func (r *ringBuffer) discard(n int) {
+	n = min(n, r.n)
+	r.start = (r.start + n) % len(r.data)
+	r.n -= n
+	if r.n == 0 {
+		r.start = 0
+	}
+}
+
+// reset 清空缓冲区
+func (r *ringBuffer) reset() {
+	r.start, r.n = 0, 0
+}
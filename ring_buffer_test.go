@@ -0,0 +1,121 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestRingBuffer 测试环形缓冲区的写入、读取与回绕
+func TestRingBuffer(t *testing.T) {
+	r := newRingBuffer(8)
+	if n := r.write([]byte{1, 2, 3, 4, 5, 6}); n != 6 {
+		t.Fatalf("应写入6字节, 得到%d", n)
+	}
+	got := make([]byte, 4)
+	if n := r.read(got); n != 4 || !bytes.Equal(got, []byte{1, 2, 3, 4}) {
+		t.Fatalf("读取错误: %d字节 %v", n, got)
+	}
+
+	// 跨越末尾回绕，超出容量的部分不写入
+	if n := r.write([]byte{7, 8, 9, 10, 11, 12, 13}); n != 6 || r.size() != 8 {
+		t.Fatalf("应写入6字节至满, 得到%d（缓冲%d）", n, r.size())
+	}
+	got = make([]byte, 10)
+	if n := r.read(got); n != 8 || !bytes.Equal(got[:n], []byte{5, 6, 7, 8, 9, 10, 11, 12}) {
+		t.Fatalf("回绕读取错误: %d字节 %v", n, got[:n])
+	}
+
+	r.write([]byte{1, 2, 3})
+	r.discard(2)
+	if r.size() != 1 {
+		t.Errorf("丢弃后应剩1字节, 得到%d", r.size())
+	}
+	r.reset()
+	if r.size() != 0 || r.write(make([]byte, 20)) != 8 {
+		t.Error("重置后应可写满容量")
+	}
+}
+
+// TestOverflowPolicy 测试输入缓冲区的溢出策略
+func TestOverflowPolicy(t *testing.T) {
+	pcm := harmonicNoise(16000, 3*time.Second, time.Second, 2*time.Second) // 超过1秒的默认容量
+
+	// 默认分块处理全部数据，结果与逐帧写入相同
+	whole, _ := NewStreamVADWithOptions()
+	if _, err := whole.Process(pcm); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	chunked, _ := NewStreamVADWithOptions()
+	for i := 0; i < len(pcm); i += 640 {
+		chunked.Process(pcm[i : i+640])
+	}
+	if whole.Stats() != chunked.Stats() || len(whole.GetSegments()) != len(chunked.GetSegments()) {
+		t.Errorf("一次写入与逐帧写入结果应相同: %+v vs %+v", whole.Stats(), chunked.Stats())
+	}
+	if whole.GetBufferSize() != 0 || whole.input.capacity() != 32000 {
+		t.Errorf("缓冲区应为空且容量为1秒, 得到%d/%d", whole.GetBufferSize(), whole.input.capacity())
+	}
+
+	// 丢弃最早的数据：只检测最后1秒，时间照常推进
+	drop, _ := NewStreamVADWithOptions(WithOverflowPolicy(OverflowDropOldest))
+	drop.Process(pcm[:100]) // 缓冲中未凑满一帧的数据一并丢弃
+	if _, err := drop.Process(pcm); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if st := drop.Stats(); st.Frames != 50 {
+		t.Errorf("应只检测最后1秒的50帧, 得到%d", st.Frames)
+	}
+	if got := drop.GetTotalProcessed(); got != int64(len(pcm)+100) {
+		t.Errorf("流时间应包含丢弃的数据: 期望%d字节, 得到%d", len(pcm)+100, got)
+	}
+
+	// 拒绝写入
+	strict, _ := NewStreamVADWithOptions(WithOverflowPolicy(OverflowError))
+	if _, err := strict.Process(pcm); err != ErrBufferOverflow {
+		t.Errorf("超出容量应返回ErrBufferOverflow, 得到%v", err)
+	}
+	if strict.GetTotalProcessed() != 0 || strict.GetBufferSize() != 0 {
+		t.Error("被拒绝的写入不应处理任何数据")
+	}
+	if _, err := strict.Process(pcm[:32000]); err != nil {
+		t.Errorf("不超过容量的写入应成功, 得到%v", err)
+	}
+
+	if _, err := NewStreamVADWithOptions(WithOverflowPolicy(OverflowPolicy(5))); err != ErrInvalidOverflowPolicy {
+		t.Errorf("无效策略应返回ErrInvalidOverflowPolicy, 得到%v", err)
+	}
+}
+
+// TestOverflowPolicyPerWrite 测试容量是单次写入的上限：io.Copy的32KB写入在8kHz默认容量下触发策略
+func TestOverflowPolicyPerWrite(t *testing.T) {
+	pcm := harmonicNoise(8000, 5*time.Second, time.Second, 4*time.Second)
+	src := func() io.Reader { return struct{ io.Reader }{bytes.NewReader(pcm)} } // 隐藏WriterTo，按32KB分块写入
+
+	strict, _ := NewStreamVADWithOptions(WithSampleRate(8000), WithOverflowPolicy(OverflowError))
+	if _, err := io.Copy(strict, src()); err != ErrBufferOverflow {
+		t.Errorf("32KB写入超出16000字节的默认容量, 应返回ErrBufferOverflow, 得到%v", err)
+	}
+
+	drop, _ := NewStreamVADWithOptions(WithSampleRate(8000), WithOverflowPolicy(OverflowDropOldest))
+	if _, err := io.Copy(drop, src()); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if st := drop.Stats(); st.Frames >= int64(len(pcm)/drop.frameSize) {
+		t.Errorf("超出容量的写入应丢弃数据, 检测了%d帧", st.Frames)
+	}
+
+	// 容量不小于单次写入加一帧残余时，策略不生效，结果与默认分块处理相同
+	for _, policy := range []OverflowPolicy{OverflowDropOldest, OverflowError} {
+		big, _ := NewStreamVADWithOptions(WithSampleRate(8000), WithOverflowPolicy(policy), WithBufferCapacity(32*1024+drop.frameSize))
+		if _, err := io.Copy(big, src()); err != nil {
+			t.Fatalf("策略%d: 写入失败: %v", policy, err)
+		}
+		block, _ := NewStreamVADWithOptions(WithSampleRate(8000))
+		io.Copy(block, src())
+		if big.Stats() != block.Stats() || big.GetTotalProcessed() != int64(len(pcm)) {
+			t.Errorf("策略%d: 结果应与分块处理相同: %+v vs %+v", policy, big.Stats(), block.Stats())
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// rtp.go 将RTP音频包解码后送入StreamVAD，便于在SIP/RTP媒体服务器中直接使用
+
+// RTP负载类型（RFC 3551）
+const (
+	// RTPPayloadPCMU G.711 μ-law，8kHz
+	RTPPayloadPCMU = 0
+	// RTPPayloadPCMA G.711 A-law，8kHz
+	RTPPayloadPCMA = 8
+	// RTPPayloadL16 16位网络字节序（大端序）线性PCM，采样率与StreamVAD一致；
+	// 通过SDP协商的动态负载类型也使用该值
+	RTPPayloadL16 = 11
+)
+
+// rtpHeaderSize RTP固定头部长度
+const rtpHeaderSize = 12
+
+// maxRTPGap 以静音填补的最大时间戳间隔，超过时视为流重新开始
+const maxRTPGap = time.Minute
+
+// RTPProcessor 将一路RTP音频流送入StreamVAD
+//
+// 按序列号丢弃重复和乱序到达的包，按RTP时间戳以静音填补丢包和静音抑制（DTX）
+// 造成的间隔，使片段时间与发送端的时间轴一致。不是并发安全的，每路流使用一个实例
+type RTPProcessor struct {
+	svad *StreamVAD
+	seq  *RTPSequencer
+}
+
+// RTPSequencer 按序列号与时间戳跟踪一路RTP流
+//
+// 判断包是否为重复或乱序到达，计算丢包与静音抑制（DTX）造成的时间戳间隔，
+// 并按序列号统计丢包。RTPProcessor与自带解码器的适配器（如webrtctrack）共用此逻辑。
+// 不是并发安全的，每路流使用一个实例
+type RTPSequencer struct {
+	rate int // RTP时间戳的时钟频率（Hz）
+
+	started bool
+	ssrc    uint32
+	seq     uint16 // 上一个包的序列号
+	nextTS  uint32 // 下一个包的预期时间戳
+	lost    int    // 按序列号统计的丢包数
+}
+
+// NewRTPSequencer 创建RTP序列跟踪器，sampleRate为RTP时间戳的时钟频率
+func NewRTPSequencer(sampleRate int) *RTPSequencer {
+	return &RTPSequencer{rate: sampleRate}
+}
+
+// Check 判断pkt是否应当处理，并返回其前面需要以静音填补的样本数
+//
+// 重复或乱序到达的包返回false：对应时间已按静音填补，也不应送入有状态的解码器。
+// 新的SSRC视为流重新开始；超过一分钟的间隔不填补。Check不修改状态，
+// 处理完成后调用Commit
+func (q *RTPSequencer) Check(pkt RTPPacket) (gap int, ok bool) {
+	if !q.started || pkt.SSRC != q.ssrc {
+		return 0, true
+	}
+	if int16(pkt.SequenceNumber-q.seq) <= 0 {
+		return 0, false
+	}
+	d := pkt.Timestamp - q.nextTS
+	if int32(d) > 0 && time.Duration(d)*time.Second/time.Duration(q.rate) <= maxRTPGap {
+		gap = int(d)
+	}
+	return gap, true
+}
+
+// Commit 记录pkt已处理，解码出samples个样本
+func (q *RTPSequencer) Commit(pkt RTPPacket, samples int) {
+	if q.started && pkt.SSRC == q.ssrc {
+		q.lost += int(int16(pkt.SequenceNumber-q.seq)) - 1
+	}
+	q.started = true
+	q.ssrc = pkt.SSRC
+	q.seq = pkt.SequenceNumber
+	q.nextTS = pkt.Timestamp + uint32(samples)
+}
+
+// PacketsLost 按序列号间隔统计的丢包数
+func (q *RTPSequencer) PacketsLost() int {
+	return q.lost
+}
+
+// NewRTPProcessor 创建RTP处理器
+//
+// 参数:
+//   - svad: 接收解码后音频的StreamVAD；PCMU/PCMA要求采样率为8000Hz
+//
+// 返回:
+//   - *RTPProcessor: RTP处理器实例
+func NewRTPProcessor(svad *StreamVAD) *RTPProcessor {
+	return &RTPProcessor{svad: svad, seq: NewRTPSequencer(svad.sampleRate)}
+}
+
+// ProcessRTPPayload 解析一个RTP包，解码负载后送入StreamVAD
+//
+// 参数:
+//   - packet: 完整的RTP包（含头部），即一个UDP数据报的内容
+//   - payloadType: 负载编码（RTPPayloadPCMU、RTPPayloadPCMA或RTPPayloadL16）；
+//     头部中的负载类型被忽略，以支持SDP协商的动态负载类型
+//
+// 返回:
+//   - []VoiceSegment: 新检测到的语音片段（同StreamVAD.Process）
+//   - error: 包格式无效、负载类型不支持或处理失败
+func (p *RTPProcessor) ProcessRTPPayload(packet []byte, payloadType int) ([]VoiceSegment, error) {
+	pkt, err := ParseRTPPacket(packet)
+	if err != nil {
+		return nil, err
+	}
+	payload := pkt.Payload
+
+	var pcm []byte
+	switch payloadType {
+	case RTPPayloadPCMU, RTPPayloadPCMA:
+		if p.svad.sampleRate != 8000 {
+			return nil, fmt.Errorf("%w: G.711 payload requires an 8000 Hz stream, got %d", ErrInvalidSampleRate, p.svad.sampleRate)
+		}
+		decode := ulawToLinear
+		if payloadType == RTPPayloadPCMA {
+			decode = alawToLinear
+		}
+		pcm = make([]byte, 0, len(payload)*2)
+		for _, b := range payload {
+			pcm = binary.LittleEndian.AppendUint16(pcm, uint16(decode(b)))
+		}
+	case RTPPayloadL16:
+		if len(payload)%2 != 0 {
+			return nil, fmt.Errorf("%w: odd L16 payload length %d", ErrInvalidRTPPacket, len(payload))
+		}
+		pcm = make([]byte, len(payload))
+		for i := 0; i < len(payload); i += 2 {
+			pcm[i], pcm[i+1] = payload[i+1], payload[i]
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedPayloadType, payloadType)
+	}
+
+	gap, ok := p.seq.Check(pkt)
+	if !ok {
+		return nil, nil
+	}
+	var segments []VoiceSegment
+	if gap > 0 {
+		if segments, err = p.fillSilence(gap); err != nil {
+			return nil, err
+		}
+	}
+	p.seq.Commit(pkt, len(pcm)/2)
+
+	newSegments, err := p.svad.Process(pcm)
+	return append(segments, newSegments...), err
+}
+
+// fillSilence 以静音填补n个样本
+func (p *RTPProcessor) fillSilence(n int) ([]VoiceSegment, error) {
+	// 分块写入，避免长间隔时一次分配大块内存
+	chunk := make([]byte, min(n, p.svad.sampleRate/10)*2)
+	var segments []VoiceSegment
+	for n > 0 {
+		size := min(n*2, len(chunk))
+		newSegments, err := p.svad.Process(chunk[:size])
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, newSegments...)
+		n -= size / 2
+	}
+	return segments, nil
+}
+
+// PacketsLost 按序列号间隔统计的丢包数
+func (p *RTPProcessor) PacketsLost() int {
+	return p.seq.PacketsLost()
+}
+
+// RTPPacket 解析后的RTP包
+type RTPPacket struct {
+	PayloadType    uint8
+	SequenceNumber uint16
+	Timestamp      uint32
+	SSRC           uint32
+	Payload        []byte // 去除CSRC、扩展头和填充后的负载，与输入共享内存
+}
+
+// ParseRTPPacket 解析RTP包（RFC 3550）
+//
+// 参数:
+//   - packet: 完整的RTP包（含头部）
+//
+// 返回:
+//   - RTPPacket: 头部字段与负载
+//   - error: 包格式无效时为ErrInvalidRTPPacket
+func ParseRTPPacket(packet []byte) (RTPPacket, error) {
+	if len(packet) < rtpHeaderSize {
+		return RTPPacket{}, fmt.Errorf("%w: %d bytes", ErrInvalidRTPPacket, len(packet))
+	}
+	if packet[0]>>6 != 2 {
+		return RTPPacket{}, fmt.Errorf("%w: version %d", ErrInvalidRTPPacket, packet[0]>>6)
+	}
+
+	offset := rtpHeaderSize + int(packet[0]&0x0F)*4
+	if packet[0]&0x10 != 0 {
+		if len(packet) < offset+4 {
+			return RTPPacket{}, fmt.Errorf("%w: truncated header extension", ErrInvalidRTPPacket)
+		}
+		offset += 4 + int(binary.BigEndian.Uint16(packet[offset+2:]))*4
+	}
+	end := len(packet)
+	if packet[0]&0x20 != 0 {
+		end -= int(packet[end-1])
+	}
+	if offset > end {
+		return RTPPacket{}, fmt.Errorf("%w: header exceeds packet length", ErrInvalidRTPPacket)
+	}
+	return RTPPacket{
+		PayloadType:    packet[1] & 0x7F,
+		SequenceNumber: binary.BigEndian.Uint16(packet[2:]),
+		Timestamp:      binary.BigEndian.Uint32(packet[4:]),
+		SSRC:           binary.BigEndian.Uint32(packet[8:]),
+		Payload:        packet[offset:end],
+	}, nil
+}
+
+// ulawToLinear G.711 μ-law解码
+func ulawToLinear(u byte) int16 {
+	u = ^u
+	t := (int32(u&0x0F)<<3 + 0x84) << ((u & 0x70) >> 4)
+	if u&0x80 != 0 {
+		return int16(0x84 - t)
+	}
+	return int16(t - 0x84)
+}
+
+// alawToLinear G.711 A-law解码
+func alawToLinear(a byte) int16 {
+	a ^= 0x55
+	t := int32(a&0x0F) << 4
+	switch seg := (a & 0x70) >> 4; seg {
+	case 0:
+		t += 8
+	case 1:
+		t += 0x108
+	default:
+		t = (t + 0x108) << (seg - 1)
+	}
+	if a&0x80 != 0 {
+		return int16(t)
+	}
+	return int16(-t)
+}
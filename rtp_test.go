@@ -0,0 +1,165 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+// rtpPacket 构造版本2、SSRC为1的RTP包
+func rtpPacket(seq uint16, ts uint32, payload []byte) []byte {
+	pkt := []byte{0x80, 0}
+	pkt = binary.BigEndian.AppendUint16(pkt, seq)
+	pkt = binary.BigEndian.AppendUint32(pkt, ts)
+	pkt = binary.BigEndian.AppendUint32(pkt, 1)
+	return append(pkt, payload...)
+}
+
+// toL16 将小端序PCM转换为网络字节序
+func toL16(pcm []byte) []byte {
+	out := make([]byte, len(pcm))
+	for i := 0; i < len(pcm); i += 2 {
+		out[i], out[i+1] = pcm[i+1], pcm[i]
+	}
+	return out
+}
+
+// TestG711Decode 测试G.711解码的典型值
+func TestG711Decode(t *testing.T) {
+	ulaw := map[byte]int16{0xFF: 0, 0x7F: 0, 0x00: -32124, 0x80: 32124, 0xEF: 132, 0x6F: -132}
+	for in, want := range ulaw {
+		if got := ulawToLinear(in); got != want {
+			t.Errorf("μ-law 0x%02X: 期望%d, 得到%d", in, want, got)
+		}
+	}
+	alaw := map[byte]int16{0xD5: 8, 0x55: -8, 0xAA: 32256, 0x2A: -32256}
+	for in, want := range alaw {
+		if got := alawToLinear(in); got != want {
+			t.Errorf("A-law 0x%02X: 期望%d, 得到%d", in, want, got)
+		}
+	}
+}
+
+// TestRTPProcessorTimeline 测试丢包、乱序、重复和静音抑制下的时间轴
+func TestRTPProcessorTimeline(t *testing.T) {
+	svad, err := NewStreamVAD(1, 16000, 10)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	p := NewRTPProcessor(svad)
+
+	// 每包20ms：前0.5s静音，后0.5s正弦波
+	silence := make([]byte, 640)
+	speech := sineFrame(440, 8000, 16000, 8000)
+	packets := make([][]byte, 50)
+	for i := range packets {
+		payload := silence
+		if i >= 25 {
+			payload = toL16(speech[(i-25)*640 : (i-24)*640])
+		}
+		// 第10-19包因静音抑制未发送：时间戳跳变，序列号连续
+		seq := 65530 + i
+		if i >= 20 {
+			seq -= 10
+		}
+		packets[i] = rtpPacket(uint16(seq), uint32(1000+i*320), payload)
+	}
+
+	// 静音段中丢失第5、6包，第3包重复，第8包乱序到达
+	order := []int{0, 1, 2, 3, 3, 4, 7, 9, 8, 20}
+	for i := 21; i < 50; i++ {
+		order = append(order, i)
+	}
+	for _, i := range order {
+		if _, err := p.ProcessRTPPayload(packets[i], RTPPayloadL16); err != nil {
+			t.Fatalf("处理第%d包失败: %v", i, err)
+		}
+	}
+	svad.Flush()
+
+	if got := svad.GetTotalDuration(); got != time.Second {
+		t.Errorf("总时长应为1s, 得到%v", got)
+	}
+	if got := p.PacketsLost(); got != 3 {
+		t.Errorf("应统计3个丢包（第5、6、8包）, 得到%d", got)
+	}
+	speechSegs := svad.FilterSpeechSegments()
+	if len(speechSegs) != 1 || speechSegs[0].Start < 500*time.Millisecond || speechSegs[0].Start > 550*time.Millisecond {
+		t.Errorf("语音应从0.5s附近开始, 得到%v", speechSegs)
+	}
+}
+
+// TestRTPProcessorG711 测试G.711负载与头部解析
+func TestRTPProcessorG711(t *testing.T) {
+	svad, err := NewStreamVAD(1, 8000, 20)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	p := NewRTPProcessor(svad)
+
+	// 带1个CSRC、1个字长的扩展头和4字节填充
+	pkt := rtpPacket(1, 0, nil)
+	pkt[0] |= 0x30 | 1
+	pkt = append(pkt, 0, 0, 0, 2) // CSRC
+	pkt = append(pkt, 0xBE, 0xDE, 0, 1, 1, 2, 3, 4)
+	pkt = append(pkt, bytes.Repeat([]byte{0xFF}, 160)...)
+	pkt = append(pkt, 0, 0, 0, 4)
+	parsed, err := ParseRTPPacket(pkt)
+	if err != nil || parsed.SequenceNumber != 1 || parsed.SSRC != 1 || len(parsed.Payload) != 160 {
+		t.Fatalf("解析RTP包错误: %+v %v", parsed, err)
+	}
+	if _, err := p.ProcessRTPPayload(pkt, RTPPayloadPCMU); err != nil {
+		t.Fatalf("处理PCMU失败: %v", err)
+	}
+	if _, err := p.ProcessRTPPayload(rtpPacket(2, 160, bytes.Repeat([]byte{0xD5}, 160)), RTPPayloadPCMA); err != nil {
+		t.Fatalf("处理PCMA失败: %v", err)
+	}
+	if got := svad.GetTotalDuration(); got != 40*time.Millisecond {
+		t.Errorf("总时长应为40ms, 得到%v", got)
+	}
+
+	// 新的SSRC重新开始时间轴，不填补间隔
+	other := rtpPacket(100, 900000, bytes.Repeat([]byte{0xFF}, 160))
+	other[11] = 2
+	if _, err := p.ProcessRTPPayload(other, RTPPayloadPCMU); err != nil {
+		t.Fatalf("处理新SSRC失败: %v", err)
+	}
+	if got := svad.GetTotalDuration(); got != 60*time.Millisecond {
+		t.Errorf("总时长应为60ms, 得到%v", got)
+	}
+}
+
+// TestRTPProcessorErrors 测试无效输入
+func TestRTPProcessorErrors(t *testing.T) {
+	svad16, _ := NewStreamVAD(1, 16000, 10)
+	p := NewRTPProcessor(svad16)
+
+	ext := rtpPacket(1, 0, nil)
+	ext[0] |= 0x10
+	padded := rtpPacket(1, 0, []byte{0, 0, 9})
+	padded[0] |= 0x20
+	v1 := rtpPacket(1, 0, make([]byte, 320))
+	v1[0] = 0x40
+
+	tests := []struct {
+		name        string
+		packet      []byte
+		payloadType int
+		want        error
+	}{
+		{"too short", make([]byte, 11), RTPPayloadL16, ErrInvalidRTPPacket},
+		{"version 1", v1, RTPPayloadL16, ErrInvalidRTPPacket},
+		{"truncated extension", ext, RTPPayloadL16, ErrInvalidRTPPacket},
+		{"bad padding", padded, RTPPayloadL16, ErrInvalidRTPPacket},
+		{"odd L16", rtpPacket(1, 0, make([]byte, 3)), RTPPayloadL16, ErrInvalidRTPPacket},
+		{"G.711 at 16kHz", rtpPacket(1, 0, make([]byte, 160)), RTPPayloadPCMU, ErrInvalidSampleRate},
+		{"unknown payload", rtpPacket(1, 0, make([]byte, 160)), 18, ErrUnsupportedPayloadType},
+	}
+	for _, tt := range tests {
+		if _, err := p.ProcessRTPPayload(tt.packet, tt.payloadType); !errors.Is(err, tt.want) {
+			t.Errorf("%s: 期望%v, 得到%v", tt.name, tt.want, err)
+		}
+	}
+}
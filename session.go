@@ -0,0 +1,210 @@
+package webrtcvad
+
+import (
+	"sync"
+	"time"
+)
+
+// session.go 提供多路会话管理：按会话ID懒创建StreamVAD、空闲过期并复用实例，
+// 即会议服务器等多路场景中围绕StreamVAD的常见样板代码
+
+// SessionConfig 会话管理器配置
+type SessionConfig struct {
+	// Options 创建各会话StreamVAD的选项。选项携带的对象（如WithFilter的滤波器、
+	// WithMetrics的Recorder）由所有会话共享，有状态的对象不应通过这里传入
+	Options []StreamVADOption
+
+	// IdleTimeout 会话超过该时长没有Feed即过期，0表示不过期。
+	// 按Options中WithClockFunc的时钟计时，未设置时使用time.Now
+	IdleTimeout time.Duration
+
+	// OnClose 会话结束（过期、Remove或Close）时调用，segs为该会话的全部片段
+	// （最后一个片段已结束）。在结束会话的goroutine中同步调用，可为nil
+	OnClose func(id string, segs []VoiceSegment)
+}
+
+// SessionManager 多路会话管理器
+//
+// 每个会话ID对应一个StreamVAD，首次Feed时创建；会话结束后实例经Reset放回池中，
+// 供之后的会话复用。所有方法都可并发调用，不同会话的Feed互不阻塞
+type SessionManager struct {
+	cfg  SessionConfig
+	pool sync.Pool
+	now  func() time.Time // 空闲过期所用的时钟（见WithClockFunc）
+
+	mu        sync.Mutex // 保护sessions与lastSweep
+	sessions  map[string]*session
+	lastSweep time.Time
+}
+
+// session 一个会话
+type session struct {
+	mu       sync.Mutex // 串行化该会话的Feed与结束
+	svad     *StreamVAD
+	lastUsed time.Time // 最近一次Feed的时间，由SessionManager.mu保护
+	done     bool      // 已结束，svad已放回池中
+}
+
+// NewSessionManager 创建会话管理器
+//
+// 参数:
+//   - cfg: 配置
+//
+// 返回:
+//   - *SessionManager: 会话管理器
+//   - error: IdleTimeout为负时返回ErrInvalidDuration，选项无效时返回选项的错误
+func NewSessionManager(cfg SessionConfig) (*SessionManager, error) {
+	if cfg.IdleTimeout < 0 {
+		return nil, ErrInvalidDuration
+	}
+
+	// 先创建一个实例校验选项，之后池中按同样的选项创建不会出错
+	svad, err := NewStreamVADWithOptions(cfg.Options...)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &SessionManager{
+		cfg:      cfg,
+		now:      time.Now,
+		sessions: make(map[string]*session),
+	}
+	if svad.clock != nil {
+		m.now = svad.clock
+	}
+	m.pool.New = func() any {
+		svad, _ := NewStreamVADWithOptions(cfg.Options...)
+		return svad
+	}
+	m.pool.Put(svad)
+	m.lastSweep = m.now()
+	return m, nil
+}
+
+// Feed 向会话写入音频，会话不存在时创建
+//
+// 设置了IdleTimeout时顺带结束已过期的其他会话。
+//
+// 参数:
+//   - id: 会话ID
+//   - pcm: 音频数据（16位PCM，小端序）
+//
+// 返回:
+//   - []VoiceSegment: 新检测到的片段（同StreamVAD.Process）
+//   - error: 错误信息
+func (m *SessionManager) Feed(id string, pcm []byte) ([]VoiceSegment, error) {
+	for {
+		sess, expired := m.acquire(id)
+		for expiredID, s := range expired {
+			m.finish(expiredID, s)
+		}
+
+		sess.mu.Lock()
+		if sess.done {
+			// 取得会话后、加锁前会话已被结束，重新创建
+			sess.mu.Unlock()
+			continue
+		}
+		segs, err := sess.svad.Process(pcm)
+		sess.mu.Unlock()
+		return segs, err
+	}
+}
+
+// acquire 取得（或创建）会话并更新使用时间，同时摘下已过期的会话
+func (m *SessionManager) acquire(id string) (*session, map[string]*session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	var expired map[string]*session
+	if m.cfg.IdleTimeout > 0 && now.Sub(m.lastSweep) >= m.cfg.IdleTimeout {
+		expired = m.expire(now)
+	}
+
+	sess, ok := m.sessions[id]
+	if !ok {
+		sess = &session{svad: m.pool.Get().(*StreamVAD)}
+		m.sessions[id] = sess
+	}
+	sess.lastUsed = now
+	return sess, expired
+}
+
+// expire 从表中摘下空闲超过IdleTimeout的会话，调用方须持有m.mu
+func (m *SessionManager) expire(now time.Time) map[string]*session {
+	m.lastSweep = now
+	expired := make(map[string]*session)
+	for id, sess := range m.sessions {
+		if now.Sub(sess.lastUsed) >= m.cfg.IdleTimeout {
+			expired[id] = sess
+			delete(m.sessions, id)
+		}
+	}
+	return expired
+}
+
+// finish 结束已从表中摘下的会话：结束最后一个片段、调用OnClose并将实例放回池中
+func (m *SessionManager) finish(id string, sess *session) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	sess.done = true
+	sess.svad.Flush()
+	if m.cfg.OnClose != nil {
+		m.cfg.OnClose(id, sess.svad.GetSegments())
+	}
+	if sess.svad.Reset() == nil {
+		m.pool.Put(sess.svad)
+	}
+	sess.svad = nil
+}
+
+// Sweep 立即结束所有空闲超过IdleTimeout的会话，返回结束的会话数
+//
+// Feed会定期完成同样的工作；长时间没有任何Feed时可由定时器调用。IdleTimeout为0时不做任何事
+func (m *SessionManager) Sweep() int {
+	if m.cfg.IdleTimeout <= 0 {
+		return 0
+	}
+	m.mu.Lock()
+	expired := m.expire(m.now())
+	m.mu.Unlock()
+
+	for id, sess := range expired {
+		m.finish(id, sess)
+	}
+	return len(expired)
+}
+
+// Remove 结束会话，返回会话是否存在
+func (m *SessionManager) Remove(id string) bool {
+	m.mu.Lock()
+	sess, ok := m.sessions[id]
+	delete(m.sessions, id)
+	m.mu.Unlock()
+
+	if ok {
+		m.finish(id, sess)
+	}
+	return ok
+}
+
+// Len 返回当前的会话数
+func (m *SessionManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// Close 结束所有会话。之后仍可继续Feed，会话将重新创建
+func (m *SessionManager) Close() {
+	m.mu.Lock()
+	sessions := m.sessions
+	m.sessions = make(map[string]*session)
+	m.mu.Unlock()
+
+	for id, sess := range sessions {
+		m.finish(id, sess)
+	}
+}
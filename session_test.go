@@ -0,0 +1,112 @@
+package webrtcvad
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSessionManager 测试会话的懒创建、空闲过期与实例复用
+func TestSessionManager(t *testing.T) {
+	closed := make(map[string][]VoiceSegment)
+	now := time.Unix(1000, 0)
+	clock := func() time.Time { return now }
+	m, err := NewSessionManager(SessionConfig{
+		Options:     []StreamVADOption{WithStreamMode(2), WithSampleRate(16000), WithFrameDuration(20), WithClockFunc(clock)},
+		IdleTimeout: time.Minute,
+		OnClose: func(id string, segs []VoiceSegment) {
+			closed[id] = segs
+		},
+	})
+	if err != nil {
+		t.Fatalf("创建会话管理器失败: %v", err)
+	}
+
+	pcm := harmonicNoise(16000, time.Second, 300*time.Millisecond, 700*time.Millisecond)
+	for _, id := range []string{"a", "b"} {
+		if _, err := m.Feed(id, pcm); err != nil {
+			t.Fatalf("会话%s写入失败: %v", id, err)
+		}
+	}
+	if m.Len() != 2 {
+		t.Fatalf("应有2个会话, 得到%d", m.Len())
+	}
+
+	// a持续写入，b空闲超时后在下一次Feed时结束
+	now = now.Add(40 * time.Second)
+	m.Feed("a", make([]byte, 640))
+	now = now.Add(30 * time.Second)
+	m.Feed("a", make([]byte, 640))
+	if _, ok := closed["b"]; !ok || m.Len() != 1 {
+		t.Fatalf("b应已过期, 剩余%d个会话", m.Len())
+	}
+	var speech bool
+	for _, seg := range closed["b"] {
+		speech = speech || seg.IsSpeech
+	}
+	if !speech {
+		t.Errorf("b的片段中应有语音: %+v", closed["b"])
+	}
+	if start := closed["b"][0].StartTime; !start.Equal(time.Unix(999, 0)) {
+		t.Errorf("片段的绝对时间应按注入的时钟对齐, 得到%v", start)
+	}
+
+	// 结束的实例被复用，且保留了模式
+	svad := m.pool.Get().(*StreamVAD)
+	if svad.GetTotalProcessed() != 0 || len(svad.GetSegments()) != 0 {
+		t.Error("池中的实例应已重置")
+	}
+	if svad.vad.inst.total != kGlobalThresholdAGG {
+		t.Errorf("复用的实例应保留模式2, 阈值%v", svad.vad.inst.total)
+	}
+	m.pool.Put(svad)
+
+	if !m.Remove("a") || m.Remove("a") || m.Len() != 0 {
+		t.Error("Remove应结束存在的会话且只结束一次")
+	}
+	if _, ok := closed["a"]; !ok {
+		t.Error("Remove应调用OnClose")
+	}
+
+	if _, err := NewSessionManager(SessionConfig{IdleTimeout: -time.Second}); err != ErrInvalidDuration {
+		t.Errorf("负的IdleTimeout应返回ErrInvalidDuration, 得到%v", err)
+	}
+	if _, err := NewSessionManager(SessionConfig{Options: []StreamVADOption{WithStreamMode(5)}}); err == nil {
+		t.Error("无效选项应返回错误")
+	}
+}
+
+// TestSessionManagerConcurrent 测试并发写入、过期与关闭（配合 go test -race）
+func TestSessionManagerConcurrent(t *testing.T) {
+	m, err := NewSessionManager(SessionConfig{IdleTimeout: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("创建会话管理器失败: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if _, err := m.Feed(id, make([]byte, 700)); err != nil {
+					t.Errorf("会话%s写入失败: %v", id, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			m.Sweep()
+		}
+	}()
+	wg.Wait()
+
+	m.Close()
+	if m.Len() != 0 {
+		t.Errorf("Close后不应有会话, 得到%d", m.Len())
+	}
+}
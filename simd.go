@@ -0,0 +1,63 @@
+package webrtcvad
+
+// simd.go 能量、最大绝对值和点积内核的公共封装
+//
+// 具体实现由构建标签选择：
+//   - simd_amd64.s：AVX2（运行时检测CPU支持）
+//   - simd_arm64.s：NEON（arm64必备）
+//   - simd_generic.go：其他平台或指定purego标签时的纯Go实现
+//
+// 汇编内核只处理simdBlock整数倍的部分，剩余样本由这里的标量代码处理。
+// 所有内核与原有标量实现逐位一致（包括int32环绕和-32768的绝对值语义）。
+
+// simdBlock SIMD内核每次处理的样本数
+const simdBlock = 16
+
+// sumSquaresW16 返回平方和（64位精确值，不做归一化移位）
+func sumSquaresW16(v []int16) uint64 {
+	n := len(v) &^ (simdBlock - 1)
+
+	var sum uint64
+	if n > 0 {
+		sum = sumSquaresBlocks(v[:n])
+	}
+	for _, x := range v[n:] {
+		sum += uint64(int32(x) * int32(x))
+	}
+
+	return sum
+}
+
+// maxAbsW16 返回最大绝对值，语义与maxAbsValueW16一致（-32768的绝对值按int16环绕处理）
+func maxAbsW16(v []int16) int16 {
+	n := len(v) &^ (simdBlock - 1)
+
+	var maxVal int16
+	if n > 0 {
+		maxVal = maxAbsBlocks(v[:n])
+	}
+	for _, x := range v[n:] {
+		if a := absW16(x); a > maxVal {
+			maxVal = a
+		}
+	}
+
+	return maxVal
+}
+
+// dotProductW16 返回 Σ (a[i]*b[i]) >> shift，按int32环绕累加
+//
+// 要求len(a) == len(b)且shift < 32
+func dotProductW16(a, b []int16, shift uint) int32 {
+	n := len(a) &^ (simdBlock - 1)
+
+	var sum int32
+	if n > 0 {
+		sum = dotProductBlocks(a[:n], b[:n], shift)
+	}
+	for i := n; i < len(a); i++ {
+		sum += (int32(a[i]) * int32(b[i])) >> shift
+	}
+
+	return sum
+}
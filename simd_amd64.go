@@ -0,0 +1,52 @@
+//go:build !purego
+
+package webrtcvad
+
+// simdEnabled 当前CPU是否支持AVX2
+var simdEnabled = hasAVX2()
+
+// sumSquaresBlocks AVX2平方和，len(v)必须是simdBlock的整数倍
+//
+//go:noescape
+func sumSquaresBlocks(v []int16) uint64
+
+// maxAbsBlocks AVX2最大绝对值，len(v)必须是simdBlock的整数倍
+//
+//go:noescape
+func maxAbsBlocks(v []int16) int16
+
+// dotProductBlocks AVX2移位点积，len(a)必须是simdBlock的整数倍且len(b) >= len(a)
+//
+//go:noescape
+func dotProductBlocks(a, b []int16, shift uint) int32
+
+// cpuid 执行CPUID指令
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv 读取扩展控制寄存器XCR0
+func xgetbv() (eax, edx uint32)
+
+// hasAVX2 检测CPU和操作系统是否都支持AVX2
+func hasAVX2() bool {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+
+	_, _, ecx1, _ := cpuid(1, 0)
+	const (
+		osxsave = 1 << 27
+		avx     = 1 << 28
+	)
+	if ecx1&osxsave == 0 || ecx1&avx == 0 {
+		return false
+	}
+
+	// 操作系统需要保存XMM和YMM寄存器状态
+	if xcr0, _ := xgetbv(); xcr0&0x6 != 0x6 {
+		return false
+	}
+
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&(1<<5) != 0
+}
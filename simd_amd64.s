@@ -0,0 +1,124 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func sumSquaresBlocks(v []int16) uint64
+TEXT ·sumSquaresBlocks(SB), NOSPLIT, $0-32
+	MOVQ v_base+0(FP), SI
+	MOVQ v_len+8(FP), CX
+	VPXOR Y4, Y4, Y4
+
+sumLoop:
+	CMPQ CX, $16
+	JL   sumDone
+	VMOVDQU (SI), Y0
+	// 相邻两个样本的平方和；最大为2^31，按无符号32位扩展到64位累加
+	VPMADDWD Y0, Y0, Y1
+	VEXTRACTI128 $1, Y1, X2
+	VPMOVZXDQ X1, Y3
+	VPADDQ Y3, Y4, Y4
+	VPMOVZXDQ X2, Y3
+	VPADDQ Y3, Y4, Y4
+	ADDQ $32, SI
+	SUBQ $16, CX
+	JMP  sumLoop
+
+sumDone:
+	VEXTRACTI128 $1, Y4, X5
+	VPADDQ X5, X4, X4
+	VPSHUFD $0x4E, X4, X5
+	VPADDQ X5, X4, X4
+	MOVQ X4, AX
+	VZEROUPPER
+	MOVQ AX, ret+24(FP)
+	RET
+
+// func maxAbsBlocks(v []int16) int16
+TEXT ·maxAbsBlocks(SB), NOSPLIT, $0-26
+	MOVQ v_base+0(FP), SI
+	MOVQ v_len+8(FP), CX
+	VPXOR Y1, Y1, Y1
+
+maxLoop:
+	CMPQ CX, $16
+	JL   maxDone
+	VMOVDQU (SI), Y0
+	// VPABSW对-32768返回-32768，与absW16的环绕语义一致；有符号比较时被忽略
+	VPABSW Y0, Y0
+	VPMAXSW Y0, Y1, Y1
+	ADDQ $32, SI
+	SUBQ $16, CX
+	JMP  maxLoop
+
+maxDone:
+	VEXTRACTI128 $1, Y1, X2
+	VPMAXSW X2, X1, X1
+	VPSHUFD $0x4E, X1, X2
+	VPMAXSW X2, X1, X1
+	VPSHUFD $0xB1, X1, X2
+	VPMAXSW X2, X1, X1
+	VPSHUFLW $0xB1, X1, X2
+	VPMAXSW X2, X1, X1
+	MOVQ X1, AX
+	VZEROUPPER
+	MOVW AX, ret+24(FP)
+	RET
+
+// func dotProductBlocks(a, b []int16, shift uint) int32
+TEXT ·dotProductBlocks(SB), NOSPLIT, $0-60
+	MOVQ a_base+0(FP), SI
+	MOVQ a_len+8(FP), CX
+	MOVQ b_base+24(FP), DI
+	MOVQ shift+48(FP), AX
+	MOVQ AX, X5
+	VPXOR Y6, Y6, Y6
+
+dotLoop:
+	CMPQ CX, $16
+	JL   dotDone
+	VMOVDQU (SI), Y0
+	VMOVDQU (DI), Y1
+	// 由乘积的低16位和高16位拼出完整的32位乘积，逐个移位后累加
+	VPMULLW Y1, Y0, Y2
+	VPMULHW Y1, Y0, Y3
+	VPUNPCKLWD Y3, Y2, Y4
+	VPSRAD X5, Y4, Y4
+	VPADDD Y4, Y6, Y6
+	VPUNPCKHWD Y3, Y2, Y4
+	VPSRAD X5, Y4, Y4
+	VPADDD Y4, Y6, Y6
+	ADDQ $32, SI
+	ADDQ $32, DI
+	SUBQ $16, CX
+	JMP  dotLoop
+
+dotDone:
+	VEXTRACTI128 $1, Y6, X7
+	VPADDD X7, X6, X6
+	VPSHUFD $0x4E, X6, X7
+	VPADDD X7, X6, X6
+	VPSHUFD $0xB1, X6, X7
+	VPADDD X7, X6, X6
+	MOVQ X6, AX
+	VZEROUPPER
+	MOVL AX, ret+56(FP)
+	RET
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
@@ -0,0 +1,21 @@
+//go:build !purego
+
+package webrtcvad
+
+// simdEnabled arm64平台的NEON（ASIMD）为必备扩展，始终启用
+const simdEnabled = true
+
+// sumSquaresBlocks NEON平方和，len(v)必须是simdBlock的整数倍
+//
+//go:noescape
+func sumSquaresBlocks(v []int16) uint64
+
+// maxAbsBlocks NEON最大绝对值，len(v)必须是simdBlock的整数倍
+//
+//go:noescape
+func maxAbsBlocks(v []int16) int16
+
+// dotProductBlocks NEON移位点积，len(a)必须是simdBlock的整数倍且len(b) >= len(a)
+//
+//go:noescape
+func dotProductBlocks(a, b []int16, shift uint) int32
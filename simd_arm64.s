@@ -0,0 +1,85 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// Go汇编器不支持部分有符号NEON指令，以下用WORD直接编码：
+//   SMULL/SMULL2 有符号扩展乘法，UADALP 无符号成对加并累加，
+//   ABS/SMAX/SMAXV 有符号绝对值和最大值，SSHL 有符号可变移位
+
+// func sumSquaresBlocks(v []int16) uint64
+TEXT ·sumSquaresBlocks(SB), NOSPLIT, $0-32
+	MOVD v_base+0(FP), R0
+	MOVD v_len+8(FP), R1
+	VEOR V4.B16, V4.B16, V4.B16
+	VEOR V5.B16, V5.B16, V5.B16
+
+sumLoop:
+	CMP  $8, R1
+	BLT  sumDone
+	VLD1.P 16(R0), [V0.H8]
+	WORD $0x0E60C001 // SMULL  V1.4S, V0.4H, V0.4H
+	WORD $0x4E60C002 // SMULL2 V2.4S, V0.8H, V0.8H
+	WORD $0x6EA06824 // UADALP V4.2D, V1.4S
+	WORD $0x6EA06845 // UADALP V5.2D, V2.4S
+	SUB  $8, R1
+	B    sumLoop
+
+sumDone:
+	VADD V5.D2, V4.D2, V4.D2
+	VMOV V4.D[0], R2
+	VMOV V4.D[1], R3
+	ADD  R3, R2
+	MOVD R2, ret+24(FP)
+	RET
+
+// func maxAbsBlocks(v []int16) int16
+TEXT ·maxAbsBlocks(SB), NOSPLIT, $0-26
+	MOVD v_base+0(FP), R0
+	MOVD v_len+8(FP), R1
+	VEOR V1.B16, V1.B16, V1.B16
+
+maxLoop:
+	CMP  $8, R1
+	BLT  maxDone
+	VLD1.P 16(R0), [V0.H8]
+	// ABS对-32768返回-32768，与absW16的环绕语义一致；有符号比较时被忽略
+	WORD $0x4E60B800 // ABS  V0.8H, V0.8H
+	WORD $0x4E606421 // SMAX V1.8H, V1.8H, V0.8H
+	SUB  $8, R1
+	B    maxLoop
+
+maxDone:
+	WORD $0x4E70A822 // SMAXV H2, V1.8H
+	VMOV V2.H[0], R2
+	MOVH R2, ret+24(FP)
+	RET
+
+// func dotProductBlocks(a, b []int16, shift uint) int32
+TEXT ·dotProductBlocks(SB), NOSPLIT, $0-60
+	MOVD a_base+0(FP), R0
+	MOVD a_len+8(FP), R1
+	MOVD b_base+24(FP), R2
+	MOVD shift+48(FP), R3
+	NEG  R3, R3
+	VDUP R3, V7.S4 // SSHL的负移位量即算术右移
+	VEOR V6.B16, V6.B16, V6.B16
+
+dotLoop:
+	CMP  $8, R1
+	BLT  dotDone
+	VLD1.P 16(R0), [V0.H8]
+	VLD1.P 16(R2), [V1.H8]
+	WORD $0x0E61C002 // SMULL  V2.4S, V0.4H, V1.4H
+	WORD $0x4E61C003 // SMULL2 V3.4S, V0.8H, V1.8H
+	WORD $0x4EA74442 // SSHL   V2.4S, V2.4S, V7.4S
+	WORD $0x4EA74463 // SSHL   V3.4S, V3.4S, V7.4S
+	VADD V2.S4, V6.S4, V6.S4
+	VADD V3.S4, V6.S4, V6.S4
+	SUB  $8, R1
+	B    dotLoop
+
+dotDone:
+	VADDV V6.S4, V6
+	VMOV V6.S[0], R4
+	MOVW R4, ret+56(FP)
+	RET
@@ -0,0 +1,35 @@
+//go:build (!amd64 && !arm64) || purego
+
+package webrtcvad
+
+// simdEnabled 当前平台是否启用SIMD内核
+const simdEnabled = false
+
+// sumSquaresBlocks 纯Go实现的平方和
+func sumSquaresBlocks(v []int16) uint64 {
+	var sum uint64
+	for _, x := range v {
+		sum += uint64(int32(x) * int32(x))
+	}
+	return sum
+}
+
+// maxAbsBlocks 纯Go实现的最大绝对值
+func maxAbsBlocks(v []int16) int16 {
+	var maxVal int16
+	for _, x := range v {
+		if a := absW16(x); a > maxVal {
+			maxVal = a
+		}
+	}
+	return maxVal
+}
+
+// dotProductBlocks 纯Go实现的移位点积
+func dotProductBlocks(a, b []int16, shift uint) int32 {
+	var sum int32
+	for i := range a {
+		sum += (int32(a[i]) * int32(b[i])) >> shift
+	}
+	return sum
+}
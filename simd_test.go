@@ -0,0 +1,140 @@
+package webrtcvad
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomSamples 生成包含极值的随机样本
+func randomSamples(rng *rand.Rand, n int) []int16 {
+	v := make([]int16, n)
+	for i := range v {
+		switch rng.Intn(16) {
+		case 0:
+			v[i] = -32768
+		case 1:
+			v[i] = 32767
+		default:
+			v[i] = int16(rng.Intn(65536) - 32768)
+		}
+	}
+	return v
+}
+
+// TestSIMDKernels 测试SIMD内核与标量实现逐位一致
+func TestSIMDKernels(t *testing.T) {
+	if !simdEnabled {
+		t.Skip("当前平台未启用SIMD内核")
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n <= 100; n++ {
+		a := randomSamples(rng, n)
+		b := randomSamples(rng, n)
+
+		var want uint64
+		for _, x := range a {
+			want += uint64(int64(x) * int64(x))
+		}
+		if got := sumSquaresW16(a); got != want {
+			t.Fatalf("n=%d: sumSquaresW16=%d, 期望%d", n, got, want)
+		}
+
+		if got, want := maxAbsW16(a), maxAbsValueW16Scalar(a, n); got != want {
+			t.Fatalf("n=%d: maxAbsW16=%d, 期望%d", n, got, want)
+		}
+
+		for _, shift := range []uint{0, 1, 7, 15, 31} {
+			var want int32
+			for i := range a {
+				want += (int32(a[i]) * int32(b[i])) >> shift
+			}
+			if got := dotProductW16(a, b, shift); got != want {
+				t.Fatalf("n=%d shift=%d: dotProductW16=%d, 期望%d", n, shift, got, want)
+			}
+		}
+	}
+}
+
+// TestSIMDCallersMatchScalar 测试使用SIMD的公共路径与标量实现一致
+func TestSIMDCallersMatchScalar(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, amplitude := range []int{100, 3000, 32768} {
+		for n := 1; n <= 480; n += 17 {
+			v := make([]int16, n)
+			for i := range v {
+				v[i] = int16(rng.Intn(2*amplitude) - amplitude)
+			}
+
+			var scale, wantScale int
+			got := calculateEnergy(v, n, &scale)
+			want := calculateEnergyScalar(v, n, &wantScale)
+			if got != want || scale != wantScale {
+				t.Fatalf("n=%d: calculateEnergy=(%d,%d), 期望(%d,%d)", n, got, scale, want, wantScale)
+			}
+
+			if got, want := maxAbsValueW16(v, n), maxAbsValueW16Scalar(v, n); got != want {
+				t.Fatalf("n=%d: maxAbsValueW16=%d, 期望%d", n, got, want)
+			}
+		}
+	}
+}
+
+// TestCrossCorrelationMatchesScalar 测试互相关的SIMD路径与逐点累加一致
+func TestCrossCorrelationMatchesScalar(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	seq1 := randomSamples(rng, 80)
+	seq2 := randomSamples(rng, 200)
+
+	for _, shifts := range []int{0, 3, 12} {
+		for _, dim := range []int{7, 16, 33, 80} {
+			got := CrossCorrelation(seq1, seq2, dim, 150, shifts, 1)
+			for i := range got {
+				var want int32
+				for j := 0; j < dim; j++ {
+					if i+j < len(seq2) {
+						want += (int32(seq1[j]) * int32(seq2[i+j])) >> uint(shifts)
+					}
+				}
+				if got[i] != want {
+					t.Fatalf("dim=%d shifts=%d lag=%d: 得到%d, 期望%d", dim, shifts, i, got[i], want)
+				}
+			}
+		}
+	}
+}
+
+// speechLevelFrame 30ms@8kHz（VAD计算能量时的采样率）的语音电平测试帧：
+// 约-12dBFS的谐波加噪声，未经缩放，能量会超过归一化门限
+func speechLevelFrame() []int16 {
+	rng := rand.New(rand.NewSource(3))
+	data := make([]int16, 240)
+	for i := range data {
+		x := 6000*math.Sin(2*math.Pi*200*float64(i)/8000) + 3000*math.Sin(2*math.Pi*600*float64(i)/8000)
+		data[i] = int16(x + float64(rng.Intn(1000)-500))
+	}
+	return data
+}
+
+// BenchmarkCalculateEnergy30ms8k 30ms@8kHz帧能量计算
+func BenchmarkCalculateEnergy30ms8k(b *testing.B) {
+	data := speechLevelFrame()
+	var scale int
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		calculateEnergy(data, len(data), &scale)
+	}
+}
+
+// BenchmarkCalculateEnergy30ms8kScalar 标量实现对比
+func BenchmarkCalculateEnergy30ms8kScalar(b *testing.B) {
+	data := speechLevelFrame()
+	var scale int
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		calculateEnergyScalar(data, len(data), &scale)
+	}
+}
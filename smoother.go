@@ -0,0 +1,112 @@
+package webrtcvad
+
+import "math"
+
+// smoother.go 提供逐帧判决的滑动平均平滑器，位于判决器与片段生成之间
+
+// SmoothingKind 平滑方式
+type SmoothingKind int
+
+const (
+	// ExponentialSmoothing 指数平滑：每帧以1-exp(-1/Frames)的系数跟随判决
+	ExponentialSmoothing SmoothingKind = iota
+	// BoxcarSmoothing 矩形窗平均：最近Frames帧中语音帧的比例
+	BoxcarSmoothing
+	// VoteSmoothing 多数表决：与BoxcarSmoothing相同，但窗口未满时空位按非语音计，
+	// 即最近Frames帧中至少Threshold*Frames帧为语音时判为语音（见WithVoteWindow）
+	VoteSmoothing
+)
+
+// SmootherConfig 平滑器配置
+type SmootherConfig struct {
+	// Kind 平滑方式
+	Kind SmoothingKind
+	// Frames 指数平滑的时间常数或矩形窗长度（帧数），不小于1
+	Frames int
+	// Threshold 平滑值不低于该门限时判为语音，范围(0, 1]
+	Threshold float64
+}
+
+// DefaultSmootherConfig 返回默认配置：5帧指数平滑，门限0.5
+func DefaultSmootherConfig() SmootherConfig {
+	return SmootherConfig{Kind: ExponentialSmoothing, Frames: 5, Threshold: 0.5}
+}
+
+// Smoother 逐帧判决的滑动平均平滑器
+//
+// 将判决（语音为1，否则为0）做指数平滑或矩形窗平均，与门限比较后输出平滑后的判决，
+// 抑制孤立的误判与漏判。与迟滞（WithHysteresis）不同，平滑按语音帧的密度判决，
+// 语音中零星的静音帧不会打断语音。Smoother有状态且不是并发安全的
+type Smoother struct {
+	cfg   SmootherConfig
+	alpha float64 // 指数平滑系数
+
+	value  float64 // 当前平滑值（0-1）
+	window []bool  // 矩形窗内的判决（环形）
+	pos    int     // window中下一帧的写入位置
+	count  int     // window中的有效帧数
+	active int     // window中的语音帧数
+}
+
+// NewSmoother 创建平滑器
+//
+// 参数:
+//   - cfg: 配置，见DefaultSmootherConfig
+//
+// 返回:
+//   - *Smoother: 平滑器实例
+//   - error: 配置无效时返回ErrInvalidSmoother
+func NewSmoother(cfg SmootherConfig) (*Smoother, error) {
+	if cfg.Kind < ExponentialSmoothing || cfg.Kind > VoteSmoothing ||
+		cfg.Frames < 1 || !(cfg.Threshold > 0 && cfg.Threshold <= 1) {
+		return nil, ErrInvalidSmoother
+	}
+	s := &Smoother{cfg: cfg, alpha: 1 - math.Exp(-1/float64(cfg.Frames))}
+	if cfg.Kind != ExponentialSmoothing {
+		s.window = make([]bool, cfg.Frames)
+	}
+	return s, nil
+}
+
+// Update 加入一帧的判决，返回平滑后的判决
+func (s *Smoother) Update(speech bool) bool {
+	var x float64
+	if speech {
+		x = 1
+	}
+	if s.cfg.Kind == ExponentialSmoothing {
+		s.value += s.alpha * (x - s.value)
+	} else {
+		if s.count == len(s.window) {
+			if s.window[s.pos] {
+				s.active--
+			}
+		} else {
+			s.count++
+		}
+		s.window[s.pos] = speech
+		if speech {
+			s.active++
+		}
+		s.pos = (s.pos + 1) % len(s.window)
+		// 矩形窗在窗口未满时按已有帧数平均，使开头的判决不被空位稀释
+		n := s.count
+		if s.cfg.Kind == VoteSmoothing {
+			n = len(s.window)
+		}
+		s.value = float64(s.active) / float64(n)
+	}
+	return s.value >= s.cfg.Threshold
+}
+
+// Value 返回当前的平滑值（0-1）
+func (s *Smoother) Value() float64 {
+	return s.value
+}
+
+// Reset 清除平滑状态
+func (s *Smoother) Reset() {
+	s.value = 0
+	clear(s.window)
+	s.pos, s.count, s.active = 0, 0, 0
+}
@@ -0,0 +1,136 @@
+package webrtcvad
+
+import (
+	"testing"
+	"time"
+)
+
+// decisions 将"1"/"0"序列转换为判决
+func decisions(s string) []bool {
+	out := make([]bool, len(s))
+	for i, c := range s {
+		out[i] = c == '1'
+	}
+	return out
+}
+
+// TestSmootherBoxcar 测试矩形窗平均：孤立毛刺被滤除，语音中零星的静音不打断语音
+func TestSmootherBoxcar(t *testing.T) {
+	s, err := NewSmoother(SmootherConfig{Kind: BoxcarSmoothing, Frames: 4, Threshold: 0.5})
+	if err != nil {
+		t.Fatalf("创建平滑器失败: %v", err)
+	}
+	in := decisions("0001000011101100000")
+	want := decisions("0000000001111111000")
+	for i, d := range in {
+		if got := s.Update(d); got != want[i] {
+			t.Fatalf("第%d帧应为%v, 得到%v（平滑值%g）", i, want[i], got, s.Value())
+		}
+	}
+
+	s.Reset()
+	if s.Value() != 0 || !s.Update(true) {
+		t.Error("重置后窗口应为空, 第一帧语音按已有帧平均即为1")
+	}
+}
+
+// TestSmootherExponential 测试指数平滑的上升与衰减
+func TestSmootherExponential(t *testing.T) {
+	s, _ := NewSmoother(DefaultSmootherConfig())
+	var onset int
+	for i := 1; !s.Update(true); i++ {
+		onset = i
+	}
+	if onset != 3 {
+		t.Errorf("5帧时间常数下第4帧语音才应超过0.5, 得到第%d帧", onset+1)
+	}
+	for i := 0; i < 20; i++ {
+		s.Update(true)
+	}
+	if s.Update(false) != true {
+		t.Error("持续语音后单个静音帧不应改变判决")
+	}
+	for i := 0; i < 10; i++ {
+		s.Update(false)
+	}
+	if v := s.Value(); v > 0.15 {
+		t.Errorf("连续静音后平滑值应衰减, 得到%g", v)
+	}
+
+	for _, cfg := range []SmootherConfig{
+		{Kind: SmoothingKind(3), Frames: 5, Threshold: 0.5},
+		{Kind: BoxcarSmoothing, Frames: 0, Threshold: 0.5},
+		{Kind: BoxcarSmoothing, Frames: 5, Threshold: 0},
+		{Kind: ExponentialSmoothing, Frames: 5, Threshold: 1.5},
+	} {
+		if _, err := NewSmoother(cfg); err != ErrInvalidSmoother {
+			t.Errorf("%+v 应返回ErrInvalidSmoother, 得到%v", cfg, err)
+		}
+	}
+}
+
+// TestWithSmoother 测试StreamVAD按平滑后的判决生成片段，统计保持平滑前的判决
+func TestWithSmoother(t *testing.T) {
+	pcm := harmonicNoise(16000, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+	cfg := SmootherConfig{Kind: BoxcarSmoothing, Frames: 10, Threshold: 0.5}
+	svad, err := NewStreamVADWithOptions(WithStreamMode(3), WithAlgorithm(Entropy), WithFrameDuration(10), WithSmoother(cfg))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	raw, _ := NewStreamVADWithOptions(WithStreamMode(3), WithAlgorithm(Entropy), WithFrameDuration(10))
+	svad.Process(pcm)
+	raw.Process(pcm)
+	svad.Flush()
+	raw.Flush()
+
+	speech := svad.FilterSpeechSegments()
+	if len(speech) != 1 {
+		t.Fatalf("平滑后应只有1个语音片段, 得到%v", speech)
+	}
+	if seg := speech[0]; seg.Start < 2*time.Second || seg.End > 2600*time.Millisecond {
+		t.Errorf("语音片段应在2s-2.6s内, 得到%v-%v", seg.Start, seg.End)
+	}
+	if svad.Stats() != raw.Stats() {
+		t.Errorf("统计应为平滑前的判决: %+v / %+v", svad.Stats(), raw.Stats())
+	}
+
+	if _, err := NewStreamVADWithOptions(WithSmoother(SmootherConfig{})); err != ErrInvalidSmoother {
+		t.Errorf("应返回ErrInvalidSmoother, 得到%v", err)
+	}
+}
+
+// TestSmootherVote 测试多数表决：窗口未满时空位按非语音计
+func TestSmootherVote(t *testing.T) {
+	s, _ := NewSmoother(SmootherConfig{Kind: VoteSmoothing, Frames: 5, Threshold: 3.0 / 5})
+	in := decisions("1101011000100")
+	want := decisions("0001111100000")
+	for i, d := range in {
+		if got := s.Update(d); got != want[i] {
+			t.Fatalf("第%d帧应为%v, 得到%v（平滑值%g）", i, want[i], got, s.Value())
+		}
+	}
+}
+
+// TestWithVoteWindow 测试StreamVAD的多数表决
+func TestWithVoteWindow(t *testing.T) {
+	pcm := harmonicNoise(16000, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+	svad, err := NewStreamVADWithOptions(WithStreamMode(3), WithAlgorithm(Entropy), WithFrameDuration(10), WithVoteWindow(8, 3))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	svad.Process(pcm)
+	svad.Flush()
+	speech := svad.FilterSpeechSegments()
+	if len(speech) != 1 {
+		t.Fatalf("表决后应只有1个语音片段, 得到%v", speech)
+	}
+	if seg := speech[0]; seg.Start < 2*time.Second || seg.End > 2600*time.Millisecond {
+		t.Errorf("语音片段应在2s-2.6s内, 得到%v-%v", seg.Start, seg.End)
+	}
+
+	for _, c := range [][2]int{{5, 0}, {3, 4}} {
+		if _, err := NewStreamVADWithOptions(WithVoteWindow(c[0], c[1])); err != ErrInvalidFrameCount {
+			t.Errorf("WithVoteWindow(%d, %d)应返回ErrInvalidFrameCount, 得到%v", c[0], c[1], err)
+		}
+	}
+}
@@ -99,8 +99,16 @@ func zerosArrayW32(vector []int32, length int) {
 	clear(vector[:length])
 }
 
-// maxAbsValueW16 返回int16向量中的最大绝对值
+// maxAbsValueW16 返回int16向量中的最大绝对值（支持时使用SIMD内核）
 func maxAbsValueW16(vector []int16, length int) int16 {
+	if simdEnabled && length >= simdBlock {
+		return maxAbsW16(vector[:length])
+	}
+	return maxAbsValueW16Scalar(vector, length)
+}
+
+// maxAbsValueW16Scalar maxAbsValueW16的标量实现
+func maxAbsValueW16Scalar(vector []int16, length int) int16 {
 	var maxVal int16 = 0
 	var absVal int16
 
@@ -184,7 +192,9 @@ func maxValueW16(vector []int16, length int) int16 {
 	return maxVal
 }
 
-// calculateEnergy 计算信号能量（循环展开优化，性能提升12%）
+// calculateEnergy 计算信号能量（支持时使用SIMD内核）
+//
+// 先求64位精确平方和，再右移到不超过0x40000000，只遍历一次数据
 //
 // 参数：
 //   - vector：输入信号向量
@@ -193,41 +203,41 @@ func maxValueW16(vector []int16, length int) int16 {
 //
 // 返回：能量值（uint32）
 func calculateEnergy(vector []int16, vectorLength int, scale *int) uint32 {
-	var (
-		energy      uint32 = 0
-		scaleFactor int    = 0
-	)
+	return normalizeEnergy(sumSquaresW16(vector[:vectorLength]), scale)
+}
+
+// calculateEnergyScalar calculateEnergy的标量实现（循环展开优化），用于对照SIMD路径
+func calculateEnergyScalar(vector []int16, vectorLength int, scale *int) uint32 {
+	var energy uint64
 
 	// 4路展开计算能量
 	i := 0
 	for ; i+3 < vectorLength; i += 4 {
-		tmp0 := int32(vector[i])
-		tmp1 := int32(vector[i+1])
-		tmp2 := int32(vector[i+2])
-		tmp3 := int32(vector[i+3])
-
-		energy += uint32(tmp0*tmp0 + tmp1*tmp1 + tmp2*tmp2 + tmp3*tmp3)
+		tmp0 := int64(vector[i])
+		tmp1 := int64(vector[i+1])
+		tmp2 := int64(vector[i+2])
+		tmp3 := int64(vector[i+3])
 
-		// 检查溢出
-		if energy > 0x40000000 {
-			energy >>= 1
-			scaleFactor++
-		}
+		energy += uint64(tmp0*tmp0 + tmp1*tmp1 + tmp2*tmp2 + tmp3*tmp3)
 	}
 
 	// 处理剩余
 	for ; i < vectorLength; i++ {
-		tmp := int32(vector[i])
-		energy += uint32(tmp * tmp)
-
-		if energy > 0x40000000 {
-			energy >>= 1
-			scaleFactor++
-		}
+		tmp := int64(vector[i])
+		energy += uint64(tmp * tmp)
 	}
 
-	*scale = scaleFactor
-	return energy
+	return normalizeEnergy(energy, scale)
+}
+
+// normalizeEnergy 将精确平方和右移到不超过0x40000000，scale输出右移次数
+func normalizeEnergy(energy uint64, scale *int) uint32 {
+	shift := max(0, bits.Len64(energy)-31)
+	if energy>>shift > 0x40000000 {
+		shift++
+	}
+	*scale = shift
+	return uint32(energy >> shift)
 }
 
 // copyFromEndW16 从向量末尾复制数据
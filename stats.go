@@ -0,0 +1,77 @@
+package webrtcvad
+
+// stats.go 提供逐帧判决的内置计数，服务无需另外维护计数器即可上报
+
+// Stats 判决统计
+type Stats struct {
+	// Frames 已判决的帧数
+	Frames int64
+	// SpeechFrames 判为语音的帧数
+	SpeechFrames int64
+	// Transitions 语音与非语音之间的切换次数（第一帧不计）
+	Transitions int64
+	// SpeechStreak 当前连续语音帧数，最近一帧为非语音时为0
+	SpeechStreak int64
+	// SilenceStreak 当前连续非语音帧数，最近一帧为语音时为0
+	SilenceStreak int64
+
+	// Timing 逐帧处理耗时统计，仅在启用WithTiming/WithStreamTiming时填充
+	Timing TimingStats
+}
+
+// SpeechRatio 返回语音帧占比（0-1），尚未判决时为0
+func (s Stats) SpeechRatio() float64 {
+	if s.Frames == 0 {
+		return 0
+	}
+	return float64(s.SpeechFrames) / float64(s.Frames)
+}
+
+// record 计入一帧的判决
+func (s *Stats) record(speech bool) {
+	if s.Frames > 0 && speech != (s.SpeechStreak > 0) {
+		s.Transitions++
+	}
+	s.Frames++
+	if speech {
+		s.SpeechFrames++
+		s.SpeechStreak++
+		s.SilenceStreak = 0
+	} else {
+		s.SilenceStreak++
+		s.SpeechStreak = 0
+	}
+}
+
+// Stats 返回IsSpeech的判决统计（Prime的预热帧不计入）
+//
+// IsSpeechBatch、Feed等基于IsSpeech的接口同样计入
+func (v *VAD) Stats() Stats {
+	s := v.stats
+	if v.timer != nil {
+		s.Timing = v.timer.snapshot()
+	}
+	return s
+}
+
+// ResetStats 清零判决统计（包括耗时统计），不影响检测状态
+func (v *VAD) ResetStats() {
+	v.stats = Stats{}
+	if v.timer != nil {
+		*v.timer = frameTimer{}
+	}
+}
+
+// Stats 返回检测器逐帧判决（平滑前）的统计，Reset时清零
+//
+// 与片段不同，统计不受WithSmoother、WithMinSpeechDuration、WithHysteresis等平滑选项影响，
+// 且与所用算法（见WithAlgorithm）无关
+func (s *StreamVAD) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	if s.timer != nil {
+		stats.Timing = s.timer.snapshot()
+	}
+	return stats
+}
@@ -0,0 +1,94 @@
+package webrtcvad
+
+import (
+	"testing"
+	"time"
+)
+
+// TestStatsRecord 测试计数、切换次数与连续帧数
+func TestStatsRecord(t *testing.T) {
+	var s Stats
+	if s.SpeechRatio() != 0 {
+		t.Errorf("尚未判决时语音占比应为0, 得到%g", s.SpeechRatio())
+	}
+	for _, speech := range []bool{false, false, true, true, true, false, true, true} {
+		s.record(speech)
+	}
+	want := Stats{Frames: 8, SpeechFrames: 5, Transitions: 3, SpeechStreak: 2}
+	if s != want {
+		t.Errorf("统计应为%+v, 得到%+v", want, s)
+	}
+	if r := s.SpeechRatio(); r != 5.0/8 {
+		t.Errorf("语音占比应为0.625, 得到%g", r)
+	}
+}
+
+// TestVADStats 测试VAD的统计包含IsSpeech与Feed的判决，不含Prime的预热帧
+func TestVADStats(t *testing.T) {
+	vad, _ := New(3)
+	pcm := harmonicNoise(16000, time.Second, 500*time.Millisecond, time.Second)
+	if err := vad.Prime(pcm[:3200], 16000); err != nil {
+		t.Fatalf("预热失败: %v", err)
+	}
+	if st := vad.Stats(); st.Frames != 0 {
+		t.Errorf("预热帧不应计入统计, 得到%+v", st)
+	}
+
+	var speech int64
+	for i := 0; i+320 <= len(pcm); i += 320 {
+		ok, err := vad.IsSpeech(pcm[i:i+320], 16000)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		if ok {
+			speech++
+		}
+	}
+	results, _ := vad.Feed(pcm[:960], 16000)
+	for _, ok := range results {
+		if ok {
+			speech++
+		}
+	}
+
+	st := vad.Stats()
+	if st.Frames != 103 || st.SpeechFrames != speech {
+		t.Errorf("应统计103帧、%d个语音帧, 得到%+v", speech, st)
+	}
+	if speech == 0 || st.Transitions == 0 {
+		t.Errorf("谐波段应检测到语音并产生切换, 得到%+v", st)
+	}
+
+	vad.ResetStats()
+	if st := vad.Stats(); st != (Stats{}) {
+		t.Errorf("ResetStats后统计应清零, 得到%+v", st)
+	}
+}
+
+// TestStreamVADStats 测试StreamVAD统计平滑前的逐帧判决，Reset时清零
+func TestStreamVADStats(t *testing.T) {
+	pcm := harmonicNoise(16000, 3*time.Second, 2*time.Second, 2500*time.Millisecond)
+	svad, _ := NewStreamVADWithOptions(WithStreamMode(3), WithAlgorithm(Entropy), WithMinSpeechDuration(time.Second))
+	svad.Process(pcm)
+	svad.Process(make([]byte, 100))
+	svad.Flush()
+
+	st := svad.Stats()
+	if st.Frames != 151 {
+		t.Errorf("应统计150个20ms帧和Flush补齐的1帧, 得到%d", st.Frames)
+	}
+	if st.SpeechFrames < 12 || st.SpeechFrames > 30 {
+		t.Errorf("谐波段的25帧应大部分判为语音, 得到%d", st.SpeechFrames)
+	}
+	if len(svad.FilterSpeechSegments()) != 0 {
+		t.Error("最短语音时长为1s时不应产生语音片段")
+	}
+	if st.SilenceStreak == 0 || st.SpeechStreak != 0 {
+		t.Errorf("以静音结束时应只有静音连续帧, 得到%+v", st)
+	}
+
+	svad.Reset()
+	if st := svad.Stats(); st != (Stats{}) {
+		t.Errorf("Reset后统计应清零, 得到%+v", st)
+	}
+}
@@ -0,0 +1,196 @@
+package webrtcvad
+
+import "math/bits"
+
+// stft.go 实现流式短时傅里叶变换（STFT）及其逆变换（加权重叠相加）
+
+// stftEpsilon 窗函数平方和低于此值的位置视为无法重建
+const stftEpsilon = 1e-10
+
+// STFTConfig STFT/ISTFT的配置，正反变换须使用相同的配置
+type STFTConfig struct {
+	FrameSize int        // 帧长度（样本数）
+	HopSize   int        // 帧移（样本数），不大于FrameSize
+	FFTSize   int        // FFT点数，2的幂且不小于FrameSize与4；0表示取满足条件的最小2的幂
+	Window    WindowFunc // 分析与合成窗，nil表示HannWindow
+}
+
+// normalize 校验配置并填充默认值
+func (c STFTConfig) normalize() (STFTConfig, error) {
+	if c.FFTSize == 0 && c.FrameSize > 0 {
+		c.FFTSize = 4
+		for c.FFTSize < c.FrameSize {
+			c.FFTSize <<= 1
+		}
+	}
+	if c.Window == nil {
+		c.Window = HannWindow
+	}
+	if c.FrameSize <= 0 || c.HopSize <= 0 || c.HopSize > c.FrameSize ||
+		c.FFTSize < max(c.FrameSize, 4) || c.FFTSize&(c.FFTSize-1) != 0 {
+		return c, ErrInvalidSTFTConfig
+	}
+	return c, nil
+}
+
+// STFT 流式短时傅里叶变换
+//
+// 每凑满FrameSize个样本输出一帧频谱，之后每HopSize个样本输出一帧。
+// 第k帧覆盖输入的[k·HopSize, k·HopSize+FrameSize)。STFT不是并发安全的
+type STFT struct {
+	cfg     STFTConfig
+	window  []float64
+	pending []float64
+	fft     *RealFFT64
+	in, out []float64 // FFT的输入与CCS格式输出
+}
+
+// NewSTFT 创建流式STFT
+//
+// 参数:
+//   - cfg: 帧长度、帧移、FFT点数与窗函数
+//
+// 返回:
+//   - *STFT: STFT实例
+//   - error: 配置无效时返回ErrInvalidSTFTConfig
+func NewSTFT(cfg STFTConfig) (*STFT, error) {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return nil, err
+	}
+	return &STFT{
+		cfg:     cfg,
+		window:  GenerateWindow(cfg.FrameSize, cfg.Window),
+		pending: make([]float64, 0, 2*cfg.FrameSize),
+		fft:     CreateRealFFT64(bits.Len(uint(cfg.FFTSize)) - 1),
+		in:      make([]float64, cfg.FFTSize),
+		out:     make([]float64, cfg.FFTSize+2),
+	}, nil
+}
+
+// Config 返回STFT的配置（默认值已填充）
+func (s *STFT) Config() STFTConfig {
+	return s.cfg
+}
+
+// Write 追加样本并返回新凑满的各帧频谱
+//
+// 每帧为FFTSize/2+1个频点（0到奈奎斯特频率），未缩放。返回的切片归调用方所有
+func (s *STFT) Write(samples []float64) [][]complex128 {
+	s.pending = append(s.pending, samples...)
+	var out [][]complex128
+	consumed := 0
+	for len(s.pending)-consumed >= s.cfg.FrameSize {
+		frame := s.pending[consumed : consumed+s.cfg.FrameSize]
+		for i, v := range frame {
+			s.in[i] = v * s.window[i]
+		}
+		clear(s.in[len(frame):])
+		s.fft.RealForwardFFT(s.in, s.out)
+
+		spectrum := make([]complex128, s.cfg.FFTSize/2+1)
+		for k := range spectrum {
+			spectrum[k] = complex(s.out[2*k], s.out[2*k+1])
+		}
+		out = append(out, spectrum)
+		consumed += s.cfg.HopSize
+	}
+	if consumed > 0 {
+		s.pending = s.pending[:copy(s.pending, s.pending[consumed:])]
+	}
+	return out
+}
+
+// Reset 清除缓冲的样本
+func (s *STFT) Reset() {
+	s.pending = s.pending[:0]
+}
+
+// ISTFT 流式逆短时傅里叶变换
+//
+// 对每帧做逆FFT后乘以合成窗重叠相加，并除以各位置上窗函数平方的累加和（WOLA），
+// 因此在频谱未修改时可以精确重建输入，包括开头重叠不足的部分；
+// 只被窗函数的0值覆盖的位置（如Hann窗下输出的首尾样本）无法重建，输出为0。ISTFT不是并发安全的
+type ISTFT struct {
+	cfg    STFTConfig
+	window []float64
+	acc    []float64 // 重叠相加的信号
+	wsum   []float64 // 重叠相加的窗函数平方
+	fft    *RealFFT64
+	in     []float64 // CCS格式的频谱
+	out    []float64 // 逆FFT的输出
+}
+
+// NewISTFT 创建流式ISTFT
+//
+// 参数:
+//   - cfg: 与对应STFT相同的配置
+//
+// 返回:
+//   - *ISTFT: ISTFT实例
+//   - error: 配置无效时返回ErrInvalidSTFTConfig
+func NewISTFT(cfg STFTConfig) (*ISTFT, error) {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return nil, err
+	}
+	return &ISTFT{
+		cfg:    cfg,
+		window: GenerateWindow(cfg.FrameSize, cfg.Window),
+		acc:    make([]float64, cfg.FrameSize),
+		wsum:   make([]float64, cfg.FrameSize),
+		fft:    CreateRealFFT64(bits.Len(uint(cfg.FFTSize)) - 1),
+		in:     make([]float64, cfg.FFTSize+2),
+		out:    make([]float64, cfg.FFTSize),
+	}, nil
+}
+
+// Write 合成一帧频谱，返回已完成重叠相加的HopSize个样本
+//
+// spectrum须为FFTSize/2+1个频点，否则返回nil。
+// 第k次调用返回输出的[k·HopSize, (k+1)·HopSize)，与STFT的输入对齐
+func (s *ISTFT) Write(spectrum []complex128) []float64 {
+	n := s.cfg.FFTSize
+	if len(spectrum) != n/2+1 {
+		return nil
+	}
+
+	for k, c := range spectrum {
+		s.in[2*k], s.in[2*k+1] = real(c), imag(c)
+	}
+	s.fft.RealInverseFFT(s.in, s.out)
+	for i, w := range s.window {
+		s.acc[i] += s.out[i] * w
+		s.wsum[i] += w * w
+	}
+
+	return s.emit(s.cfg.HopSize)
+}
+
+// Flush 返回最后一帧剩余的FrameSize-HopSize个样本并重置状态
+func (s *ISTFT) Flush() []float64 {
+	out := s.emit(s.cfg.FrameSize - s.cfg.HopSize)
+	s.Reset()
+	return out
+}
+
+// Reset 清除重叠相加的状态
+func (s *ISTFT) Reset() {
+	clear(s.acc)
+	clear(s.wsum)
+}
+
+// emit 输出前n个样本的归一化结果并将缓冲区前移
+func (s *ISTFT) emit(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		if s.wsum[i] > stftEpsilon {
+			out[i] = s.acc[i] / s.wsum[i]
+		}
+	}
+	copy(s.acc, s.acc[n:])
+	copy(s.wsum, s.wsum[n:])
+	clear(s.acc[len(s.acc)-n:])
+	clear(s.wsum[len(s.wsum)-n:])
+	return out
+}
@@ -0,0 +1,93 @@
+package webrtcvad
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// TestSTFTRoundTrip 测试STFT→ISTFT在频谱未修改时精确重建输入
+func TestSTFTRoundTrip(t *testing.T) {
+	for _, cfg := range []STFTConfig{
+		{FrameSize: 400, HopSize: 160},
+		{FrameSize: 256, HopSize: 128, FFTSize: 512, Window: HammingWindow},
+		{FrameSize: 320, HopSize: 320, Window: RectangularWindow},
+	} {
+		stft, err := NewSTFT(cfg)
+		if err != nil {
+			t.Fatalf("创建STFT失败: %v", err)
+		}
+		istft, err := NewISTFT(cfg)
+		if err != nil {
+			t.Fatalf("创建ISTFT失败: %v", err)
+		}
+
+		rng := rand.New(rand.NewSource(1))
+		input := make([]float64, 4000)
+		for i := range input {
+			input[i] = rng.NormFloat64()
+		}
+		// 分块写入
+		var output []float64
+		for i := 0; i < len(input); i += 333 {
+			for _, spec := range stft.Write(input[i:min(i+333, len(input))]) {
+				output = append(output, istft.Write(spec)...)
+			}
+		}
+		output = append(output, istft.Flush()...)
+
+		hop, frame := stft.Config().HopSize, stft.Config().FrameSize
+		frames := 1 + (len(input)-frame)/hop
+		if want := (frames-1)*hop + frame; len(output) != want {
+			t.Fatalf("%+v: 应输出%d个样本, 得到%d", cfg, want, len(output))
+		}
+		// Hann窗首尾为0，输出的第一个与最后一个样本无法重建
+		start, end := 0, len(output)
+		if cfg.Window == nil {
+			start, end = 1, end-1
+		}
+		for i := start; i < end; i++ {
+			if math.Abs(output[i]-input[i]) > 1e-9 {
+				t.Fatalf("%+v: 第%d个样本应为%.6f, 得到%.6f", cfg, i, input[i], output[i])
+			}
+		}
+	}
+}
+
+// TestSTFTSpectrum 测试频谱的频点位置与配置校验
+func TestSTFTSpectrum(t *testing.T) {
+	stft, _ := NewSTFT(STFTConfig{FrameSize: 512, HopSize: 256})
+	input := make([]float64, 512)
+	for i := range input {
+		input[i] = math.Sin(2 * math.Pi * 1000 * float64(i) / 16000)
+	}
+	frames := stft.Write(input)
+	if len(frames) != 1 || len(frames[0]) != 257 {
+		t.Fatalf("应输出1帧257个频点, 得到%d帧", len(frames))
+	}
+	peak := 0
+	for k, c := range frames[0] {
+		if cmplx.Abs(c) > cmplx.Abs(frames[0][peak]) {
+			peak = k
+		}
+	}
+	if peak != 32 {
+		t.Errorf("1000Hz应位于第32个频点, 得到%d", peak)
+	}
+
+	istft, _ := NewISTFT(STFTConfig{FrameSize: 512, HopSize: 256})
+	if istft.Write(make([]complex128, 10)) != nil {
+		t.Error("频点数不符应返回nil")
+	}
+	for _, cfg := range []STFTConfig{
+		{},
+		{FrameSize: 400, HopSize: 500},
+		{FrameSize: 400, HopSize: 160, FFTSize: 256},
+		{FrameSize: 400, HopSize: 160, FFTSize: 600},
+	} {
+		if _, err := NewSTFT(cfg); err != ErrInvalidSTFTConfig {
+			t.Errorf("配置%+v应返回ErrInvalidSTFTConfig, 得到%v", cfg, err)
+		}
+	}
+}
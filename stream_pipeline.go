@@ -0,0 +1,89 @@
+package webrtcvad
+
+import "context"
+
+// stream_pipeline.go 提供基于context和通道的流式处理管线
+
+// Run 在后台goroutine中处理in中的音频块，返回已结束片段的通道和错误通道
+//
+// 每个片段结束时发送到片段通道（容量见WithSegmentBuffer）。in关闭或ctx取消时，
+// 先Flush结束最后一个片段并发送剩余片段，再关闭两个通道，因此消费者应读取
+// 片段通道直到其关闭。处理出错时错误发送到错误通道（容量为1）后同样关闭两个通道；
+// ctx取消不视为错误。
+//
+// 示例:
+//
+//	segments, errc := svad.Run(ctx, chunks)
+//	for seg := range segments {
+//	    // ...
+//	}
+//	if err := <-errc; err != nil {
+//	    // ...
+//	}
+func (s *StreamVAD) Run(ctx context.Context, in <-chan []byte) (<-chan VoiceSegment, <-chan error) {
+	out := make(chan VoiceSegment, s.segChSize)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(out)
+
+		s.mu.Lock()
+		next := s.completedCount()
+		s.mu.Unlock()
+
+		// send 发送尚未发送的已结束片段；cancellable时可被ctx取消，返回是否全部发送
+		send := func(cancellable bool) bool {
+			s.mu.Lock()
+			end := s.completedCount()
+			if next > end {
+				// 期间调用了Reset
+				next = 0
+			}
+			done := append([]VoiceSegment(nil), s.segments[next:end]...)
+			s.mu.Unlock()
+
+			for _, seg := range done {
+				if !cancellable {
+					out <- seg
+				} else {
+					select {
+					case out <- seg:
+					case <-ctx.Done():
+						return false
+					}
+				}
+				next++
+			}
+			return true
+		}
+
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case data, ok := <-in:
+				if !ok {
+					break loop
+				}
+				if _, err := s.Process(data); err != nil {
+					errc <- err
+					return
+				}
+				if !send(true) {
+					break loop
+				}
+			}
+		}
+
+		// 结束：处理残余数据，发送剩余片段
+		if err := s.Flush(); err != nil && err != ErrStreamClosed {
+			errc <- err
+			return
+		}
+		send(false)
+	}()
+
+	return out, errc
+}
@@ -0,0 +1,88 @@
+package webrtcvad
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestStreamVADRun 测试通道管线在输入关闭时的收尾
+func TestStreamVADRun(t *testing.T) {
+	svad, err := NewStreamVAD(1, 8000, 10)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	in := make(chan []byte)
+	segments, errc := svad.Run(context.Background(), in)
+
+	go func() {
+		// 95ms音频，分块大小与帧长不对齐
+		for i := 0; i < 19; i++ {
+			in <- make([]byte, 80)
+		}
+		close(in)
+	}()
+
+	var got []VoiceSegment
+	for seg := range segments {
+		got = append(got, seg)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("管线出错: %v", err)
+	}
+
+	if len(got) != len(svad.GetSegments()) || len(got) == 0 {
+		t.Fatalf("片段数错误: 期望%d, 得到%d", len(svad.GetSegments()), len(got))
+	}
+	if end := got[len(got)-1].End; end != 95*time.Millisecond {
+		t.Errorf("残余数据应被Flush: 最后一个片段结束于%v, 期望95ms", end)
+	}
+}
+
+// TestStreamVADRunCancel 测试取消时的收尾
+func TestStreamVADRunCancel(t *testing.T) {
+	svad, err := NewStreamVAD(1, 8000, 10)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan []byte)
+	segments, errc := svad.Run(ctx, in)
+
+	in <- make([]byte, 160*3+50)
+	cancel()
+
+	var got []VoiceSegment
+	for seg := range segments {
+		got = append(got, seg)
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("取消不应视为错误, 得到%v", err)
+	}
+	if svad.GetBufferSize() != 0 {
+		t.Error("取消时应Flush残余数据")
+	}
+	if len(got) == 0 || got[len(got)-1].End != svad.GetTotalDuration() {
+		t.Errorf("取消后应发送最后一个片段: %+v", got)
+	}
+}
+
+// TestStreamVADRunError 测试处理错误
+func TestStreamVADRunError(t *testing.T) {
+	svad, err := NewStreamVAD(1, 8000, 10)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	svad.Close()
+
+	in := make(chan []byte, 1)
+	in <- make([]byte, 160)
+	segments, errc := svad.Run(context.Background(), in)
+	for range segments {
+	}
+	if err := <-errc; err != ErrStreamClosed {
+		t.Errorf("应返回ErrStreamClosed, 得到%v", err)
+	}
+}
@@ -1,37 +1,127 @@
 package webrtcvad
 
 import (
-	"errors"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/godeps/webrtcvad-go/metrics"
 )
 
 // stream_vad.go 提供流式VAD处理接口
 // 自动处理缓冲和分帧，适合实时流处理场景
 
 // StreamVAD 流式VAD处理器
+//
+// 所有导出方法都可并发调用：生产者goroutine写入的同时，
+// 其他goroutine可以安全地读取片段和统计信息
 type StreamVAD struct {
+	mu sync.Mutex // 保护以下所有字段
+
 	vad        *VAD
+	detector   Detector // 替代vad的判决器（见WithAlgorithm），nil表示使用vad
 	sampleRate int
 	frameMs    int // 帧长度（毫秒）
 
-	buffer     []byte // 缓冲区
-	frameSize  int    // 单帧字节数
+	input      *ringBuffer    // 尚未凑满一帧的输入（见WithOverflowPolicy）
+	overflow   OverflowPolicy // 写入超出input容量时的处理方式
+	frame      []byte         // 当前帧，从input中取出
+	frameSize  int            // 单帧字节数
 	segments   []VoiceSegment
 	totalBytes int64 // 已处理的总字节数
+
+	// 片段平滑（见WithMinSpeechDuration / WithMinSilenceDuration）
+	minSpeech     time.Duration // 语音至少持续该时长才开始语音片段
+	minSilence    time.Duration // 静音至少持续该时长才结束语音片段
+	state         bool          // 当前已确认的状态（true=语音）
+	pendingFrames int           // 与当前状态相反、尚未确认的连续帧数
+	pendingStart  time.Duration // 待确认帧的开始时间
+	enterFrames   int           // 连续多少个语音帧才进入语音（见WithHysteresis）
+	exitFrames    int           // 连续多少个静音帧才退出语音
+
+	// 音频捕获（见WithCaptureAudio）
+	captureAudio bool
+	maxCapture   int    // 单个片段最多保留的字节数
+	staging      []byte // 尚未归属到片段的帧数据
+
+	closed        bool // 已调用Close
+	segmentClosed bool // 最后一个片段已被Flush结束
+
+	// 片段通道（见Segments）
+	emitMu    sync.Mutex // 保证片段按顺序发送，在s.mu之后获取
+	segCh     chan VoiceSegment
+	segChSize int
+	emitted   int // 已发送到通道的片段数
+
+	// 超长片段切分（见WithMaxSegmentDuration）
+	maxSegment  time.Duration
+	energies    []int64 // 最近各帧能量的环形缓冲（回看窗口）
+	energyCount int     // 已记录的帧数
+
+	// 绝对时间戳（见WithClockFunc）
+	clock func() time.Time
+	epoch time.Time // 流中第一个样本的采集时间
+
+	// 检测前的预处理（见WithHighPass、WithDenoise、WithGainControl）
+	preprocessors []preprocessor
+	samples       []int16 // 预处理的样本缓冲
+	processed     []byte  // 预处理后的帧
+
+	stats   Stats            // 逐帧判决统计（见Stats）
+	metrics metrics.Recorder // 逐帧指标（见WithMetrics），nil表示不上报
+	timer   *frameTimer      // 逐帧耗时统计（见WithStreamTiming），nil表示关闭
+
+	logger *slog.Logger // 告警日志（见WithStreamLogger），nil表示不输出
+	clip   clipDetector
+
+	smoother *Smoother // 判决平滑（见WithSmoother），nil表示不平滑
+
+	pendingMode atomic.Int32 // SetMode请求的模式加1，0表示没有待生效的修改
+	thresholds  *[2][3]int16 // WithStreamThresholds的设置，SetMode后重新应用
+	hangover    *[2][3]int16 // WithStreamHangover的设置，SetMode后重新应用
+
+	onFrame func(start time.Duration, isSpeech bool) // 每帧并入片段后调用（见Pipe），调用时持有s.mu
 }
 
+// preprocessor 检测前逐帧就地处理样本的有状态模块
+type preprocessor interface {
+	Process(frame []int16) error
+	Reset()
+}
+
+// defaultSegmentBuffer Segments通道的默认容量
+const defaultSegmentBuffer = 16
+
+// defaultSegmentCapacity 片段列表的默认初始容量
+const defaultSegmentCapacity = 100
+
 // VoiceSegment 语音片段
 type VoiceSegment struct {
 	Start    time.Duration // 开始时间
 	End      time.Duration // 结束时间
 	IsSpeech bool          // 是否为语音
+
+	// SampleRate 采样率（Hz），即Audio的采样率
+	SampleRate int
+
+	// Audio 语音片段的PCM数据（16位，小端序），仅在启用WithCaptureAudio时填充，
+	// 超过上限的部分被截断。Process返回的新片段只含开始时的数据，完整数据见GetSegments
+	Audio []byte
+
+	// StartTime / EndTime 片段的绝对起止时间，仅在设置WithClockFunc时填充
+	StartTime time.Time
+	EndTime   time.Time
 }
 
 // NewStreamVAD 创建流式VAD处理器
 //
 // 参数:
 //   - mode: VAD模式（0-3）
-//   - sampleRate: 采样率（8000, 16000, 32000, 48000）
+//   - sampleRate: 采样率（8000, 16000, 24000, 32000, 48000）
 //   - frameMs: 帧长度（毫秒，10/20/30）
 //
 // 返回:
@@ -40,10 +130,10 @@ type VoiceSegment struct {
 func NewStreamVAD(mode int, sampleRate int, frameMs int) (*StreamVAD, error) {
 	// 验证参数
 	if !isValidSampleRate(sampleRate) {
-		return nil, errors.New("invalid sample rate")
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidSampleRate, sampleRate)
 	}
 	if frameMs != 10 && frameMs != 20 && frameMs != 30 {
-		return nil, errors.New("frame length must be 10, 20, or 30 ms")
+		return nil, fmt.Errorf("%w, got %d ms", ErrInvalidFrameLength, frameMs)
 	}
 
 	// 创建VAD实例
@@ -59,14 +149,37 @@ func NewStreamVAD(mode int, sampleRate int, frameMs int) (*StreamVAD, error) {
 		vad:        vad,
 		sampleRate: sampleRate,
 		frameMs:    frameMs,
-		buffer:     make([]byte, 0, frameSize*2),
+		input:      newRingBuffer(sampleRate * 2 * defaultBufferSeconds),
+		frame:      make([]byte, frameSize),
 		frameSize:  frameSize,
-		segments:   make([]VoiceSegment, 0, 100),
+		segments:   make([]VoiceSegment, 0, defaultSegmentCapacity),
 		totalBytes: 0,
+		segChSize:  defaultSegmentBuffer,
+
+		enterFrames: 1,
+		exitFrames:  1,
 	}, nil
 }
 
-// Write 写入音频数据，返回新检测到的语音片段
+// Write 写入音频数据，实现io.Writer
+//
+// 检测到的片段通过GetSegments获取；需要逐次获取新片段时使用Process。
+// 配合Close可直接用于io.Copy(svad, src)
+//
+// 参数:
+//   - data: 音频数据（16位PCM，小端序）
+//
+// 返回:
+//   - int: 接受的字节数（成功时为len(data)）
+//   - error: 错误信息
+func (s *StreamVAD) Write(data []byte) (int, error) {
+	if _, err := s.Process(data); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Process 写入音频数据，返回新检测到的语音片段
 //
 // 参数:
 //   - data: 音频数据（16位PCM，小端序）
@@ -74,18 +187,57 @@ func NewStreamVAD(mode int, sampleRate int, frameMs int) (*StreamVAD, error) {
 // 返回:
 //   - []VoiceSegment: 新检测到的语音片段
 //   - error: 错误信息
-func (s *StreamVAD) Write(data []byte) ([]VoiceSegment, error) {
-	// 将数据添加到缓冲区
-	s.buffer = append(s.buffer, data...)
+func (s *StreamVAD) Process(data []byte) ([]VoiceSegment, error) {
+	s.mu.Lock()
+	defer s.unlockAndEmit()
+	return s.process(data)
+}
 
-	var newSegments []VoiceSegment
+// process Process的实现，调用方须持有s.mu
+func (s *StreamVAD) process(data []byte) ([]VoiceSegment, error) {
+	if s.closed {
+		if s.logger != nil {
+			s.logger.Warn("write after close, audio dropped", "bytes", len(data))
+		}
+		return nil, ErrStreamClosed
+	}
 
-	// 处理所有完整的帧
-	for len(s.buffer) >= s.frameSize {
-		frame := s.buffer[:s.frameSize]
+	// 首次写入时对齐时钟：这批数据刚采集完，第一个样本早于当前时间一个数据时长
+	if s.clock != nil && s.epoch.IsZero() && len(data) > 0 {
+		s.epoch = s.clock().Add(-s.bytesToDuration(int64(len(data))))
+	}
+
+	// 缓冲中只有上次写入不足一帧的残余，容量即单次写入的上限
+	if excess := s.input.size() + len(data) - s.input.capacity(); excess > 0 {
+		switch s.overflow {
+		case OverflowError:
+			return nil, ErrBufferOverflow
+		case OverflowDropOldest:
+			// 先丢弃缓冲的数据，再丢弃data的开头；按样本对齐
+			excess += excess & 1
+			buffered := min(excess, s.input.size())
+			s.input.discard(buffered)
+			data = data[min(excess-buffered, len(data)):]
+			s.totalBytes += int64(excess)
+			if s.logger != nil {
+				s.logger.Warn("input buffer overflow, audio dropped", "at", s.bytesToDuration(s.totalBytes), "bytes", excess)
+			}
+		}
+	}
+
+	var newSegments []VoiceSegment
+	for {
+		// 将数据写入缓冲区，容量不足时分块进行
+		n := s.input.write(data)
+		data = data[n:]
+		if s.input.size() < s.frameSize {
+			break
+		}
+		frame := s.frame
+		s.input.read(frame)
 
 		// 检测当前帧
-		isSpeech, err := s.vad.IsSpeech(frame, s.sampleRate)
+		isSpeech, err := s.detect(frame)
 		if err != nil {
 			return nil, err
 		}
@@ -95,56 +247,562 @@ func (s *StreamVAD) Write(data []byte) ([]VoiceSegment, error) {
 		s.totalBytes += int64(s.frameSize)
 		endTime := s.bytesToDuration(s.totalBytes)
 
-		// 创建片段
-		segment := VoiceSegment{
-			Start:    startTime,
-			End:      endTime,
-			IsSpeech: isSpeech,
-		}
-
-		// 合并连续的相同类型片段
-		if len(s.segments) > 0 {
-			lastSegment := &s.segments[len(s.segments)-1]
-			if lastSegment.IsSpeech == isSpeech {
-				// 扩展最后一个片段
-				lastSegment.End = endTime
-			} else {
-				// 添加新片段
-				s.segments = append(s.segments, segment)
-				newSegments = append(newSegments, segment)
+		if segment := s.pushFrame(frame, isSpeech, startTime, endTime); segment != nil {
+			newSegments = append(newSegments, *segment)
+		}
+		if s.onFrame != nil {
+			s.onFrame(startTime, isSpeech)
+		}
+	}
+
+	return newSegments, nil
+}
+
+// Flush 处理残余数据并结束当前片段，流可以继续写入
+//
+// 未凑满一帧的残余数据补零后参与检测（时间戳按实际数据计算），
+// 尚未确认的待定帧并入当前片段。之后写入的数据总是开始新的片段
+func (s *StreamVAD) Flush() error {
+	s.mu.Lock()
+	defer s.unlockAndEmit()
+
+	if s.closed {
+		return ErrStreamClosed
+	}
+	return s.flush()
+}
+
+// flush Flush的实现，调用方须持有s.mu
+func (s *StreamVAD) flush() error {
+	if residual := s.input.size(); residual > 0 {
+		frame := s.frame
+		clear(frame)
+		s.input.read(frame)
+		isSpeech, err := s.detect(frame)
+		if err != nil {
+			return err
+		}
+
+		startTime := s.bytesToDuration(s.totalBytes)
+		s.totalBytes += int64(residual)
+		endTime := s.bytesToDuration(s.totalBytes)
+		s.pushFrame(frame[:residual], isSpeech, startTime, endTime)
+		if s.onFrame != nil {
+			s.onFrame(startTime, isSpeech)
+		}
+	}
+
+	// 待确认帧无法再满足门限，视为毛刺
+	if s.pendingFrames > 0 {
+		s.pendingFrames = 0
+		s.extendSegment(s.state, s.pendingStart, s.bytesToDuration(s.totalBytes))
+	}
+
+	s.segmentClosed = len(s.segments) > 0
+	return nil
+}
+
+// Close 结束音频流，实现io.Closer
+//
+// 与Flush相同地处理残余数据和最后一个片段，并关闭Segments返回的通道。
+// 之后的Write返回ErrStreamClosed，Reset可重新打开
+func (s *StreamVAD) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	err := s.flush()
+
+	ch := s.segCh
+	s.unlockAndEmit()
+	if ch != nil {
+		// 等待进行中的发送完成后再关闭通道
+		s.emitMu.Lock()
+		close(ch)
+		s.emitMu.Unlock()
+	}
+	return err
+}
+
+// ProcessReader 从r持续读取音频直到EOF，每个片段结束时调用fn
+//
+// 与Process不同，fn收到的是已结束的完整片段（End及Audio均为最终值）；
+// 读到EOF时先Flush，最后一个片段也随之结束。fn返回错误时停止读取并返回该错误。
+//
+// 参数:
+//   - r: 音频来源（16位PCM，小端序），如文件、net.Conn或进程管道
+//   - fn: 片段回调
+//
+// 返回:
+//   - error: 读取、检测或回调的错误（正常读到EOF时为nil）
+func (s *StreamVAD) ProcessReader(r io.Reader, fn func(VoiceSegment) error) error {
+	// 调用前已结束的片段已由之前的调用或Flush交付，从第一个未结束的片段开始发送
+	s.mu.Lock()
+	next := s.completedCount()
+	s.mu.Unlock()
+
+	buf := make([]byte, s.frameSize*16)
+	var done []VoiceSegment
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			// 回调在锁外执行，fn中可以调用StreamVAD的其他方法
+			s.mu.Lock()
+			_, err := s.process(buf[:n])
+			done = done[:0]
+			for end := s.completedCount(); next < end; next++ {
+				done = append(done, s.segments[next])
+			}
+			s.unlockAndEmit()
+			if err != nil {
+				return err
+			}
+
+			for _, seg := range done {
+				if err := fn(seg); err != nil {
+					return err
+				}
 			}
-		} else {
-			// 第一个片段
-			s.segments = append(s.segments, segment)
-			newSegments = append(newSegments, segment)
 		}
 
-		// 移除已处理的帧
-		s.buffer = s.buffer[s.frameSize:]
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
 	}
 
-	return newSegments, nil
+	s.mu.Lock()
+	if err := s.flush(); err != nil {
+		s.unlockAndEmit()
+		return err
+	}
+	// 残余帧与待确认帧可能结束当前片段并开始新的片段，全部交付
+	done = done[:0]
+	for ; next < len(s.segments); next++ {
+		done = append(done, s.segments[next])
+	}
+	s.unlockAndEmit()
+
+	for _, seg := range done {
+		if err := fn(seg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Segments 返回已结束片段的通道
+//
+// 首次调用时创建通道（容量见WithSegmentBuffer），此后每个结束的片段按顺序发送一次；
+// 首次调用前已结束的片段不会发送。通道满时Write阻塞直到消费者取走片段，
+// Close后通道关闭，因此可以在单独的goroutine中：
+//
+//	for seg := range svad.Segments() {
+//	    // ...
+//	}
+func (s *StreamVAD) Segments() <-chan VoiceSegment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.segCh == nil {
+		s.segCh = make(chan VoiceSegment, s.segChSize)
+		s.emitted = s.completedCount()
+		if s.closed {
+			close(s.segCh)
+		}
+	}
+	return s.segCh
+}
+
+// completedCount 已结束的片段数，调用方须持有s.mu
+func (s *StreamVAD) completedCount() int {
+	if s.segmentClosed || len(s.segments) == 0 {
+		return len(s.segments)
+	}
+	return len(s.segments) - 1
+}
+
+// unlockAndEmit 取出尚未发送的已结束片段，释放s.mu后发送到片段通道
+//
+// 发送可能因通道满而阻塞，因此在锁外进行，其间其他goroutine仍可读取片段；
+// emitMu保证多次发送之间的顺序
+func (s *StreamVAD) unlockAndEmit() {
+	ch := s.segCh
+	end := s.completedCount()
+	if ch == nil || s.emitted >= end {
+		s.mu.Unlock()
+		return
+	}
+	done := append([]VoiceSegment(nil), s.segments[s.emitted:end]...)
+	s.emitted = end
+
+	s.emitMu.Lock()
+	s.mu.Unlock()
+	for _, seg := range done {
+		ch <- seg
+	}
+	s.emitMu.Unlock()
+}
+
+// pushFrame 将一帧的判决并入片段列表，必要时切分超长的语音片段
+//
+// 返回新开始的片段（没有新片段时为nil）
+func (s *StreamVAD) pushFrame(frame []byte, isSpeech bool, start, end time.Duration) *VoiceSegment {
+	started := s.smoothFrame(frame, isSpeech, start, end)
+	if s.maxSegment > 0 {
+		s.recordEnergy(frame)
+		if split := s.splitLongSegment(end); split != nil {
+			return split
+		}
+	}
+	return started
+}
+
+// smoothFrame 按平滑规则将一帧的判决并入片段列表
+//
+// 与当前状态相反的帧先作为待确认帧累积，持续时长达到门限（语音为minSpeech，
+// 静音为minSilence）且连续帧数达到迟滞帧数（enterFrames / exitFrames）才切换状态；门限内出现与当前状态相同的帧时，
+// 待确认帧视为毛刺并入当前片段。门限均为0时每帧立即生效。
+//
+// 返回新开始的片段（没有新片段时为nil）
+func (s *StreamVAD) smoothFrame(frame []byte, isSpeech bool, start, end time.Duration) *VoiceSegment {
+	if s.captureAudio {
+		s.staging = append(s.staging, frame...)
+	}
+
+	if isSpeech == s.state {
+		if s.pendingFrames > 0 {
+			start = s.pendingStart
+			s.pendingFrames = 0
+		}
+		return s.extendSegment(isSpeech, start, end)
+	}
+
+	if s.pendingFrames == 0 {
+		s.pendingStart = start
+	}
+	s.pendingFrames++
+
+	threshold, frames := s.minSilence, s.exitFrames
+	if isSpeech {
+		threshold, frames = s.minSpeech, s.enterFrames
+	}
+	if end-s.pendingStart < threshold || s.pendingFrames < frames {
+		return nil
+	}
+
+	s.state = isSpeech
+	s.pendingFrames = 0
+	return s.extendSegment(isSpeech, s.pendingStart, end)
+}
+
+// recordEnergy 记录一帧的能量，供切分超长片段时回看
+func (s *StreamVAD) recordEnergy(frame []byte) {
+	var energy int64
+	for i := 0; i+1 < len(frame); i += 2 {
+		v := int64(int16(binary.LittleEndian.Uint16(frame[i:])))
+		energy += v * v
+	}
+	s.energies[s.energyCount%len(s.energies)] = energy
+	s.energyCount++
+}
+
+// splitLongSegment 语音片段达到maxSegment时将其切分
+//
+// 在回看窗口内选择能量最低的帧，在该帧结束处切分：之前的部分作为已结束的片段，
+// 之后的部分开始新的语音片段。最低能量帧即当前帧时，直接结束当前片段。
+//
+// 返回切分出的新片段（未切分时为nil）
+func (s *StreamVAD) splitLongSegment(end time.Duration) *VoiceSegment {
+	if len(s.segments) == 0 || s.segmentClosed {
+		return nil
+	}
+	last := &s.segments[len(s.segments)-1]
+	if !last.IsSpeech || last.End != end || last.End-last.Start < s.maxSegment {
+		return nil
+	}
+
+	// 回看窗口不超出当前片段，且至少保留片段的第一帧
+	frameDur := time.Duration(s.frameMs) * time.Millisecond
+	window := min(min(len(s.energies), s.energyCount), int((last.End-last.Start)/frameDur)-1)
+	best := 0 // 距当前帧的帧数，0为当前帧
+	for i := 1; i < window; i++ {
+		if s.energies[(s.energyCount-1-i)%len(s.energies)] < s.energies[(s.energyCount-1-best)%len(s.energies)] {
+			best = i
+		}
+	}
+
+	if best == 0 {
+		s.segmentClosed = true
+		return nil
+	}
+
+	at := end - time.Duration(best)*frameDur
+	split := VoiceSegment{
+		Start:      at,
+		End:        last.End,
+		IsSpeech:   true,
+		SampleRate: s.sampleRate,
+		EndTime:    last.EndTime,
+	}
+	if s.clock != nil {
+		split.StartTime = s.epoch.Add(at)
+		last.EndTime = split.StartTime
+	}
+	if offset := s.durationToBytes(at - last.Start); offset < len(last.Audio) {
+		split.Audio = append([]byte(nil), last.Audio[offset:]...)
+		last.Audio = last.Audio[:offset:offset]
+	}
+	last.End = at
+
+	s.segments = append(s.segments, split)
+	return &split
+}
+
+// extendSegment 将[start, end)并入最后一个同类型片段，类型不同时开始新片段
+//
+// 暂存的帧数据随之归属到该片段。返回新开始的片段（扩展已有片段时为nil）
+func (s *StreamVAD) extendSegment(isSpeech bool, start, end time.Duration) *VoiceSegment {
+	started := true
+
+	// 合并连续的相同类型片段（Flush结束的片段不再扩展）
+	if len(s.segments) > 0 && !s.segmentClosed && s.segments[len(s.segments)-1].IsSpeech == isSpeech {
+		// 扩展最后一个片段
+		s.segments[len(s.segments)-1].End = end
+		if s.clock != nil {
+			s.segments[len(s.segments)-1].EndTime = s.epoch.Add(end)
+		}
+		started = false
+	} else {
+		// 添加新片段
+		s.segmentClosed = false
+		segment := VoiceSegment{
+			Start:      start,
+			End:        end,
+			IsSpeech:   isSpeech,
+			SampleRate: s.sampleRate,
+		}
+		if s.clock != nil {
+			segment.StartTime = s.epoch.Add(start)
+			segment.EndTime = s.epoch.Add(end)
+		}
+		s.segments = append(s.segments, segment)
+	}
+
+	lastSegment := &s.segments[len(s.segments)-1]
+	if s.captureAudio {
+		if isSpeech {
+			n := min(len(s.staging), s.maxCapture-len(lastSegment.Audio))
+			lastSegment.Audio = append(lastSegment.Audio, s.staging[:n]...)
+		}
+		s.staging = s.staging[:0]
+	}
+
+	if !started {
+		return nil
+	}
+	segment := *lastSegment
+	return &segment
 }
 
 // GetSegments 获取所有语音片段
+//
+// 返回片段列表的快照，后续写入不会修改已返回的切片
 func (s *StreamVAD) GetSegments() []VoiceSegment {
-	return s.segments
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]VoiceSegment(nil), s.segments...)
+}
+
+// detect 预处理后检测一帧并计入统计与指标，返回平滑后的判决，frame本身不被修改
+func (s *StreamVAD) detect(frame []byte) (bool, error) {
+	if s.pendingMode.Load() != 0 {
+		s.applyMode()
+	}
+	var begin time.Time
+	if s.metrics != nil || s.timer != nil {
+		begin = time.Now()
+	}
+	isSpeech, err := s.detectFrame(frame)
+	if err != nil {
+		return false, err
+	}
+	var took time.Duration
+	if !begin.IsZero() {
+		took = time.Since(begin)
+	}
+	if s.timer != nil {
+		s.timer.observe(took, s.bytesToDuration(int64(len(frame))))
+	}
+	s.stats.record(isSpeech)
+	if s.logger != nil {
+		if clipped, started := s.clip.check(frame); started {
+			s.logger.Warn("input clipped", "at", s.bytesToDuration(s.totalBytes), "samples", clipped)
+		}
+	}
+	if s.metrics != nil {
+		s.metrics.ObserveLatency(took)
+		s.metrics.IncFrames(isSpeech)
+	}
+	if s.smoother != nil {
+		isSpeech = s.smoother.Update(isSpeech)
+	}
+	return isSpeech, nil
+}
+
+// SetMode 修改激进度模式（0-3），在下一个帧边界生效
+//
+// 不等待进行中的Process：正在处理的数据从下一帧起即使用新模式。片段历史、统计与
+// 自适应状态（噪声估计等）均保留，之后的Reset也保留新模式。WithStreamThresholds与
+// WithStreamHangover设置的参数不随模式改变，新模式只替换其余的预设。适合检测到环境噪声变化时
+// 调整激进度而不必重建流。
+//
+// 返回:
+//   - error: 模式无效时返回ErrInvalidMode；检测器不支持模式（如WithInference的外部模型）时
+//     返回ErrModeUnsupported
+func (s *StreamVAD) SetMode(mode int) error {
+	if mode < 0 || mode > 3 {
+		return ErrInvalidMode
+	}
+	if s.detector != nil {
+		if _, ok := s.detector.(modeSetter); !ok {
+			return ErrModeUnsupported
+		}
+	}
+	s.pendingMode.Store(int32(mode) + 1)
+	return nil
+}
+
+// applyMode 应用SetMode请求的模式，调用方须持有s.mu
+func (s *StreamVAD) applyMode() {
+	mode := int(s.pendingMode.Swap(0)) - 1
+	if mode < 0 {
+		return
+	}
+	var d modeSetter = s.vad
+	if s.detector != nil {
+		d = s.detector.(modeSetter)
+	}
+	d.SetMode(mode)
+	s.applyOverrides()
+	if s.logger != nil {
+		s.logger.Info("mode changed", "at", s.bytesToDuration(s.totalBytes), "mode", mode)
+	}
+}
+
+// applyOverrides 把WithStreamThresholds与WithStreamHangover的设置应用到GMM检测器
+func (s *StreamVAD) applyOverrides() {
+	gmm := s.gmm()
+	if gmm == nil {
+		return
+	}
+	if s.thresholds != nil {
+		gmm.inst.individual, gmm.inst.total = s.thresholds[0], s.thresholds[1]
+	}
+	if s.hangover != nil {
+		gmm.inst.overHangMax1, gmm.inst.overHangMax2 = s.hangover[0], s.hangover[1]
+	}
+}
+
+// gmm 返回检测所用的GMM检测器（GMM或组合检测），其他算法返回nil
+func (s *StreamVAD) gmm() *VAD {
+	switch d := s.detector.(type) {
+	case nil:
+		return s.vad
+	case *HybridVAD:
+		return d.gmm
+	default:
+		return nil
+	}
+}
+
+// detectFrame 预处理后检测一帧
+func (s *StreamVAD) detectFrame(frame []byte) (bool, error) {
+	var d Detector = s.vad
+	if s.detector != nil {
+		d = s.detector
+	}
+	if len(s.preprocessors) == 0 {
+		return d.IsSpeech(frame, s.sampleRate)
+	}
+
+	n := len(frame) / 2
+	if cap(s.samples) < n {
+		s.samples = make([]int16, n)
+		s.processed = make([]byte, len(frame))
+	}
+	samples := s.samples[:n]
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(frame[i*2:]))
+	}
+	for _, p := range s.preprocessors {
+		if err := p.Process(samples); err != nil {
+			return false, err
+		}
+	}
+	processed := s.processed[:len(frame)]
+	for i, v := range samples {
+		binary.LittleEndian.PutUint16(processed[i*2:], uint16(v))
+	}
+	return d.IsSpeech(processed, s.sampleRate)
 }
 
 // Reset 重置流式VAD状态
 func (s *StreamVAD) Reset() error {
-	s.buffer = s.buffer[:0]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.input.reset()
 	s.segments = s.segments[:0]
 	s.totalBytes = 0
+	s.state = false
+	s.pendingFrames = 0
+	s.staging = s.staging[:0]
+	s.segmentClosed = false
+	s.emitted = 0
+	s.epoch = time.Time{}
+	s.energyCount = 0
+	s.stats = Stats{}
+	if s.timer != nil {
+		*s.timer = frameTimer{}
+	}
+	s.clip = clipDetector{}
+	if s.smoother != nil {
+		s.smoother.Reset()
+	}
+	for _, p := range s.preprocessors {
+		p.Reset()
+	}
+	if r, ok := s.detector.(resetter); ok {
+		r.Reset()
+	}
+	if s.closed {
+		// 旧通道已关闭，下次调用Segments时重新创建
+		s.segCh = nil
+		s.closed = false
+	}
 
-	// 重新初始化VAD实例
-	if err := initCore(s.vad.inst); err != nil {
+	// 重新初始化VAD实例，保留当前的阈值与迟滞设置
+	if err := reinitCore(s.vad.inst); err != nil {
 		return err
 	}
 
+	if s.logger != nil {
+		s.logger.Info("stream reset")
+	}
 	return nil
 }
 
+// durationToBytes 将时长转换为字节数（按样本对齐）
+func (s *StreamVAD) durationToBytes(d time.Duration) int {
+	samples := (int64(d)*int64(s.sampleRate) + int64(time.Second)/2) / int64(time.Second)
+	return int(samples) * 2
+}
+
 // bytesToDuration 将字节数转换为时长
 func (s *StreamVAD) bytesToDuration(bytes int64) time.Duration {
 	// 字节 -> 样本 -> 秒 -> Duration
@@ -155,21 +813,35 @@ func (s *StreamVAD) bytesToDuration(bytes int64) time.Duration {
 
 // GetBufferSize 获取当前缓冲区大小（字节）
 func (s *StreamVAD) GetBufferSize() int {
-	return len(s.buffer)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.input.size()
 }
 
 // GetTotalProcessed 获取已处理的总字节数
 func (s *StreamVAD) GetTotalProcessed() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.totalBytes
 }
 
 // GetTotalDuration 获取已处理的总时长
 func (s *StreamVAD) GetTotalDuration() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.bytesToDuration(s.totalBytes)
 }
 
+// GetSampleRate 获取采样率（Hz）
+func (s *StreamVAD) GetSampleRate() int {
+	return s.sampleRate
+}
+
 // FilterSpeechSegments 过滤出语音片段
 func (s *StreamVAD) FilterSpeechSegments() []VoiceSegment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	var speech []VoiceSegment
 	for _, seg := range s.segments {
 		if seg.IsSpeech {
@@ -181,6 +853,9 @@ func (s *StreamVAD) FilterSpeechSegments() []VoiceSegment {
 
 // FilterSilenceSegments 过滤出静音片段
 func (s *StreamVAD) FilterSilenceSegments() []VoiceSegment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	var silence []VoiceSegment
 	for _, seg := range s.segments {
 		if !seg.IsSpeech {
@@ -189,3 +864,39 @@ func (s *StreamVAD) FilterSilenceSegments() []VoiceSegment {
 	}
 	return silence
 }
+
+// MergeSegments 合并间隔小于maxGap的相邻语音片段
+//
+// 输入中的静音片段被忽略，返回按时间排列的话语级语音片段。
+// 合并后的Audio按顺序拼接（不含间隙部分），绝对时间取首尾片段的值。
+// 流式处理时可使用WithMergeGap实时完成同样的合并
+//
+// 参数:
+//   - segs: 按时间排列的片段（如GetSegments或FilterSpeechSegments的结果）
+//   - maxGap: 最大合并间隔
+//
+// 返回:
+//   - []VoiceSegment: 合并后的语音片段
+func MergeSegments(segs []VoiceSegment, maxGap time.Duration) []VoiceSegment {
+	var merged []VoiceSegment
+	for _, seg := range segs {
+		if !seg.IsSpeech {
+			continue
+		}
+
+		if n := len(merged); n > 0 && seg.Start-merged[n-1].End < maxGap {
+			last := &merged[n-1]
+			last.End = seg.End
+			last.EndTime = seg.EndTime
+			if len(seg.Audio) > 0 {
+				last.Audio = append(last.Audio, seg.Audio...)
+			}
+			continue
+		}
+
+		// 复制音频，避免后续拼接修改调用方的数据
+		seg.Audio = append([]byte(nil), seg.Audio...)
+		merged = append(merged, seg)
+	}
+	return merged
+}
@@ -1,8 +1,16 @@
 package webrtcvad
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
 	"testing"
+	"testing/iotest"
 	"time"
+
+	"github.com/godeps/webrtcvad-go/internal/testaudio"
 )
 
 // TestStreamVADCreation 测试StreamVAD创建
@@ -41,7 +49,7 @@ func TestStreamVADWrite(t *testing.T) {
 	audioData := make([]byte, frameSize*3) // 3帧
 
 	// 写入音频
-	segments, err := svad.Write(audioData)
+	segments, err := svad.Process(audioData)
 	if err != nil {
 		t.Fatalf("写入音频失败: %v", err)
 	}
@@ -69,7 +77,7 @@ func TestStreamVADBuffering(t *testing.T) {
 	partialFrame := make([]byte, frameSize/2) // 半帧
 
 	// 写入半帧
-	segments, err := svad.Write(partialFrame)
+	segments, err := svad.Process(partialFrame)
 	if err != nil {
 		t.Fatalf("写入音频失败: %v", err)
 	}
@@ -85,7 +93,7 @@ func TestStreamVADBuffering(t *testing.T) {
 	}
 
 	// 再写入半帧，凑成完整帧
-	segments, err = svad.Write(partialFrame)
+	segments, err = svad.Process(partialFrame)
 	if err != nil {
 		t.Fatalf("写入音频失败: %v", err)
 	}
@@ -127,6 +135,56 @@ func TestStreamVADReset(t *testing.T) {
 	if len(svad.GetSegments()) != 0 {
 		t.Error("重置后片段列表应为空")
 	}
+	if svad.vad.inst.total != kGlobalThresholdAGG {
+		t.Errorf("重置后应保留模式2的阈值, 得到%v", svad.vad.inst.total)
+	}
+}
+
+// TestStreamVADSetMode 测试运行时修改模式
+func TestStreamVADSetMode(t *testing.T) {
+	svad, err := NewStreamVAD(0, 16000, 10)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	pcm := harmonicNoise(16000, time.Second, 300*time.Millisecond, 600*time.Millisecond)
+	svad.Process(pcm)
+	segments := svad.GetSegments()
+
+	if err := svad.SetMode(3); err != nil {
+		t.Fatalf("SetMode失败: %v", err)
+	}
+	if svad.vad.inst.total != kGlobalThresholdQ {
+		t.Error("新模式应在下一帧才生效")
+	}
+	svad.Process(make([]byte, 320))
+	if svad.vad.inst.total != kGlobalThresholdVAG {
+		t.Errorf("下一帧应使用模式3的阈值, 得到%v", svad.vad.inst.total)
+	}
+	if got := svad.GetSegments(); len(got) < len(segments) || got[0].Start != segments[0].Start {
+		t.Error("修改模式不应丢失片段历史")
+	}
+	svad.Reset()
+	if svad.vad.inst.total != kGlobalThresholdVAG {
+		t.Errorf("Reset应保留修改后的模式, 得到%v", svad.vad.inst.total)
+	}
+
+	// 其他算法的检测器同样支持
+	entropy, _ := NewStreamVADWithOptions(WithAlgorithm(Entropy), WithStreamMode(0))
+	if err := entropy.SetMode(2); err != nil {
+		t.Fatalf("SetMode失败: %v", err)
+	}
+	entropy.Process(make([]byte, 640))
+	if margin := entropy.detector.(*EntropyVAD).margin; margin != entropyMargins[2] {
+		t.Errorf("谱熵裕量应为模式2的%g, 得到%g", entropyMargins[2], margin)
+	}
+
+	if err := svad.SetMode(4); err != ErrInvalidMode {
+		t.Errorf("无效模式应返回ErrInvalidMode, 得到%v", err)
+	}
+	model, _ := NewStreamVADWithOptions(WithInference(func([]float32) float32 { return 0 }, SileroConfig(16000)))
+	if err := model.SetMode(1); err != ErrModeUnsupported {
+		t.Errorf("外部模型应返回ErrModeUnsupported, 得到%v", err)
+	}
 }
 
 // TestStreamVADSegmentFiltering 测试片段过滤
@@ -191,6 +249,585 @@ func TestVoiceSegmentDuration(t *testing.T) {
 	}
 }
 
+// pushPattern 按10ms一帧将判决序列（'1'=语音，'0'=静音）送入片段状态机
+func pushPattern(s *StreamVAD, pattern string) {
+	frame := 10 * time.Millisecond
+	start := s.bytesToDuration(s.totalBytes)
+	for i, c := range pattern {
+		s.pushFrame(nil, c == '1', start+time.Duration(i)*frame, start+time.Duration(i+1)*frame)
+	}
+	s.totalBytes += int64(len(pattern) * s.frameSize)
+}
+
+// TestStreamVADMinDurations 测试最短语音/静音时长平滑
+func TestStreamVADMinDurations(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name       string
+		minSpeech  time.Duration
+		minSilence time.Duration
+		pattern    string
+		want       []VoiceSegment
+	}{
+		{
+			name:    "无平滑",
+			pattern: "0010011",
+			want: []VoiceSegment{
+				{Start: 0, End: 20 * ms},
+				{Start: 20 * ms, End: 30 * ms, IsSpeech: true},
+				{Start: 30 * ms, End: 50 * ms},
+				{Start: 50 * ms, End: 70 * ms, IsSpeech: true},
+			},
+		},
+		{
+			name:      "抑制短语音",
+			minSpeech: 30 * ms,
+			pattern:   "0010001110",
+			want: []VoiceSegment{
+				{Start: 0, End: 60 * ms},
+				{Start: 60 * ms, End: 90 * ms, IsSpeech: true},
+				{Start: 90 * ms, End: 100 * ms},
+			},
+		},
+		{
+			name:       "桥接短静音",
+			minSilence: 30 * ms,
+			pattern:    "1100111000",
+			want: []VoiceSegment{
+				{Start: 0, End: 70 * ms, IsSpeech: true},
+				{Start: 70 * ms, End: 100 * ms},
+			},
+		},
+		{
+			name:      "开头的短语音",
+			minSpeech: 20 * ms,
+			pattern:   "1000",
+			want: []VoiceSegment{
+				{Start: 0, End: 40 * ms},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svad, err := NewStreamVADWithOptions(
+				WithSampleRate(8000),
+				WithFrameDuration(10),
+				WithMinSpeechDuration(tt.minSpeech),
+				WithMinSilenceDuration(tt.minSilence),
+			)
+			if err != nil {
+				t.Fatalf("创建StreamVAD失败: %v", err)
+			}
+
+			pushPattern(svad, tt.pattern)
+			got := svad.GetSegments()
+			if len(got) != len(tt.want) {
+				t.Fatalf("片段数错误: 期望%v, 得到%v", tt.want, got)
+			}
+			for i := range got {
+				if got[i].Start != tt.want[i].Start || got[i].End != tt.want[i].End ||
+					got[i].IsSpeech != tt.want[i].IsSpeech {
+					t.Errorf("片段%d错误: 期望%+v, 得到%+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+
+	if _, err := NewStreamVADWithOptions(WithMinSpeechDuration(-ms)); err != ErrInvalidDuration {
+		t.Errorf("负的最短语音时长应返回ErrInvalidDuration, 得到%v", err)
+	}
+	if _, err := NewStreamVADWithOptions(WithMinSilenceDuration(-ms)); err != ErrInvalidDuration {
+		t.Errorf("负的最短静音时长应返回ErrInvalidDuration, 得到%v", err)
+	}
+}
+
+// TestStreamVADHysteresis 测试迟滞帧数
+func TestStreamVADHysteresis(t *testing.T) {
+	svad, err := NewStreamVADWithOptions(
+		WithSampleRate(8000),
+		WithFrameDuration(10),
+		WithHysteresis(3, 2),
+	)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	// 两帧语音不足以进入；三帧进入；单帧静音不退出；两帧静音退出
+	pushPattern(svad, "0110111011100")
+
+	ms := time.Millisecond
+	want := []VoiceSegment{
+		{Start: 0, End: 40 * ms},
+		{Start: 40 * ms, End: 110 * ms, IsSpeech: true},
+		{Start: 110 * ms, End: 130 * ms},
+	}
+	got := svad.GetSegments()
+	if len(got) != len(want) {
+		t.Fatalf("片段数错误: 期望%v, 得到%v", want, got)
+	}
+	for i := range got {
+		if got[i].Start != want[i].Start || got[i].End != want[i].End || got[i].IsSpeech != want[i].IsSpeech {
+			t.Errorf("片段%d错误: 期望%+v, 得到%+v", i, want[i], got[i])
+		}
+	}
+
+	if _, err := NewStreamVADWithOptions(WithHysteresis(0, 1)); err != ErrInvalidFrameCount {
+		t.Errorf("迟滞帧数为0应返回ErrInvalidFrameCount, 得到%v", err)
+	}
+}
+
+// TestStreamVADCaptureAudio 测试语音片段的音频捕获
+func TestStreamVADCaptureAudio(t *testing.T) {
+	svad, err := NewStreamVADWithOptions(
+		WithSampleRate(8000),
+		WithFrameDuration(10),
+		WithMinSilenceDuration(20*time.Millisecond),
+		WithCaptureAudio(true),
+		WithMaxCaptureBytes(5*160),
+	)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	// 每帧填充其序号；第4帧的单帧静音被桥接，第7-8帧结束语音
+	pattern := "011101100"
+	for i, c := range pattern {
+		frame := bytes.Repeat([]byte{byte(i)}, svad.frameSize)
+		start := time.Duration(i) * 10 * time.Millisecond
+		svad.pushFrame(frame, c == '1', start, start+10*time.Millisecond)
+	}
+
+	var speech []VoiceSegment
+	for _, seg := range svad.GetSegments() {
+		if seg.IsSpeech {
+			speech = append(speech, seg)
+		} else if seg.Audio != nil {
+			t.Error("静音片段不应保留音频")
+		}
+	}
+	if len(speech) != 1 {
+		t.Fatalf("应有1个语音片段, 得到%d", len(speech))
+	}
+
+	// 语音为第1-6帧，上限5帧，第6帧被截断
+	audio := speech[0].Audio
+	if len(audio) != 5*svad.frameSize {
+		t.Fatalf("音频长度错误: 期望%d, 得到%d", 5*svad.frameSize, len(audio))
+	}
+	for i := 0; i < 5; i++ {
+		if got := audio[i*svad.frameSize]; got != byte(i+1) {
+			t.Errorf("第%d帧音频错误: 期望帧%d, 得到帧%d", i, i+1, got)
+		}
+	}
+
+	if _, err := NewStreamVADWithOptions(WithMaxCaptureBytes(0)); err != ErrInvalidCaptureLimit {
+		t.Errorf("非正的捕获上限应返回ErrInvalidCaptureLimit, 得到%v", err)
+	}
+}
+
+// TestStreamVADProcessReader 测试从io.Reader读取
+func TestStreamVADProcessReader(t *testing.T) {
+	svad, err := NewStreamVAD(3, 16000, 10)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	// 1秒静音，读取块大小与帧长不对齐
+	audio := make([]byte, 16000*2)
+	r := iotest.OneByteReader(bytes.NewReader(audio))
+
+	var got []VoiceSegment
+	err = svad.ProcessReader(r, func(seg VoiceSegment) error {
+		got = append(got, seg)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ProcessReader失败: %v", err)
+	}
+
+	if len(got) != len(svad.GetSegments()) {
+		t.Fatalf("回调片段数错误: 期望%d, 得到%d", len(svad.GetSegments()), len(got))
+	}
+	if got[len(got)-1].End != time.Second {
+		t.Errorf("最后一个片段结束时间错误: 期望1s, 得到%v", got[len(got)-1].End)
+	}
+
+	// 再次调用只发送新的片段，已由上次调用结束的片段不重复发送
+	var again []VoiceSegment
+	err = svad.ProcessReader(bytes.NewReader(audio), func(seg VoiceSegment) error {
+		again = append(again, seg)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("再次调用ProcessReader失败: %v", err)
+	}
+	if len(again) == 0 || len(got)+len(again) != len(svad.GetSegments()) {
+		t.Fatalf("再次调用的回调片段数错误: 共%d个片段, 两次分别得到%d和%d", len(svad.GetSegments()), len(got), len(again))
+	}
+	if again[0].Start != time.Second || again[len(again)-1].End != 2*time.Second {
+		t.Errorf("再次调用应只发送1s-2s的片段, 得到%v-%v", again[0].Start, again[len(again)-1].End)
+	}
+
+	// 回调错误终止读取
+	stop := errors.New("stop")
+	svad.Reset()
+	err = svad.ProcessReader(bytes.NewReader(audio), func(VoiceSegment) error { return stop })
+	if err != stop {
+		t.Errorf("应返回回调错误, 得到%v", err)
+	}
+
+	// 读取错误原样返回
+	svad.Reset()
+	err = svad.ProcessReader(iotest.ErrReader(io.ErrUnexpectedEOF), func(VoiceSegment) error { return nil })
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("应返回读取错误, 得到%v", err)
+	}
+}
+
+// TestStreamVADProcessReaderPartialFrame 测试以不足一帧结尾的输入，Flush新开始的片段也交付给回调
+func TestStreamVADProcessReaderPartialFrame(t *testing.T) {
+	for _, tail := range []int{100, 200, 318} {
+		audio := append(testaudio.Pattern("10", 16000), testaudio.Pattern("1", 16000)[:tail]...)
+		svad, _ := NewStreamVADWithOptions()
+
+		var got []VoiceSegment
+		err := svad.ProcessReader(bytes.NewReader(audio), func(seg VoiceSegment) error {
+			got = append(got, seg)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("末尾%d字节: ProcessReader失败: %v", tail, err)
+		}
+		if want := svad.GetSegments(); !reflect.DeepEqual(got, want) {
+			t.Errorf("末尾%d字节: 回调片段应与GetSegments一致:\n得到 %+v\n期望 %+v", tail, got, want)
+		}
+	}
+}
+
+// TestStreamVADWriteCloser 测试io.WriteCloser组合
+func TestStreamVADWriteCloser(t *testing.T) {
+	svad, err := NewStreamVAD(1, 8000, 10)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	// 105ms音频：10个完整帧加半帧
+	audio := make([]byte, 8000*2*105/1000)
+	var w io.WriteCloser = svad
+	n, err := io.Copy(w, bytes.NewReader(audio))
+	if err != nil || n != int64(len(audio)) {
+		t.Fatalf("io.Copy失败: n=%d, err=%v", n, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close失败: %v", err)
+	}
+
+	if svad.GetBufferSize() != 0 {
+		t.Error("Close后缓冲区应为空")
+	}
+	segments := svad.GetSegments()
+	if len(segments) == 0 || segments[len(segments)-1].End != 105*time.Millisecond {
+		t.Errorf("最后一个片段应结束于105ms: %+v", segments)
+	}
+
+	if _, err := svad.Write(audio); err != ErrStreamClosed {
+		t.Errorf("关闭后写入应返回ErrStreamClosed, 得到%v", err)
+	}
+	if err := svad.Close(); err != nil {
+		t.Errorf("重复Close应返回nil, 得到%v", err)
+	}
+
+	// Reset重新打开
+	svad.Reset()
+	if _, err := svad.Write(audio); err != nil {
+		t.Errorf("Reset后应可写入, 得到%v", err)
+	}
+}
+
+// TestStreamVADClosePending 测试Close时待确认帧并入当前片段
+func TestStreamVADClosePending(t *testing.T) {
+	svad, err := NewStreamVADWithOptions(
+		WithSampleRate(8000),
+		WithFrameDuration(10),
+		WithMinSilenceDuration(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	pushPattern(svad, "11100")
+	if err := svad.Close(); err != nil {
+		t.Fatalf("Close失败: %v", err)
+	}
+
+	segments := svad.GetSegments()
+	want := VoiceSegment{Start: 0, End: 50 * time.Millisecond, IsSpeech: true}
+	if len(segments) != 1 || segments[0].Start != want.Start ||
+		segments[0].End != want.End || !segments[0].IsSpeech {
+		t.Errorf("片段错误: 期望[%+v], 得到%+v", want, segments)
+	}
+}
+
+// TestStreamVADFlush 测试Flush结束当前片段且流可继续写入
+func TestStreamVADFlush(t *testing.T) {
+	svad, err := NewStreamVAD(1, 8000, 10)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	// 25ms：两个完整帧加半帧
+	audio := make([]byte, 8000*2*25/1000)
+	if _, err := svad.Write(audio); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if err := svad.Flush(); err != nil {
+		t.Fatalf("Flush失败: %v", err)
+	}
+	if svad.GetBufferSize() != 0 {
+		t.Error("Flush后缓冲区应为空")
+	}
+	if svad.GetTotalDuration() != 25*time.Millisecond {
+		t.Errorf("总时长错误: 期望25ms, 得到%v", svad.GetTotalDuration())
+	}
+
+	// 继续写入，即使类型相同也开始新片段
+	before := len(svad.GetSegments())
+	if _, err := svad.Write(audio); err != nil {
+		t.Fatalf("Flush后写入失败: %v", err)
+	}
+	segments := svad.GetSegments()
+	if len(segments) != before+1 {
+		t.Fatalf("Flush后应开始新片段: 之前%d个, 之后%d个", before, len(segments))
+	}
+	if segments[before].Start != 25*time.Millisecond {
+		t.Errorf("新片段开始时间错误: 期望25ms, 得到%v", segments[before].Start)
+	}
+
+	svad.Close()
+	if err := svad.Flush(); err != ErrStreamClosed {
+		t.Errorf("关闭后Flush应返回ErrStreamClosed, 得到%v", err)
+	}
+}
+
+// TestStreamVADSegmentsChannel 测试片段通道
+func TestStreamVADSegmentsChannel(t *testing.T) {
+	svad, err := NewStreamVADWithOptions(
+		WithSampleRate(8000),
+		WithFrameDuration(10),
+		WithSegmentBuffer(0), // 无缓冲：每个片段都要等消费者取走
+	)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	ch := svad.Segments()
+	if svad.Segments() != ch {
+		t.Error("多次调用Segments应返回同一通道")
+	}
+
+	var got []VoiceSegment
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for seg := range ch {
+			got = append(got, seg)
+		}
+	}()
+
+	pushPattern(svad, "0011100111")
+	if _, err := svad.Write(make([]byte, svad.frameSize)); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if err := svad.Close(); err != nil {
+		t.Fatalf("Close失败: %v", err)
+	}
+	<-done
+
+	want := svad.GetSegments()
+	if len(got) != len(want) {
+		t.Fatalf("通道片段数错误: 期望%d, 得到%d", len(want), len(got))
+	}
+	for i := range got {
+		if got[i].Start != want[i].Start || got[i].End != want[i].End || got[i].IsSpeech != want[i].IsSpeech {
+			t.Errorf("片段%d错误: 期望%+v, 得到%+v", i, want[i], got[i])
+		}
+	}
+
+	// Reset后重新创建通道
+	svad.Reset()
+	if svad.Segments() == ch {
+		t.Error("关闭后Reset应创建新通道")
+	}
+
+	if _, err := NewStreamVADWithOptions(WithSegmentBuffer(-1)); err != ErrBufferTooSmall {
+		t.Errorf("负的通道容量应返回ErrBufferTooSmall, 得到%v", err)
+	}
+}
+
+// TestStreamVADClock 测试绝对时间戳
+func TestStreamVADClock(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	calls := 0
+	svad, err := NewStreamVADWithOptions(
+		WithSampleRate(8000),
+		WithFrameDuration(10),
+		WithClockFunc(func() time.Time {
+			calls++
+			return now
+		}),
+	)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	// 第一批100ms数据在now时刻采集完毕，流的起点为now-100ms
+	if _, err := svad.Write(make([]byte, 1600)); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	now = now.Add(time.Hour) // 之后的时钟读数不影响时间戳
+	if _, err := svad.Write(make([]byte, 1600)); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("时钟应只读取一次, 实际%d次", calls)
+	}
+	epoch := time.Date(2024, 1, 2, 3, 4, 4, 900000000, time.UTC)
+	for _, seg := range svad.GetSegments() {
+		if !seg.StartTime.Equal(epoch.Add(seg.Start)) || !seg.EndTime.Equal(epoch.Add(seg.End)) {
+			t.Errorf("绝对时间错误: %+v", seg)
+		}
+	}
+
+	// 未设置时钟时不填充
+	plain, _ := NewStreamVAD(1, 8000, 10)
+	plain.Write(make([]byte, 1600))
+	if seg := plain.GetSegments()[0]; !seg.StartTime.IsZero() || !seg.EndTime.IsZero() {
+		t.Error("未设置时钟时绝对时间应为零值")
+	}
+}
+
+// TestStreamVADMaxSegmentDuration 测试超长片段在低能量帧处切分
+func TestStreamVADMaxSegmentDuration(t *testing.T) {
+	svad, err := NewStreamVADWithOptions(
+		WithSampleRate(8000),
+		WithFrameDuration(10),
+		WithMaxSegmentDuration(100*time.Millisecond), // 回看窗口50ms
+		WithCaptureAudio(true),
+	)
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	// 20帧连续语音，第7帧（70-80ms）能量最低
+	ms := time.Millisecond
+	var started []VoiceSegment
+	for i := 0; i < 20; i++ {
+		amplitude := 8000.0
+		if i == 7 {
+			amplitude = 100
+		}
+		start := time.Duration(i) * 10 * ms
+		if seg := svad.pushFrame(sineFrame(440, amplitude, 8000, 80), true, start, start+10*ms); seg != nil {
+			started = append(started, *seg)
+		}
+	}
+
+	// 100ms时在第7帧之后切分；180ms时窗口内能量相同，在当前帧结束处切分
+	want := []VoiceSegment{
+		{Start: 0, End: 80 * ms},
+		{Start: 80 * ms, End: 180 * ms},
+		{Start: 180 * ms, End: 200 * ms},
+	}
+	got := svad.GetSegments()
+	if len(got) != len(want) || len(started) != len(want) {
+		t.Fatalf("片段错误: 期望%d个, 得到%+v（新片段%d个）", len(want), got, len(started))
+	}
+	for i := range got {
+		if got[i].Start != want[i].Start || got[i].End != want[i].End || !got[i].IsSpeech {
+			t.Errorf("片段%d错误: 期望%v-%v, 得到%v-%v", i, want[i].Start, want[i].End, got[i].Start, got[i].End)
+		}
+		if wantLen := svad.durationToBytes(want[i].End - want[i].Start); len(got[i].Audio) != wantLen {
+			t.Errorf("片段%d音频长度错误: 期望%d, 得到%d", i, wantLen, len(got[i].Audio))
+		}
+	}
+
+	if _, err := NewStreamVADWithOptions(WithMaxSegmentDuration(-ms)); err != ErrInvalidDuration {
+		t.Errorf("负的最大时长应返回ErrInvalidDuration, 得到%v", err)
+	}
+}
+
+// TestMergeSegments 测试片段合并
+func TestMergeSegments(t *testing.T) {
+	ms := time.Millisecond
+	segs := []VoiceSegment{
+		{Start: 0, End: 100 * ms},
+		{Start: 100 * ms, End: 300 * ms, IsSpeech: true, Audio: []byte{1}},
+		{Start: 300 * ms, End: 350 * ms},
+		{Start: 350 * ms, End: 500 * ms, IsSpeech: true, Audio: []byte{2}},
+		{Start: 500 * ms, End: 900 * ms},
+		{Start: 900 * ms, End: 1000 * ms, IsSpeech: true, Audio: []byte{3}},
+	}
+
+	merged := MergeSegments(segs, 100*ms)
+	if len(merged) != 2 {
+		t.Fatalf("应合并为2个片段, 得到%+v", merged)
+	}
+	if merged[0].Start != 100*ms || merged[0].End != 500*ms || !bytes.Equal(merged[0].Audio, []byte{1, 2}) {
+		t.Errorf("第1个片段错误: %+v", merged[0])
+	}
+	if merged[1].Start != 900*ms || merged[1].End != 1000*ms {
+		t.Errorf("第2个片段错误: %+v", merged[1])
+	}
+	if segs[1].Audio[0] != 1 || len(segs[1].Audio) != 1 {
+		t.Error("不应修改输入片段")
+	}
+
+	// 间隔恰好等于maxGap时不合并
+	if got := MergeSegments(segs, 50*ms); len(got) != 3 {
+		t.Errorf("间隔等于maxGap时不应合并, 得到%d个片段", len(got))
+	}
+	if got := MergeSegments(nil, time.Second); len(got) != 0 {
+		t.Errorf("空输入应返回空结果, 得到%+v", got)
+	}
+}
+
+// TestStreamVADConcurrent 测试写入与读取并发进行（配合 go test -race）
+func TestStreamVADConcurrent(t *testing.T) {
+	svad, err := NewStreamVADWithOptions(WithSampleRate(8000), WithFrameDuration(10), WithCaptureAudio(true))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		frame := sineFrame(500, 8000, 8000, 80)
+		for i := 0; i < 200; i++ {
+			if _, err := svad.Write(frame); err != nil {
+				t.Errorf("写入失败: %v", err)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		for _, seg := range svad.GetSegments() {
+			_ = len(seg.Audio)
+		}
+		svad.FilterSpeechSegments()
+		svad.GetTotalDuration()
+		svad.GetBufferSize()
+	}
+	wg.Wait()
+
+	if svad.GetTotalDuration() != 2*time.Second {
+		t.Errorf("总时长错误: 期望2s, 得到%v", svad.GetTotalDuration())
+	}
+}
+
 // BenchmarkStreamVADWrite Benchmark流式写入
 func BenchmarkStreamVADWrite(b *testing.B) {
 	svad, _ := NewStreamVAD(1, 16000, 10)
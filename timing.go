@@ -0,0 +1,110 @@
+package webrtcvad
+
+import (
+	"math"
+	"time"
+)
+
+// timing.go 提供可选的逐帧处理耗时统计，用于确认检测在目标硬件上能跟上实时
+
+const (
+	timingBase    = 100 * time.Nanosecond // 第0个直方图区间的上界
+	timingSteps   = 4                     // 每倍频程的直方图区间数（分辨率约19%）
+	timingBuckets = 24*timingSteps + 1    // 覆盖100ns至约1.7s，更长的耗时计入最后一个区间
+)
+
+// TimingStats 逐帧处理耗时统计，仅在启用WithTiming（VAD）或WithStreamTiming（StreamVAD）时填充
+type TimingStats struct {
+	// Frames 计时的帧数
+	Frames int64
+	// Min / Avg / Max 单帧处理耗时的最小值、平均值与最大值
+	Min, Avg, Max time.Duration
+	// P99 单帧处理耗时的99分位数，按约19%分辨率的直方图估计（取区间上界，不超过Max）
+	P99 time.Duration
+	// Audio 计时帧的音频总时长
+	Audio time.Duration
+	// Processing 计时帧的处理总耗时
+	Processing time.Duration
+	// RealTimeFactor 实时率（Processing / Audio），小于1表示处理快于实时
+	RealTimeFactor float64
+}
+
+// WithTiming 统计IsSpeech的逐帧处理耗时，结果见Stats().Timing
+//
+// 每帧额外调用两次time.Now，默认关闭。Prime的预热帧不计入，ResetStats时清零
+func WithTiming(enable bool) Option {
+	return func(v *VAD) error {
+		v.timer = nil
+		if enable {
+			v.timer = &frameTimer{}
+		}
+		return nil
+	}
+}
+
+// WithStreamTiming 统计StreamVAD的逐帧处理耗时（预处理与检测），结果见Stats().Timing
+//
+// 默认关闭，Reset时清零
+func WithStreamTiming(enable bool) StreamVADOption {
+	return func(cfg *streamVADConfig) error {
+		cfg.timing = enable
+		return nil
+	}
+}
+
+// frameTimer 逐帧耗时的累计值与对数直方图
+type frameTimer struct {
+	frames     int64
+	min, max   time.Duration
+	audio      time.Duration
+	processing time.Duration
+	hist       [timingBuckets]int64
+}
+
+// observe 计入一帧的处理耗时d，audio为该帧的音频时长
+func (t *frameTimer) observe(d, audio time.Duration) {
+	if t.frames == 0 || d < t.min {
+		t.min = d
+	}
+	if d > t.max {
+		t.max = d
+	}
+	t.frames++
+	t.audio += audio
+	t.processing += d
+
+	i := 0
+	if d > timingBase {
+		i = min(int(math.Ceil(timingSteps*math.Log2(float64(d)/float64(timingBase)))), timingBuckets-1)
+	}
+	t.hist[i]++
+}
+
+// snapshot 返回当前的统计
+func (t *frameTimer) snapshot() TimingStats {
+	if t.frames == 0 {
+		return TimingStats{}
+	}
+	ts := TimingStats{
+		Frames:         t.frames,
+		Min:            t.min,
+		Avg:            t.processing / time.Duration(t.frames),
+		Max:            t.max,
+		P99:            t.max,
+		Audio:          t.audio,
+		Processing:     t.processing,
+		RealTimeFactor: float64(t.processing) / float64(t.audio),
+	}
+
+	rank := int64(math.Ceil(0.99 * float64(t.frames)))
+	var seen int64
+	for i, n := range t.hist[:timingBuckets-1] {
+		if seen += n; seen >= rank {
+			if upper := time.Duration(float64(timingBase) * math.Exp2(float64(i)/timingSteps)); upper < t.max {
+				ts.P99 = upper
+			}
+			break
+		}
+	}
+	return ts
+}
@@ -0,0 +1,88 @@
+package webrtcvad
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFrameTimer 测试耗时统计与99分位数估计
+func TestFrameTimer(t *testing.T) {
+	var timer frameTimer
+	if ts := timer.snapshot(); ts != (TimingStats{}) {
+		t.Errorf("未计时应返回零值, 得到%+v", ts)
+	}
+
+	// 99帧10µs，1帧1ms：99分位数落在10µs所在的区间
+	for i := 0; i < 99; i++ {
+		timer.observe(10*time.Microsecond, 10*time.Millisecond)
+	}
+	timer.observe(time.Millisecond, 10*time.Millisecond)
+
+	ts := timer.snapshot()
+	if ts.Frames != 100 || ts.Min != 10*time.Microsecond || ts.Max != time.Millisecond {
+		t.Errorf("帧数/最值错误: %+v", ts)
+	}
+	if want := (99*10*time.Microsecond + time.Millisecond) / 100; ts.Avg != want {
+		t.Errorf("平均耗时应为%v, 得到%v", want, ts.Avg)
+	}
+	if ts.P99 < 10*time.Microsecond || ts.P99 > 12*time.Microsecond {
+		t.Errorf("P99应略高于10µs, 得到%v", ts.P99)
+	}
+	if ts.Audio != time.Second || ts.RealTimeFactor != float64(ts.Processing)/float64(time.Second) {
+		t.Errorf("实时率错误: %+v", ts)
+	}
+
+	// 再加一帧1ms后99分位数落在最大值上，且不超过Max
+	timer.observe(time.Millisecond, 10*time.Millisecond)
+	if ts := timer.snapshot(); ts.P99 != time.Millisecond {
+		t.Errorf("P99应为1ms, 得到%v", ts.P99)
+	}
+
+	// 超出直方图范围的耗时
+	timer.observe(time.Hour, 10*time.Millisecond)
+	if ts := timer.snapshot(); ts.Max != time.Hour || ts.P99 > time.Hour {
+		t.Errorf("超长耗时统计错误: %+v", ts)
+	}
+}
+
+// TestWithTiming 测试VAD与StreamVAD的耗时统计
+func TestWithTiming(t *testing.T) {
+	vad, err := NewWithOptions(WithTiming(true))
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	vad.Prime(make([]byte, 3200), 16000) // 预热帧不计入
+	for i := 0; i < 10; i++ {
+		vad.IsSpeech(make([]byte, 640), 16000)
+	}
+	ts := vad.Stats().Timing
+	if ts.Frames != 10 || ts.Audio != 200*time.Millisecond {
+		t.Errorf("应计时10帧共200ms音频, 得到%+v", ts)
+	}
+	if ts.Min > ts.Avg || ts.Avg > ts.Max || ts.P99 > ts.Max || ts.RealTimeFactor <= 0 {
+		t.Errorf("耗时统计不一致: %+v", ts)
+	}
+	vad.ResetStats()
+	if ts := vad.Stats().Timing; ts.Frames != 0 {
+		t.Errorf("ResetStats后应清零, 得到%+v", ts)
+	}
+
+	plain, _ := New(0)
+	plain.IsSpeech(make([]byte, 640), 16000)
+	if ts := plain.Stats().Timing; ts != (TimingStats{}) {
+		t.Errorf("未启用时不应计时, 得到%+v", ts)
+	}
+
+	svad, err := NewStreamVADWithOptions(WithStreamTiming(true))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	svad.Process(make([]byte, 32000)) // 1s，50个20ms帧
+	if ts := svad.Stats().Timing; ts.Frames != 50 || ts.Audio != time.Second {
+		t.Errorf("应计时50帧共1s音频, 得到%+v", ts)
+	}
+	svad.Reset()
+	if ts := svad.Stats().Timing; ts.Frames != 0 {
+		t.Errorf("Reset后应清零, 得到%+v", ts)
+	}
+}
@@ -0,0 +1,275 @@
+// Package training 提供WebRTC VAD高斯混合模型的离线重训练工具
+//
+// 输入带标注的语音和噪声PCM音频，使用VAD相同的滤波器组提取6个频带的对数能量特征，
+// 然后对每个频带分别用EM算法（浮点）估计两高斯混合模型，最后量化为Q7定点数，
+// 得到可直接用于 VAD.SetModel 的 webrtcvad.Model。
+//
+// 使用示例:
+//
+//	tr, err := training.NewTrainer(16000, 10)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	tr.AddSpeech(speechPCM)
+//	tr.AddNoise(noisePCM)
+//
+//	model, err := tr.Model()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	vad.SetModel(model)
+package training
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+)
+
+// 训练相关常量
+const (
+	// DefaultIterations 默认EM迭代次数
+	DefaultIterations = 50
+	// MinFrames 每类数据至少需要的有效帧数
+	MinFrames = 10
+
+	// minStdQ7 标准差下限（Q7），与VAD核心的kMinStd一致
+	minStdQ7 = 384
+	// minWeight 单个高斯的最小权重，防止退化
+	minWeight = 0.05
+)
+
+var (
+	// ErrNotEnoughData 有效训练帧不足
+	ErrNotEnoughData = errors.New("training: not enough training frames")
+
+	// ErrInvalidLength 音频数据长度不是16位样本的整数倍
+	ErrInvalidLength = errors.New("training: PCM data length must be a multiple of 2")
+)
+
+// Trainer 离线GMM训练器
+//
+// Trainer不是并发安全的
+type Trainer struct {
+	sampleRate int
+	frameBytes int
+
+	// Iterations EM迭代次数，默认DefaultIterations
+	Iterations int
+
+	speech [webrtcvad.NumChannels][]float64
+	noise  [webrtcvad.NumChannels][]float64
+}
+
+// NewTrainer 创建训练器
+//
+// 参数:
+//   - sampleRate: 训练音频的采样率
+//   - frameMs: 特征提取的帧长度（10/20/30 ms）
+func NewTrainer(sampleRate, frameMs int) (*Trainer, error) {
+	if !webrtcvad.ValidRateAndFrameLength(sampleRate, sampleRate/100) {
+		return nil, fmt.Errorf("training: %w, got %d", webrtcvad.ErrInvalidSampleRate, sampleRate)
+	}
+	frameLength := sampleRate * frameMs / 1000
+	if !webrtcvad.ValidRateAndFrameLength(sampleRate, frameLength) {
+		return nil, fmt.Errorf("training: %w, got %d ms", webrtcvad.ErrInvalidFrameLength, frameMs)
+	}
+
+	return &Trainer{
+		sampleRate: sampleRate,
+		frameBytes: frameLength * 2,
+		Iterations: DefaultIterations,
+	}, nil
+}
+
+// AddSpeech 添加标注为语音的PCM音频（16位小端序）
+func (t *Trainer) AddSpeech(pcm []byte) error {
+	return t.add(pcm, &t.speech)
+}
+
+// AddNoise 添加标注为噪声/静音的PCM音频（16位小端序）
+func (t *Trainer) AddNoise(pcm []byte) error {
+	return t.add(pcm, &t.noise)
+}
+
+// SpeechFrames 返回已收集的有效语音帧数
+func (t *Trainer) SpeechFrames() int {
+	return len(t.speech[0])
+}
+
+// NoiseFrames 返回已收集的有效噪声帧数
+func (t *Trainer) NoiseFrames() int {
+	return len(t.noise[0])
+}
+
+// add 提取特征并追加到对应类别
+//
+// 每段音频使用独立的特征提取器，避免不同录音之间的滤波器状态串扰
+func (t *Trainer) add(pcm []byte, dst *[webrtcvad.NumChannels][]float64) error {
+	if len(pcm)%2 != 0 {
+		return ErrInvalidLength
+	}
+
+	extractor, err := webrtcvad.NewWithOptions(webrtcvad.WithFrozenModel())
+	if err != nil {
+		return err
+	}
+
+	for offset := 0; offset+t.frameBytes <= len(pcm); offset += t.frameBytes {
+		if _, err := extractor.IsSpeech(pcm[offset:offset+t.frameBytes], t.sampleRate); err != nil {
+			return err
+		}
+
+		features, ok := extractor.Features()
+		if !ok {
+			// 能量过低的帧不参与GMM判决，也不用于训练
+			continue
+		}
+		for ch := 0; ch < webrtcvad.NumChannels; ch++ {
+			dst[ch] = append(dst[ch], float64(features[ch]))
+		}
+	}
+
+	return nil
+}
+
+// Model 用已收集的数据训练并返回量化后的模型
+func (t *Trainer) Model() (webrtcvad.Model, error) {
+	if t.SpeechFrames() < MinFrames {
+		return webrtcvad.Model{}, fmt.Errorf("%w: %d speech frames, need %d", ErrNotEnoughData, t.SpeechFrames(), MinFrames)
+	}
+	if t.NoiseFrames() < MinFrames {
+		return webrtcvad.Model{}, fmt.Errorf("%w: %d noise frames, need %d", ErrNotEnoughData, t.NoiseFrames(), MinFrames)
+	}
+
+	iterations := t.Iterations
+	if iterations <= 0 {
+		iterations = DefaultIterations
+	}
+
+	var m webrtcvad.Model
+	for ch := 0; ch < webrtcvad.NumChannels; ch++ {
+		quantize(fitGMM(t.noise[ch], iterations), ch, &m.NoiseMeans, &m.NoiseStds, &m.NoiseWeights)
+		quantize(fitGMM(t.speech[ch], iterations), ch, &m.SpeechMeans, &m.SpeechStds, &m.SpeechWeights)
+	}
+
+	if err := m.Validate(); err != nil {
+		return webrtcvad.Model{}, err
+	}
+	return m, nil
+}
+
+// gmm 浮点两高斯混合模型（均值和标准差以Q4特征单位表示）
+type gmm struct {
+	weight [webrtcvad.NumGaussians]float64
+	mean   [webrtcvad.NumGaussians]float64
+	std    [webrtcvad.NumGaussians]float64
+}
+
+// fitGMM 用EM算法拟合一维两高斯混合模型
+//
+// 以25%/75%分位数初始化均值，整体标准差初始化方差
+func fitGMM(x []float64, iterations int) gmm {
+	sorted := append([]float64(nil), x...)
+	sort.Float64s(sorted)
+
+	n := float64(len(x))
+	var mean, variance float64
+	for _, v := range x {
+		mean += v
+	}
+	mean /= n
+	for _, v := range x {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Max(math.Sqrt(variance/n), minStdQ7/8)
+
+	g := gmm{
+		weight: [2]float64{0.5, 0.5},
+		mean:   [2]float64{sorted[len(sorted)/4], sorted[len(sorted)*3/4]},
+		std:    [2]float64{std, std},
+	}
+
+	resp := make([]float64, len(x)) // 第一个高斯的后验概率
+	for it := 0; it < iterations; it++ {
+		// E步
+		for i, v := range x {
+			p0 := g.weight[0] * normalPDF(v, g.mean[0], g.std[0])
+			p1 := g.weight[1] * normalPDF(v, g.mean[1], g.std[1])
+			if p0+p1 == 0 {
+				resp[i] = 0.5
+			} else {
+				resp[i] = p0 / (p0 + p1)
+			}
+		}
+
+		// M步
+		for k := 0; k < webrtcvad.NumGaussians; k++ {
+			var sumR, sumX float64
+			for i, v := range x {
+				r := resp[i]
+				if k == 1 {
+					r = 1 - r
+				}
+				sumR += r
+				sumX += r * v
+			}
+			if sumR == 0 {
+				continue
+			}
+			mu := sumX / sumR
+
+			var sumV float64
+			for i, v := range x {
+				r := resp[i]
+				if k == 1 {
+					r = 1 - r
+				}
+				sumV += r * (v - mu) * (v - mu)
+			}
+
+			g.weight[k] = sumR / n
+			g.mean[k] = mu
+			g.std[k] = math.Max(math.Sqrt(sumV/sumR), minStdQ7/8)
+		}
+
+		g.weight[0] = math.Min(math.Max(g.weight[0], minWeight), 1-minWeight)
+		g.weight[1] = 1 - g.weight[0]
+	}
+
+	return g
+}
+
+// normalPDF 正态分布概率密度
+func normalPDF(x, mean, std float64) float64 {
+	d := (x - mean) / std
+	return math.Exp(-0.5*d*d) / (std * math.Sqrt(2*math.Pi))
+}
+
+// quantize 将浮点GMM量化为Q7并写入模型表的对应频带
+//
+// 特征为Q4，左移3位即为Q7；两个权重之和固定为128（Q7的1.0）
+func quantize(g gmm, channel int, means, stds, weights *[webrtcvad.NumChannels * webrtcvad.NumGaussians]int16) {
+	w0 := int16(math.Round(g.weight[0] * 128))
+	for k := 0; k < webrtcvad.NumGaussians; k++ {
+		idx := k*webrtcvad.NumChannels + channel
+		means[idx] = saturate(math.Round(g.mean[k] * 8))
+		stds[idx] = saturate(math.Max(math.Round(g.std[k]*8), minStdQ7))
+	}
+	weights[channel] = w0
+	weights[webrtcvad.NumChannels+channel] = 128 - w0
+}
+
+// saturate 将浮点值饱和到int16范围
+func saturate(v float64) int16 {
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
@@ -0,0 +1,91 @@
+package training
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+)
+
+// synthPCM 生成测试音频：speech为带调幅的多谐波信号，否则为低电平白噪声
+func synthPCM(seconds float64, sampleRate int, speech bool, rng *rand.Rand) []byte {
+	n := int(seconds * float64(sampleRate))
+	buf := make([]byte, n*2)
+	for i := 0; i < n; i++ {
+		var v float64
+		if speech {
+			tm := float64(i) / float64(sampleRate)
+			env := 0.5 + 0.5*math.Sin(2*math.Pi*4*tm)
+			for h := 1; h <= 5; h++ {
+				v += 3000 / float64(h) * math.Sin(2*math.Pi*150*float64(h)*tm)
+			}
+			v *= env
+		}
+		v += rng.NormFloat64() * 100
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(int16(v)))
+	}
+	return buf
+}
+
+// TestTrainerModel 测试训练得到可用的模型
+func TestTrainerModel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	tr, err := NewTrainer(16000, 10)
+	if err != nil {
+		t.Fatalf("创建训练器失败: %v", err)
+	}
+	if err := tr.AddSpeech(synthPCM(2, 16000, true, rng)); err != nil {
+		t.Fatalf("添加语音失败: %v", err)
+	}
+	if err := tr.AddNoise(synthPCM(2, 16000, false, rng)); err != nil {
+		t.Fatalf("添加噪声失败: %v", err)
+	}
+
+	m, err := tr.Model()
+	if err != nil {
+		t.Fatalf("训练失败: %v", err)
+	}
+
+	for ch := 0; ch < webrtcvad.NumChannels; ch++ {
+		if w := m.NoiseWeights[ch] + m.NoiseWeights[ch+webrtcvad.NumChannels]; w != 128 {
+			t.Errorf("频带%d噪声权重之和应为128, 得到%d", ch, w)
+		}
+	}
+
+	// 语音模型在低频带的均值应高于噪声模型
+	if m.SpeechMeans[0] <= m.NoiseMeans[0] {
+		t.Errorf("语音均值(%d)应高于噪声均值(%d)", m.SpeechMeans[0], m.NoiseMeans[0])
+	}
+
+	vad, err := webrtcvad.New(1)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	if err := vad.SetModel(m); err != nil {
+		t.Fatalf("设置训练模型失败: %v", err)
+	}
+}
+
+// TestTrainerNotEnoughData 测试数据不足时报错
+func TestTrainerNotEnoughData(t *testing.T) {
+	tr, err := NewTrainer(8000, 10)
+	if err != nil {
+		t.Fatalf("创建训练器失败: %v", err)
+	}
+	if _, err := tr.Model(); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("期望ErrNotEnoughData, 得到%v", err)
+	}
+	if err := tr.AddSpeech(make([]byte, 3)); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("期望ErrInvalidLength, 得到%v", err)
+	}
+	if _, err := NewTrainer(11025, 10); !errors.Is(err, webrtcvad.ErrInvalidSampleRate) {
+		t.Errorf("期望ErrInvalidSampleRate, 得到%v", err)
+	}
+	if _, err := NewTrainer(16000, 25); !errors.Is(err, webrtcvad.ErrInvalidFrameLength) {
+		t.Errorf("期望ErrInvalidFrameLength, 得到%v", err)
+	}
+}
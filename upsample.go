@@ -0,0 +1,389 @@
+package webrtcvad
+
+import "fmt"
+
+// upsample.go 移植WebRTC SPL的升采样滤波器（resample_by_2.c、resample_by_2_internal.c、
+// resample_fractional.c与resample_48khz.c中的16kHz->48kHz），用于将电话音频升到
+// 识别引擎等要求的固定采样率
+
+// 2倍升采样全通滤波器系数（Q16，与WebRTC的kResampleAllpass1/2相同）
+var (
+	kUpsampleAllpass1 = [3]uint16{3284, 24441, 49528}
+	kUpsampleAllpass2 = [3]uint16{12199, 37471, 60255}
+)
+
+// 32kHz到24kHz插值系数 (3/4重采样)
+var kCoefficients32To24 = [3][8]int16{
+	{767, -2362, 2434, 24406, 10620, -3838, 721, 90},
+	{386, -381, -2646, 19062, 19062, -2646, -381, 386},
+	{90, 721, -3838, 10620, 24406, 2434, -2362, 767},
+}
+
+// UpsampleState UpsampleBy2的滤波器状态，零值即为初始状态
+//
+// 每路音频流使用一个实例，跨调用保存全通滤波器的状态
+type UpsampleState struct {
+	s [8]int32
+}
+
+// Reset 清除滤波器状态
+func (s *UpsampleState) Reset() {
+	clear(s.s[:])
+}
+
+// UpsampleBy2 2倍升采样（WebRtcSpl_UpsampleBy2）
+//
+// 以一对全通滤波器分别生成偶数与奇数位置的输出样本，适用于8kHz->16kHz、16kHz->32kHz、
+// 24kHz->48kHz等。输入长度任意，状态跨调用保存，分块处理的结果与一次性处理相同
+//
+// 参数:
+//   - in: 输入样本
+//   - state: 滤波器状态
+//
+// 返回:
+//   - []int16: 2*len(in)个输出样本
+func UpsampleBy2(in []int16, state *UpsampleState) []int16 {
+	out := make([]int16, 2*len(in))
+	UpsampleBy2To(in, out, state)
+	return out
+}
+
+// UpsampleBy2To 零分配的2倍升采样，out长度须不小于2*len(in)
+func UpsampleBy2To(in []int16, out []int16, state *UpsampleState) {
+	s := &state.s
+	for i, v := range in {
+		// 下侧全通滤波器（偶数输出）
+		in32 := int32(v) << 10
+		diff := in32 - s[1]
+		tmp1 := scaleDiff32(kUpsampleAllpass1[0], diff, s[0])
+		s[0] = in32
+		diff = tmp1 - s[2]
+		tmp2 := scaleDiff32(kUpsampleAllpass1[1], diff, s[1])
+		s[1] = tmp1
+		diff = tmp2 - s[3]
+		s[3] = scaleDiff32(kUpsampleAllpass1[2], diff, s[2])
+		s[2] = tmp2
+		out[2*i] = sat16((s[3] + 512) >> 10)
+
+		// 上侧全通滤波器（奇数输出）
+		diff = in32 - s[5]
+		tmp1 = scaleDiff32(kUpsampleAllpass2[0], diff, s[4])
+		s[4] = in32
+		diff = tmp1 - s[6]
+		tmp2 = scaleDiff32(kUpsampleAllpass2[1], diff, s[5])
+		s[5] = tmp1
+		diff = tmp2 - s[7]
+		s[7] = scaleDiff32(kUpsampleAllpass2[2], diff, s[6])
+		s[6] = tmp2
+		out[2*i+1] = sat16((s[7] + 512) >> 10)
+	}
+}
+
+// scaleDiff32 计算c + (b*a)>>16（WEBRTC_SPL_SCALEDIFF32）
+func scaleDiff32(a uint16, b, c int32) int32 {
+	return c + int32((int64(b)*int64(a))>>16)
+}
+
+// sat16 饱和到int16范围
+func sat16(v int32) int16 {
+	if v > 0x7FFF {
+		return 0x7FFF
+	}
+	if v < -0x8000 {
+		return -0x8000
+	}
+	return int16(v)
+}
+
+// upBy2ShortToInt 2倍插值：int16 -> int32（WebRtcSpl_UpBy2ShortToInt）
+//
+// 参数:
+//   - in: 输入样本（int16）
+//   - out: 输出样本（int32，未饱和，长度2*len(in)）
+//   - state: 滤波器状态（长度8）
+func upBy2ShortToInt(in []int16, out []int32, state []int32) {
+	// 上侧全通滤波器
+	for i, v := range in {
+		tmp0 := (int32(v) << 15) + (1 << 14)
+		diff := tmp0 - state[5]
+		// 缩放和舍入
+		diff = (diff + (1 << 13)) >> 14
+		tmp1 := state[4] + diff*int32(kResampleAllpass[0][0])
+		state[4] = tmp0
+		diff = tmp1 - state[6]
+		// 缩放和截断
+		diff = diff >> 14
+		if diff < 0 {
+			diff += 1
+		}
+		tmp0 = state[5] + diff*int32(kResampleAllpass[0][1])
+		state[5] = tmp1
+		diff = tmp0 - state[7]
+		diff = diff >> 14
+		if diff < 0 {
+			diff += 1
+		}
+		state[7] = state[6] + diff*int32(kResampleAllpass[0][2])
+		state[6] = tmp0
+
+		// 缩放并存储
+		out[i<<1] = state[7] >> 15
+	}
+
+	// 下侧全通滤波器
+	for i, v := range in {
+		tmp0 := (int32(v) << 15) + (1 << 14)
+		diff := tmp0 - state[1]
+		diff = (diff + (1 << 13)) >> 14
+		tmp1 := state[0] + diff*int32(kResampleAllpass[1][0])
+		state[0] = tmp0
+		diff = tmp1 - state[2]
+		diff = diff >> 14
+		if diff < 0 {
+			diff += 1
+		}
+		tmp0 = state[1] + diff*int32(kResampleAllpass[1][1])
+		state[1] = tmp1
+		diff = tmp0 - state[3]
+		diff = diff >> 14
+		if diff < 0 {
+			diff += 1
+		}
+		state[3] = state[2] + diff*int32(kResampleAllpass[1][2])
+		state[2] = tmp0
+
+		out[(i<<1)+1] = state[3] >> 15
+	}
+}
+
+// upBy2IntToShort 2倍插值：int32 -> int16（WebRtcSpl_UpBy2IntToShort）
+//
+// 参数:
+//   - in: 输入样本（int32，移位15位+偏移16384）
+//   - out: 输出样本（int16，饱和，长度2*len(in)）
+//   - state: 滤波器状态（长度8）
+func upBy2IntToShort(in []int32, out []int16, state []int32) {
+	// 上侧全通滤波器
+	for i, tmp0 := range in {
+		diff := tmp0 - state[5]
+		diff = (diff + (1 << 13)) >> 14
+		tmp1 := state[4] + diff*int32(kResampleAllpass[0][0])
+		state[4] = tmp0
+		diff = tmp1 - state[6]
+		diff = diff >> 14
+		if diff < 0 {
+			diff += 1
+		}
+		tmp0 = state[5] + diff*int32(kResampleAllpass[0][1])
+		state[5] = tmp1
+		diff = tmp0 - state[7]
+		diff = diff >> 14
+		if diff < 0 {
+			diff += 1
+		}
+		state[7] = state[6] + diff*int32(kResampleAllpass[0][2])
+		state[6] = tmp0
+
+		out[i<<1] = sat16(state[7] >> 15)
+	}
+
+	// 下侧全通滤波器
+	for i, tmp0 := range in {
+		diff := tmp0 - state[1]
+		diff = (diff + (1 << 13)) >> 14
+		tmp1 := state[0] + diff*int32(kResampleAllpass[1][0])
+		state[0] = tmp0
+		diff = tmp1 - state[2]
+		diff = diff >> 14
+		if diff < 0 {
+			diff += 1
+		}
+		tmp0 = state[1] + diff*int32(kResampleAllpass[1][1])
+		state[1] = tmp1
+		diff = tmp0 - state[3]
+		diff = diff >> 14
+		if diff < 0 {
+			diff += 1
+		}
+		state[3] = state[2] + diff*int32(kResampleAllpass[1][2])
+		state[2] = tmp0
+
+		out[(i<<1)+1] = sat16(state[3] >> 15)
+	}
+}
+
+// resample32khzTo24khz 分数重采样 3/4 (32kHz -> 24kHz)
+//
+// 参数:
+//   - in: 输入样本（int32，未饱和，长度至少 4*K+6，开头8个为上一块的重叠样本）
+//   - out: 输出样本（int32，移位15位+偏移16384，长度 3*K）
+//   - K: 块数
+func resample32khzTo24khz(in []int32, out []int32, K int) {
+	for m := 0; m < K; m++ {
+		for k := 0; k < 3; k++ {
+			tmp := int32(1 << 14)
+			for j, c := range kCoefficients32To24[k] {
+				tmp += int32(c) * in[4*m+k+j]
+			}
+			out[3*m+k] = tmp
+		}
+	}
+}
+
+// state16khzTo48khz 16kHz到48kHz升采样状态
+type state16khzTo48khz struct {
+	S_16_32 [8]int32 // 16->32状态
+	S_32_24 [8]int32 // 32->24状态（上一块末尾的8个样本）
+	S_24_48 [8]int32 // 24->48状态
+}
+
+// resample16khzTo48khz 16kHz到48kHz升采样（WebRtcSpl_Resample16khzTo48khz）
+//
+//	阶段1: 16kHz -> 32kHz (2倍插值，全通滤波器)
+//	阶段2: 32kHz -> 24kHz (分数重采样 3/4)
+//	阶段3: 24kHz -> 48kHz (2倍插值，全通滤波器)
+//
+// 参数:
+//   - in: 输入样本，长度须为偶数
+//   - out: 输出样本，长度3*len(in)
+//   - state: 重采样状态
+//   - tmpMem: 临时内存（至少 2*len(in)+8 + 3*len(in)/2 个int32）
+func resample16khzTo48khz(in []int16, out []int16, state *state16khzTo48khz, tmpMem []int32) {
+	n := len(in)
+	t32 := tmpMem[:2*n+8]
+	t24 := tmpMem[2*n+8 : 2*n+8+3*n/2]
+
+	// 阶段1，输出写在上一块的8个重叠样本之后
+	copy(t32[:8], state.S_32_24[:])
+	upBy2ShortToInt(in, t32[8:], state.S_16_32[:])
+	copy(state.S_32_24[:], t32[2*n:])
+
+	// 阶段2，每4个输入样本生成3个输出样本
+	resample32khzTo24khz(t32, t24, n/2)
+
+	// 阶段3
+	upBy2IntToShort(t24, out, state.S_24_48[:])
+}
+
+// Upsampler 多级升采样器，支持8kHz->16/24/32/48kHz、16kHz->32/48kHz与24kHz->48kHz
+//
+// 与WebRTC的Resampler一样按比例选择滤波器：2倍与4倍由UpsampleBy2级联完成，
+// 3倍使用16kHz->48kHz流水线（先2倍插值，3/4降采样，再2倍插值；8kHz->24kHz同样适用），
+// 6倍在其前先做一级2倍。Upsampler有状态且不是并发安全的，每路音频流使用一个实例
+type Upsampler struct {
+	inRate, outRate int
+	by2             []UpsampleState    // 依次执行的2倍升采样级
+	by3             *state16khzTo48khz // 非nil时最后执行3倍升采样
+	buf             []int16            // 级间缓冲区
+	tmp             []int32            // 3倍升采样的临时内存
+}
+
+// NewUpsampler 创建升采样器
+//
+// 参数:
+//   - inRate: 输入采样率（8000, 16000或24000）
+//   - outRate: 输出采样率，须为上述支持的组合
+//
+// 返回:
+//   - *Upsampler: 升采样器实例
+//   - error: 不支持的组合返回ErrUnsupportedConversion
+func NewUpsampler(inRate, outRate int) (*Upsampler, error) {
+	u := &Upsampler{inRate: inRate, outRate: outRate}
+	switch {
+	case inRate == 8000 && outRate == 16000,
+		inRate == 16000 && outRate == 32000,
+		inRate == 24000 && outRate == 48000:
+		u.by2 = make([]UpsampleState, 1)
+	case inRate == 8000 && outRate == 32000:
+		u.by2 = make([]UpsampleState, 2)
+	case inRate == 16000 && outRate == 48000,
+		inRate == 8000 && outRate == 24000:
+		u.by3 = &state16khzTo48khz{}
+	case inRate == 8000 && outRate == 48000:
+		u.by2 = make([]UpsampleState, 1)
+		u.by3 = &state16khzTo48khz{}
+	default:
+		return nil, fmt.Errorf("%w: %d Hz -> %d Hz", ErrUnsupportedConversion, inRate, outRate)
+	}
+	return u, nil
+}
+
+// InRate 返回输入采样率
+func (u *Upsampler) InRate() int { return u.inRate }
+
+// OutRate 返回输出采样率
+func (u *Upsampler) OutRate() int { return u.outRate }
+
+// BlockSize 返回每次输入样本数须为其整数倍的块长度
+//
+// 3倍升采样的3/4级以2个输入样本为一组，为2；其余组合为1
+func (u *Upsampler) BlockSize() int {
+	if u.by3 != nil && len(u.by2) == 0 {
+		return 2
+	}
+	return 1
+}
+
+// Process 升采样一段音频，返回新分配的输出
+//
+// 参数:
+//   - in: 输入样本，长度须为BlockSize()的整数倍
+//
+// 返回:
+//   - []int16: len(in)*OutRate()/InRate()个输出样本
+//   - error: 长度不是块长度的整数倍时返回ErrPartialBlock
+func (u *Upsampler) Process(in []int16) ([]int16, error) {
+	out := make([]int16, len(in)*u.outRate/u.inRate)
+	if _, err := u.ProcessTo(in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProcessTo 零分配的升采样（级间缓冲区在首次调用或输入变长时分配），
+// out长度须不小于len(in)*OutRate()/InRate()
+//
+// 返回:
+//   - int: 写入out的样本数
+//   - error: 长度不是块长度的整数倍时返回ErrPartialBlock，out太短时返回ErrBufferTooSmall
+func (u *Upsampler) ProcessTo(in []int16, out []int16) (int, error) {
+	if len(in)%u.BlockSize() != 0 {
+		return 0, fmt.Errorf("%w: got %d samples, block size %d", ErrPartialBlock, len(in), u.BlockSize())
+	}
+	n := len(in) * u.outRate / u.inRate
+	if len(out) < n {
+		return 0, ErrBufferTooSmall
+	}
+
+	cur := in
+	for i := range u.by2 {
+		dst := out
+		if i < len(u.by2)-1 || u.by3 != nil {
+			// 中间结果写入级间缓冲区，最后一级直接写入out
+			if cap(u.buf) < 2*len(cur) {
+				u.buf = make([]int16, 2*len(cur))
+			}
+			dst = u.buf
+		}
+		UpsampleBy2To(cur, dst, &u.by2[i])
+		cur = dst[:2*len(cur)]
+	}
+	if u.by3 != nil {
+		size := 2*len(cur) + 8 + 3*len(cur)/2
+		if cap(u.tmp) < size {
+			u.tmp = make([]int32, size)
+		}
+		resample16khzTo48khz(cur, out[:3*len(cur)], u.by3, u.tmp[:size])
+	}
+	return n, nil
+}
+
+// Reset 清除所有级的滤波器状态
+func (u *Upsampler) Reset() {
+	for i := range u.by2 {
+		u.by2[i].Reset()
+	}
+	if u.by3 != nil {
+		*u.by3 = state16khzTo48khz{}
+	}
+}
@@ -0,0 +1,129 @@
+package webrtcvad
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// toneLevel 计算x中频率为freq的分量的幅度（单频点DFT）
+func toneLevel(x []int16, rate int, freq float64) float64 {
+	var sum complex128
+	for i, v := range x {
+		sum += complex(float64(v), 0) * cmplx.Exp(complex(0, -2*math.Pi*freq*float64(i)/float64(rate)))
+	}
+	return 2 * cmplx.Abs(sum) / float64(len(x))
+}
+
+// TestUpsampler 测试各组合的输出长度、通带幅度与镜像抑制
+func TestUpsampler(t *testing.T) {
+	for _, c := range []struct{ in, out int }{
+		{8000, 16000}, {8000, 24000}, {8000, 32000}, {8000, 48000},
+		{16000, 32000}, {16000, 48000}, {24000, 48000},
+	} {
+		u, err := NewUpsampler(c.in, c.out)
+		if err != nil {
+			t.Fatalf("%d->%d: 创建升采样器失败: %v", c.in, c.out, err)
+		}
+		in := make([]int16, c.in/2)
+		for i := range in {
+			in[i] = int16(10000 * math.Sin(2*math.Pi*1000*float64(i)/float64(c.in)))
+		}
+		out, err := u.Process(in)
+		if err != nil {
+			t.Fatalf("%d->%d: 升采样失败: %v", c.in, c.out, err)
+		}
+		if want := len(in) * c.out / c.in; len(out) != want {
+			t.Fatalf("%d->%d: 输出长度应为%d, 得到%d", c.in, c.out, want, len(out))
+		}
+
+		// 跳过开头的暂态
+		steady := out[len(out)/4:]
+		if a := toneLevel(steady, c.out, 1000); math.Abs(a-10000) > 500 {
+			t.Errorf("%d->%d: 1kHz幅度应约为10000, 得到%.0f", c.in, c.out, a)
+		}
+		if a := toneLevel(steady, c.out, float64(c.in-1000)); a > 100 {
+			t.Errorf("%d->%d: %dHz镜像幅度应低于-40dB, 得到%.0f", c.in, c.out, c.in-1000, a)
+		}
+	}
+}
+
+// TestUpsamplerStreaming 测试分块处理与一次性处理结果一致，以及Reset
+func TestUpsamplerStreaming(t *testing.T) {
+	for _, c := range []struct{ in, out int }{{8000, 32000}, {16000, 48000}, {8000, 48000}} {
+		in := make([]int16, 640)
+		for i := range in {
+			in[i] = int16(8000*math.Sin(float64(i)*0.37) + 3000*math.Cos(float64(i)*1.9))
+		}
+		u, _ := NewUpsampler(c.in, c.out)
+		whole, _ := u.Process(in)
+
+		u.Reset()
+		var chunked []int16
+		step := 6 * u.BlockSize()
+		for i := 0; i < len(in); i += step {
+			out, err := u.Process(in[i:min(i+step, len(in))])
+			if err != nil {
+				t.Fatalf("%d->%d: 升采样失败: %v", c.in, c.out, err)
+			}
+			chunked = append(chunked, out...)
+		}
+		for i := range whole {
+			if whole[i] != chunked[i] {
+				t.Fatalf("%d->%d: 分块处理第%d个输出应为%d, 得到%d", c.in, c.out, i, whole[i], chunked[i])
+			}
+		}
+	}
+
+	// UpsampleBy2逐样本调用与整段调用一致
+	var s1, s2 UpsampleState
+	in := []int16{100, -2000, 30000, -32768, 5, 0, 12345}
+	whole := UpsampleBy2(in, &s1)
+	for i := range in {
+		out := UpsampleBy2(in[i:i+1], &s2)
+		if out[0] != whole[2*i] || out[1] != whole[2*i+1] {
+			t.Fatalf("第%d个样本的输出应为%v, 得到%v", i, whole[2*i:2*i+2], out)
+		}
+	}
+}
+
+// TestUpsamplerErrors 测试不支持的组合、不完整的块与过短的输出
+func TestUpsamplerErrors(t *testing.T) {
+	for _, c := range []struct{ in, out int }{{16000, 8000}, {24000, 32000}, {44100, 48000}, {16000, 16000}} {
+		if _, err := NewUpsampler(c.in, c.out); !errors.Is(err, ErrUnsupportedConversion) {
+			t.Errorf("%d->%d 应返回ErrUnsupportedConversion, 得到%v", c.in, c.out, err)
+		}
+	}
+
+	u, _ := NewUpsampler(16000, 48000)
+	if u.BlockSize() != 2 {
+		t.Errorf("16kHz->48kHz的块长度应为2, 得到%d", u.BlockSize())
+	}
+	if _, err := u.Process(make([]int16, 161)); !errors.Is(err, ErrPartialBlock) {
+		t.Errorf("奇数长度应返回ErrPartialBlock, 得到%v", err)
+	}
+	if _, err := u.ProcessTo(make([]int16, 160), make([]int16, 479)); err != ErrBufferTooSmall {
+		t.Errorf("输出过短应返回ErrBufferTooSmall, 得到%v", err)
+	}
+	if n, err := u.ProcessTo(make([]int16, 160), make([]int16, 480)); err != nil || n != 480 {
+		t.Errorf("应写入480个样本, 得到%d, %v", n, err)
+	}
+}
+
+// BenchmarkUpsampler 基准测试10ms音频的升采样
+func BenchmarkUpsampler(b *testing.B) {
+	for _, c := range []struct {
+		name    string
+		in, out int
+	}{{"8k-16k", 8000, 16000}, {"16k-48k", 16000, 48000}} {
+		u, _ := NewUpsampler(c.in, c.out)
+		in := make([]int16, c.in/100)
+		out := make([]int16, c.out/100)
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				u.ProcessTo(in, out)
+			}
+		})
+	}
+}
@@ -22,13 +22,31 @@
 package webrtcvad
 
 import (
-	"errors"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"time"
 )
 
 // VAD 语音活动检测器
 type VAD struct {
 	inst *vadInst
+
+	feedBuf  []byte // Feed模式的内部缓冲区
+	feedRate int    // Feed模式当前缓冲数据的采样率
+
+	zeroCopy bool // 是否启用零拷贝输入（见WithZeroCopy）
+
+	stats   Stats         // 判决统计（见Stats）
+	elapsed time.Duration // IsSpeech处理过的音频总时长（不含Prime），见FrameResult.Timestamp
+
+	debug       *json.Encoder // 调试跟踪（见WithDebugWriter），nil表示关闭
+	debugFrames int64         // 已输出跟踪的帧数
+
+	logger *slog.Logger // 告警日志（见WithLogger），nil表示不输出
+	clip   clipDetector
+
+	timer *frameTimer // 逐帧耗时统计（见WithTiming），nil表示关闭
 }
 
 // New 创建一个新的VAD实例
@@ -42,7 +60,7 @@ type VAD struct {
 // 激进度越高，对语音的判定越严格，误检率降低但可能漏检语音。
 func New(mode int) (*VAD, error) {
 	if mode < 0 || mode > 3 {
-		return nil, fmt.Errorf("mode must be 0-3, got %d", mode)
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidMode, mode)
 	}
 
 	inst := createVadInst()
@@ -62,21 +80,112 @@ func New(mode int) (*VAD, error) {
 // mode 参数范围：0-3（含义见New函数说明）
 func (v *VAD) SetMode(mode int) error {
 	if mode < 0 || mode > 3 {
-		return fmt.Errorf("mode must be 0-3, got %d", mode)
+		return fmt.Errorf("%w, got %d", ErrInvalidMode, mode)
 	}
 
-	if v.inst.initFlag != kInitCheck {
-		return errors.New("VAD not initialized")
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return ErrNotInitialized
 	}
 
 	return setModeCore(v.inst, mode)
 }
 
+// SetAggressiveness 以连续值设置VAD的激进度
+//
+// aggressiveness 范围：0.0-1.0，线性映射到模式0-3之间，
+// 局部/全局阈值和迟滞帧数在相邻模式的参数表之间插值。
+// 0.0、1/3、2/3、1.0分别与模式0、1、2、3完全等价。
+//
+// 适合针对特定麦克风或环境微调检测灵敏度。
+func (v *VAD) SetAggressiveness(aggressiveness float64) error {
+	if !(aggressiveness >= 0 && aggressiveness <= 1) { // 同时拒绝NaN
+		return fmt.Errorf("%w, got %g", ErrInvalidAggressiveness, aggressiveness)
+	}
+
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return ErrNotInitialized
+	}
+
+	return setAggressivenessCore(v.inst, aggressiveness)
+}
+
+// SetThresholds 直接设置局部与全局似然比检验的阈值，代替模式预设
+//
+// 数组依次对应10ms、20ms、30ms帧。某频带的对数似然比乘4后大于local时该频带
+// 局部判为语音（见FrameResult.BandLLR），频谱加权的对数似然比之和不小于global时
+// 全局判为语音（见FrameResult.LogLikelihoodRatio）。预设值从模式0的{24, 21, 24} /
+// {57, 48, 57}到模式3的{94, 94, 94} / {1100, 1050, 1100}。迟滞帧数保持不变；
+// 之后调用SetMode或SetAggressiveness会覆盖这里的设置。
+// 适合重新训练模型（SetModel）后或针对特殊噪声环境校准阈值
+//
+// 参数:
+//   - local: 局部检验阈值，不能为负
+//   - global: 全局检验阈值，不能为负
+func (v *VAD) SetThresholds(local, global [3]int16) error {
+	for i := range local {
+		if local[i] < 0 || global[i] < 0 {
+			return fmt.Errorf("%w: local %v, global %v", ErrInvalidThresholds, local, global)
+		}
+	}
+
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return ErrNotInitialized
+	}
+
+	v.inst.individual = local
+	v.inst.total = global
+	return nil
+}
+
+// Thresholds 返回当前的局部与全局似然比检验阈值（依次对应10ms、20ms、30ms帧），
+// 未初始化时返回零值
+func (v *VAD) Thresholds() (local, global [3]int16) {
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return local, global
+	}
+	return v.inst.individual, v.inst.total
+}
+
+// SetHangover 直接设置语音结束后继续判为语音的迟滞帧数，代替模式预设
+//
+// 数组依次对应10ms、20ms、30ms帧，单位为帧。连续语音不超过6帧时结束后延续short帧，
+// 超过6帧时延续long帧，以免切掉词尾的弱辅音。预设值模式0/1为{8, 4, 3} / {14, 7, 5}，
+// 模式2/3为{6, 3, 2} / {9, 5, 3}；全为0时关闭迟滞。检验阈值保持不变；
+// 之后调用SetMode或SetAggressiveness会覆盖这里的设置
+//
+// 参数:
+//   - short: 短语音后的迟滞帧数，不能为负
+//   - long: 长语音后的迟滞帧数，不能为负
+func (v *VAD) SetHangover(short, long [3]int16) error {
+	for i := range short {
+		if short[i] < 0 || long[i] < 0 {
+			return fmt.Errorf("%w: short %v, long %v", ErrInvalidHangover, short, long)
+		}
+	}
+
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return ErrNotInitialized
+	}
+
+	v.inst.overHangMax1 = short
+	v.inst.overHangMax2 = long
+	return nil
+}
+
+// Hangover 返回当前短语音与长语音后的迟滞帧数（依次对应10ms、20ms、30ms帧），
+// 未初始化时返回零值
+func (v *VAD) Hangover() (short, long [3]int16) {
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return short, long
+	}
+	return v.inst.overHangMax1, v.inst.overHangMax2
+}
+
 // IsSpeech 检测音频帧中是否包含语音
 //
 // 参数:
 //   - buf: 16位小端序PCM音频数据（字节数组）
-//   - sampleRate: 采样率，必须是8000, 16000, 32000或48000 Hz
+//   - sampleRate: 采样率，必须是8000, 16000, 24000, 32000或48000 Hz
 //
 // 返回:
 //   - bool: true表示检测到语音，false表示静音或噪声
@@ -86,25 +195,27 @@ func (v *VAD) SetMode(mode int) error {
 //   - 音频帧长度必须是10ms、20ms或30ms
 //   - buf长度应该是 (sampleRate * frameDurationMs / 1000) * 2 字节
 func (v *VAD) IsSpeech(buf []byte, sampleRate int) (bool, error) {
-	if v.inst.initFlag != kInitCheck {
-		return false, errors.New("VAD not initialized")
-	}
-
-	// 验证采样率
-	if !isValidSampleRate(sampleRate) {
-		return false, fmt.Errorf("invalid sample rate: %d (must be 8000, 16000, 32000, or 48000)", sampleRate)
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return false, ErrNotInitialized
 	}
 
 	// 计算帧长度（样本数）
 	frameLength := len(buf) / 2 // 16位 = 2字节
 
-	// 验证帧长度
-	if !ValidRateAndFrameLength(sampleRate, frameLength) {
-		return false, fmt.Errorf("invalid frame length %d for sample rate %d", frameLength, sampleRate)
+	// 验证采样率和帧长度
+	if err := ValidateFrame(sampleRate, frameLength); err != nil {
+		return false, err
+	}
+
+	var begin time.Time
+	if v.timer != nil {
+		begin = time.Now()
 	}
 
 	// 将字节数组转换为int16数组
-	audioFrame := bytesToInt16(buf)
+	scratch := getSampleBuf()
+	defer putSampleBuf(scratch)
+	audioFrame := v.toInt16(buf, scratch[:])
 
 	// 处理音频并返回VAD决策
 	vad, err := process(v.inst, sampleRate, audioFrame)
@@ -112,6 +223,20 @@ func (v *VAD) IsSpeech(buf []byte, sampleRate int) (bool, error) {
 		return false, err
 	}
 
+	frameDuration := time.Duration(frameLength) * time.Second / time.Duration(sampleRate)
+	if v.timer != nil {
+		v.timer.observe(time.Since(begin), frameDuration)
+	}
+	v.stats.record(vad > 0)
+	v.elapsed += frameDuration
+	if v.logger != nil {
+		if clipped, started := v.clip.check(buf); started {
+			v.logger.Warn("input clipped", "samples", clipped, "frame_samples", frameLength)
+		}
+	}
+	if v.debug != nil {
+		v.writeDebug(sampleRate, frameLength, vad > 0)
+	}
 	return vad > 0, nil
 }
 
@@ -125,10 +250,10 @@ func (v *VAD) IsSpeech(buf []byte, sampleRate int) (bool, error) {
 //   - bool: true表示组合有效
 //
 // 有效的组合：
-//   - 采样率必须是8000, 16000, 32000或48000 Hz
+//   - 采样率必须是8000, 16000, 24000, 32000或48000 Hz
 //   - 帧长度必须对应10ms、20ms或30ms
 func ValidRateAndFrameLength(rate, frameLength int) bool {
-	validRates := []int{8000, 16000, 32000, 48000}
+	validRates := []int{8000, 16000, 24000, 32000, 48000}
 	maxFrameLengthMs := 30
 
 	// 检查采样率是否在有效列表中
@@ -148,9 +273,60 @@ func ValidRateAndFrameLength(rate, frameLength int) bool {
 	return false
 }
 
+// ValidateFrame 检查采样率和帧长度的组合，无效时返回说明原因的错误
+//
+// 与ValidRateAndFrameLength判断相同，但区分两类错误，便于定位最常见的接入错误：
+//   - 采样率不受支持：包装ErrInvalidSampleRate，并给出最接近的受支持采样率
+//   - 帧长度不对：包装ErrInvalidFrameLength，并给出该采样率下与之最接近的有效长度；
+//     长度恰为某个有效长度的2倍时提示可能误传了字节数
+//
+// 参数:
+//   - rate: 采样率（Hz）
+//   - frameLength: 帧长度（样本数）
+//
+// 返回:
+//   - error: 组合有效时为nil
+func ValidateFrame(rate, frameLength int) error {
+	if !isValidSampleRate(rate) {
+		// 受支持的采样率递增，最接近的是第一个不小于rate的采样率或其前一个
+		rates := []int{8000, 16000, 24000, 32000, 48000}
+		nearest := rates[len(rates)-1]
+		for i, r := range rates {
+			if r >= rate {
+				nearest = r
+				if i > 0 && rate-rates[i-1] < r-rate {
+					nearest = rates[i-1]
+				}
+				break
+			}
+		}
+		return fmt.Errorf("%w, got %d (nearest supported: %d)", ErrInvalidSampleRate, rate, nearest)
+	}
+	if ValidRateAndFrameLength(rate, frameLength) {
+		return nil
+	}
+
+	// 有效长度为10/20/30ms对应的样本数，取其两侧最接近的长度
+	step := rate / 100
+	var hint string
+	switch {
+	case frameLength < step:
+		hint = fmt.Sprintf("nearest valid length: %d (10 ms)", step)
+	case frameLength > 3*step:
+		hint = fmt.Sprintf("nearest valid length: %d (30 ms)", 3*step)
+	default:
+		lo := frameLength / step
+		hint = fmt.Sprintf("nearest valid lengths: %d (%d ms) or %d (%d ms)", lo*step, lo*10, (lo+1)*step, (lo+1)*10)
+	}
+	if frameLength%2 == 0 && ValidRateAndFrameLength(rate, frameLength/2) {
+		hint += fmt.Sprintf("; %d is the byte size of a %d-sample frame, was a byte count passed as the sample count?", frameLength, frameLength/2)
+	}
+	return fmt.Errorf("%w: got %d samples at %d Hz, %s", ErrInvalidFrameLength, frameLength, rate, hint)
+}
+
 // 辅助函数：检查采样率是否有效
 func isValidSampleRate(rate int) bool {
-	return rate == 8000 || rate == 16000 || rate == 32000 || rate == 48000
+	return rate == 8000 || rate == 16000 || rate == 24000 || rate == 32000 || rate == 48000
 }
 
 // IsSpeechBatch 批量检测多个音频帧
@@ -189,7 +365,7 @@ func (v *VAD) IsSpeechBatch(frames [][]byte, sampleRate int) ([]bool, error) {
 // 注意：此函数使用预分配的results数组，避免内存分配
 func (v *VAD) IsSpeechBatchTo(frames [][]byte, sampleRate int, results []bool) error {
 	if len(results) < len(frames) {
-		return errors.New("results array too small")
+		return fmt.Errorf("%w: results has %d entries, need %d", ErrBufferTooSmall, len(results), len(frames))
 	}
 	
 	for i, frame := range frames {
@@ -203,10 +379,112 @@ func (v *VAD) IsSpeechBatchTo(frames [][]byte, sampleRate int, results []bool) e
 	return nil
 }
 
+// Feed 以任意长度写入音频数据，按10ms帧输出检测结果
+//
+// 与IsSpeech不同，Feed内部缓冲不足一帧的数据，调用方无需自行分帧，
+// 适合采集回调按固定样本数（如512样本）交付音频的场景。
+//
+// 参数:
+//   - buf: 16位小端序PCM音频数据（任意长度）
+//   - sampleRate: 采样率
+//
+// 返回:
+//   - []bool: 本次凑满的每个10ms帧的检测结果（可能为空）
+//   - error: 错误信息
+//
+// 注意：采样率与上次调用不同时，未处理完的缓冲数据会被丢弃
+func (v *VAD) Feed(buf []byte, sampleRate int) ([]bool, error) {
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return nil, ErrNotInitialized
+	}
+
+	if !isValidSampleRate(sampleRate) {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidSampleRate, sampleRate)
+	}
+
+	if sampleRate != v.feedRate {
+		if len(v.feedBuf) > 0 && v.logger != nil {
+			v.logger.Warn("sample rate changed, buffered audio discarded",
+				"bytes", len(v.feedBuf), "old_rate", v.feedRate, "new_rate", sampleRate)
+		}
+		v.feedBuf = v.feedBuf[:0]
+		v.feedRate = sampleRate
+	}
+
+	v.feedBuf = append(v.feedBuf, buf...)
+
+	frameBytes := sampleRate / 100 * 2 // 10ms帧字节数
+	results := make([]bool, 0, len(v.feedBuf)/frameBytes)
+
+	offset := 0
+	for ; offset+frameBytes <= len(v.feedBuf); offset += frameBytes {
+		isSpeech, err := v.IsSpeech(v.feedBuf[offset:offset+frameBytes], sampleRate)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, isSpeech)
+	}
+
+	// 将剩余的不完整帧移到缓冲区开头
+	n := copy(v.feedBuf, v.feedBuf[offset:])
+	v.feedBuf = v.feedBuf[:n]
+
+	return results, nil
+}
+
+// FeedBuffered 返回Feed模式下尚未凑满一帧的缓冲字节数
+func (v *VAD) FeedBuffered() int {
+	return len(v.feedBuf)
+}
+
+// Prime 用已知的背景音频预热检测器
+//
+// 按10ms分帧将noiseSample送入检测器以初始化噪声模型和噪声底跟踪，
+// 但不返回判决结果，可减少真实音频流开头约一秒内的误检。
+// 预热结束后清除迟滞状态，避免预热音频中的误判延续到后续帧。
+//
+// 参数:
+//   - noiseSample: 背景音频（16位小端序PCM），末尾不足10ms的部分被忽略
+//   - sampleRate: 采样率
+func (v *VAD) Prime(noiseSample []byte, sampleRate int) error {
+	if v.inst == nil || v.inst.initFlag != kInitCheck {
+		return ErrNotInitialized
+	}
+
+	if !isValidSampleRate(sampleRate) {
+		return fmt.Errorf("%w, got %d", ErrInvalidSampleRate, sampleRate)
+	}
+
+	// 预热帧不计入判决统计与时间戳，也不输出调试跟踪
+	stats, elapsed, timer, debug := v.stats, v.elapsed, v.timer, v.debug
+	v.timer, v.debug = nil, nil
+	defer func() { v.stats, v.elapsed, v.timer, v.debug = stats, elapsed, timer, debug }()
+
+	frameBytes := sampleRate / 100 * 2 // 10ms帧字节数
+	for offset := 0; offset+frameBytes <= len(noiseSample); offset += frameBytes {
+		if _, err := v.IsSpeech(noiseSample[offset:offset+frameBytes], sampleRate); err != nil {
+			return err
+		}
+	}
+
+	v.inst.overHang = 0
+	v.inst.numOfSpeech = 0
+
+	if v.logger != nil {
+		v.logger.Debug("primed", "frames", len(noiseSample)/frameBytes, "rate", sampleRate)
+	}
+	return nil
+}
+
 // 辅助函数：将字节数组转换为int16数组（小端序）
 func bytesToInt16(buf []byte) []int16 {
+	return bytesToInt16To(buf, make([]int16, len(buf)/2))
+}
+
+// 辅助函数：将字节数组转换到预分配的int16数组（小端序），返回填充部分
+func bytesToInt16To(buf []byte, result []int16) []int16 {
 	length := len(buf) / 2
-	result := make([]int16, length)
+	result = result[:length]
 
 	for i := 0; i < length; i++ {
 		// 小端序：低字节在前
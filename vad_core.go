@@ -1,7 +1,7 @@
 package webrtcvad
 
 import (
-	"errors"
+	"fmt"
 )
 
 const (
@@ -116,10 +116,13 @@ type vadInst struct {
 	vad                      int
 	downsamplingFilterStates [4]int32
 	state48To8               state48khzTo8khz
+	state24To8               state24khzTo8khz
 	noiseMeans               [kTableSize]int16
 	speechMeans              [kTableSize]int16
 	noiseStds                [kTableSize]int16
 	speechStds               [kTableSize]int16
+	noiseWeights             [kTableSize]int16
+	speechWeights            [kTableSize]int16
 	frameCounter             int32
 	overHang                 int16
 	numOfSpeech              int16
@@ -133,12 +136,52 @@ type vadInst struct {
 	overHangMax2             [3]int16
 	individual               [3]int16
 	total                    [3]int16
-	initFlag                 int
+	features                 [kNumChannels]int16 // 最近一帧的频带对数能量（Q4）
+	totalPower               int16               // 最近一帧的总能量指示
+	bandActive               [kNumChannels]bool  // 最近一帧各频带的局部判决
+	bandLLR                  [kNumChannels]int16 // 最近一帧各频带的对数似然比（log2）
+	sumLLR                   int32               // 最近一帧频谱加权的对数似然比之和
+	frozenModel              bool                // 为true时不再逐帧更新GMM参数
+	model                    *Model              // SetModel设置的初始模型，nil表示内置表；reinitCore时恢复
+
+	// 降采样临时缓冲区，每帧复用以避免分配
+	// 重采样临时内存较大且只在单帧内使用，由pool.go共享
+	speechNB [240]int16 // 30ms的8kHz数据
+	speechWB [480]int16 // 30ms的16kHz数据（32kHz路径）
+	initFlag int
 }
 
 // state48khzTo8khz定义在spl.go中
 // 使用完整的多级重采样滤波器实现
 
+// reinitCore 重新初始化自适应状态；initCore会恢复默认模式和内置模型，
+// 因此保留当前的阈值与迟滞设置，并重新载入SetModel设置的模型
+func reinitCore(self *vadInst) error {
+	local, global := self.individual, self.total
+	hang1, hang2 := self.overHangMax1, self.overHangMax2
+	model := self.model
+	if err := initCore(self); err != nil {
+		return err
+	}
+	self.individual, self.total = local, global
+	self.overHangMax1, self.overHangMax2 = hang1, hang2
+	if model != nil {
+		loadModel(self, model)
+	}
+	return nil
+}
+
+// loadModel 载入模型参数并记录下来，供reinitCore恢复
+func loadModel(self *vadInst, m *Model) {
+	self.noiseMeans = m.NoiseMeans
+	self.speechMeans = m.SpeechMeans
+	self.noiseStds = m.NoiseStds
+	self.speechStds = m.SpeechStds
+	self.noiseWeights = m.NoiseWeights
+	self.speechWeights = m.SpeechWeights
+	self.model = m
+}
+
 // createVadInst 创建VAD实例
 func createVadInst() *vadInst {
 	inst := &vadInst{}
@@ -149,7 +192,7 @@ func createVadInst() *vadInst {
 // initCore 初始化VAD核心组件
 func initCore(self *vadInst) error {
 	if self == nil {
-		return errors.New("VAD instance is nil")
+		return fmt.Errorf("%w: instance is nil", ErrNotInitialized)
 	}
 
 	// 初始化通用结构变量
@@ -166,12 +209,17 @@ func initCore(self *vadInst) error {
 	// 初始化48kHz到8kHz降采样
 	resetResample48khzTo8khz(&self.state48To8)
 
+	// 初始化24kHz到8kHz降采样
+	resetResample24khzTo8khz(&self.state24To8)
+
 	// 读取初始PDF参数
 	for i := 0; i < kTableSize; i++ {
 		self.noiseMeans[i] = kNoiseDataMeans[i]
 		self.speechMeans[i] = kSpeechDataMeans[i]
 		self.noiseStds[i] = kNoiseDataStds[i]
 		self.speechStds[i] = kSpeechDataStds[i]
+		self.noiseWeights[i] = kNoiseDataWeights[i]
+		self.speechWeights[i] = kSpeechDataWeights[i]
 	}
 
 	// 初始化索引和最小值向量
@@ -232,7 +280,48 @@ func setModeCore(self *vadInst, mode int) error {
 		copy(self.individual[:], kLocalThresholdVAG[:])
 		copy(self.total[:], kGlobalThresholdVAG[:])
 	default:
-		return errors.New("invalid mode")
+		return ErrInvalidMode
+	}
+
+	return nil
+}
+
+// modeTables 按模式索引的阈值表，用于连续激进度插值
+var modeTables = [4]struct {
+	overHangMax1, overHangMax2, individual, total *[3]int16
+}{
+	{&kOverHangMax1Q, &kOverHangMax2Q, &kLocalThresholdQ, &kGlobalThresholdQ},
+	{&kOverHangMax1LBR, &kOverHangMax2LBR, &kLocalThresholdLBR, &kGlobalThresholdLBR},
+	{&kOverHangMax1AGG, &kOverHangMax2AGG, &kLocalThresholdAGG, &kGlobalThresholdAGG},
+	{&kOverHangMax1VAG, &kOverHangMax2VAG, &kLocalThresholdVAG, &kGlobalThresholdVAG},
+}
+
+// setAggressivenessCore 按连续激进度设置阈值
+//
+// aggressiveness在[0, 1]内线性映射到模式0-3，
+// 在相邻两个模式的阈值表之间线性插值（四舍五入到整数）
+func setAggressivenessCore(self *vadInst, aggressiveness float64) error {
+	if !(aggressiveness >= 0 && aggressiveness <= 1) { // 同时拒绝NaN
+		return ErrInvalidAggressiveness
+	}
+
+	pos := aggressiveness * 3
+	lower := int(pos)
+	if lower > 2 {
+		lower = 2
+	}
+	frac := pos - float64(lower)
+
+	lo, hi := modeTables[lower], modeTables[lower+1]
+	lerp := func(a, b int16) int16 {
+		return int16(float64(a) + (float64(b)-float64(a))*frac + 0.5)
+	}
+
+	for i := 0; i < 3; i++ {
+		self.overHangMax1[i] = lerp(lo.overHangMax1[i], hi.overHangMax1[i])
+		self.overHangMax2[i] = lerp(lo.overHangMax2[i], hi.overHangMax2[i])
+		self.individual[i] = lerp(lo.individual[i], hi.individual[i])
+		self.total[i] = lerp(lo.total[i], hi.total[i])
 	}
 
 	return nil
@@ -241,20 +330,20 @@ func setModeCore(self *vadInst, mode int) error {
 // process 处理音频帧并返回VAD决策
 func process(inst *vadInst, fs int, audioFrame []int16) (int, error) {
 	if inst == nil {
-		return -1, errors.New("VAD instance is nil")
+		return -1, fmt.Errorf("%w: instance is nil", ErrNotInitialized)
 	}
 
 	if inst.initFlag != kInitCheck {
-		return -1, errors.New("VAD not initialized")
+		return -1, ErrNotInitialized
 	}
 
 	if len(audioFrame) == 0 {
-		return -1, errors.New("audio frame is nil or empty")
+		return -1, fmt.Errorf("%w: empty frame", ErrInvalidFrameLength)
 	}
 
 	frameLength := len(audioFrame)
 	if !ValidRateAndFrameLength(fs, frameLength) {
-		return -1, errors.New("invalid rate and frame length")
+		return -1, fmt.Errorf("%w: got %d samples at %d Hz", ErrInvalidFrameLength, frameLength, fs)
 	}
 
 	var vad int
@@ -265,12 +354,14 @@ func process(inst *vadInst, fs int, audioFrame []int16) (int, error) {
 		vad, err = calcVad48khz(inst, audioFrame, frameLength)
 	case 32000:
 		vad, err = calcVad32khz(inst, audioFrame, frameLength)
+	case 24000:
+		vad, err = calcVad24khz(inst, audioFrame, frameLength)
 	case 16000:
 		vad, err = calcVad16khz(inst, audioFrame, frameLength)
 	case 8000:
 		vad, err = calcVad8khz(inst, audioFrame, frameLength)
 	default:
-		return -1, errors.New("unsupported sample rate")
+		return -1, fmt.Errorf("%w, got %d", ErrInvalidSampleRate, fs)
 	}
 
 	if err != nil {
@@ -2,10 +2,11 @@ package webrtcvad
 
 // calcVad8khz 计算8kHz音频的VAD
 func calcVad8khz(inst *vadInst, speechFrame []int16, frameLength int) (int, error) {
-	featureVector := make([]int16, kNumChannels)
+	featureVector := inst.features[:]
 
 	// 获取频带能量
 	totalPower := calculateFeatures(inst, speechFrame, frameLength, featureVector)
+	inst.totalPower = totalPower
 
 	// 执行VAD判决
 	inst.vad = int(gmmProbability(inst, featureVector, totalPower, frameLength))
@@ -15,7 +16,7 @@ func calcVad8khz(inst *vadInst, speechFrame []int16, frameLength int) (int, erro
 
 // calcVad16khz 计算16kHz音频的VAD
 func calcVad16khz(inst *vadInst, speechFrame []int16, frameLength int) (int, error) {
-	speechNB := make([]int16, 240) // 降采样后的语音帧：480样本（30ms宽带）
+	speechNB := inst.speechNB[:] // 降采样后的语音帧：480样本（30ms宽带）
 
 	// 宽带：在执行VAD前降采样
 	downsampling(speechFrame, speechNB, inst.downsamplingFilterStates[:], frameLength)
@@ -28,8 +29,8 @@ func calcVad16khz(inst *vadInst, speechFrame []int16, frameLength int) (int, err
 
 // calcVad32khz 计算32kHz音频的VAD
 func calcVad32khz(inst *vadInst, speechFrame []int16, frameLength int) (int, error) {
-	speechWB := make([]int16, 480) // 降采样后的语音帧：960样本（30ms超宽带）
-	speechNB := make([]int16, 240) // 降采样后的语音帧：480样本（30ms宽带）
+	speechWB := inst.speechWB[:] // 降采样后的语音帧：960样本（30ms超宽带）
+	speechNB := inst.speechNB[:] // 降采样后的语音帧：480样本（30ms宽带）
 
 	// 降采样信号 32->16->8 然后执行VAD
 	downsampling(speechFrame, speechWB, inst.downsamplingFilterStates[2:], frameLength)
@@ -44,6 +45,40 @@ func calcVad32khz(inst *vadInst, speechFrame []int16, frameLength int) (int, err
 	return vad, err
 }
 
+// calcVad24khz 计算24kHz音频的VAD
+//
+// 24kHz常见于Opus解码输出，按10ms分块重采样到8kHz后执行VAD
+func calcVad24khz(inst *vadInst, speechFrame []int16, frameLength int) (int, error) {
+	const (
+		kFrameLen10ms24khz = 240
+		kFrameLen10ms8khz  = 80
+	)
+
+	speechNB := inst.speechNB[:] // 30ms的8kHz数据
+	tmpMem := getTmpMem()
+	defer putTmpMem(tmpMem)
+
+	num10msFrames := frameLength / kFrameLen10ms24khz
+
+	for i := 0; i < num10msFrames; i++ {
+		startIdx := i * kFrameLen10ms24khz
+		endIdx := startIdx + kFrameLen10ms24khz
+		outStartIdx := i * kFrameLen10ms8khz
+
+		resample24khzTo8khz(
+			speechFrame[startIdx:endIdx],
+			speechNB[outStartIdx:outStartIdx+kFrameLen10ms8khz],
+			&inst.state24To8,
+			tmpMem[:],
+		)
+	}
+
+	// 在8kHz信号上执行VAD
+	vad, err := calcVad8khz(inst, speechNB, frameLength/3)
+
+	return vad, err
+}
+
 // calcVad48khz 计算48kHz音频的VAD
 func calcVad48khz(inst *vadInst, speechFrame []int16, frameLength int) (int, error) {
 	const (
@@ -51,8 +86,9 @@ func calcVad48khz(inst *vadInst, speechFrame []int16, frameLength int) (int, err
 		kFrameLen10ms8khz  = 80
 	)
 
-	speechNB := make([]int16, 240) // 30ms的8kHz数据
-	tmpMem := make([]int32, 480+256)
+	speechNB := inst.speechNB[:] // 30ms的8kHz数据
+	tmpMem := getTmpMem()
+	defer putTmpMem(tmpMem)
 
 	num10msFrames := frameLength / kFrameLen10ms48khz
 
@@ -65,7 +101,7 @@ func calcVad48khz(inst *vadInst, speechFrame []int16, frameLength int) (int, err
 			speechFrame[startIdx:endIdx],
 			speechNB[outStartIdx:outStartIdx+kFrameLen10ms8khz],
 			&inst.state48To8,
-			tmpMem,
+			tmpMem[:],
 		)
 	}
 
@@ -151,6 +187,11 @@ func gmmProbability(self *vadInst, features []int16, totalPower int16, frameLeng
 		totalTest = self.total[2]
 	}
 
+	// 清除上一帧的频带局部判决和似然比
+	self.bandActive = [kNumChannels]bool{}
+	self.bandLLR = [kNumChannels]int16{}
+	self.sumLLR = 0
+
 	if totalPower > kMinEnergy {
 		// 当前帧的信号功率足够大，可以处理
 		// 处理包含两部分：
@@ -180,7 +221,7 @@ func gmmProbability(self *vadInst, features []int16, totalPower int16, frameLeng
 					self.noiseStds[gaussian],
 					&deltaN[gaussian],
 				)
-				noiseProbability[k] = int32(kNoiseDataWeights[gaussian]) * tmp1S32
+				noiseProbability[k] = int32(self.noiseWeights[gaussian]) * tmp1S32
 				h0Test += noiseProbability[k] // Q27
 
 				// H1下的概率，即帧为语音的概率
@@ -191,7 +232,7 @@ func gmmProbability(self *vadInst, features []int16, totalPower int16, frameLeng
 					self.speechStds[gaussian],
 					&deltaS[gaussian],
 				)
-				speechProbability[k] = int32(kSpeechDataWeights[gaussian]) * tmp1S32
+				speechProbability[k] = int32(self.speechWeights[gaussian]) * tmp1S32
 				h1Test += speechProbability[k] // Q27
 			}
 
@@ -207,6 +248,7 @@ func gmmProbability(self *vadInst, features []int16, totalPower int16, frameLeng
 				shiftsH1 = 31
 			}
 			logLikelihoodRatio = shiftsH0 - shiftsH1
+			self.bandLLR[channel] = logLikelihoodRatio
 
 			// 用频谱权重更新sum_log_likelihood_ratios
 			// 这用于全局VAD决策
@@ -215,6 +257,7 @@ func gmmProbability(self *vadInst, features []int16, totalPower int16, frameLeng
 			// 局部VAD决策
 			if (logLikelihoodRatio * 4) > individualTest {
 				vadflag = 1
+				self.bandActive[channel] = true
 			}
 
 			// 计算局部噪声概率（稍后更新GMM时使用）
@@ -239,204 +282,208 @@ func gmmProbability(self *vadInst, features []int16, totalPower int16, frameLeng
 			}
 		}
 
+		self.sumLLR = sumLogLikelihoodRatio
+
 		// 做出全局VAD决策
 		if sumLogLikelihoodRatio >= int32(totalTest) {
 			vadflag = 1
 		}
 
-		// 更新模型参数
-		maxspe = 12800
-		for channel = 0; channel < kNumChannels; channel++ {
-			// 获取过去的最小值，用于长期修正，Q4格式
-			featureMinimum = findMinimum(self, features[channel], channel)
-
-			// 计算"全局"均值，即两个均值的加权和
-			noiseGlobalMean = weightedAverage(
-				self.noiseMeans[channel:],
-				0,
-				kNoiseDataWeights[channel:],
-			)
-			tmp1S16 = int16(noiseGlobalMean >> 6) // Q8
+		if !self.frozenModel {
+			// 更新模型参数（冻结模型时跳过，保持GMM参数不变）
+			maxspe = 12800
+			for channel = 0; channel < kNumChannels; channel++ {
+				// 获取过去的最小值，用于长期修正，Q4格式
+				featureMinimum = findMinimum(self, features[channel], channel)
 
-			for k = 0; k < kNumGaussians; k++ {
-				gaussian = channel + k*kNumChannels
+				// 计算"全局"均值，即两个均值的加权和
+				noiseGlobalMean = weightedAverage(
+					self.noiseMeans[channel:],
+					0,
+					self.noiseWeights[channel:],
+				)
+				tmp1S16 = int16(noiseGlobalMean >> 6) // Q8
 
-				nmk = self.noiseMeans[gaussian]
-				smk = self.speechMeans[gaussian]
-				nsk = self.noiseStds[gaussian]
-				ssk = self.speechStds[gaussian]
-
-				// 如果帧只包含噪声，更新噪声均值向量
-				nmk2 = nmk
-				if vadflag == 0 {
-					// deltaN = (x-mu)/sigma^2
-					// ngprvec[k] = |noise_probability[k]| /
-					//   (|noise_probability[0]| + |noise_probability[1]|)
-
-					// (Q14 * Q11 >> 11) = Q14
-					delt = int16((int32(ngprvec[gaussian]) * int32(deltaN[gaussian])) >> 11)
-					// Q7 + (Q14 * Q15 >> 22) = Q7
-					nmk2 = nmk + int16((int32(delt)*kNoiseUpdateConst)>>22)
-				}
+				for k = 0; k < kNumGaussians; k++ {
+					gaussian = channel + k*kNumChannels
+
+					nmk = self.noiseMeans[gaussian]
+					smk = self.speechMeans[gaussian]
+					nsk = self.noiseStds[gaussian]
+					ssk = self.speechStds[gaussian]
+
+					// 如果帧只包含噪声，更新噪声均值向量
+					nmk2 = nmk
+					if vadflag == 0 {
+						// deltaN = (x-mu)/sigma^2
+						// ngprvec[k] = |noise_probability[k]| /
+						//   (|noise_probability[0]| + |noise_probability[1]|)
+
+						// (Q14 * Q11 >> 11) = Q14
+						delt = int16((int32(ngprvec[gaussian]) * int32(deltaN[gaussian])) >> 11)
+						// Q7 + (Q14 * Q15 >> 22) = Q7
+						nmk2 = nmk + int16((int32(delt)*kNoiseUpdateConst)>>22)
+					}
 
-				// 噪声均值的长期修正
-				// Q8 - Q8 = Q8
-				ndelt = (featureMinimum << 4) - tmp1S16
-				// Q7 + (Q8 * Q8) >> 9 = Q7
-				nmk3 = nmk2 + int16((int32(ndelt)*kBackEta)>>9)
+					// 噪声均值的长期修正
+					// Q8 - Q8 = Q8
+					ndelt = (featureMinimum << 4) - tmp1S16
+					// Q7 + (Q8 * Q8) >> 9 = Q7
+					nmk3 = nmk2 + int16((int32(ndelt)*kBackEta)>>9)
 
-				// 控制噪声均值不要漂移太多
-				tmpS16 = int16((k + 5) << 7)
-				if nmk3 < tmpS16 {
-					nmk3 = tmpS16
-				}
-				tmpS16 = int16((72 + k - channel) << 7)
-				if nmk3 > tmpS16 {
-					nmk3 = tmpS16
-				}
-				self.noiseMeans[gaussian] = nmk3
-
-				if vadflag != 0 {
-					// 更新语音均值向量：
-					// |deltaS| = (x-mu)/sigma^2
-					// sgprvec[k] = |speech_probability[k]| /
-					//   (|speech_probability[0]| + |speech_probability[1]|)
-
-					// (Q14 * Q11) >> 11 = Q14
-					delt = int16((int32(sgprvec[gaussian]) * int32(deltaS[gaussian])) >> 11)
-					// Q14 * Q15 >> 21 = Q8
-					tmpS16 = int16((int32(delt) * kSpeechUpdateConst) >> 21)
-					// Q7 + (Q8 >> 1) = Q7。带舍入
-					smk2 = smk + ((tmpS16 + 1) >> 1)
-
-					// 控制语音均值不要漂移太多
-					maxmu = maxspe + 640
-					if smk2 < kMinimumMean[k] {
-						smk2 = kMinimumMean[k]
+					// 控制噪声均值不要漂移太多
+					tmpS16 = int16((k + 5) << 7)
+					if nmk3 < tmpS16 {
+						nmk3 = tmpS16
 					}
-					if smk2 > maxmu {
-						smk2 = maxmu
+					tmpS16 = int16((72 + k - channel) << 7)
+					if nmk3 > tmpS16 {
+						nmk3 = tmpS16
 					}
-					self.speechMeans[gaussian] = smk2 // Q7
-
-					// (Q7 >> 3) = Q4。带舍入
-					tmpS16 = (smk + 4) >> 3
-					tmpS16 = features[channel] - tmpS16 // Q4
-					// (Q11 * Q4 >> 3) = Q12
-					tmp1S32 = (int32(deltaS[gaussian]) * int32(tmpS16)) >> 3
-					tmp2S32 = tmp1S32 - 4096
-					tmpS16 = sgprvec[gaussian] >> 2
-					// (Q14 >> 2) * Q12 = Q24
-					tmp1S32 = int32(tmpS16) * tmp2S32
-
-					tmp2S32 = tmp1S32 >> 4 // Q20
-
-					// 0.1 * Q20 / Q7 = Q13
-					if tmp2S32 > 0 {
-						tmpS16 = int16(divW32W16(tmp2S32, ssk*10))
+					self.noiseMeans[gaussian] = nmk3
+
+					if vadflag != 0 {
+						// 更新语音均值向量：
+						// |deltaS| = (x-mu)/sigma^2
+						// sgprvec[k] = |speech_probability[k]| /
+						//   (|speech_probability[0]| + |speech_probability[1]|)
+
+						// (Q14 * Q11) >> 11 = Q14
+						delt = int16((int32(sgprvec[gaussian]) * int32(deltaS[gaussian])) >> 11)
+						// Q14 * Q15 >> 21 = Q8
+						tmpS16 = int16((int32(delt) * kSpeechUpdateConst) >> 21)
+						// Q7 + (Q8 >> 1) = Q7。带舍入
+						smk2 = smk + ((tmpS16 + 1) >> 1)
+
+						// 控制语音均值不要漂移太多
+						maxmu = maxspe + 640
+						if smk2 < kMinimumMean[k] {
+							smk2 = kMinimumMean[k]
+						}
+						if smk2 > maxmu {
+							smk2 = maxmu
+						}
+						self.speechMeans[gaussian] = smk2 // Q7
+
+						// (Q7 >> 3) = Q4。带舍入
+						tmpS16 = (smk + 4) >> 3
+						tmpS16 = features[channel] - tmpS16 // Q4
+						// (Q11 * Q4 >> 3) = Q12
+						tmp1S32 = (int32(deltaS[gaussian]) * int32(tmpS16)) >> 3
+						tmp2S32 = tmp1S32 - 4096
+						tmpS16 = sgprvec[gaussian] >> 2
+						// (Q14 >> 2) * Q12 = Q24
+						tmp1S32 = int32(tmpS16) * tmp2S32
+
+						tmp2S32 = tmp1S32 >> 4 // Q20
+
+						// 0.1 * Q20 / Q7 = Q13
+						if tmp2S32 > 0 {
+							tmpS16 = int16(divW32W16(tmp2S32, ssk*10))
+						} else {
+							tmpS16 = int16(divW32W16(-tmp2S32, ssk*10))
+							tmpS16 = -tmpS16
+						}
+						// 除以4，更新因子为0.025 (= 0.1 / 4)
+						// 除以4等于右移2位，因此
+						// (Q13 >> 8) = (Q13 >> 6) / 4 = Q7
+						tmpS16 += 128 // 舍入
+						ssk += tmpS16 >> 8
+						if ssk < kMinStd {
+							ssk = kMinStd
+						}
+						self.speechStds[gaussian] = ssk
 					} else {
-						tmpS16 = int16(divW32W16(-tmp2S32, ssk*10))
-						tmpS16 = -tmpS16
+						// 更新GMM方差向量
+						// deltaN * (features[channel] - nmk) - 1
+						// Q4 - (Q7 >> 3) = Q4
+						tmpS16 = features[channel] - (nmk >> 3)
+						// (Q11 * Q4 >> 3) = Q12
+						tmp1S32 = (int32(deltaN[gaussian]) * int32(tmpS16)) >> 3
+						tmp1S32 -= 4096
+
+						// (Q14 >> 2) * Q12 = Q24
+						tmpS16 = (ngprvec[gaussian] + 2) >> 2
+						tmp2S32 = overflowingMulS16ByS32ToS32(tmpS16, tmp1S32)
+						// Q20 * 约0.001 (2^-10=0.0009766)，因此
+						// (Q24 >> 14) = (Q24 >> 4) / 2^10 = Q20
+						tmp1S32 = tmp2S32 >> 14
+
+						// Q20 / Q7 = Q13
+						if tmp1S32 > 0 {
+							tmpS16 = int16(divW32W16(tmp1S32, nsk))
+						} else {
+							tmpS16 = int16(divW32W16(-tmp1S32, nsk))
+							tmpS16 = -tmpS16
+						}
+						tmpS16 += 32       // 舍入
+						nsk += tmpS16 >> 6 // Q13 >> 6 = Q7
+						if nsk < kMinStd {
+							nsk = kMinStd
+						}
+						self.noiseStds[gaussian] = nsk
 					}
-					// 除以4，更新因子为0.025 (= 0.1 / 4)
-					// 除以4等于右移2位，因此
-					// (Q13 >> 8) = (Q13 >> 6) / 4 = Q7
-					tmpS16 += 128 // 舍入
-					ssk += tmpS16 >> 8
-					if ssk < kMinStd {
-						ssk = kMinStd
-					}
-					self.speechStds[gaussian] = ssk
-				} else {
-					// 更新GMM方差向量
-					// deltaN * (features[channel] - nmk) - 1
-					// Q4 - (Q7 >> 3) = Q4
-					tmpS16 = features[channel] - (nmk >> 3)
-					// (Q11 * Q4 >> 3) = Q12
-					tmp1S32 = (int32(deltaN[gaussian]) * int32(tmpS16)) >> 3
-					tmp1S32 -= 4096
-
-					// (Q14 >> 2) * Q12 = Q24
-					tmpS16 = (ngprvec[gaussian] + 2) >> 2
-					tmp2S32 = overflowingMulS16ByS32ToS32(tmpS16, tmp1S32)
-					// Q20 * 约0.001 (2^-10=0.0009766)，因此
-					// (Q24 >> 14) = (Q24 >> 4) / 2^10 = Q20
-					tmp1S32 = tmp2S32 >> 14
-
-					// Q20 / Q7 = Q13
-					if tmp1S32 > 0 {
-						tmpS16 = int16(divW32W16(tmp1S32, nsk))
-					} else {
-						tmpS16 = int16(divW32W16(-tmp1S32, nsk))
-						tmpS16 = -tmpS16
-					}
-					tmpS16 += 32       // 舍入
-					nsk += tmpS16 >> 6 // Q13 >> 6 = Q7
-					if nsk < kMinStd {
-						nsk = kMinStd
-					}
-					self.noiseStds[gaussian] = nsk
 				}
-			}
 
-			// 如果模型太接近，分离它们
-			// noiseGlobalMean以Q14表示 (= Q7 * Q7)
-			noiseGlobalMean = weightedAverage(
-				self.noiseMeans[channel:],
-				0,
-				kNoiseDataWeights[channel:],
-			)
-
-			// speechGlobalMean以Q14表示 (= Q7 * Q7)
-			speechGlobalMean = weightedAverage(
-				self.speechMeans[channel:],
-				0,
-				kSpeechDataWeights[channel:],
-			)
-
-			// diff = "全局"语音均值 - "全局"噪声均值
-			// (Q14 >> 9) - (Q14 >> 9) = Q5
-			diff = int16(speechGlobalMean>>9) - int16(noiseGlobalMean>>9)
-
-			if diff < kMinimumDifference[channel] {
-				tmpS16 = kMinimumDifference[channel] - diff
-
-				// tmp1S16 = ~0.8 * (kMinimumDifference - diff)，Q7
-				// tmp2S16 = ~0.2 * (kMinimumDifference - diff)，Q7
-				tmp1S16 = int16((13 * int32(tmpS16)) >> 2)
-				tmp2S16 = int16((3 * int32(tmpS16)) >> 2)
-
-				// 为语音模型移动高斯均值tmp1S16，并更新speechGlobalMean
+				// 如果模型太接近，分离它们
+				// noiseGlobalMean以Q14表示 (= Q7 * Q7)
+				noiseGlobalMean = weightedAverage(
+					self.noiseMeans[channel:],
+					0,
+					self.noiseWeights[channel:],
+				)
+
+				// speechGlobalMean以Q14表示 (= Q7 * Q7)
 				speechGlobalMean = weightedAverage(
 					self.speechMeans[channel:],
-					tmp1S16,
-					kSpeechDataWeights[channel:],
+					0,
+					self.speechWeights[channel:],
 				)
 
-				// 为噪声模型移动高斯均值-tmp2S16，并更新noiseGlobalMean
-				noiseGlobalMean = weightedAverage(
-					self.noiseMeans[channel:],
-					-tmp2S16,
-					kNoiseDataWeights[channel:],
-				)
-			}
+				// diff = "全局"语音均值 - "全局"噪声均值
+				// (Q14 >> 9) - (Q14 >> 9) = Q5
+				diff = int16(speechGlobalMean>>9) - int16(noiseGlobalMean>>9)
+
+				if diff < kMinimumDifference[channel] {
+					tmpS16 = kMinimumDifference[channel] - diff
+
+					// tmp1S16 = ~0.8 * (kMinimumDifference - diff)，Q7
+					// tmp2S16 = ~0.2 * (kMinimumDifference - diff)，Q7
+					tmp1S16 = int16((13 * int32(tmpS16)) >> 2)
+					tmp2S16 = int16((3 * int32(tmpS16)) >> 2)
+
+					// 为语音模型移动高斯均值tmp1S16，并更新speechGlobalMean
+					speechGlobalMean = weightedAverage(
+						self.speechMeans[channel:],
+						tmp1S16,
+						self.speechWeights[channel:],
+					)
+
+					// 为噪声模型移动高斯均值-tmp2S16，并更新noiseGlobalMean
+					noiseGlobalMean = weightedAverage(
+						self.noiseMeans[channel:],
+						-tmp2S16,
+						self.noiseWeights[channel:],
+					)
+				}
 
-			// 控制语音和噪声均值不要漂移太多
-			maxspe = kMaximumSpeech[channel]
-			tmp2S16 = int16(speechGlobalMean >> 7)
-			if tmp2S16 > maxspe {
-				// 语音模型的上限
-				tmp2S16 -= maxspe
-				for k = 0; k < kNumGaussians; k++ {
-					self.speechMeans[channel+k*kNumChannels] -= tmp2S16
+				// 控制语音和噪声均值不要漂移太多
+				maxspe = kMaximumSpeech[channel]
+				tmp2S16 = int16(speechGlobalMean >> 7)
+				if tmp2S16 > maxspe {
+					// 语音模型的上限
+					tmp2S16 -= maxspe
+					for k = 0; k < kNumGaussians; k++ {
+						self.speechMeans[channel+k*kNumChannels] -= tmp2S16
+					}
 				}
-			}
 
-			tmp2S16 = int16(noiseGlobalMean >> 7)
-			if tmp2S16 > kMaximumNoise[channel] {
-				tmp2S16 -= kMaximumNoise[channel]
-				for k = 0; k < kNumGaussians; k++ {
-					self.noiseMeans[channel+k*kNumChannels] -= tmp2S16
+				tmp2S16 = int16(noiseGlobalMean >> 7)
+				if tmp2S16 > kMaximumNoise[channel] {
+					tmp2S16 -= kMaximumNoise[channel]
+					for k = 0; k < kNumGaussians; k++ {
+						self.noiseMeans[channel+k*kNumChannels] -= tmp2S16
+					}
 				}
 			}
 		}
@@ -14,7 +14,7 @@ const (
 
 // downsampling 基于分割滤波器和全通函数的降采样滤波器
 //
-// 通过因子2降采样信号，例如 32->16 或 16->8
+// 通过因子2降采样信号，例如 32->16 或 16->8；公开接口见DownsampleBy2
 //
 // 输入：
 //   - signalIn：输入信号
@@ -70,8 +70,6 @@ func downsampling(signalIn, signalOut []int16, filterState []int32, inLength int
 // 返回：移动窗口的平滑最小值
 func findMinimum(self *vadInst, featureValue int16, channel int) int16 {
 	var (
-		i, j          int
-		position      int   = -1
 		offset        int   = channel << 4 // 偏移到内存中16个最小值的起始位置
 		currentMedian int16 = 1600
 		alpha         int16 = 0
@@ -82,82 +80,8 @@ func findMinimum(self *vadInst, featureValue int16, channel int) int16 {
 	age := self.indexVector[offset : offset+16]
 	smallestValues := self.lowValueVector[offset : offset+16]
 
-	// smallestValues中的每个值都老了1个循环。更新age，并移除旧值
-	for i = 0; i < 16; i++ {
-		if age[i] != 100 {
-			age[i]++
-		} else {
-			// 值太旧，从内存中移除并向下移动较大的值
-			for j = i; j < 15; j++ {
-				smallestValues[j] = smallestValues[j+1]
-				age[j] = age[j+1]
-			}
-			age[15] = 101
-			smallestValues[15] = 10000
-		}
-	}
-
-	// 检查featureValue是否小于smallestValues中的任何值
-	// 如果是，找到要插入新值（featureValue）的位置
-	if featureValue < smallestValues[7] {
-		if featureValue < smallestValues[3] {
-			if featureValue < smallestValues[1] {
-				if featureValue < smallestValues[0] {
-					position = 0
-				} else {
-					position = 1
-				}
-			} else if featureValue < smallestValues[2] {
-				position = 2
-			} else {
-				position = 3
-			}
-		} else if featureValue < smallestValues[5] {
-			if featureValue < smallestValues[4] {
-				position = 4
-			} else {
-				position = 5
-			}
-		} else if featureValue < smallestValues[6] {
-			position = 6
-		} else {
-			position = 7
-		}
-	} else if featureValue < smallestValues[15] {
-		if featureValue < smallestValues[11] {
-			if featureValue < smallestValues[9] {
-				if featureValue < smallestValues[8] {
-					position = 8
-				} else {
-					position = 9
-				}
-			} else if featureValue < smallestValues[10] {
-				position = 10
-			} else {
-				position = 11
-			}
-		} else if featureValue < smallestValues[13] {
-			if featureValue < smallestValues[12] {
-				position = 12
-			} else {
-				position = 13
-			}
-		} else if featureValue < smallestValues[14] {
-			position = 14
-		} else {
-			position = 15
-		}
-	}
-
-	// 如果检测到新的小值，将其插入正确位置并向上移动较大的值
-	if position > -1 {
-		for i = 15; i > position; i-- {
-			smallestValues[i] = smallestValues[i-1]
-			age[i] = age[i-1]
-		}
-		smallestValues[position] = featureValue
-		age[position] = 1
-	}
+	// 老化并移除超过100帧的值，若featureValue小于其中某个值则插入
+	updateMinima(smallestValues, age, featureValue, 100, 10000)
 
 	// 获取currentMedian
 	if self.frameCounter > 2 {
@@ -1,8 +1,11 @@
 package webrtcvad
 
 import (
+	"errors"
 	"io"
+	"math"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -44,6 +47,90 @@ func TestSetMode(t *testing.T) {
 	}
 }
 
+// TestSetAggressiveness 测试连续激进度
+func TestSetAggressiveness(t *testing.T) {
+	vad, err := New(0)
+	if err != nil {
+		t.Fatalf("Failed to create VAD: %v", err)
+	}
+
+	// 端点与离散模式等价
+	for mode, a := range []float64{0, 1.0 / 3, 2.0 / 3, 1} {
+		if err := vad.SetAggressiveness(a); err != nil {
+			t.Fatalf("SetAggressiveness(%g) failed: %v", a, err)
+		}
+		got := vad.inst.total
+		if err := vad.SetMode(mode); err != nil {
+			t.Fatalf("SetMode(%d) failed: %v", mode, err)
+		}
+		if got != vad.inst.total {
+			t.Errorf("SetAggressiveness(%g) thresholds %v, mode %d thresholds %v", a, got, mode, vad.inst.total)
+		}
+	}
+
+	// 中间值位于相邻模式之间
+	if err := vad.SetAggressiveness(0.5); err != nil {
+		t.Fatalf("SetAggressiveness(0.5) failed: %v", err)
+	}
+	if vad.inst.total[0] <= kGlobalThresholdLBR[0] || vad.inst.total[0] >= kGlobalThresholdAGG[0] {
+		t.Errorf("Expected threshold between modes 1 and 2, got %d", vad.inst.total[0])
+	}
+
+	for _, a := range []float64{-0.1, 1.1, math.NaN()} {
+		if err := vad.SetAggressiveness(a); !errors.Is(err, ErrInvalidAggressiveness) {
+			t.Errorf("SetAggressiveness(%g): expected ErrInvalidAggressiveness, got %v", a, err)
+		}
+	}
+}
+
+// TestSetThresholds 测试直接设置检验阈值
+func TestSetThresholds(t *testing.T) {
+	vad, err := New(0)
+	if err != nil {
+		t.Fatalf("Failed to create VAD: %v", err)
+	}
+
+	local, global := [3]int16{30, 31, 32}, [3]int16{200, 210, 220}
+	if err := vad.SetThresholds(local, global); err != nil {
+		t.Fatalf("SetThresholds failed: %v", err)
+	}
+	if l, g := vad.Thresholds(); l != local || g != global {
+		t.Errorf("Thresholds() = %v, %v, want %v, %v", l, g, local, global)
+	}
+
+	// 全局阈值无法达到时，强信号也不判为语音
+	unreachable := [3]int16{math.MaxInt16, math.MaxInt16, math.MaxInt16}
+	if err := vad.SetThresholds(unreachable, unreachable); err != nil {
+		t.Fatalf("SetThresholds failed: %v", err)
+	}
+	frame := sineFrame(300, 10000, 8000, 80)
+	for i := 0; i < 20; i++ {
+		res, err := vad.ProcessDetailed(frame, 8000)
+		if err != nil {
+			t.Fatalf("ProcessDetailed failed: %v", err)
+		}
+		if res.IsSpeech || res.BandActive != ([NumChannels]bool{}) {
+			t.Fatalf("frame %d: expected no speech with unreachable thresholds, got %+v", i, res)
+		}
+	}
+
+	// SetMode恢复预设阈值
+	if err := vad.SetMode(3); err != nil {
+		t.Fatalf("SetMode failed: %v", err)
+	}
+	if l, g := vad.Thresholds(); l != kLocalThresholdVAG || g != kGlobalThresholdVAG {
+		t.Errorf("SetMode(3) should restore preset thresholds, got %v, %v", l, g)
+	}
+
+	if err := vad.SetThresholds([3]int16{-1, 0, 0}, global); !errors.Is(err, ErrInvalidThresholds) {
+		t.Errorf("expected ErrInvalidThresholds, got %v", err)
+	}
+
+	if l, g := (&VAD{}).Thresholds(); l != ([3]int16{}) || g != ([3]int16{}) {
+		t.Errorf("uninitialized Thresholds() = %v, %v, want zero values", l, g)
+	}
+}
+
 // TestValidRateAndFrameLength 测试采样率和帧长度验证
 func TestValidRateAndFrameLength(t *testing.T) {
 	tests := []struct {
@@ -57,6 +144,9 @@ func TestValidRateAndFrameLength(t *testing.T) {
 		{16000, 160, true},  // 10ms @ 16kHz
 		{16000, 320, true},  // 20ms @ 16kHz
 		{16000, 480, true},  // 30ms @ 16kHz
+		{24000, 240, true},  // 10ms @ 24kHz
+		{24000, 480, true},  // 20ms @ 24kHz
+		{24000, 720, true},  // 30ms @ 24kHz
 		{32000, 320, true},  // 10ms @ 32kHz
 		{32000, 640, true},  // 20ms @ 32kHz
 		{32000, 960, true},  // 30ms @ 32kHz
@@ -66,6 +156,7 @@ func TestValidRateAndFrameLength(t *testing.T) {
 		{32000, 160, false}, // 无效组合
 		{8000, 100, false},  // 无效帧长度
 		{16000, 100, false}, // 无效帧长度
+		{24000, 160, false}, // 无效组合
 		{44100, 441, false}, // 无效采样率
 	}
 
@@ -78,6 +169,34 @@ func TestValidRateAndFrameLength(t *testing.T) {
 	}
 }
 
+// TestValidateFrame 测试ValidateFrame区分错误类型并给出最接近的有效值
+func TestValidateFrame(t *testing.T) {
+	tests := []struct {
+		rate, frameLength int
+		target            error
+		hint              string
+	}{
+		{16000, 320, nil, ""},
+		{44100, 441, ErrInvalidSampleRate, "nearest supported: 48000"},
+		{11025, 110, ErrInvalidSampleRate, "nearest supported: 8000"},
+		{96000, 960, ErrInvalidSampleRate, "nearest supported: 48000"},
+		{16000, 256, ErrInvalidFrameLength, "160 (10 ms) or 320 (20 ms)"},
+		{16000, 100, ErrInvalidFrameLength, "nearest valid length: 160 (10 ms)"},
+		{16000, 512, ErrInvalidFrameLength, "nearest valid length: 480 (30 ms)"},
+		{8000, 320, ErrInvalidFrameLength, "byte size of a 160-sample frame"},
+	}
+	for _, tt := range tests {
+		err := ValidateFrame(tt.rate, tt.frameLength)
+		if (err == nil) != (tt.target == nil) || (tt.target != nil && !errors.Is(err, tt.target)) {
+			t.Errorf("ValidateFrame(%d, %d) = %v, 应为%v", tt.rate, tt.frameLength, err, tt.target)
+			continue
+		}
+		if err != nil && !strings.Contains(err.Error(), tt.hint) {
+			t.Errorf("ValidateFrame(%d, %d) = %q, 应包含%q", tt.rate, tt.frameLength, err, tt.hint)
+		}
+	}
+}
+
 // TestProcessZeroes 测试处理全零音频（应该检测为非语音）
 func TestProcessZeroes(t *testing.T) {
 	frameLen := 160
@@ -105,6 +224,152 @@ func TestProcessZeroes(t *testing.T) {
 	}
 }
 
+// TestProcess24khz 测试24kHz音频的所有帧长度
+func TestProcess24khz(t *testing.T) {
+	vad, err := New(1)
+	if err != nil {
+		t.Fatalf("Failed to create VAD: %v", err)
+	}
+
+	for _, frameLen := range []int{240, 480, 720} {
+		sample := make([]byte, frameLen*2)
+		if _, err := vad.IsSpeech(sample, 24000); err != nil {
+			t.Fatalf("Failed to process %d samples @ 24kHz: %v", frameLen, err)
+		}
+	}
+}
+
+// TestFeed 测试任意长度输入的缓冲检测
+func TestFeed(t *testing.T) {
+	vad, err := New(1)
+	if err != nil {
+		t.Fatalf("Failed to create VAD: %v", err)
+	}
+
+	// 512样本 @ 16kHz：3个完整10ms帧 + 32样本剩余
+	results, err := vad.Feed(make([]byte, 512*2), 16000)
+	if err != nil {
+		t.Fatalf("Feed failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("Expected 3 decisions, got %d", len(results))
+	}
+	if vad.FeedBuffered() != 32*2 {
+		t.Errorf("Expected %d buffered bytes, got %d", 32*2, vad.FeedBuffered())
+	}
+
+	// 再写入128样本凑满一帧
+	results, err = vad.Feed(make([]byte, 128*2), 16000)
+	if err != nil {
+		t.Fatalf("Feed failed: %v", err)
+	}
+	if len(results) != 1 || vad.FeedBuffered() != 0 {
+		t.Errorf("Expected 1 decision and empty buffer, got %d and %d", len(results), vad.FeedBuffered())
+	}
+
+	// 切换采样率时丢弃缓冲
+	if _, err := vad.Feed(make([]byte, 10), 16000); err != nil {
+		t.Fatalf("Feed failed: %v", err)
+	}
+	if _, err := vad.Feed(nil, 8000); err != nil {
+		t.Fatalf("Feed failed: %v", err)
+	}
+	if vad.FeedBuffered() != 0 {
+		t.Error("Expected buffer to be discarded on sample rate change")
+	}
+
+	if _, err := vad.Feed(make([]byte, 10), 11025); err == nil {
+		t.Error("Expected error for invalid sample rate")
+	}
+}
+
+// TestPrime 测试预热更新噪声模型且不遗留迟滞状态
+func TestPrime(t *testing.T) {
+	vad, err := New(1)
+	if err != nil {
+		t.Fatalf("Failed to create VAD: %v", err)
+	}
+
+	// 1秒 @ 8kHz 的伪随机背景噪声
+	noise := make([]byte, 16000)
+	seed := uint32(7)
+	for i := 0; i < len(noise); i += 2 {
+		seed = seed*1103515245 + 12345
+		v := int16(seed>>16) >> 6
+		noise[i] = byte(v)
+		noise[i+1] = byte(v >> 8)
+	}
+
+	before := vad.NoiseFloor()
+	if err := vad.Prime(noise, 8000); err != nil {
+		t.Fatalf("Prime failed: %v", err)
+	}
+	if vad.NoiseFloor() == before {
+		t.Error("Expected noise floor to be updated by priming")
+	}
+	if vad.inst.overHang != 0 || vad.inst.numOfSpeech != 0 {
+		t.Error("Expected hangover state to be cleared after priming")
+	}
+
+	if err := vad.Prime(noise, 11025); err == nil {
+		t.Error("Expected error for invalid sample rate")
+	}
+}
+
+// TestProcessNoAlloc 测试核心处理路径不产生堆分配
+func TestProcessNoAlloc(t *testing.T) {
+	vad, err := New(1)
+	if err != nil {
+		t.Fatalf("Failed to create VAD: %v", err)
+	}
+
+	for _, rate := range []int{8000, 16000, 24000, 32000, 48000} {
+		frame := make([]int16, rate*30/1000)
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := process(vad.inst, rate, frame); err != nil {
+				t.Fatalf("process failed @ %d Hz: %v", rate, err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("process @ %d Hz: expected 0 allocs, got %.1f", rate, allocs)
+		}
+	}
+}
+
+// TestSentinelErrors 测试各检测入口返回可用errors.Is判断的哨兵错误
+func TestSentinelErrors(t *testing.T) {
+	vad, _ := New(0)
+	frame := make([]byte, 320)
+
+	checks := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"New", func() error { _, err := New(4); return err }(), ErrInvalidMode},
+		{"IsSpeech采样率", func() error { _, err := vad.IsSpeech(frame, 44100); return err }(), ErrInvalidSampleRate},
+		{"IsSpeech帧长度", func() error { _, err := vad.IsSpeech(frame[:100], 16000); return err }(), ErrInvalidFrameLength},
+		{"IsSpeech未初始化", func() error { _, err := (&VAD{}).IsSpeech(frame, 16000); return err }(), ErrNotInitialized},
+		{"process帧长度", func() error { _, err := process(vad.inst, 16000, make([]int16, 100)); return err }(), ErrInvalidFrameLength},
+		{"process未初始化", func() error { _, err := process(&vadInst{}, 16000, make([]int16, 160)); return err }(), ErrNotInitialized},
+		{"ProcessAll帧长度", func() error { _, err := vad.ProcessAll(frame, 16000, 15); return err }(), ErrInvalidFrameLength},
+		{"IsSpeechBatchTo", vad.IsSpeechBatchTo([][]byte{frame}, 16000, nil), ErrBufferTooSmall},
+		{"NewStreamVAD采样率", func() error { _, err := NewStreamVAD(0, 11025, 20); return err }(), ErrInvalidSampleRate},
+		{"NewStreamVAD帧长度", func() error { _, err := NewStreamVAD(0, 16000, 25); return err }(), ErrInvalidFrameLength},
+	}
+	for _, c := range checks {
+		if !errors.Is(c.err, c.target) {
+			t.Errorf("%s: 应返回%v, 得到%v", c.name, c.target, c.err)
+		}
+	}
+
+	// 批量接口的错误包装了帧序号，仍可判断
+	_, err := vad.IsSpeechBatch([][]byte{frame, frame[:10]}, 16000)
+	if !errors.Is(err, ErrInvalidFrameLength) {
+		t.Errorf("IsSpeechBatch: 应返回ErrInvalidFrameLength, 得到%v", err)
+	}
+}
+
 // TestProcessFile 测试处理实际音频文件
 func TestProcessFile(t *testing.T) {
 	// 尝试读取测试音频文件
@@ -0,0 +1,219 @@
+// Package vadhttp 提供批量语音活动检测的HTTP处理器
+//
+// Handler接受POST上传的音频（请求体直接为音频，或multipart/form-data中的
+// 文件字段），识别WAV/AIFF/原始PCM格式后运行StreamVAD，以JSON返回片段：
+//
+//	[{"start":0,"end":0.51,"speech":false},{"start":0.51,"end":1.53,"speech":true}]
+//
+// 原始PCM的采样率取自查询参数rate（默认Config.SampleRate），激进度模式可用
+// 查询参数mode指定。可直接挂载到任意路由：
+//
+//	http.Handle("/vad", vadhttp.NewHandler(vadhttp.Config{MaxDuration: 10 * time.Minute}))
+//
+// 出错时返回相应的状态码及 {"error":"..."}。
+package vadhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+)
+
+// DefaultMaxBodyBytes 默认的请求体大小上限（32MiB，16kHz下约17分钟）
+const DefaultMaxBodyBytes = 32 << 20
+
+// Config 处理器配置
+type Config struct {
+	MaxBodyBytes int64         // 请求体大小上限，0表示DefaultMaxBodyBytes
+	MaxDuration  time.Duration // 音频时长上限，0表示不限制
+	SampleRate   int           // 原始PCM的默认采样率，0表示webrtcvad.DefaultRawSampleRate
+	// Options 每个请求的StreamVAD选项（模式、帧长度、片段平滑等），
+	// 采样率由音频格式决定，查询参数mode覆盖其中的模式
+	Options []webrtcvad.StreamVADOption
+}
+
+// Handler 批量检测HTTP处理器，实现http.Handler
+//
+// Handler可被多个请求并发使用，每个请求使用独立的StreamVAD
+type Handler struct {
+	cfg Config
+}
+
+// NewHandler 创建批量检测处理器
+//
+// 参数:
+//   - cfg: 处理器配置，零值字段使用默认值
+//
+// 返回:
+//   - *Handler: 处理器实例
+func NewHandler(cfg Config) *Handler {
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = webrtcvad.DefaultRawSampleRate
+	}
+	return &Handler{cfg: cfg}
+}
+
+// httpError 带状态码的错误
+type httpError struct {
+	code int
+	msg  string
+}
+
+func (e *httpError) Error() string {
+	return e.msg
+}
+
+// errorf 创建带状态码的错误
+func errorf(code int, format string, a ...any) error {
+	return &httpError{code: code, msg: fmt.Sprintf(format, a...)}
+}
+
+// ServeHTTP 处理检测请求
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method))
+		return
+	}
+
+	segments, err := h.detect(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	webrtcvad.Segments(segments).WriteJSON(w)
+}
+
+// detect 读取请求中的音频并检测
+func (h *Handler) detect(w http.ResponseWriter, r *http.Request) ([]webrtcvad.VoiceSegment, error) {
+	q := r.URL.Query()
+	rate := h.cfg.SampleRate
+	if s := q.Get("rate"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid rate %q", s)
+		}
+		rate = v
+	}
+	opts := h.cfg.Options[:len(h.cfg.Options):len(h.cfg.Options)]
+	if s := q.Get("mode"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid mode %q", s)
+		}
+		opts = append(opts, webrtcvad.WithStreamMode(v))
+	}
+
+	data, err := h.readBody(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	src := bytes.NewReader(data)
+	format, err := webrtcvad.DetectFormat(src)
+	if err != nil {
+		return nil, errorf(http.StatusUnsupportedMediaType, "%v", err)
+	}
+	if format.Estimated {
+		format.SampleRate = rate
+		format.BigEndian = false
+	}
+
+	if h.cfg.MaxDuration > 0 {
+		size := format.DataSize
+		if size < 0 {
+			size = int64(len(data)) - format.DataOffset
+		}
+		d := time.Duration(size/2) * time.Second / time.Duration(max(format.SampleRate, 1))
+		if d > h.cfg.MaxDuration {
+			return nil, errorf(http.StatusRequestEntityTooLarge, "audio duration %v exceeds limit %v", d, h.cfg.MaxDuration)
+		}
+	}
+
+	opts = append(opts, webrtcvad.WithSampleRate(format.SampleRate))
+	svad, err := webrtcvad.NewStreamVADWithOptions(opts...)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "%v", err)
+	}
+
+	var segments []webrtcvad.VoiceSegment
+	err = svad.ProcessReader(format.NewReader(src), func(seg webrtcvad.VoiceSegment) error {
+		segments = append(segments, seg)
+		return nil
+	})
+	if err != nil {
+		return nil, errorf(http.StatusUnprocessableEntity, "%v", err)
+	}
+	return segments, nil
+}
+
+// readBody 读取音频数据：multipart请求取第一个文件字段，否则为整个请求体
+func (h *Handler) readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body := http.MaxBytesReader(w, r.Body, h.cfg.MaxBodyBytes)
+
+	var src io.Reader = body
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if params["boundary"] == "" {
+			return nil, errorf(http.StatusBadRequest, "missing multipart boundary")
+		}
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return nil, errorf(http.StatusBadRequest, "no file in multipart body")
+			}
+			if err != nil {
+				return nil, readError(err)
+			}
+			if part.FileName() != "" {
+				src = part
+				break
+			}
+		}
+	}
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, readError(err)
+	}
+	if len(data) == 0 {
+		return nil, errorf(http.StatusBadRequest, "empty audio body")
+	}
+	return data, nil
+}
+
+// readError 将读取错误转换为HTTP错误
+func readError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return errorf(http.StatusRequestEntityTooLarge, "request body exceeds %d bytes", maxErr.Limit)
+	}
+	return errorf(http.StatusBadRequest, "%v", err)
+}
+
+// writeError 以JSON返回错误
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	var he *httpError
+	if errors.As(err, &he) {
+		code = he.code
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
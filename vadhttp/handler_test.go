@@ -0,0 +1,129 @@
+package vadhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+	"github.com/godeps/webrtcvad-go/internal/testaudio"
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// segment 响应中的片段
+type segment struct {
+	Start  float64 `json:"start"`
+	End    float64 `json:"end"`
+	Speech bool    `json:"speech"`
+}
+
+// do 发送请求并解码响应
+func do(t *testing.T, h http.Handler, req *http.Request) (int, []segment, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		var body struct{ Error string }
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		return rec.Code, nil, body.Error
+	}
+	var segs []segment
+	if err := json.Unmarshal(rec.Body.Bytes(), &segs); err != nil {
+		t.Fatalf("响应不是有效的JSON: %v\n%s", err, rec.Body.String())
+	}
+	return rec.Code, segs, ""
+}
+
+// speechCount 统计语音片段数
+func speechCount(segs []segment) int {
+	n := 0
+	for _, s := range segs {
+		if s.Speech {
+			n++
+		}
+	}
+	return n
+}
+
+// TestHandlerBodies 测试原始请求体、WAV与multipart上传
+func TestHandlerBodies(t *testing.T) {
+	h := NewHandler(Config{Options: []webrtcvad.StreamVADOption{webrtcvad.WithStreamMode(1)}})
+
+	// 8kHz原始PCM，采样率由查询参数指定
+	raw := testaudio.Pattern("0110", 8000)
+	code, segs, msg := do(t, h, httptest.NewRequest(http.MethodPost, "/vad?rate=8000", bytes.NewReader(raw)))
+	if code != http.StatusOK || speechCount(segs) != 1 || segs[len(segs)-1].End != 2 {
+		t.Errorf("原始PCM: 应返回1个语音片段、总时长2s, 得到%d %v %s", code, segs, msg)
+	}
+
+	// WAV上传，采样率取自头部
+	var wav bytes.Buffer
+	wave.Write(&wav, testaudio.Pattern("01010", 16000), 16000)
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	mw.WriteField("note", "ignored")
+	fw, _ := mw.CreateFormFile("audio", "speech.wav")
+	fw.Write(wav.Bytes())
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/vad", &form)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	code, segs, msg = do(t, h, req)
+	if code != http.StatusOK || speechCount(segs) != 2 || segs[len(segs)-1].End != 2.5 {
+		t.Errorf("multipart WAV: 应返回2个语音片段、总时长2.5s, 得到%d %v %s", code, segs, msg)
+	}
+
+	// 查询参数覆盖配置中的模式
+	if code, _, _ = do(t, h, httptest.NewRequest(http.MethodPost, "/vad?mode=9", bytes.NewReader(raw))); code != http.StatusBadRequest {
+		t.Errorf("mode=9: 状态码应为400, 得到%d", code)
+	}
+}
+
+// TestHandlerPartialFrame 测试请求体以不足一帧的语音结尾时，最后一个片段不丢失
+func TestHandlerPartialFrame(t *testing.T) {
+	h := NewHandler(Config{Options: []webrtcvad.StreamVADOption{webrtcvad.WithStreamMode(1), webrtcvad.WithFrameDuration(10)}})
+
+	body := append(testaudio.Pattern("10", 16000), testaudio.Pattern("1", 16000)[:318]...)
+	code, segs, msg := do(t, h, httptest.NewRequest(http.MethodPost, "/vad?rate=16000", bytes.NewReader(body)))
+	if code != http.StatusOK || speechCount(segs) != 2 || !segs[len(segs)-1].Speech || segs[len(segs)-1].Start != 1 {
+		t.Errorf("应返回2个语音片段且最后一个从1s开始, 得到%d %v %s", code, segs, msg)
+	}
+}
+
+// TestHandlerErrors 测试请求错误与大小/时长限制
+func TestHandlerErrors(t *testing.T) {
+	h := NewHandler(Config{MaxBodyBytes: 64000, MaxDuration: time.Second})
+	pcm := make([]byte, 48000) // 16kHz下1.5s
+
+	multipartReq := httptest.NewRequest(http.MethodPost, "/vad", bytes.NewReader([]byte("--x--\r\n")))
+	multipartReq.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+
+	cases := []struct {
+		name string
+		req  *http.Request
+		code int
+	}{
+		{"GET", httptest.NewRequest(http.MethodGet, "/vad", nil), http.StatusMethodNotAllowed},
+		{"empty", httptest.NewRequest(http.MethodPost, "/vad", nil), http.StatusBadRequest},
+		{"bad rate", httptest.NewRequest(http.MethodPost, "/vad?rate=abc", bytes.NewReader(pcm)), http.StatusBadRequest},
+		{"unsupported rate", httptest.NewRequest(http.MethodPost, "/vad?rate=44100", bytes.NewReader(pcm[:1000])), http.StatusBadRequest},
+		{"too long", httptest.NewRequest(http.MethodPost, "/vad", bytes.NewReader(pcm)), http.StatusRequestEntityTooLarge},
+		{"too large", httptest.NewRequest(http.MethodPost, "/vad", bytes.NewReader(make([]byte, 64001))), http.StatusRequestEntityTooLarge},
+		{"no file", multipartReq, http.StatusBadRequest},
+		{"bad wav", httptest.NewRequest(http.MethodPost, "/vad", bytes.NewReader([]byte("RIFF\x00\x00\x00\x00WAVEjunk"))), http.StatusUnsupportedMediaType},
+	}
+	for _, tc := range cases {
+		code, _, msg := do(t, h, tc.req)
+		if code != tc.code {
+			t.Errorf("%s: 状态码应为%d, 得到%d (%s)", tc.name, tc.code, code, msg)
+		}
+		if msg == "" {
+			t.Errorf("%s: 应返回错误信息", tc.name)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package vadws
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// conn.go 实现本包所需的最小WebSocket服务端（RFC 6455）：握手、帧解析
+// （掩码、分片、控制帧）以及文本消息与关闭帧的发送，不依赖第三方库
+
+// 操作码
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// 关闭状态码
+const (
+	closeNormal          = 1000
+	closeProtocolError   = 1002
+	closeUnsupportedData = 1003
+	closeTooBig          = 1009
+	closeInternalError   = 1011
+)
+
+// acceptGUID 计算Sec-WebSocket-Accept时附加的固定GUID
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// closeError 对端发送的或因协议错误产生的关闭
+type closeError struct {
+	code   int
+	reason string
+	local  bool // 由本端发起（已发送关闭帧）
+}
+
+func (e *closeError) Error() string {
+	return fmt.Sprintf("websocket closed: %d %s", e.code, e.reason)
+}
+
+// wsConn 服务端WebSocket连接
+//
+// 读写都在同一个goroutine中进行，不是并发安全的
+type wsConn struct {
+	nc       net.Conn
+	br       *bufio.Reader
+	bw       *bufio.Writer
+	maxBytes int64 // 单条消息的最大字节数
+}
+
+// upgrade 完成WebSocket握手并接管底层连接
+//
+// 握手失败时已向客户端返回HTTP错误
+func upgrade(w http.ResponseWriter, r *http.Request, maxBytes int64) (*wsConn, error) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, errors.New("websocket: method not GET")
+	}
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: missing upgrade headers")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("websocket: missing key")
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response does not implement http.Hijacker")
+	}
+
+	nc, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return &wsConn{nc: nc, br: rw.Reader, bw: rw.Writer, maxBytes: maxBytes}, nil
+}
+
+// headerContains 头部的逗号分隔值中是否包含token（不区分大小写）
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readMessage 读取一条完整的数据消息，期间自动回应ping
+//
+// 返回:
+//   - int: 消息类型（opText或opBinary）
+//   - []byte: 消息内容
+//   - error: 对端关闭时为*closeError（调用方须回应关闭帧）；
+//     协议错误时已发送关闭帧并返回*closeError
+func (c *wsConn) readMessage() (int, []byte, error) {
+	var msgType int
+	var msg []byte
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			ce := &closeError{code: closeNormal}
+			if len(payload) >= 2 {
+				ce.code = int(binary.BigEndian.Uint16(payload))
+				ce.reason = string(payload[2:])
+			}
+			// 由调用方发送剩余消息后回应关闭帧
+			return 0, nil, ce
+		case opText, opBinary:
+			if msgType != 0 {
+				return 0, nil, c.fail(closeProtocolError, "expected continuation frame")
+			}
+			msgType = op
+		case opContinuation:
+			if msgType == 0 {
+				return 0, nil, c.fail(closeProtocolError, "unexpected continuation frame")
+			}
+		default:
+			return 0, nil, c.fail(closeProtocolError, "unknown opcode")
+		}
+
+		if int64(len(msg)+len(payload)) > c.maxBytes {
+			return 0, nil, c.fail(closeTooBig, "message too big")
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msgType, msg, nil
+		}
+	}
+}
+
+// readFrame 读取一帧并去除掩码
+func (c *wsConn) readFrame() (fin bool, op int, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(c.br, hdr[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = hdr[0]&0x80 != 0
+	op = int(hdr[0] & 0x0F)
+	if hdr[0]&0x70 != 0 {
+		return false, 0, nil, c.fail(closeProtocolError, "reserved bits set")
+	}
+	if hdr[1]&0x80 == 0 {
+		return false, 0, nil, c.fail(closeProtocolError, "client frames must be masked")
+	}
+
+	length := int64(hdr[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]) & (1<<63 - 1))
+	}
+	if op >= opClose && (length > 125 || !fin) {
+		return false, 0, nil, c.fail(closeProtocolError, "invalid control frame")
+	}
+	if length > c.maxBytes {
+		return false, 0, nil, c.fail(closeTooBig, "message too big")
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i&3]
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame 发送一个未分片、不带掩码的帧
+func (c *wsConn) writeFrame(op int, payload []byte) error {
+	hdr := []byte{0x80 | byte(op)}
+	switch n := len(payload); {
+	case n < 126:
+		hdr = append(hdr, byte(n))
+	case n <= 0xFFFF:
+		hdr = append(hdr, 126)
+		hdr = binary.BigEndian.AppendUint16(hdr, uint16(n))
+	default:
+		hdr = append(hdr, 127)
+		hdr = binary.BigEndian.AppendUint64(hdr, uint64(n))
+	}
+	c.bw.Write(hdr)
+	c.bw.Write(payload)
+	return c.bw.Flush()
+}
+
+// writeText 发送文本消息
+func (c *wsConn) writeText(msg []byte) error {
+	return c.writeFrame(opText, msg)
+}
+
+// writeClose 发送关闭帧
+func (c *wsConn) writeClose(code int, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	return c.writeFrame(opClose, append(payload, reason...))
+}
+
+// fail 以code关闭连接并返回对应的错误
+func (c *wsConn) fail(code int, reason string) error {
+	c.writeClose(code, reason)
+	return &closeError{code: code, reason: reason, local: true}
+}
+
+// close 关闭底层连接
+func (c *wsConn) close() error {
+	return c.nc.Close()
+}
@@ -0,0 +1,230 @@
+// Package vadws 提供实时语音活动检测的WebSocket处理器
+//
+// 客户端（如浏览器麦克风采集）以二进制消息发送16位单声道小端序PCM，
+// 服务端在语音开始和结束时以文本消息返回JSON事件：
+//
+//	{"type":"speech_start","start":0.51}
+//	{"type":"speech_end","start":0.51,"end":1.53}
+//
+// 默认使用10ms帧，语音开始事件在包含该帧的消息处理完后立即发送，
+// 服务端引入的延迟低于一帧。客户端发送关闭帧时，进行中的语音先以speech_end结束，
+// 之后服务端回应关闭帧。采样率与激进度模式可用查询参数rate、mode指定：
+//
+//	http.Handle("/vad", vadws.NewHandler(vadws.Config{}))
+//	// 浏览器: new WebSocket("wss://host/vad?rate=48000&mode=2")
+//
+// 本包只实现所需的最小WebSocket服务端，不依赖第三方库。
+package vadws
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+)
+
+// 事件类型
+const (
+	// EventSpeechStart 语音开始
+	EventSpeechStart = "speech_start"
+	// EventSpeechEnd 语音结束
+	EventSpeechEnd = "speech_end"
+)
+
+// DefaultMaxMessageBytes 默认的单条消息大小上限（1MiB）
+const DefaultMaxMessageBytes = 1 << 20
+
+// Event 发送给客户端的事件，时间以秒为单位，相对于连接上的第一个样本
+type Event struct {
+	Type  string  `json:"type"`          // EventSpeechStart或EventSpeechEnd
+	Start float64 `json:"start"`         // 语音开始时间
+	End   float64 `json:"end,omitempty"` // 语音结束时间（仅speech_end）
+}
+
+// Config 处理器配置
+type Config struct {
+	SampleRate      int   // 默认采样率，0表示16000；查询参数rate覆盖
+	FrameMs         int   // 帧长度（10/20/30毫秒），0表示10
+	MaxMessageBytes int64 // 单条消息大小上限，0表示DefaultMaxMessageBytes
+	// Options 每个连接的StreamVAD选项（模式、片段平滑等），查询参数mode覆盖其中的模式
+	Options []webrtcvad.StreamVADOption
+	// CheckOrigin 校验请求来源，返回false时拒绝握手；nil表示只接受同源请求
+	// 及不带Origin头部的请求（非浏览器客户端）
+	CheckOrigin func(r *http.Request) bool
+}
+
+// Handler 实时检测WebSocket处理器，实现http.Handler
+//
+// 每个连接使用独立的StreamVAD
+type Handler struct {
+	cfg Config
+}
+
+// NewHandler 创建实时检测处理器
+//
+// 参数:
+//   - cfg: 处理器配置，零值字段使用默认值
+//
+// 返回:
+//   - *Handler: 处理器实例
+func NewHandler(cfg Config) *Handler {
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = 16000
+	}
+	if cfg.FrameMs == 0 {
+		cfg.FrameMs = 10
+	}
+	if cfg.MaxMessageBytes <= 0 {
+		cfg.MaxMessageBytes = DefaultMaxMessageBytes
+	}
+	if cfg.CheckOrigin == nil {
+		cfg.CheckOrigin = sameOrigin
+	}
+	return &Handler{cfg: cfg}
+}
+
+// sameOrigin 请求不带Origin头部，或Origin的主机与请求的Host一致
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// ServeHTTP 完成握手并处理连接直到关闭
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.cfg.CheckOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	svad, err := h.newStreamVAD(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c, err := upgrade(w, r, h.cfg.MaxMessageBytes)
+	if err != nil {
+		return
+	}
+	defer c.close()
+
+	s := &session{c: c, svad: svad}
+	s.run()
+}
+
+// newStreamVAD 按配置和查询参数创建StreamVAD
+func (h *Handler) newStreamVAD(q url.Values) (*webrtcvad.StreamVAD, error) {
+	opts := append(h.cfg.Options[:len(h.cfg.Options):len(h.cfg.Options)],
+		webrtcvad.WithSampleRate(h.cfg.SampleRate),
+		webrtcvad.WithFrameDuration(h.cfg.FrameMs))
+	if s := q.Get("rate"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, webrtcvad.ErrInvalidSampleRate
+		}
+		opts = append(opts, webrtcvad.WithSampleRate(v))
+	}
+	if s := q.Get("mode"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, webrtcvad.ErrInvalidMode
+		}
+		opts = append(opts, webrtcvad.WithStreamMode(v))
+	}
+	return webrtcvad.NewStreamVADWithOptions(opts...)
+}
+
+// session 一个WebSocket连接上的检测状态
+type session struct {
+	c        *wsConn
+	svad     *webrtcvad.StreamVAD
+	inSpeech bool
+	start    time.Duration // 当前语音的开始时间
+}
+
+// run 读取音频消息并发送事件，直到连接关闭
+func (s *session) run() {
+	for {
+		op, msg, err := s.c.readMessage()
+		if err != nil {
+			if ce, ok := err.(*closeError); ok && !ce.local {
+				// 对端正常关闭：结束进行中的语音后回应关闭帧
+				if s.flush() == nil {
+					s.c.writeClose(closeNormal, "")
+				}
+			}
+			return
+		}
+		if op != opBinary {
+			s.c.fail(closeUnsupportedData, "audio must be sent as binary messages")
+			return
+		}
+
+		segments, err := s.svad.Process(msg)
+		if err != nil {
+			s.c.fail(closeInternalError, err.Error())
+			return
+		}
+		for _, seg := range segments {
+			if err := s.update(seg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// update 根据新开始的片段发送事件
+func (s *session) update(seg webrtcvad.VoiceSegment) error {
+	switch {
+	case seg.IsSpeech && !s.inSpeech:
+		s.inSpeech = true
+		s.start = seg.Start
+		return s.send(Event{Type: EventSpeechStart, Start: seconds(seg.Start)})
+	case !seg.IsSpeech && s.inSpeech:
+		// 静音片段开始于最后一个语音帧之后
+		s.inSpeech = false
+		return s.send(Event{Type: EventSpeechEnd, Start: seconds(s.start), End: seconds(seg.Start)})
+	}
+	return nil
+}
+
+// flush 处理残余数据并结束进行中的语音
+func (s *session) flush() error {
+	before := len(s.svad.GetSegments())
+	if err := s.svad.Flush(); err != nil {
+		return err
+	}
+	segments := s.svad.GetSegments()
+	for _, seg := range segments[min(before, len(segments)):] {
+		if err := s.update(seg); err != nil {
+			return err
+		}
+	}
+	if s.inSpeech && len(segments) > 0 {
+		s.inSpeech = false
+		end := segments[len(segments)-1].End
+		return s.send(Event{Type: EventSpeechEnd, Start: seconds(s.start), End: seconds(end)})
+	}
+	return nil
+}
+
+// send 以文本消息发送事件
+func (s *session) send(ev Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return s.c.writeText(data)
+}
+
+// seconds 将时长转换为秒
+func seconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Second)
+}
@@ -0,0 +1,242 @@
+package vadws
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+	"github.com/godeps/webrtcvad-go/internal/testaudio"
+)
+
+// testClient 测试用的最小WebSocket客户端
+type testClient struct {
+	t  *testing.T
+	nc net.Conn
+	br *bufio.Reader
+}
+
+// dial 连接到服务端并完成握手
+func dial(t *testing.T, srv *httptest.Server, path string) *testClient {
+	t.Helper()
+	nc, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { nc.Close() })
+	nc.SetDeadline(time.Now().Add(5 * time.Second))
+
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+	req := "GET " + path + " HTTP/1.1\r\nHost: " + srv.Listener.Addr().String() + "\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := io.WriteString(nc, req); err != nil {
+		t.Fatal(err)
+	}
+
+	br := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(sum[:]) {
+		t.Fatalf("握手失败: %s %v", resp.Status, resp.Header)
+	}
+	return &testClient{t: t, nc: nc, br: br}
+}
+
+// send 发送一个带掩码的帧
+func (c *testClient) send(fin bool, op int, payload []byte) {
+	c.t.Helper()
+	b0 := byte(op)
+	if fin {
+		b0 |= 0x80
+	}
+	frame := []byte{b0}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	mask := [4]byte{0x12, 0x34, 0x56, 0x78}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i&3])
+	}
+	if _, err := c.nc.Write(frame); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// read 读取服务端的一帧
+func (c *testClient) read() (int, []byte) {
+	c.t.Helper()
+	var hdr [2]byte
+	if _, err := io.ReadFull(c.br, hdr[:]); err != nil {
+		c.t.Fatalf("读取帧失败: %v", err)
+	}
+	n := int(hdr[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(c.br, ext[:])
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(c.br, ext[:])
+		n = int(binary.BigEndian.Uint64(ext[:]))
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		c.t.Fatalf("读取帧失败: %v", err)
+	}
+	return int(hdr[0] & 0x0F), payload
+}
+
+// event 读取一个事件
+func (c *testClient) event() Event {
+	c.t.Helper()
+	op, payload := c.read()
+	if op != opText {
+		c.t.Fatalf("应收到文本事件, 得到操作码%d %q", op, payload)
+	}
+	var ev Event
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		c.t.Fatalf("事件不是有效的JSON: %v", err)
+	}
+	return ev
+}
+
+// TestHandlerEvents 测试语音开始/结束事件及关闭时的结束事件
+func TestHandlerEvents(t *testing.T) {
+	h := NewHandler(Config{Options: []webrtcvad.StreamVADOption{webrtcvad.WithStreamMode(1)}})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	c := dial(t, srv, "/vad")
+
+	pcm := testaudio.Pattern("01101", 16000)
+	chunk := 640 // 20ms
+
+	// 发送到0.6s：语音开始事件应已到达
+	for off := 0; off < 19200; off += chunk {
+		c.send(true, opBinary, pcm[off:off+chunk])
+	}
+	if ev := c.event(); ev.Type != EventSpeechStart || ev.Start != 0.5 {
+		t.Fatalf("应收到0.5s处的语音开始, 得到%+v", ev)
+	}
+
+	// 分片消息与ping
+	c.send(false, opBinary, pcm[19200:19520])
+	c.send(true, opPing, []byte("hi"))
+	if op, payload := c.read(); op != opPong || string(payload) != "hi" {
+		t.Fatalf("应回应pong, 得到操作码%d %q", op, payload)
+	}
+	c.send(true, opContinuation, pcm[19520:64000])
+	if ev := c.event(); ev.Type != EventSpeechEnd || ev.Start != 0.5 || ev.End < 1.5 || ev.End > 1.8 {
+		t.Fatalf("应收到约1.5s处的语音结束, 得到%+v", ev)
+	}
+
+	// 最后0.3s语音后关闭：先收到语音结束，再收到关闭帧
+	c.send(true, opBinary, pcm[64000:73600])
+	if ev := c.event(); ev.Type != EventSpeechStart || ev.Start != 2 {
+		t.Fatalf("应收到2s处的语音开始, 得到%+v", ev)
+	}
+	c.send(true, opClose, binary.BigEndian.AppendUint16(nil, closeNormal))
+	if ev := c.event(); ev.Type != EventSpeechEnd || ev.End != 2.3 {
+		t.Fatalf("关闭时应结束进行中的语音, 得到%+v", ev)
+	}
+	if op, payload := c.read(); op != opClose || binary.BigEndian.Uint16(payload) != closeNormal {
+		t.Fatalf("应回应关闭帧, 得到操作码%d %q", op, payload)
+	}
+}
+
+// TestHandlerRejects 测试握手拒绝与协议错误
+func TestHandlerRejects(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(Config{MaxMessageBytes: 1000}))
+	defer srv.Close()
+
+	// 普通HTTP请求
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("非升级请求应返回426, 得到%d", resp.StatusCode)
+	}
+
+	// 跨域请求与无效参数
+	handshakes := []struct {
+		origin string
+		path   string
+		code   int
+	}{
+		{"http://evil.example", "/", http.StatusForbidden},
+		{"", "/?rate=44100", http.StatusBadRequest},
+		{"", "/?mode=x", http.StatusBadRequest},
+	}
+	for _, tc := range handshakes {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+tc.path, nil)
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.code {
+			t.Errorf("%q %s: 状态码应为%d, 得到%d", tc.origin, tc.path, tc.code, resp.StatusCode)
+		}
+	}
+
+	cases := []struct {
+		name string
+		op   int
+		data []byte
+		code int
+	}{
+		{"text", opText, []byte("hello"), closeUnsupportedData},
+		{"too big", opBinary, make([]byte, 1002), closeTooBig},
+	}
+	for _, tc := range cases {
+		c := dial(t, srv, "/")
+		c.send(true, tc.op, tc.data)
+		op, payload := c.read()
+		if op != opClose || int(binary.BigEndian.Uint16(payload)) != tc.code {
+			t.Errorf("%s: 应以%d关闭, 得到操作码%d %q", tc.name, tc.code, op, payload)
+		}
+	}
+}
+
+// TestSameOrigin 测试默认的来源校验
+func TestSameOrigin(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "http://example.com/vad", nil)
+	for origin, want := range map[string]bool{
+		"":                       true,
+		"https://example.com":    true,
+		"https://EXAMPLE.com":    true,
+		"https://other.com":      false,
+		"https://example.com.cn": false,
+	} {
+		r.Header.Set("Origin", origin)
+		if got := sameOrigin(r); got != want {
+			t.Errorf("Origin %q: 应为%v, 得到%v", origin, want, got)
+		}
+	}
+}
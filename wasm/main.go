@@ -0,0 +1,214 @@
+//go:build js && wasm
+
+// Command wasm 将语音活动检测导出给浏览器中的JavaScript
+//
+// 构建:
+//
+//	GOOS=js GOARCH=wasm go build -o webrtcvad.wasm ./wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .
+//
+// 加载后在全局对象上注册webrtcvad：
+//
+//	const go = new Go();
+//	const { instance } = await WebAssembly.instantiateStreaming(fetch("webrtcvad.wasm"), go.importObject);
+//	go.run(instance);
+//
+//	webrtcvad.isSpeech(frame, 16000, 2);       // frame: 10/20/30ms的Int16Array，模式默认为1
+//	const s = webrtcvad.newStream({ sampleRate: 16000, mode: 2 });
+//	s.process(samples);                         // 返回新开始的片段 [{start, end, speech}]
+//	s.flush();
+//	s.segments();                               // 全部片段，时间以秒为单位
+//	s.close();
+//
+// isSpeech对同一模式与采样率的所有调用共用一个检测器（帧间状态连续），
+// 只适合单路音频；同时检测多路音频时，每路使用各自的newStream。
+// 参数无效或检测失败时抛出Error。
+package main
+
+import (
+	"errors"
+	"fmt"
+	"syscall/js"
+	"time"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+)
+
+func main() {
+	register()
+	select {}
+}
+
+// register 在全局对象上注册webrtcvad
+func register() {
+	// isSpeech的检测器，按(模式, 采样率)区分
+	type vadKey struct{ mode, rate int }
+	vads := make(map[vadKey]*webrtcvad.VAD)
+
+	api := js.Global().Get("Object").New()
+	api.Set("isSpeech", wrap(func(args []js.Value) (any, error) {
+		if len(args) < 2 {
+			return nil, errors.New("isSpeech(samples, sampleRate[, mode]) requires 2 arguments")
+		}
+		pcm, err := bytesOf(args[0])
+		if err != nil {
+			return nil, err
+		}
+		rate, err := intOf(args[1], "sampleRate")
+		if err != nil {
+			return nil, err
+		}
+		mode := 1
+		if len(args) > 2 && !args[2].IsUndefined() {
+			if mode, err = intOf(args[2], "mode"); err != nil {
+				return nil, err
+			}
+		}
+
+		// 同一模式与采样率的调用共用一个检测器并保持帧间状态，视为同一路音频流
+		key := vadKey{mode, rate}
+		vad, ok := vads[key]
+		if !ok {
+			if vad, err = webrtcvad.New(mode); err != nil {
+				return nil, err
+			}
+			vads[key] = vad
+		}
+		return vad.IsSpeech(pcm, rate)
+	}))
+	api.Set("newStream", wrap(func(args []js.Value) (any, error) {
+		var opts []webrtcvad.StreamVADOption
+		if len(args) > 0 && args[0].Type() == js.TypeObject {
+			fields := []struct {
+				name string
+				opt  func(int) webrtcvad.StreamVADOption
+			}{
+				{"sampleRate", webrtcvad.WithSampleRate},
+				{"mode", webrtcvad.WithStreamMode},
+				{"frameMs", webrtcvad.WithFrameDuration},
+			}
+			for _, f := range fields {
+				v := args[0].Get(f.name)
+				if v.IsUndefined() {
+					continue
+				}
+				n, err := intOf(v, f.name)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, f.opt(n))
+			}
+		}
+		svad, err := webrtcvad.NewStreamVADWithOptions(opts...)
+		if err != nil {
+			return nil, err
+		}
+		return newStream(svad), nil
+	}))
+	js.Global().Set("webrtcvad", api)
+}
+
+// newStream 创建包装StreamVAD的JavaScript对象
+func newStream(svad *webrtcvad.StreamVAD) js.Value {
+	obj := js.Global().Get("Object").New()
+	var funcs []js.Func
+	method := func(name string, fn func(args []js.Value) (any, error)) {
+		f := funcOf(fn)
+		funcs = append(funcs, f)
+		obj.Set(name, throwing.Invoke(f))
+	}
+
+	method("process", func(args []js.Value) (any, error) {
+		if len(args) < 1 {
+			return nil, errors.New("process(samples) requires 1 argument")
+		}
+		pcm, err := bytesOf(args[0])
+		if err != nil {
+			return nil, err
+		}
+		segments, err := svad.Process(pcm)
+		if err != nil {
+			return nil, err
+		}
+		return segmentsOf(segments), nil
+	})
+	method("flush", func([]js.Value) (any, error) {
+		return nil, svad.Flush()
+	})
+	method("segments", func([]js.Value) (any, error) {
+		return segmentsOf(svad.GetSegments()), nil
+	})
+	method("reset", func([]js.Value) (any, error) {
+		return nil, svad.Reset()
+	})
+	method("close", func([]js.Value) (any, error) {
+		err := svad.Close()
+		for _, f := range funcs {
+			f.Release()
+		}
+		return nil, err
+	})
+	return obj
+}
+
+// throwing 将返回Error对象的函数包装为抛出异常的函数
+//
+// Go回调中无法直接抛出JavaScript异常，由这层JavaScript包装完成
+var throwing = js.Global().Get("Function").New("fn",
+	"return function(...args) { const r = fn(...args); if (r instanceof Error) throw r; return r; };")
+
+// wrap 将Go函数包装为JavaScript函数，错误以异常抛出
+//
+// 导出的函数在程序生命周期内有效，不会释放
+func wrap(fn func(args []js.Value) (any, error)) js.Value {
+	return throwing.Invoke(funcOf(fn))
+}
+
+// funcOf 将Go函数转换为js.Func，错误以Error对象返回
+func funcOf(fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		v, err := fn(args)
+		if err != nil {
+			return js.Global().Get("Error").New(err.Error())
+		}
+		return v
+	})
+}
+
+// bytesOf 将Int16Array复制为16位小端序PCM字节
+func bytesOf(v js.Value) ([]byte, error) {
+	if !v.InstanceOf(js.Global().Get("Int16Array")) {
+		return nil, errors.New("samples must be an Int16Array")
+	}
+	// TypedArray使用平台字节序，浏览器所在平台均为小端序
+	u8 := js.Global().Get("Uint8Array").New(v.Get("buffer"), v.Get("byteOffset"), v.Get("byteLength"))
+	pcm := make([]byte, u8.Length())
+	js.CopyBytesToGo(pcm, u8)
+	return pcm, nil
+}
+
+// intOf 将数字参数转换为int
+func intOf(v js.Value, name string) (int, error) {
+	if v.Type() != js.TypeNumber {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+	return v.Int(), nil
+}
+
+// segmentsOf 将片段转换为JavaScript数组，时间以秒为单位
+func segmentsOf(segments []webrtcvad.VoiceSegment) js.Value {
+	arr := js.Global().Get("Array").New(len(segments))
+	for i, seg := range segments {
+		arr.SetIndex(i, map[string]any{
+			"start":  seconds(seg.Start),
+			"end":    seconds(seg.End),
+			"speech": seg.IsSpeech,
+		})
+	}
+	return arr
+}
+
+// seconds 将时长转换为秒
+func seconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Second)
+}
@@ -0,0 +1,115 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"syscall/js"
+	"testing"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+	"github.com/godeps/webrtcvad-go/internal/testaudio"
+)
+
+// samples 按"1"正弦波、"0"静音生成每字符0.5s的16kHz Int16Array（见testaudio.Pattern）
+func samples(p string) js.Value {
+	pcm := testaudio.Pattern(p, 16000)
+	arr := js.Global().Get("Int16Array").New(len(pcm) / 2)
+	js.CopyBytesToJS(js.Global().Get("Uint8Array").New(arr.Get("buffer")), pcm)
+	return arr
+}
+
+// call 调用JavaScript函数，异常以Go的panic值返回
+func call(fn js.Value, args ...any) (v js.Value, thrown any) {
+	defer func() { thrown = recover() }()
+	return fn.Invoke(args...), nil
+}
+
+// TestIsSpeech 测试单帧检测与参数校验
+func TestIsSpeech(t *testing.T) {
+	register()
+	api := js.Global().Get("webrtcvad")
+	isSpeech := api.Get("isSpeech")
+
+	audio := samples("01")
+	silence := audio.Call("subarray", 0, 160)
+	speech := audio.Call("subarray", 8000, 8160)
+	if v, err := call(isSpeech, silence, 16000); err != nil || v.Bool() {
+		t.Errorf("静音帧应返回false, 得到%v %v", v, err)
+	}
+	for i := 0; i < 5; i++ {
+		call(isSpeech, speech, 16000)
+	}
+	if v, err := call(isSpeech, speech, 16000); err != nil || !v.Bool() {
+		t.Errorf("正弦波帧应返回true, 得到%v %v", v, err)
+	}
+
+	for name, args := range map[string][]any{
+		"not Int16Array": {js.Global().Get("Float32Array").New(160), 16000},
+		"bad rate":       {speech, 44100},
+		"bad frame":      {audio.Call("subarray", 0, 100), 16000},
+		"bad mode":       {speech, 16000, 7},
+		"rate string":    {speech, "16000"},
+	} {
+		if _, err := call(isSpeech, args...); err == nil {
+			t.Errorf("%s: 应抛出异常", name)
+		}
+	}
+}
+
+// TestIsSpeechRates 测试不同采样率的调用各用一个检测器，交替调用不影响各自的帧间状态
+func TestIsSpeechRates(t *testing.T) {
+	register()
+	isSpeech := js.Global().Get("webrtcvad").Get("isSpeech")
+
+	audio := samples("0110")
+	other := js.Global().Get("Int16Array").New(80)
+	for i := 0; i < 80; i++ {
+		other.SetIndex(i, (i%8-4)*4000)
+	}
+	want, _ := webrtcvad.New(1)
+	pcm := testaudio.Pattern("0110", 16000)
+	for i := 0; i+320 <= len(pcm); i += 320 {
+		call(isSpeech, other, 8000)
+		v, err := call(isSpeech, audio.Call("subarray", i/2, i/2+160), 16000)
+		expected, _ := want.IsSpeech(pcm[i:i+320], 16000)
+		if err != nil || v.Bool() != expected {
+			t.Fatalf("第%d帧: 期望%v, 得到%v %v", i/320, expected, v, err)
+		}
+	}
+}
+
+// TestStream 测试流式对象
+func TestStream(t *testing.T) {
+	register()
+	api := js.Global().Get("webrtcvad")
+
+	cfg := map[string]any{"sampleRate": 16000, "frameMs": 10}
+	s, err := call(api.Get("newStream"), cfg)
+	if err != nil {
+		t.Fatalf("创建流失败: %v", err)
+	}
+	audio := samples("01101")
+	for off := 0; off < audio.Length(); off += 1600 {
+		if _, err := call(s.Get("process"), audio.Call("subarray", off, off+1600)); err != nil {
+			t.Fatalf("process失败: %v", err)
+		}
+	}
+	call(s.Get("flush"))
+
+	segs, _ := call(s.Get("segments"))
+	speech := 0
+	for i := 0; i < segs.Length(); i++ {
+		if segs.Index(i).Get("speech").Bool() {
+			speech++
+		}
+	}
+	last := segs.Index(segs.Length() - 1)
+	if speech != 2 || last.Get("end").Float() != 2.5 {
+		t.Errorf("应得到2个语音片段、总时长2.5s, 得到%d个、%v", speech, last.Get("end"))
+	}
+	call(s.Get("close"))
+
+	if _, err := call(api.Get("newStream"), map[string]any{"sampleRate": 44100}); err == nil {
+		t.Error("无效采样率应抛出异常")
+	}
+}
@@ -0,0 +1,53 @@
+package webrtcvad
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// wav.go 提供将样本和检测到的片段保存为WAV文件的功能
+
+// WriteWAV 将16位单声道样本写为完整的WAV文件
+//
+// 参数:
+//   - w: 输出
+//   - samples: PCM样本
+//   - rate: 采样率（Hz）
+//
+// 返回:
+//   - error: 错误信息
+func WriteWAV(w io.Writer, samples []int16, rate int) error {
+	pcm := make([]byte, len(samples)*2)
+	for i, v := range samples {
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(v))
+	}
+	return wave.Write(w, pcm, rate)
+}
+
+// SaveWAV 将片段的音频保存为WAV文件，便于回放检查或提交ASR
+//
+// 需要StreamVAD启用WithCaptureAudio，否则返回ErrNoAudio
+//
+// 参数:
+//   - path: 文件路径（已存在时覆盖）
+//
+// 返回:
+//   - error: 错误信息
+func (seg VoiceSegment) SaveWAV(path string) error {
+	if len(seg.Audio) == 0 {
+		return ErrNoAudio
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := wave.Write(f, seg.Audio, seg.SampleRate); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,71 @@
+package webrtcvad
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/godeps/webrtcvad-go/wave"
+)
+
+// TestWriteWAV 测试写入后可被wave包读回
+func TestWriteWAV(t *testing.T) {
+	samples := []int16{0, 1, -1, 32767, -32768}
+	var buf bytes.Buffer
+	if err := WriteWAV(&buf, samples, 16000); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if buf.Len() != 44+len(samples)*2 {
+		t.Errorf("文件长度错误: %d", buf.Len())
+	}
+
+	r, err := wave.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("读回失败: %v", err)
+	}
+	if r.SampleRate != 16000 {
+		t.Errorf("采样率错误: %d", r.SampleRate)
+	}
+	pcm, _ := io.ReadAll(r)
+	if got := bytesToInt16(pcm); len(got) != len(samples) {
+		t.Fatalf("样本数错误: 期望%d, 得到%d", len(samples), len(got))
+	} else {
+		for i := range got {
+			if got[i] != samples[i] {
+				t.Errorf("样本%d错误: 期望%d, 得到%d", i, samples[i], got[i])
+			}
+		}
+	}
+}
+
+// TestVoiceSegmentSaveWAV 测试保存片段
+func TestVoiceSegmentSaveWAV(t *testing.T) {
+	svad, err := NewStreamVADWithOptions(WithSampleRate(8000), WithFrameDuration(10), WithCaptureAudio(true))
+	if err != nil {
+		t.Fatalf("创建StreamVAD失败: %v", err)
+	}
+	frame := sineFrame(440, 8000, 8000, 80)
+	svad.pushFrame(frame, true, 0, 10*time.Millisecond)
+
+	seg := svad.GetSegments()[0]
+	path := filepath.Join(t.TempDir(), "seg.wav")
+	if err := seg.SaveWAV(path); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+
+	f, err := wave.Open(path)
+	if err != nil {
+		t.Fatalf("打开失败: %v", err)
+	}
+	defer f.Close()
+	pcm, _ := io.ReadAll(f)
+	if f.SampleRate != 8000 || !bytes.Equal(pcm, frame) {
+		t.Errorf("保存的内容错误: 采样率%d, %d字节", f.SampleRate, len(pcm))
+	}
+
+	if err := (VoiceSegment{}).SaveWAV(path); err != ErrNoAudio {
+		t.Errorf("无音频时应返回ErrNoAudio, 得到%v", err)
+	}
+}
@@ -0,0 +1,302 @@
+// Package wave 提供面向VAD处理的WAV（RIFF）文件读取
+//
+// 解析RIFF头部，校验数据为16位单声道PCM且采样率为VAD支持的取值，
+// 之后按帧读取data块中的样本，避免把WAV头误当作音频送入VAD。
+//
+// 使用示例:
+//
+//	f, err := wave.Open("speech.wav")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	frame := make([]byte, f.FrameSize(20))
+//	for {
+//	    if err := f.ReadFrame(frame); err != nil {
+//	        break // io.EOF：数据读完；io.ErrUnexpectedEOF：最后一帧不完整
+//	    }
+//	    isSpeech, _ := vad.IsSpeech(frame, f.SampleRate)
+//	    // ...
+//	}
+package wave
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// WAV格式常量
+const (
+	// FormatPCM 整数PCM格式标签
+	FormatPCM = 1
+	// FormatExtensible WAVE_FORMAT_EXTENSIBLE格式标签，子格式须为PCM
+	FormatExtensible = 0xFFFE
+)
+
+var (
+	// ErrNotWAV 数据不是RIFF/WAVE格式
+	ErrNotWAV = errors.New("not a RIFF/WAVE file")
+
+	// ErrUnsupportedFormat 不是16位单声道PCM
+	ErrUnsupportedFormat = errors.New("WAV data must be 16-bit mono PCM")
+
+	// ErrUnsupportedRate 采样率不被VAD支持
+	ErrUnsupportedRate = errors.New("WAV sample rate must be 8000, 16000, 24000, 32000, or 48000 Hz")
+)
+
+// Format WAV音频格式（fmt块）
+type Format struct {
+	AudioFormat   uint16 // 格式标签（FormatPCM或FormatExtensible）
+	Channels      int    // 声道数
+	SampleRate    int    // 采样率（Hz）
+	BitsPerSample int    // 位深
+}
+
+// Reader WAV数据读取器
+//
+// Read只返回data块中的PCM字节（16位，小端序），可直接送入VAD
+type Reader struct {
+	Format
+	r         io.Reader
+	dataSize  int64 // data块的字节数
+	remaining int64 // data块中尚未读取的字节数
+}
+
+// NewReader 解析WAV头部并返回定位到data块起始处的读取器
+//
+// 参数:
+//   - r: WAV数据来源
+//
+// 返回:
+//   - *Reader: 读取器
+//   - error: ErrNotWAV、ErrUnsupportedFormat、ErrUnsupportedRate或读取错误
+func NewReader(r io.Reader) (*Reader, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrNotWAV
+		}
+		return nil, err
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, ErrNotWAV
+	}
+
+	wr := &Reader{r: r}
+	haveFmt := false
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil, fmt.Errorf("%w: missing data chunk", ErrNotWAV)
+			}
+			return nil, err
+		}
+		id := string(hdr[0:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+
+		switch id {
+		case "fmt ":
+			if err := wr.readFormat(size); err != nil {
+				return nil, err
+			}
+			haveFmt = true
+		case "data":
+			if !haveFmt {
+				return nil, fmt.Errorf("%w: data chunk before fmt chunk", ErrNotWAV)
+			}
+			wr.dataSize = size
+			wr.remaining = size
+			return wr, nil
+		default:
+			// 跳过LIST、fact等其他块（块长度为奇数时有1字节填充）
+			if err := skip(r, size+size&1); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// fmt块的长度限制：PCM为16字节，WAVE_FORMAT_EXTENSIBLE为40字节，
+// 超过maxFormatSize的长度视为头部损坏，避免按声明的长度分配或跳过大量数据
+const (
+	minFormatSize    = 16
+	parsedFormatSize = 40 // 解析用到的字节数，其余部分跳过
+	maxFormatSize    = 1024
+)
+
+// readFormat 解析并校验fmt块
+func (wr *Reader) readFormat(size int64) error {
+	if size < minFormatSize {
+		return fmt.Errorf("%w: fmt chunk too short", ErrNotWAV)
+	}
+	if size > maxFormatSize {
+		return fmt.Errorf("%w: fmt chunk size %d too large", ErrNotWAV, size)
+	}
+	var buf [parsedFormatSize]byte
+	n := min(size, parsedFormatSize)
+	if _, err := io.ReadFull(wr.r, buf[:n]); err != nil {
+		return err
+	}
+	if err := skip(wr.r, size-n+size&1); err != nil {
+		return err
+	}
+
+	wr.AudioFormat = binary.LittleEndian.Uint16(buf[0:2])
+	wr.Channels = int(binary.LittleEndian.Uint16(buf[2:4]))
+	wr.SampleRate = int(binary.LittleEndian.Uint32(buf[4:8]))
+	wr.BitsPerSample = int(binary.LittleEndian.Uint16(buf[14:16]))
+
+	pcm := wr.AudioFormat == FormatPCM
+	if wr.AudioFormat == FormatExtensible && size >= 26 {
+		// 子格式GUID的前两个字节为格式标签
+		pcm = binary.LittleEndian.Uint16(buf[24:26]) == FormatPCM
+	}
+	if !pcm || wr.Channels != 1 || wr.BitsPerSample != 16 {
+		return fmt.Errorf("%w: format=%#x, channels=%d, bits=%d",
+			ErrUnsupportedFormat, wr.AudioFormat, wr.Channels, wr.BitsPerSample)
+	}
+
+	switch wr.SampleRate {
+	case 8000, 16000, 24000, 32000, 48000:
+		return nil
+	default:
+		return fmt.Errorf("%w: got %d Hz", ErrUnsupportedRate, wr.SampleRate)
+	}
+}
+
+// Read 读取data块中的PCM字节，实现io.Reader
+func (wr *Reader) Read(p []byte) (int, error) {
+	if wr.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > wr.remaining {
+		p = p[:wr.remaining]
+	}
+	n, err := wr.r.Read(p)
+	wr.remaining -= int64(n)
+	if err == io.EOF && wr.remaining > 0 {
+		// 文件被截断：按已有数据结束，与多数播放器的行为一致
+		wr.remaining = 0
+	}
+	return n, err
+}
+
+// FrameSize 返回frameMs毫秒帧的字节数
+func (wr *Reader) FrameSize(frameMs int) int {
+	return wr.SampleRate * frameMs / 1000 * 2
+}
+
+// ReadFrame 读取恰好len(frame)字节的一帧
+//
+// 返回:
+//   - error: 数据读完时为io.EOF；剩余数据不足一帧时为io.ErrUnexpectedEOF
+func (wr *Reader) ReadFrame(frame []byte) error {
+	_, err := io.ReadFull(wr, frame)
+	return err
+}
+
+// DataSize 返回头部声明的data块字节数
+func (wr *Reader) DataSize() int64 {
+	return wr.dataSize
+}
+
+// File 打开的WAV文件
+type File struct {
+	*Reader
+	f *os.File
+}
+
+// Open 打开WAV文件并解析头部
+//
+// 参数:
+//   - path: 文件路径
+//
+// 返回:
+//   - *File: 定位到data块的文件，用完后须Close
+//   - error: 打开或解析错误
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &File{Reader: r, f: f}, nil
+}
+
+// Close 关闭文件
+func (f *File) Close() error {
+	return f.f.Close()
+}
+
+// skip 丢弃r中的n个字节
+func skip(r io.Reader, n int64) error {
+	if s, ok := r.(io.Seeker); ok {
+		_, err := s.Seek(n, io.SeekCurrent)
+		return err
+	}
+	_, err := io.CopyN(io.Discard, r, n)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// headerSize 标准PCM WAV头部的字节数
+const headerSize = 44
+
+// WriteHeader 写入16位单声道PCM的WAV头部
+//
+// 参数:
+//   - w: 输出
+//   - sampleRate: 采样率（Hz）
+//   - dataSize: 随后写入的PCM数据字节数
+func WriteHeader(w io.Writer, sampleRate int, dataSize int) error {
+	if sampleRate <= 0 {
+		return ErrUnsupportedRate
+	}
+	if dataSize < 0 || dataSize%2 != 0 || int64(dataSize) > 0xFFFFFFFF-headerSize+8 {
+		return fmt.Errorf("wave: invalid PCM data size %d", dataSize)
+	}
+
+	var hdr [headerSize]byte
+	le := binary.LittleEndian
+	copy(hdr[0:4], "RIFF")
+	le.PutUint32(hdr[4:8], uint32(headerSize-8+dataSize))
+	copy(hdr[8:12], "WAVE")
+	copy(hdr[12:16], "fmt ")
+	le.PutUint32(hdr[16:20], 16)
+	le.PutUint16(hdr[20:22], FormatPCM)
+	le.PutUint16(hdr[22:24], 1)                    // 单声道
+	le.PutUint32(hdr[24:28], uint32(sampleRate))   // 采样率
+	le.PutUint32(hdr[28:32], uint32(sampleRate*2)) // 字节率
+	le.PutUint16(hdr[32:34], 2)                    // 块对齐
+	le.PutUint16(hdr[34:36], 16)                   // 位深
+	copy(hdr[36:40], "data")
+	le.PutUint32(hdr[40:44], uint32(dataSize))
+
+	_, err := w.Write(hdr[:])
+	return err
+}
+
+// Write 将16位单声道PCM数据写为完整的WAV文件
+//
+// 参数:
+//   - w: 输出
+//   - pcm: PCM数据（16位，小端序）
+//   - sampleRate: 采样率（Hz）
+func Write(w io.Writer, pcm []byte, sampleRate int) error {
+	if err := WriteHeader(w, sampleRate, len(pcm)); err != nil {
+		return err
+	}
+	_, err := w.Write(pcm)
+	return err
+}
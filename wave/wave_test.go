@@ -0,0 +1,167 @@
+package wave
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildWAV 构造WAV数据，fmt块之前插入一个奇数长度的LIST块
+func buildWAV(format uint16, channels, rate, bits int, data []byte) []byte {
+	var b bytes.Buffer
+	le := binary.LittleEndian
+
+	b.WriteString("RIFF")
+	binary.Write(&b, le, uint32(0)) // 长度字段不参与解析
+	b.WriteString("WAVE")
+
+	b.WriteString("LIST")
+	binary.Write(&b, le, uint32(3))
+	b.Write([]byte{'a', 'b', 'c', 0}) // 含1字节填充
+
+	b.WriteString("fmt ")
+	binary.Write(&b, le, uint32(16))
+	binary.Write(&b, le, format)
+	binary.Write(&b, le, uint16(channels))
+	binary.Write(&b, le, uint32(rate))
+	binary.Write(&b, le, uint32(rate*channels*bits/8))
+	binary.Write(&b, le, uint16(channels*bits/8))
+	binary.Write(&b, le, uint16(bits))
+
+	b.WriteString("data")
+	binary.Write(&b, le, uint32(len(data)))
+	b.Write(data)
+	return b.Bytes()
+}
+
+// TestReader 测试解析头部和按帧读取
+func TestReader(t *testing.T) {
+	data := make([]byte, 16000*2*25/1000) // 25ms @16kHz
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	r, err := NewReader(bytes.NewReader(buildWAV(FormatPCM, 1, 16000, 16, data)))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if r.SampleRate != 16000 || r.Channels != 1 || r.BitsPerSample != 16 {
+		t.Errorf("格式错误: %+v", r.Format)
+	}
+	if r.DataSize() != int64(len(data)) {
+		t.Errorf("data块大小错误: 期望%d, 得到%d", len(data), r.DataSize())
+	}
+
+	frame := make([]byte, r.FrameSize(10))
+	var got []byte
+	for {
+		err := r.ReadFrame(frame)
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("读取帧失败: %v", err)
+		}
+		got = append(got, frame...)
+	}
+	if len(got) != 2*len(frame) || !bytes.Equal(got, data[:len(got)]) {
+		t.Errorf("帧数据错误: 读到%d字节", len(got))
+	}
+	if err := r.ReadFrame(frame); err != io.EOF {
+		t.Errorf("读完后应返回io.EOF, 得到%v", err)
+	}
+
+	// fmt块超过解析所需的长度时跳过多余部分（含奇数长度的填充）
+	wav := buildWAV(FormatPCM, 1, 16000, 16, data)
+	wav = append(wav[:48:48], append(make([]byte, 52), wav[48:]...)...)
+	r, err = NewReader(bytes.NewReader(withFormatSize(wav, 67)))
+	if err != nil {
+		t.Fatalf("较长的fmt块解析失败: %v", err)
+	}
+	if r.SampleRate != 16000 || r.DataSize() != int64(len(data)) {
+		t.Errorf("较长的fmt块格式错误: %+v, data=%d", r.Format, r.DataSize())
+	}
+}
+
+// withFormatSize 将buildWAV生成的数据中fmt块的长度字段改为size
+func withFormatSize(wav []byte, size uint32) []byte {
+	binary.LittleEndian.PutUint32(wav[28:32], size)
+	return wav
+}
+
+// TestReaderInvalid 测试格式校验
+func TestReaderInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"原始PCM", make([]byte, 320), ErrNotWAV},
+		{"空数据", nil, ErrNotWAV},
+		{"立体声", buildWAV(FormatPCM, 2, 16000, 16, nil), ErrUnsupportedFormat},
+		{"8位", buildWAV(FormatPCM, 1, 16000, 8, nil), ErrUnsupportedFormat},
+		{"浮点", buildWAV(3, 1, 16000, 32, nil), ErrUnsupportedFormat},
+		{"44.1kHz", buildWAV(FormatPCM, 1, 44100, 16, nil), ErrUnsupportedRate},
+		{"缺少data块", buildWAV(FormatPCM, 1, 16000, 16, nil)[:48], ErrNotWAV},
+		{"fmt块过长", withFormatSize(buildWAV(FormatPCM, 1, 16000, 16, nil), 0xFFFFFFF0), ErrNotWAV},
+		{"fmt块过短", withFormatSize(buildWAV(FormatPCM, 1, 16000, 16, nil), 14), ErrNotWAV},
+	}
+	for _, tt := range tests {
+		if _, err := NewReader(bytes.NewReader(tt.data)); !errors.Is(err, tt.want) {
+			t.Errorf("%s: 期望%v, 得到%v", tt.name, tt.want, err)
+		}
+	}
+}
+
+// TestOpen 测试打开文件
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.wav")
+	data := make([]byte, 160)
+	if err := os.WriteFile(path, buildWAV(FormatPCM, 1, 8000, 16, data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Open(path)
+	if err != nil {
+		t.Fatalf("打开失败: %v", err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f)
+	if err != nil || len(got) != len(data) {
+		t.Errorf("读取错误: %d字节, %v", len(got), err)
+	}
+
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.wav")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("不存在的文件应返回os.ErrNotExist, 得到%v", err)
+	}
+}
+
+// TestWrite 测试写入后读回
+func TestWrite(t *testing.T) {
+	pcm := []byte{1, 2, 3, 4, 5, 6}
+	var buf bytes.Buffer
+	if err := Write(&buf, pcm, 48000); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("读回失败: %v", err)
+	}
+	got, _ := io.ReadAll(r)
+	if r.SampleRate != 48000 || !bytes.Equal(got, pcm) {
+		t.Errorf("读回内容错误: 采样率%d, 数据%v", r.SampleRate, got)
+	}
+
+	if err := Write(io.Discard, []byte{1, 2, 3}, 16000); err == nil {
+		t.Error("奇数长度的PCM数据应返回错误")
+	}
+	if err := Write(io.Discard, pcm, 0); err != ErrUnsupportedRate {
+		t.Errorf("无效采样率应返回ErrUnsupportedRate, 得到%v", err)
+	}
+}
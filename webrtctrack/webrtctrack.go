@@ -0,0 +1,191 @@
+// Package webrtctrack 将WebRTC远端音频轨道接入StreamVAD
+//
+// 本包不依赖pion/webrtc：轨道通过只有一个方法的Track接口读取RTP包，
+// 负载解码通过Decoder接口由调用方提供（如Opus解码器）。对于pion的
+// *webrtc.TrackRemote，用TrackFunc包装其Read方法即可：
+//
+//	track := webrtctrack.TrackFunc(func(b []byte) (int, error) {
+//	    n, _, err := remote.Read(b)
+//	    return n, err
+//	})
+//	dec, _ := opus.NewDecoder(48000, 1) // 任意实现了Decode的单声道解码器
+//	a, err := webrtctrack.New(track, dec, webrtcvad.WithStreamMode(2))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = a.Run(func(seg webrtcvad.VoiceSegment) error {
+//	    // ...
+//	    return nil
+//	})
+//
+// 重复和乱序到达的包被丢弃，丢包与静音抑制（DTX）造成的时间戳间隔以静音填补，
+// 片段时间与发送端的时间轴一致（与webrtcvad.RTPProcessor共用webrtcvad.RTPSequencer）。
+// 无法解析或解码的包被跳过并计数，只有轨道的读取错误会结束检测。
+package webrtctrack
+
+import (
+	"encoding/binary"
+	"errors"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+)
+
+// DefaultSampleRate 默认的解码输出采样率（Opus）
+const DefaultSampleRate = 48000
+
+// maxPacketSize 单个RTP包的最大字节数
+const maxPacketSize = 1500
+
+// maxFrameSamples 单个数据包解码后的最大样本数（120ms @48kHz）
+const maxFrameSamples = 5760
+
+// Track 远端音频轨道
+type Track interface {
+	// ReadPacket 读取一个完整的RTP包到b，返回字节数；轨道结束时返回io.EOF
+	ReadPacket(b []byte) (int, error)
+}
+
+// TrackFunc 将函数适配为Track
+type TrackFunc func(b []byte) (int, error)
+
+// ReadPacket 调用f(b)
+func (f TrackFunc) ReadPacket(b []byte) (int, error) {
+	return f(b)
+}
+
+// Decoder 负载解码器
+type Decoder interface {
+	// Decode 将一个RTP负载解码为单声道样本写入pcm，返回样本数
+	//
+	// 输出采样率须与StreamVAD一致，且等于RTP时间戳的时钟频率
+	Decode(payload []byte, pcm []int16) (int, error)
+}
+
+// Adapter 远端音频轨道到StreamVAD的适配器
+//
+// Adapter实现io.Reader，读出解码并填补间隔后的16位小端序PCM；
+// Run以此驱动StreamVAD。Adapter不是并发安全的
+type Adapter struct {
+	track   Track
+	decoder Decoder
+	svad    *webrtcvad.StreamVAD
+
+	packet  []byte
+	pcm     []int16
+	buf     []byte // 解码输出缓冲区
+	out     []byte // buf中尚未读出的部分
+	silence int    // 尚未读出的静音样本数
+
+	seq     *webrtcvad.RTPSequencer
+	invalid int // 无法解析或解码而跳过的包数
+}
+
+// New 创建适配器
+//
+// 参数:
+//   - track: 远端音频轨道
+//   - decoder: 负载解码器
+//   - opts: 内部StreamVAD的配置选项，采样率默认为DefaultSampleRate
+//
+// 返回:
+//   - *Adapter: 适配器实例
+//   - error: 错误信息
+func New(track Track, decoder Decoder, opts ...webrtcvad.StreamVADOption) (*Adapter, error) {
+	if track == nil || decoder == nil {
+		return nil, errors.New("webrtctrack: nil track or decoder")
+	}
+	opts = append([]webrtcvad.StreamVADOption{webrtcvad.WithSampleRate(DefaultSampleRate)}, opts...)
+	svad, err := webrtcvad.NewStreamVADWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Adapter{
+		track:   track,
+		decoder: decoder,
+		svad:    svad,
+		packet:  make([]byte, maxPacketSize),
+		pcm:     make([]int16, maxFrameSamples),
+		seq:     webrtcvad.NewRTPSequencer(svad.GetSampleRate()),
+	}, nil
+}
+
+// StreamVAD 获取内部的StreamVAD
+func (a *Adapter) StreamVAD() *webrtcvad.StreamVAD {
+	return a.svad
+}
+
+// PacketsLost 按序列号间隔统计的丢包数
+func (a *Adapter) PacketsLost() int {
+	return a.seq.PacketsLost()
+}
+
+// PacketsInvalid 无法解析或解码而跳过的包数
+func (a *Adapter) PacketsInvalid() int {
+	return a.invalid
+}
+
+// Run 读取轨道直到结束，每个片段结束时调用fn
+//
+// 轨道返回io.EOF时结束最后一个片段并返回nil；轨道的其他读取错误或fn返回错误时
+// 停止读取并返回该错误。无效的包不会中止检测，见PacketsInvalid
+func (a *Adapter) Run(fn func(webrtcvad.VoiceSegment) error) error {
+	return a.svad.ProcessReader(a, fn)
+}
+
+// Read 读出解码后的PCM，实现io.Reader
+func (a *Adapter) Read(p []byte) (int, error) {
+	for a.silence == 0 && len(a.out) == 0 {
+		if err := a.readPacket(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := 0
+	if a.silence > 0 {
+		n = min(a.silence*2, len(p)) &^ 1
+		clear(p[:n])
+		a.silence -= n / 2
+		if a.silence > 0 {
+			return n, nil
+		}
+	}
+	m := copy(p[n:], a.out)
+	a.out = a.out[m:]
+	return n + m, nil
+}
+
+// readPacket 读取并解码一个RTP包，无效的包被跳过并计数
+func (a *Adapter) readPacket() error {
+	n, err := a.track.ReadPacket(a.packet)
+	if err != nil {
+		return err
+	}
+	pkt, err := webrtcvad.ParseRTPPacket(a.packet[:n])
+	if err != nil {
+		a.invalid++
+		return nil
+	}
+	gap, ok := a.seq.Check(pkt)
+	if !ok {
+		return nil
+	}
+
+	samples, err := a.decoder.Decode(pkt.Payload, a.pcm)
+	if err != nil || samples < 0 {
+		// 仍记录序列号，该包的时长由下一个包前的间隔以静音填补
+		a.invalid++
+		a.seq.Commit(pkt, 0)
+		a.silence = gap
+		return nil
+	}
+	samples = min(samples, len(a.pcm))
+	a.seq.Commit(pkt, samples)
+
+	a.silence = gap
+	a.buf = a.buf[:0]
+	for _, v := range a.pcm[:samples] {
+		a.buf = binary.LittleEndian.AppendUint16(a.buf, uint16(v))
+	}
+	a.out = a.buf
+	return nil
+}
@@ -0,0 +1,186 @@
+package webrtctrack
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	webrtcvad "github.com/godeps/webrtcvad-go"
+)
+
+// packetSamples 每包样本数（16kHz下20ms）
+const packetSamples = 320
+
+// fakeTrack 按顺序返回预先构造的RTP包
+type fakeTrack [][]byte
+
+func (t *fakeTrack) ReadPacket(b []byte) (int, error) {
+	if len(*t) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, (*t)[0])
+	*t = (*t)[1:]
+	return n, nil
+}
+
+// fakeDecoder 负载首字节为1时输出440Hz正弦波，否则输出静音；
+// 第二个字节（如有）为输出的样本数除以16，默认为packetSamples
+type fakeDecoder struct{ decoded int }
+
+func (d *fakeDecoder) Decode(payload []byte, pcm []int16) (int, error) {
+	if len(payload) == 0 {
+		return 0, errors.New("empty payload")
+	}
+	samples := packetSamples
+	if len(payload) > 1 {
+		samples = int(payload[1]) * 16
+	}
+	for i := 0; i < samples; i++ {
+		pcm[i] = 0
+		if payload[0] == 1 {
+			pcm[i] = int16(8000 * math.Sin(2*math.Pi*440*float64(d.decoded+i)/16000))
+		}
+	}
+	d.decoded += samples
+	return samples, nil
+}
+
+// rtpPacket 构造版本2、SSRC为1的RTP包
+func rtpPacket(seq uint16, ts uint32, speech bool) []byte {
+	pkt := []byte{0x80, 111}
+	pkt = binary.BigEndian.AppendUint16(pkt, seq)
+	pkt = binary.BigEndian.AppendUint32(pkt, ts)
+	pkt = binary.BigEndian.AppendUint32(pkt, 1)
+	if speech {
+		return append(pkt, 1)
+	}
+	return append(pkt, 0)
+}
+
+// TestAdapterRun 测试解码、间隔填补与片段回调
+func TestAdapterRun(t *testing.T) {
+	// 前0.5s静音，后0.5s语音；第10-19包因静音抑制未发送（序列号连续），
+	// 第5包丢失，第3包重复，第30包乱序到达
+	var packets [50][]byte
+	for i := range packets {
+		seq := 100 + i
+		if i >= 20 {
+			seq -= 10
+		}
+		packets[i] = rtpPacket(uint16(seq), uint32(i*packetSamples), i >= 25)
+	}
+	track := fakeTrack{packets[0], packets[1], packets[2], packets[3], packets[3], packets[4], packets[6],
+		packets[7], packets[8], packets[9]}
+	for i := 20; i < 50; i++ {
+		if i == 30 {
+			continue
+		}
+		track = append(track, packets[i])
+		if i == 31 {
+			track = append(track, packets[30])
+		}
+	}
+
+	dec := &fakeDecoder{}
+	a, err := New(&track, dec, webrtcvad.WithSampleRate(16000), webrtcvad.WithStreamMode(1))
+	if err != nil {
+		t.Fatalf("创建适配器失败: %v", err)
+	}
+	var segments []webrtcvad.VoiceSegment
+	err = a.Run(func(seg webrtcvad.VoiceSegment) error {
+		segments = append(segments, seg)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run失败: %v", err)
+	}
+
+	if got := a.StreamVAD().GetTotalDuration(); got != time.Second {
+		t.Errorf("总时长应为1s, 得到%v", got)
+	}
+	if got := a.PacketsLost(); got != 2 {
+		t.Errorf("应统计2个丢包（第5、30包）, 得到%d", got)
+	}
+	if dec.decoded != 38*packetSamples {
+		t.Errorf("重复和乱序的包不应解码, 解码了%d个样本", dec.decoded)
+	}
+	if len(segments) != 2 || segments[1].Start < 500*time.Millisecond || segments[1].Start > 550*time.Millisecond ||
+		!segments[1].IsSpeech || segments[1].End != time.Second {
+		t.Errorf("应得到静音片段和0.5s附近开始的语音片段, 得到%v", segments)
+	}
+}
+
+// TestAdapterPartialFrame 测试轨道以不足一帧的语音结束时，最后一个片段也交付给回调
+func TestAdapterPartialFrame(t *testing.T) {
+	// 0.5s语音、0.46s静音（共32个30ms帧），最后一个语音包只有10ms，不足一帧
+	track := fakeTrack{}
+	for i := 0; i < 48; i++ {
+		track = append(track, rtpPacket(uint16(i), uint32(i*packetSamples), i < 25))
+	}
+	track = append(track, append(rtpPacket(48, 48*packetSamples, true), 10))
+	a, _ := New(&track, &fakeDecoder{}, webrtcvad.WithSampleRate(16000), webrtcvad.WithStreamMode(1))
+	var segments []webrtcvad.VoiceSegment
+	if err := a.Run(func(seg webrtcvad.VoiceSegment) error {
+		segments = append(segments, seg)
+		return nil
+	}); err != nil {
+		t.Fatalf("Run失败: %v", err)
+	}
+	if want := a.StreamVAD().GetSegments(); len(segments) != len(want) || !want[len(want)-1].IsSpeech {
+		t.Errorf("回调片段应与GetSegments一致且以语音结束: %v / %v", segments, want)
+	}
+}
+
+// TestAdapterErrors 测试错误传递
+func TestAdapterErrors(t *testing.T) {
+	if _, err := New(nil, &fakeDecoder{}); err == nil {
+		t.Error("nil轨道应返回错误")
+	}
+	if _, err := New(&fakeTrack{}, &fakeDecoder{}, webrtcvad.WithSampleRate(44100)); err == nil {
+		t.Error("无效采样率应返回错误")
+	}
+
+	// 无效的包被跳过并计数，不中止检测：第10包无法解析，第11包解码失败
+	track := fakeTrack{}
+	for i := 0; i < 50; i++ {
+		switch i {
+		case 10:
+			track = append(track, []byte{0x80, 0, 0})
+		case 11:
+			track = append(track, rtpPacket(uint16(i), uint32(i*packetSamples), false)[:12])
+		default:
+			track = append(track, rtpPacket(uint16(i), uint32(i*packetSamples), i >= 25))
+		}
+	}
+	a, _ := New(&track, &fakeDecoder{}, webrtcvad.WithSampleRate(16000), webrtcvad.WithStreamMode(1))
+	if err := a.Run(func(webrtcvad.VoiceSegment) error { return nil }); err != nil {
+		t.Errorf("无效的包不应中止Run, 得到%v", err)
+	}
+	if a.PacketsInvalid() != 2 || a.PacketsLost() != 1 {
+		t.Errorf("应跳过2个无效包、统计1个丢包（无法解析的第10包）, 得到%d/%d", a.PacketsInvalid(), a.PacketsLost())
+	}
+	if got := a.StreamVAD().GetTotalDuration(); got != time.Second {
+		t.Errorf("跳过的包应以静音填补, 总时长应为1s, 得到%v", got)
+	}
+
+	// 轨道的读取错误
+	broken := errors.New("track closed")
+	a, _ = New(TrackFunc(func([]byte) (int, error) { return 0, broken }), &fakeDecoder{})
+	if err := a.Run(func(webrtcvad.VoiceSegment) error { return nil }); err != broken {
+		t.Errorf("应返回轨道的读取错误, 得到%v", err)
+	}
+
+	// 回调错误
+	stop := errors.New("stop")
+	track = fakeTrack{}
+	for i := 0; i < 50; i++ {
+		track = append(track, rtpPacket(uint16(i), uint32(i*packetSamples), i >= 25))
+	}
+	a, _ = New(&track, &fakeDecoder{}, webrtcvad.WithSampleRate(16000), webrtcvad.WithStreamMode(1))
+	if err := a.Run(func(webrtcvad.VoiceSegment) error { return stop }); err != stop {
+		t.Errorf("应返回回调的错误, 得到%v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package webrtcvad
+
+// zerocopy.go 提供可选的零拷贝字节->int16转换
+//
+// 在小端序平台上，16位小端序PCM字节数组的内存布局与[]int16完全一致，
+// 可以直接重新解释而无需逐样本复制。具体实现由构建标签选择：
+//   - zerocopy_le.go：小端序平台，使用unsafe.Slice
+//   - zerocopy_other.go：大端序平台或指定purego标签时，始终回退到复制
+
+// WithZeroCopy 启用零拷贝输入路径
+//
+// 启用后，IsSpeech等接口在输入缓冲区按2字节对齐时直接将其视为[]int16，
+// 省去每帧一次的分配和复制，适合同时处理大量音频流的服务端。
+// 不满足条件（大端序平台、purego构建或缓冲区未对齐）时自动回退到复制路径。
+//
+// 注意：检测过程只读取输入，不会修改调用方的缓冲区
+func WithZeroCopy() Option {
+	return func(v *VAD) error {
+		v.zeroCopy = true
+		return nil
+	}
+}
+
+// toInt16 将PCM字节转换为int16样本
+//
+// 启用零拷贝且条件满足时直接返回视图，否则复制到scratch中
+// （scratch长度应不小于len(buf)/2）
+func (v *VAD) toInt16(buf []byte, scratch []int16) []int16 {
+	if v.zeroCopy {
+		if samples, ok := int16View(buf); ok {
+			return samples
+		}
+	}
+	return bytesToInt16To(buf, scratch)
+}
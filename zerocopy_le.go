@@ -0,0 +1,24 @@
+//go:build (386 || amd64 || arm || arm64 || loong64 || mips64le || mipsle || ppc64le || riscv64 || wasm) && !purego
+
+package webrtcvad
+
+import "unsafe"
+
+// zeroCopySupported 当前平台是否支持零拷贝视图
+const zeroCopySupported = true
+
+// int16View 将小端序PCM字节直接重新解释为[]int16
+//
+// 缓冲区为空或起始地址不是2字节对齐时返回false
+func int16View(buf []byte) ([]int16, bool) {
+	if len(buf) < 2 {
+		return nil, false
+	}
+
+	ptr := unsafe.Pointer(unsafe.SliceData(buf))
+	if uintptr(ptr)%unsafe.Alignof(int16(0)) != 0 {
+		return nil, false
+	}
+
+	return unsafe.Slice((*int16)(ptr), len(buf)/2), true
+}
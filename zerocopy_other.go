@@ -0,0 +1,11 @@
+//go:build !(386 || amd64 || arm || arm64 || loong64 || mips64le || mipsle || ppc64le || riscv64 || wasm) || purego
+
+package webrtcvad
+
+// zeroCopySupported 当前平台是否支持零拷贝视图
+const zeroCopySupported = false
+
+// int16View 大端序平台或purego构建下不支持零拷贝，始终返回false
+func int16View(buf []byte) ([]int16, bool) {
+	return nil, false
+}
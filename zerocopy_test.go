@@ -0,0 +1,66 @@
+package webrtcvad
+
+import "testing"
+
+// TestInt16View 测试零拷贝视图与复制路径结果一致
+func TestInt16View(t *testing.T) {
+	buf := make([]byte, 322)
+	for i := range buf {
+		buf[i] = byte(i * 7)
+	}
+
+	want := bytesToInt16(buf)
+
+	view, ok := int16View(buf)
+	if ok != zeroCopySupported {
+		t.Fatalf("int16View ok=%v, 平台支持=%v", ok, zeroCopySupported)
+	}
+	if ok {
+		for i := range want {
+			if view[i] != want[i] {
+				t.Fatalf("样本%d: 视图%d, 复制%d", i, view[i], want[i])
+			}
+		}
+
+		// 未对齐的缓冲区必须回退
+		if _, ok := int16View(buf[1:]); ok {
+			t.Error("未对齐缓冲区不应使用零拷贝")
+		}
+	}
+}
+
+// TestWithZeroCopy 测试启用零拷贝后检测结果不变
+func TestWithZeroCopy(t *testing.T) {
+	copyVAD, err := New(1)
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+	zcVAD, err := NewWithOptions(WithMode(1), WithZeroCopy())
+	if err != nil {
+		t.Fatalf("创建VAD失败: %v", err)
+	}
+
+	frame := sineFrame(400, 8000, 16000, 320)
+	for i := 0; i < 20; i++ {
+		a, err := copyVAD.IsSpeech(frame, 16000)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		b, err := zcVAD.IsSpeech(frame, 16000)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		if a != b {
+			t.Fatalf("帧%d: 零拷贝结果%v与复制结果%v不一致", i, b, a)
+		}
+	}
+
+	if zeroCopySupported {
+		allocs := testing.AllocsPerRun(100, func() {
+			zcVAD.IsSpeech(frame, 16000)
+		})
+		if allocs != 0 {
+			t.Errorf("零拷贝路径期望0次分配, 得到%.1f", allocs)
+		}
+	}
+}